@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+)
+
+// BehaviorParams holds the data-driven parameters of a behavior, as decoded from the IR.
+type BehaviorParams map[string]any
+
+// GetFloat returns the numeric parameter associated with key, or def when missing or not a number.
+func (p BehaviorParams) GetFloat(key string, def float64) float64 {
+	switch v := p[key].(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return def
+}
+
+// GetString returns the string parameter associated with key, or def when missing or not a string.
+func (p BehaviorParams) GetString(key string, def string) string {
+	if v, ok := p[key].(string); ok {
+		return v
+	}
+	return def
+}
+
+// GetBool returns the boolean parameter associated with key, or def when missing or not a boolean.
+func (p BehaviorParams) GetBool(key string, def bool) bool {
+	if v, ok := p[key].(bool); ok {
+		return v
+	}
+	return def
+}
+
+// GetStrings returns the string list associated with key, accepting both native and JSON-decoded slices.
+func (p BehaviorParams) GetStrings(key string) []string {
+	switch v := p[key].(type) {
+	case []string:
+		return v
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// Behavior references a registered behavior by its string id, together with its parameters.
+type Behavior struct {
+	Id     string         `json:"id"`
+	Params BehaviorParams `json:"params"`
+}
+
+// NewConfigBehavior creates a Behavior referencing the given registered id with the specified parameters.
+func NewConfigBehavior(id string, params BehaviorParams) *Behavior {
+	if params == nil {
+		params = BehaviorParams{}
+	}
+	return &Behavior{Id: id, Params: params}
+}
+
+// Clone returns a copy of the Behavior with a shallow copy of its parameters.
+func (b *Behavior) Clone() *Behavior {
+	if b == nil {
+		return nil
+	}
+	params := make(BehaviorParams, len(b.Params))
+	for k, v := range b.Params {
+		params[k] = v
+	}
+	return &Behavior{Id: b.Id, Params: params}
+}
+
+// BehaviorHandlers groups the callbacks that implement a behavior for a single thing instance. OnDeath and OnDestroy
+// are optional: OnDestroy runs once the thing has left the simulation, to release what the behavior holds.
+type BehaviorHandlers struct {
+	OnThinking  ThinkingFunc
+	OnCollision CollisionFunc
+	OnImpact    ImpactFunc
+	OnDeath     DeathFunc
+	OnDestroy   DestroyFunc
+}
+
+// BehaviorFactory creates the handlers of a behavior for the given thing, using the behavior parameters. Any random
+// choice of the behavior must be drawn from a generator initialized with seed, so that a deterministic simulation
+// replays the same choices.
+type BehaviorFactory func(cfg *Thing, params BehaviorParams, seed int64) *BehaviorHandlers
+
+// _behaviors is the global registry of behavior factories, indexed by behavior id.
+// _defaultBehaviors maps a ThingType to the behavior id used when a thing does not reference any behavior.
+var (
+	_behaviorsMu      sync.RWMutex
+	_behaviors        = make(map[string]BehaviorFactory)
+	_defaultBehaviors = make(map[ThingType]string)
+)
+
+// RegisterBehavior registers a behavior factory under the given id, replacing any previous registration.
+func RegisterBehavior(id string, factory BehaviorFactory) {
+	_behaviorsMu.Lock()
+	defer _behaviorsMu.Unlock()
+	_behaviors[id] = factory
+}
+
+// SetDefaultBehavior sets the behavior id used by things of the given kind that do not reference a behavior.
+func SetDefaultBehavior(kind ThingType, id string) {
+	_behaviorsMu.Lock()
+	defer _behaviorsMu.Unlock()
+	_defaultBehaviors[kind] = id
+}
+
+// HasBehavior reports whether a behavior with the given id is registered.
+func HasBehavior(id string) bool {
+	_behaviorsMu.RLock()
+	defer _behaviorsMu.RUnlock()
+	_, ok := _behaviors[id]
+	return ok
+}
+
+// lookupBehavior returns the behavior id referenced by the thing (or the default one for kind) and whether it is registered.
+func lookupBehavior(cfg *Thing, kind ThingType) (string, bool) {
+	_behaviorsMu.RLock()
+	defer _behaviorsMu.RUnlock()
+	id := _defaultBehaviors[kind]
+	if cfg.Behavior != nil {
+		id = cfg.Behavior.Id
+	}
+	_, ok := _behaviors[id]
+	return id, ok
+}
+
+// ResolveBehavior instantiates the handlers of the behavior referenced by the thing (or the default one for its kind),
+// passing seed to the factory.
+func ResolveBehavior(cfg *Thing, seed int64) (*BehaviorHandlers, error) {
+	id, ok := lookupBehavior(cfg, cfg.Kind)
+	if !ok {
+		return nil, fmt.Errorf("unknown behavior '%s' for thing: %s", id, cfg.Id)
+	}
+	_behaviorsMu.RLock()
+	factory := _behaviors[id]
+	_behaviorsMu.RUnlock()
+	var params BehaviorParams
+	if cfg.Behavior != nil {
+		params = cfg.Behavior.Params
+	}
+	if params == nil {
+		params = BehaviorParams{}
+	}
+	handlers := factory(cfg, params, seed)
+	if handlers == nil {
+		return nil, fmt.Errorf("behavior '%s' returned no handlers for thing: %s", id, cfg.Id)
+	}
+	return handlers, nil
+}
@@ -0,0 +1,361 @@
+package config
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/markel1974/godoom/mr_tech/textures"
+)
+
+// bundleRootFile is the name of the JSON IR entry inside a bundle.
+// bundleTexturesFile is the name of the texture manifest entry inside a bundle.
+// bundleTexturesDir is the folder holding the extracted texture images.
+const (
+	bundleRootFile     = "root.json"
+	bundleTexturesFile = "textures.json"
+	bundleTexturesDir  = "textures"
+)
+
+// BundleTexture describes a texture image stored inside a bundle.
+type BundleTexture struct {
+	Name     string `json:"name"`
+	File     string `json:"file"`
+	Emissive bool   `json:"emissive"`
+}
+
+// bundleWriter abstracts the destination of a bundle, either a zip archive or a plain directory.
+type bundleWriter interface {
+	Create(name string) (io.Writer, error)
+
+	Close() error
+}
+
+// zipBundleWriter writes bundle entries into a zip archive.
+type zipBundleWriter struct {
+	file *os.File
+	zw   *zip.Writer
+}
+
+// Create adds a new entry to the zip archive.
+func (w *zipBundleWriter) Create(name string) (io.Writer, error) {
+	return w.zw.Create(name)
+}
+
+// Close finalizes the zip archive and closes the underlying file.
+func (w *zipBundleWriter) Close() error {
+	if err := w.zw.Close(); err != nil {
+		_ = w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// dirBundleWriter writes bundle entries as files below a root directory.
+type dirBundleWriter struct {
+	root    string
+	current *os.File
+}
+
+// Create creates a new file below the root directory, closing the previously created one.
+func (w *dirBundleWriter) Create(name string) (io.Writer, error) {
+	if err := w.closeCurrent(); err != nil {
+		return nil, err
+	}
+	target := filepath.Join(w.root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(target)
+	if err != nil {
+		return nil, err
+	}
+	w.current = f
+	return f, nil
+}
+
+// Close closes the last created file.
+func (w *dirBundleWriter) Close() error {
+	return w.closeCurrent()
+}
+
+// closeCurrent closes the file currently open for writing, if any.
+func (w *dirBundleWriter) closeCurrent() error {
+	if w.current == nil {
+		return nil
+	}
+	err := w.current.Close()
+	w.current = nil
+	return err
+}
+
+// SaveBundle writes the Root as a self-contained bundle: the JSON IR plus every referenced texture as a PNG image.
+// A path ending in ".zip" produces a zip archive, any other path is created as a directory.
+func (cfg *Root) SaveBundle(path string) error {
+	var w bundleWriter
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		w = &zipBundleWriter{file: f, zw: zip.NewWriter(f)}
+	} else {
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			return err
+		}
+		w = &dirBundleWriter{root: path}
+	}
+	if err := cfg.writeBundle(w); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// writeBundle serializes the IR, the texture manifest and the texture images into the given writer.
+func (cfg *Root) writeBundle(w bundleWriter) error {
+	out, err := w.Create(bundleRootFile)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(out)
+	if err = enc.Encode(cfg); err != nil {
+		return err
+	}
+
+	var manifest []*BundleTexture
+	if cfg.textures != nil {
+		for idx, name := range cfg.collectFrames() {
+			texs := cfg.textures.Get([]string{name})
+			if len(texs) != 1 || texs[0] == nil {
+				fmt.Println("bundle: missing texture", name)
+				continue
+			}
+			bt := &BundleTexture{
+				Name:     name,
+				File:     fmt.Sprintf("%s/%05d.png", bundleTexturesDir, idx),
+				Emissive: texs[0].IsEmissive(),
+			}
+			img, err := w.Create(bt.File)
+			if err != nil {
+				return err
+			}
+			if err = png.Encode(img, textureToImage(texs[0])); err != nil {
+				return err
+			}
+			manifest = append(manifest, bt)
+		}
+	}
+	out, err = w.Create(bundleTexturesFile)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(out).Encode(manifest)
+}
+
+// collectFrames returns the sorted, unique list of texture frame names referenced by any material of the Root,
+// including the view models and the projectile templates of the weapons of the player, the drops of the things and
+// the templates of the spawners.
+func (cfg *Root) collectFrames() []string {
+	seen := make(map[string]bool)
+	add := func(m *Material) {
+		if m == nil {
+			return
+		}
+		for _, f := range m.Frames {
+			seen[f] = true
+		}
+	}
+	for _, s := range cfg.Sectors {
+		add(s.Ceil)
+		add(s.Floor)
+		for _, seg := range s.Segments {
+			add(seg.Upper)
+			add(seg.Middle)
+			add(seg.Lower)
+		}
+	}
+	for _, v := range cfg.Volumes {
+		for _, f := range v.Faces {
+			add(f.Material)
+		}
+	}
+	var addThing func(t *Thing)
+	addThing = func(t *Thing) {
+		if t == nil {
+			return
+		}
+		if t.Health != nil {
+			// Gli oggetti lasciati alla morte sono template completi
+			for _, drop := range t.Health.Drops {
+				addThing(drop)
+			}
+		}
+		if t.Sprite != nil {
+			add(t.Sprite.Material)
+		}
+		if t.MultiSprite != nil {
+			for _, m := range t.MultiSprite.Materials {
+				add(m)
+			}
+		}
+		if t.MD1 != nil {
+			for _, frame := range t.MD1.Frames {
+				for _, tri := range frame.Triangles {
+					add(tri.Material)
+				}
+			}
+		}
+	}
+	if cfg.Player != nil {
+		addThing(cfg.Player.Thing)
+		// Modelli in prima persona e proiettili dichiarati dalle armi
+		for _, w := range cfg.Player.Weapons {
+			if w == nil {
+				continue
+			}
+			if w.ViewModel != nil {
+				add(w.ViewModel.Idle)
+				add(w.ViewModel.Fire)
+			}
+			addThing(w.Projectile)
+		}
+	}
+	for _, t := range cfg.Things {
+		addThing(t)
+	}
+	for _, sp := range cfg.Spawners {
+		addThing(sp.Template)
+	}
+	out := make([]string, 0, len(seen))
+	for name := range seen {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// LoadBundle reads a bundle written by SaveBundle, either a zip archive or a directory, and returns its Root.
+func LoadBundle(path string) (*Root, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return readBundle(os.DirFS(path))
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return readBundle(zr)
+}
+
+// readBundle decodes the IR and the textures from the given file system.
+func readBundle(fsys fs.FS) (*Root, error) {
+	data, err := fs.ReadFile(fsys, bundleRootFile)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Root{}
+	if err = json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("bundle: invalid %s: %w", bundleRootFile, err)
+	}
+	data, err = fs.ReadFile(fsys, bundleTexturesFile)
+	if err != nil {
+		return nil, err
+	}
+	var manifest []*BundleTexture
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("bundle: invalid %s: %w", bundleTexturesFile, err)
+	}
+	tex := NewBundleTextures()
+	for idx, bt := range manifest {
+		f, err := fsys.Open(bt.File)
+		if err != nil {
+			return nil, err
+		}
+		img, err := png.Decode(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("bundle: invalid texture %s: %w", bt.File, err)
+		}
+		tex.Add(imageToTexture(bt.Name, uint32(idx), bt.Emissive, img))
+	}
+	cfg.SetTextures(tex)
+	return cfg, nil
+}
+
+// textureToImage converts a Texture into a non-premultiplied RGBA image.
+func textureToImage(t *textures.Texture) image.Image {
+	w, h := t.Size()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := t.Get(x, y)
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(c >> 24), G: uint8(c >> 16), B: uint8(c >> 8), A: uint8(c)})
+		}
+	}
+	return img
+}
+
+// imageToTexture converts a decoded image back into a Texture with the given name, id and emissive flag.
+func imageToTexture(name string, id uint32, emissive bool, img image.Image) *textures.Texture {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	tex := textures.NewTexture(name, id, w, h, emissive)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			tex.Set(x, y, int(c.R)<<24|int(c.G)<<16|int(c.B)<<8|int(c.A))
+		}
+	}
+	return tex
+}
+
+// BundleTextures is an in-memory texture collection restored from a bundle.
+type BundleTextures struct {
+	resources map[string]*textures.Texture
+}
+
+// NewBundleTextures creates an empty BundleTextures collection.
+func NewBundleTextures() *BundleTextures {
+	return &BundleTextures{resources: make(map[string]*textures.Texture)}
+}
+
+// Add registers a texture under its name.
+func (b *BundleTextures) Add(t *textures.Texture) {
+	b.resources[t.GetName()] = t
+}
+
+// Get retrieves textures matching the provided `ids`. Returns nil if an id is not found.
+func (b *BundleTextures) Get(ids []string) []*textures.Texture {
+	var out []*textures.Texture
+	for _, id := range ids {
+		x, ok := b.resources[id]
+		if !ok {
+			return nil
+		}
+		out = append(out, x)
+	}
+	return out
+}
+
+// GetNames returns a list of all texture names stored in the collection.
+func (b *BundleTextures) GetNames() []string {
+	var out []string
+	for id := range b.resources {
+		out = append(out, id)
+	}
+	return out
+}
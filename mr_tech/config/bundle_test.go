@@ -0,0 +1,120 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/geometry"
+	"github.com/markel1974/godoom/mr_tech/textures"
+)
+
+// newTestTexture creates a w x h texture whose texels encode their coordinates and a translucent alpha.
+func newTestTexture(name string, w, h int, emissive bool) *textures.Texture {
+	t := textures.NewTexture(name, 0, w, h, emissive)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			t.Set(x, y, x<<24|y<<16|(x+y)<<8|0x80)
+		}
+	}
+	return t
+}
+
+// bundleTestFrames are the texture frames referenced by the root of newTestBundleRoot.
+var bundleTestFrames = []string{"FLOOR", "LAMP", "PISTOL", "PISTOLF", "BALL", "MEDKIT", "IMP"}
+
+// newTestBundleRoot creates the root of newTestRoot with textures on the sector and on the view model and the
+// projectile template of a weapon, on the item dropped by the thing and on the template of a spawner, plus a texture
+// no material references.
+func newTestBundleRoot() (*Root, *BundleTextures) {
+	tex := NewBundleTextures()
+	for idx, name := range bundleTestFrames {
+		tex.Add(newTestTexture(name, 4-idx%2, 3, name == "LAMP"))
+	}
+	tex.Add(newTestTexture("UNUSED", 1, 1, false))
+	cfg := newTestRoot(tex)
+	material := func(name string) *Material {
+		return NewConfigMaterial([]string{name}, MaterialKindNone, 1, 1, 0, 0)
+	}
+	cfg.Sectors[0].Floor = material("FLOOR")
+	cfg.Sectors[0].Ceil = material("LAMP")
+	weapon := NewConfigWeapon("launcher", WeaponProjectile)
+	weapon.ViewModel = NewConfigWeaponViewModel(material("PISTOL"), material("PISTOLF"), 0.1)
+	weapon.Projectile = NewConfigThing("ball", cfg.Player.Position, 0, ThingThrowableDef, 1, 0.5, 0.5, 0)
+	weapon.Projectile.Behavior = NewConfigBehavior(testBehavior, nil)
+	weapon.Projectile.Sprite = NewConfigSprite(material("BALL"))
+	cfg.Player.Weapons = append(cfg.Player.Weapons, weapon)
+	drop := NewConfigThing("medkit", geometry.XYZ{}, 0, ThingItemDef, 1, 0.5, 0.5, 0)
+	drop.Behavior = NewConfigBehavior(testBehavior, nil)
+	drop.Sprite = NewConfigSprite(material("MEDKIT"))
+	cfg.Things[0].Health = NewConfigHealth(10)
+	cfg.Things[0].Health.Drops = []*Thing{drop}
+	template := NewConfigThing("imp", geometry.XYZ{}, 0, ThingEnemyDef, 10, 0.5, 1, 1)
+	template.Behavior = NewConfigBehavior(testBehavior, nil)
+	template.Sprite = NewConfigSprite(material("IMP"))
+	cfg.Spawners = append(cfg.Spawners, newTestSpawner(template))
+	return cfg, tex
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	for _, name := range []string{"level", "level.zip"} {
+		t.Run(name, func(t *testing.T) {
+			cfg, tex := newTestBundleRoot()
+			path := filepath.Join(t.TempDir(), name)
+			if err := cfg.SaveBundle(path); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := LoadBundle(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want, _ := json.Marshal(cfg)
+			got, _ := json.Marshal(loaded)
+			if !bytes.Equal(got, want) {
+				t.Fatalf("the IR changed in the round trip:\n%s\nwant\n%s", got, want)
+			}
+			if ds := loaded.Validate(); len(ds) != 0 {
+				t.Fatalf("the loaded root reported %d diagnostics, the first: %s", len(ds), ds[0])
+			}
+
+			// Solo le texture referenziate dai materiali entrano nel bundle
+			restored := loaded.textures.(*BundleTextures)
+			if names := restored.GetNames(); len(names) != len(bundleTestFrames) {
+				t.Fatalf("the bundle holds the textures %v, want %v", names, bundleTestFrames)
+			}
+			for _, id := range bundleTestFrames {
+				dst := restored.Get([]string{id})
+				if dst == nil {
+					t.Fatalf("texture %s is missing from the bundle", id)
+				}
+				src := tex.Get([]string{id})[0]
+				if dst[0].IsEmissive() != src.IsEmissive() {
+					t.Fatalf("texture %s: emissive %t, want %t", id, dst[0].IsEmissive(), src.IsEmissive())
+				}
+				w, h := src.Size()
+				if dw, dh := dst[0].Size(); dw != w || dh != h {
+					t.Fatalf("texture %s: size %dx%d, want %dx%d", id, dw, dh, w, h)
+				}
+				for y := 0; y < h; y++ {
+					for x := 0; x < w; x++ {
+						if dst[0].Get(x, y) != src.Get(x, y) {
+							t.Fatalf("texture %s: texel (%d, %d) is %08x, want %08x", id, x, y, dst[0].Get(x, y), src.Get(x, y))
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLoadBundleMissing(t *testing.T) {
+	if _, err := LoadBundle(filepath.Join(t.TempDir(), "missing.zip")); err == nil {
+		t.Fatal("a missing bundle has been loaded")
+	}
+	// Una cartella senza root.json non è un bundle
+	if _, err := LoadBundle(t.TempDir()); err == nil {
+		t.Fatal("an empty directory has been loaded as a bundle")
+	}
+}
@@ -0,0 +1,49 @@
+package config
+
+// CollisionLayer is a set of collision categories, one per bit. Two objects collide only when the layer of each one
+// is included in the mask of the other.
+type CollisionLayer uint32
+
+// LayerNone is the empty set: in a Thing or a Volume it selects the default layer or mask.
+// LayerWorld is the category of the level geometry.
+// LayerPlayer is the category of the player.
+// LayerMonster is the category of the enemies.
+// LayerItem is the category of the solid items and decorations.
+// LayerPickup is the category of the pickup items, crossed by the things that collect them.
+// LayerProjectile is the category of the throwables and of the hitscan rays.
+// LayerCorpse is the category of the corpses, that only rest on the level geometry.
+// LayerMonsterClip is the category of the volumes that, with a LayerMonster mask, block the enemies only.
+// LayerAll includes every category.
+const (
+	LayerNone  CollisionLayer = 0
+	LayerWorld CollisionLayer = 1 << (iota - 1)
+	LayerPlayer
+	LayerMonster
+	LayerItem
+	LayerPickup
+	LayerProjectile
+	LayerCorpse
+	LayerMonsterClip
+	LayerAll = ^LayerNone
+)
+
+// Collides reports whether an object with layer1 and mask1 collides with an object with layer2 and mask2.
+func Collides(layer1, mask1, layer2, mask2 CollisionLayer) bool {
+	return layer1&mask2 != 0 && layer2&mask1 != 0
+}
+
+// DefaultThingCollision returns the collision layer and mask of a thing of the given kind that does not declare them.
+// Pickup items are crossed by every thing and only rest on the level geometry.
+func DefaultThingCollision(kind ThingType, pickup bool) (CollisionLayer, CollisionLayer) {
+	switch {
+	case kind == ThingPlayerDef:
+		return LayerPlayer, LayerAll
+	case kind == ThingEnemyDef:
+		return LayerMonster, LayerAll
+	case kind == ThingThrowableDef || kind == ThingBulletDef:
+		return LayerProjectile, LayerAll
+	case pickup:
+		return LayerPickup, LayerWorld
+	}
+	return LayerItem, LayerAll
+}
@@ -0,0 +1,99 @@
+package config
+
+import "github.com/markel1974/godoom/mr_tech/geometry"
+
+// ElevatorKind identifies the sector property animated by an Elevator.
+type ElevatorKind int
+
+// ElevatorKindFloor animates the floor height of the sectors.
+// ElevatorKindCeil animates the ceiling height of the sectors.
+// ElevatorKindLight animates the light intensity of the sectors.
+const (
+	ElevatorKindFloor ElevatorKind = iota
+	ElevatorKindCeil
+	ElevatorKindLight
+)
+
+// ElevatorStopAction identifies what an Elevator does once it reaches a stop.
+type ElevatorStopAction int
+
+// ElevatorStopWait waits Wait seconds and then moves to the next stop.
+// ElevatorStopHold stays at the stop until the elevator receives a message.
+// ElevatorStopTerminate stays at the stop forever.
+// ElevatorStopComplete stays at the stop and completes the level.
+const (
+	ElevatorStopWait ElevatorStopAction = iota
+	ElevatorStopHold
+	ElevatorStopTerminate
+	ElevatorStopComplete
+)
+
+// ElevatorMessageTrigger moves a waiting or holding elevator to its next stop.
+// ElevatorMessageNextStop moves the elevator to its next stop.
+// ElevatorMessagePrevStop moves the elevator to its previous stop.
+// ElevatorMessageGotoStop moves the elevator to the stop given as parameter.
+// ElevatorMessageDone is sent by an elevator to notify that a stop has been reached; it has no effect on elevators.
+// ElevatorMessageComplete completes the level.
+const (
+	ElevatorMessageTrigger  = "m_trigger"
+	ElevatorMessageNextStop = "next_stop"
+	ElevatorMessagePrevStop = "prev_stop"
+	ElevatorMessageGotoStop = "goto_stop"
+	ElevatorMessageDone     = "done"
+	ElevatorMessageComplete = "complete"
+)
+
+// ElevatorMessage is a message sent to the Target elevator when a stop is reached.
+type ElevatorMessage struct {
+	Target  string `json:"target"`
+	Message string `json:"message"`
+	Param   int    `json:"param"`
+}
+
+// ElevatorStop is a position of an Elevator: a height (or a light intensity) and the action taken when reached.
+type ElevatorStop struct {
+	Value    float64            `json:"value"`
+	Action   ElevatorStopAction `json:"action"`
+	Wait     float64            `json:"wait"`
+	Messages []*ElevatorMessage `json:"messages"`
+}
+
+// Elevator describes a scripted animation of a group of sectors through an ordered, cyclic list of stops.
+type Elevator struct {
+	Id      string          `json:"id"`
+	Kind    ElevatorKind    `json:"kind"`
+	Sectors []string        `json:"sectors"`
+	Speed   float64         `json:"speed"`
+	Start   int             `json:"start"`
+	Stops   []*ElevatorStop `json:"stops"`
+}
+
+// NewConfigElevator creates an Elevator without stops animating the given sectors at speed units per second.
+func NewConfigElevator(id string, kind ElevatorKind, sectors []string, speed float64) *Elevator {
+	return &Elevator{
+		Id:      id,
+		Kind:    kind,
+		Sectors: sectors,
+		Speed:   speed,
+		Start:   0,
+		Stops:   nil,
+	}
+}
+
+// AddStop appends a stop to the Elevator and returns it.
+func (e *Elevator) AddStop(value float64, action ElevatorStopAction, wait float64) *ElevatorStop {
+	stop := &ElevatorStop{Value: value, Action: action, Wait: wait}
+	e.Stops = append(e.Stops, stop)
+	return stop
+}
+
+// Scale applies the vertical scale factor to the stops and speed of height elevators.
+func (e *Elevator) Scale(scale geometry.XYZ) {
+	if e.Kind == ElevatorKindLight {
+		return
+	}
+	e.Speed *= scale.Z
+	for _, stop := range e.Stops {
+		stop.Value *= scale.Z
+	}
+}
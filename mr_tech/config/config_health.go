@@ -0,0 +1,108 @@
+package config
+
+import "strings"
+
+// DamageType identifies the nature of the damage applied to a thing, used to select its resistance.
+type DamageType int
+
+// DamageGeneric is the damage of an impact without a known source.
+// DamageBullet is the damage of a hitscan weapon.
+// DamageProjectile is the damage of a thrown object or projectile.
+// DamageExplosion is the damage of an explosion.
+// DamageCrush is the damage of a crushing sector mover.
+// DamageMelee is the damage of a close range attack.
+// DamageFall is the damage of a fall.
+const (
+	DamageGeneric DamageType = iota
+	DamageBullet
+	DamageProjectile
+	DamageExplosion
+	DamageCrush
+	DamageMelee
+	DamageFall
+)
+
+// _damageIds maps the impact ids to their damage type.
+var _damageIds = map[string]DamageType{
+	"generic":    DamageGeneric,
+	"bullet":     DamageBullet,
+	"projectile": DamageProjectile,
+	"explosion":  DamageExplosion,
+	"crush":      DamageCrush,
+	"melee":      DamageMelee,
+	"fall":       DamageFall,
+}
+
+// ParseDamageType returns the damage type of an impact id (as "crush" or "explosion"), or DamageGeneric if unknown.
+func ParseDamageType(id string) DamageType {
+	if kind, ok := _damageIds[strings.ToLower(id)]; ok {
+		return kind
+	}
+	return DamageGeneric
+}
+
+// String returns the impact id of the damage type.
+func (d DamageType) String() string {
+	for id, kind := range _damageIds {
+		if kind == d {
+			return id
+		}
+	}
+	return "generic"
+}
+
+// Health describes the health model of a thing: its health and armor, how the impact force becomes damage, and what
+// happens on death. DeathAction is an MD1 action name or an action index; Drops are spawned at the thing position
+// when it dies. A dead thing is kept as a corpse (rendered but no longer simulated) when Corpse is set,
+// otherwise it is removed.
+type Health struct {
+	Health       float64                `json:"health"`
+	Armor        float64                `json:"armor"`
+	ArmorAbsorb  float64                `json:"armorAbsorb"`
+	DamageFactor float64                `json:"damageFactor"`
+	Resistances  map[DamageType]float64 `json:"resistances"`
+	DeathAction  string                 `json:"deathAction"`
+	Corpse       bool                   `json:"corpse"`
+	Drops        []*Thing               `json:"drops"`
+}
+
+// NewConfigHealth creates a Health with the given health, no armor and the default damage conversion.
+func NewConfigHealth(health float64) *Health {
+	return &Health{
+		Health:       health,
+		Armor:        0,
+		ArmorAbsorb:  1.0 / 3.0,
+		DamageFactor: 0.5,
+		Resistances:  nil,
+		DeathAction:  "death",
+		Corpse:       true,
+		Drops:        nil,
+	}
+}
+
+// GetResistance returns the damage multiplier of the given damage type: 0 means immune, 1 means no resistance.
+func (h *Health) GetResistance(kind DamageType) float64 {
+	if r, ok := h.Resistances[kind]; ok {
+		return r
+	}
+	return 1.0
+}
+
+// Clone returns a copy of the Health with copies of its resistances and drops.
+func (h *Health) Clone() *Health {
+	if h == nil {
+		return nil
+	}
+	out := *h
+	if h.Resistances != nil {
+		out.Resistances = make(map[DamageType]float64, len(h.Resistances))
+		for k, v := range h.Resistances {
+			out.Resistances[k] = v
+		}
+	}
+	out.Drops = make([]*Thing, 0, len(h.Drops))
+	for _, drop := range h.Drops {
+		out.Drops = append(out.Drops, drop.Clone())
+	}
+	return &out
+}
@@ -0,0 +1,20 @@
+package config
+
+// Inventory describes the starting inventory of the player: the ammo amounts, the maximum amount of each ammo type,
+// the owned weapons and keys. Ammo types missing from MaxAmmo have no maximum.
+type Inventory struct {
+	Ammo    map[string]float64 `json:"ammo"`
+	MaxAmmo map[string]float64 `json:"maxAmmo"`
+	Weapons []string           `json:"weapons"`
+	Keys    []string           `json:"keys"`
+}
+
+// NewConfigInventory creates an empty Inventory.
+func NewConfigInventory() *Inventory {
+	return &Inventory{
+		Ammo:    make(map[string]float64),
+		MaxAmmo: make(map[string]float64),
+		Weapons: nil,
+		Keys:    nil,
+	}
+}
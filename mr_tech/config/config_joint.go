@@ -0,0 +1,61 @@
+package config
+
+import "github.com/markel1974/godoom/mr_tech/geometry"
+
+// JointKind identifies the constraint a Joint enforces between two things, or between a thing and the world.
+type JointKind int
+
+// JointDistance keeps the anchors at a fixed distance, like a rigid rod (pendulums).
+// JointRope keeps the anchors no farther than Length and only pulls (ropes, chain links, swinging lamps).
+// JointSpring pulls the anchors towards Length with a damped spring of the given Stiffness and Damping.
+// JointHinge joins the anchors and lets the things turn only around Axis (hinged doors, trapdoors).
+// JointFixed joins the anchors and locks the relative rotation of the things.
+const (
+	JointDistance JointKind = iota
+	JointRope
+	JointSpring
+	JointHinge
+	JointFixed
+)
+
+// Joint describes a physics constraint between the thing ThingA and the thing ThingB or, when ThingB is empty, a
+// point of the world. AnchorA is the offset of the joint from the center of ThingA; AnchorB is the offset from the
+// center of ThingB or, without ThingB, the world position of the anchor. Length is the distance kept by distances and
+// ropes and the rest length of springs: zero uses the distance between the anchors when the level is loaded. Axis is
+// the world direction of a hinge.
+type Joint struct {
+	Id        string       `json:"id"`
+	Kind      JointKind    `json:"kind"`
+	ThingA    string       `json:"thingA"`
+	ThingB    string       `json:"thingB"`
+	AnchorA   geometry.XYZ `json:"anchorA"`
+	AnchorB   geometry.XYZ `json:"anchorB"`
+	Length    float64      `json:"length"`
+	Stiffness float64      `json:"stiffness"`
+	Damping   float64      `json:"damping"`
+	Axis      geometry.XYZ `json:"axis"`
+}
+
+// NewConfigJoint creates a Joint of the given kind between the things with ids a and b (empty for the world), whose
+// length is the distance between the anchors when the level is loaded. A hinge turns around the Z axis.
+func NewConfigJoint(id string, kind JointKind, a, b string, anchorA, anchorB geometry.XYZ) *Joint {
+	return &Joint{
+		Id:        id,
+		Kind:      kind,
+		ThingA:    a,
+		ThingB:    b,
+		AnchorA:   anchorA,
+		AnchorB:   anchorB,
+		Length:    0,
+		Stiffness: 0,
+		Damping:   0,
+		Axis:      geometry.XYZ{X: 0, Y: 0, Z: 1},
+	}
+}
+
+// Scale applies the scale factor to the anchors and to the length of the Joint.
+func (j *Joint) Scale(scale geometry.XYZ) {
+	j.AnchorA.Scale(scale)
+	j.AnchorB.Scale(scale)
+	j.Length *= scale.X
+}
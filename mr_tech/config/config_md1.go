@@ -8,15 +8,15 @@ import (
 
 // MD1Vertex represents a single vertex in an MD1 3D model with position and texture coordinates.
 type MD1Vertex struct {
-	Pos geometry.XYZ
-	U   float32
-	V   float32
+	Pos geometry.XYZ `json:"pos"`
+	U   float32      `json:"u"`
+	V   float32      `json:"v"`
 }
 
 // MD1Triangle represents a triangular mesh with 3 vertices and an associated material.
 type MD1Triangle struct {
-	Vertices [3]MD1Vertex
-	Material *Material
+	Vertices [3]MD1Vertex `json:"vertices"`
+	Material *Material    `json:"material"`
 }
 
 // NewMD1Triangle creates a new MD1Triangle with the specified material and initializes its vertices to default values.
@@ -29,7 +29,7 @@ func NewMD1Triangle(material *Material) MD1Triangle {
 
 // MD1Frame represents a collection of triangles that define a single frame in an MD1 animation sequence.
 type MD1Frame struct {
-	Triangles []MD1Triangle
+	Triangles []MD1Triangle `json:"triangles"`
 }
 
 // NewMD1Frame creates a new MD1Frame with the specified list of MD1Triangle structures.
@@ -41,9 +41,9 @@ func NewMD1Frame(triangles []MD1Triangle) MD1Frame {
 
 // MD1 represents a structure holding animation frames, action definitions, and corresponding action intervals.
 type MD1 struct {
-	Frames            []MD1Frame
-	ActionDefinitions []string
-	ActionIntervals   [][2]int
+	Frames            []MD1Frame `json:"frames"`
+	ActionDefinitions []string   `json:"actionDefinitions"`
+	ActionIntervals   [][2]int   `json:"actionIntervals"`
 }
 
 // NewMD1 creates a new MD1 instance with the specified number of frames and initializes it using the provided frame names.
@@ -0,0 +1,64 @@
+package config
+
+import "github.com/markel1974/godoom/mr_tech/geometry"
+
+// MoverKind identifies the plane of a sector animated by a Mover.
+type MoverKind int
+
+// MoverKindFloor animates the floor height of the sector (lifts, platforms).
+// MoverKindCeil animates the ceiling height of the sector (doors, crushers).
+const (
+	MoverKindFloor MoverKind = iota
+	MoverKindCeil
+)
+
+// MoverTrigger identifies how a Mover is activated.
+type MoverTrigger int
+
+// MoverTriggerNone means the mover is activated only by scripts or specials.
+// MoverTriggerProximity activates the mover when the player or an enemy gets within Radius of the sector.
+const (
+	MoverTriggerNone MoverTrigger = iota
+	MoverTriggerProximity
+)
+
+// Mover describes a runtime animation of a sector floor or ceiling between a rest height and a target height.
+type Mover struct {
+	Id          string       `json:"id"`
+	Sector      string       `json:"sector"`
+	Kind        MoverKind    `json:"kind"`
+	Start       float64      `json:"start"`
+	End         float64      `json:"end"`
+	Speed       float64      `json:"speed"`
+	Wait        float64      `json:"wait"`
+	Crush       bool         `json:"crush"`
+	CrushDamage float64      `json:"crushDamage"`
+	Trigger     MoverTrigger `json:"trigger"`
+	Radius      float64      `json:"radius"`
+}
+
+// NewConfigMover creates a Mover for the given sector moving its plane from start (rest) to end at speed units per second.
+// By default the mover waits 3 seconds at the end position and then returns to start.
+func NewConfigMover(id string, sector string, kind MoverKind, start, end, speed float64) *Mover {
+	return &Mover{
+		Id:          id,
+		Sector:      sector,
+		Kind:        kind,
+		Start:       start,
+		End:         end,
+		Speed:       speed,
+		Wait:        3.0,
+		Crush:       false,
+		CrushDamage: 10.0,
+		Trigger:     MoverTriggerNone,
+		Radius:      0,
+	}
+}
+
+// Scale applies the scale factor to the heights, speed and trigger radius of the Mover.
+func (m *Mover) Scale(scale geometry.XYZ) {
+	m.Start *= scale.Z
+	m.End *= scale.Z
+	m.Speed *= scale.Z
+	m.Radius *= scale.X
+}
@@ -2,7 +2,7 @@ package config
 
 // MultiSprite represents a collection of materials used for multi-frame animations or visual compositions.
 type MultiSprite struct {
-	Materials []*Material
+	Materials []*Material `json:"materials"`
 }
 
 // NewMultiSprite creates and returns a new instance of MultiSprite with an initialized empty slice of Materials.
@@ -0,0 +1,44 @@
+package config
+
+// PickupKind identifies what a Pickup gives to the player.
+type PickupKind int
+
+// PickupHealth adds Amount to the health of the player, up to Limit.
+// PickupArmor adds Amount to the armor of the player, up to Limit.
+// PickupAmmo adds Amount of the ammo Id to the inventory, up to Limit or the inventory maximum.
+// PickupWeapon adds the weapon Id to the inventory.
+// PickupKey adds the key Id (as "blue" or "red") to the inventory.
+const (
+	PickupHealth PickupKind = iota
+	PickupArmor
+	PickupAmmo
+	PickupWeapon
+	PickupKey
+)
+
+// Pickup describes one of the things a pickup item gives to the player when touched. A Limit of zero means no limit.
+type Pickup struct {
+	Kind   PickupKind `json:"kind"`
+	Id     string     `json:"id"`
+	Amount float64    `json:"amount"`
+	Limit  float64    `json:"limit"`
+}
+
+// NewConfigPickup creates a Pickup of the given kind, id, amount and limit.
+func NewConfigPickup(kind PickupKind, id string, amount, limit float64) *Pickup {
+	return &Pickup{
+		Kind:   kind,
+		Id:     id,
+		Amount: amount,
+		Limit:  limit,
+	}
+}
+
+// Clone returns a copy of the Pickup.
+func (p *Pickup) Clone() *Pickup {
+	if p == nil {
+		return nil
+	}
+	out := *p
+	return &out
+}
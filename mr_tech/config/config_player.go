@@ -39,18 +39,31 @@ type Flash struct {
 // Player represents a specialized game entity with inherited attributes and behaviors from the Thing type.
 type Player struct {
 	*Thing
-	Bobbing *Bobbing `json:"bobbing"`
-	Flash   *Flash   `json:"flash"`
+	Bobbing   *Bobbing   `json:"bobbing"`
+	Flash     *Flash     `json:"flash"`
+	Inventory *Inventory `json:"inventory"`
+	Weapons   []*Weapon  `json:"weapons"`
 }
 
 // NewConfigPlayer creates and returns a new Player instance configured with the given position, angle, height, radius, and mass.
+// The player owns the default arsenal, a hitscan gun; SetProjectile adds a throw weapon launching copies of a level
+// thing.
+// A killed player is kept in the world as a corpse, so that the camera and the queries still find it.
 func NewConfigPlayer(position geometry.XYZ, angle float64, mass, speed, radius, height float64) *Player {
 	thing := NewConfigThing("PLAYER", position, angle, -1, mass, radius, height, speed)
+	thing.Health = NewConfigHealth(100)
+	thing.Health.DeathAction = ""
+	thing.Health.Corpse = true
 	p := &Player{
-		Thing:   thing,
-		Bobbing: &Bobbing{},
-		Flash:   &Flash{},
+		Thing:     thing,
+		Bobbing:   &Bobbing{},
+		Flash:     &Flash{},
+		Inventory: NewConfigInventory(),
+		Weapons:   nil,
 	}
+	gun := NewConfigWeapon("gun", WeaponHitscan)
+	p.Weapons = []*Weapon{gun}
+	p.Inventory.Weapons = []string{gun.Id}
 	p.Flash.FovDeg = 80.0
 	p.Flash.ZNear = 0.1
 	p.Flash.ZFar = 2048.0
@@ -80,3 +93,22 @@ func NewConfigPlayer(position geometry.XYZ, angle float64, mass, speed, radius,
 
 	return p
 }
+
+// SetProjectile makes the projectile weapons without a template of their own launch copies of the level thing with
+// the given id. When there is no such weapon, an owned throw weapon is added.
+func (p *Player) SetProjectile(id string) {
+	found := false
+	for _, w := range p.Weapons {
+		if w != nil && w.Kind == WeaponProjectile && w.Projectile == nil {
+			w.ProjectileId = id
+			found = true
+		}
+	}
+	if found {
+		return
+	}
+	throw := NewConfigWeapon("throw", WeaponProjectile)
+	throw.ProjectileId = id
+	p.Weapons = append(p.Weapons, throw)
+	p.Inventory.Weapons = append(p.Inventory.Weapons, throw.Id)
+}
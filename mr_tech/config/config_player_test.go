@@ -0,0 +1,33 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+func TestPlayerSetProjectile(t *testing.T) {
+	cfg := newTestRoot(NewBundleTextures())
+	cfg.Player = NewConfigPlayer(geometry.XYZ{X: 2, Y: 2}, 0, 10, 10, 1, 2)
+	cfg.Player.Behavior = NewConfigBehavior(testBehavior, nil)
+	// Senza un template il giocatore non ha un'arma da lancio da segnalare
+	if ds := cfg.Validate(); len(ds) != 0 {
+		t.Fatalf("a player without projectile reported %d diagnostics, the first: %s", len(ds), ds[0])
+	}
+	if len(cfg.Player.Weapons) != 1 || cfg.Player.Weapons[0].Kind != WeaponHitscan {
+		t.Fatalf("the default arsenal has %d weapons, want the gun only", len(cfg.Player.Weapons))
+	}
+
+	cfg.Player.SetProjectile("barrel")
+	cfg.Player.SetProjectile("barrel")
+	if len(cfg.Player.Weapons) != 2 || cfg.Player.Weapons[1].ProjectileId != "barrel" {
+		t.Fatalf("the throw weapon has not been added once: %d weapons", len(cfg.Player.Weapons))
+	}
+	if !reflect.DeepEqual(cfg.Player.Inventory.Weapons, []string{"gun", "throw"}) {
+		t.Fatalf("owned weapons %v, want [gun throw]", cfg.Player.Inventory.Weapons)
+	}
+	if ds := cfg.Validate(); len(ds) != 0 {
+		t.Fatalf("a player throwing a level thing reported %d diagnostics, the first: %s", len(ds), ds[0])
+	}
+}
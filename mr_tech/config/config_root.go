@@ -7,17 +7,26 @@ import (
 )
 
 // Root represents the top-level configuration container, including sectors, things, player, and rendering properties.
+// Seed initializes the random generators of the behaviors: the same seed and the same input replay the same level.
 type Root struct {
-	Id          string           `json:"id"`
-	Calibration *Calibration     `json:"calibration"`
-	Sectors     []*Sector        `json:"sectors"`
-	Things      []*Thing         `json:"things"`
-	Player      *Player          `json:"player"`
-	ScaleFactor geometry.XYZ     `json:"scaleFactor"`
-	Vertices    geometry.Polygon `json:"vertices"`
-	Volumes     []*Volume        `json:"volumes"`
-	Lights      []*Light         `json:"lights"`
-	textures    textures.ITextures
+	Id             string           `json:"id"`
+	Calibration    *Calibration     `json:"calibration"`
+	Sectors        []*Sector        `json:"sectors"`
+	Things         []*Thing         `json:"things"`
+	Player         *Player          `json:"player"`
+	ScaleFactor    geometry.XYZ     `json:"scaleFactor"`
+	Vertices       geometry.Polygon `json:"vertices"`
+	Volumes        []*Volume        `json:"volumes"`
+	Lights         []*Light         `json:"lights"`
+	Movers         []*Mover         `json:"movers"`
+	Triggers       []*Trigger       `json:"triggers"`
+	TriggerVolumes []*TriggerVolume `json:"triggerVolumes"`
+	Elevators      []*Elevator      `json:"elevators"`
+	Joints         []*Joint         `json:"joints"`
+	Spawners       []*Spawner       `json:"spawners"`
+	Seed           int64            `json:"seed"`
+	textures       textures.ITextures
+	reported       Diagnostics
 }
 
 // NewConfigRoot creates and initializes a new Root object with the specified sectors, player, things, and configuration.
@@ -38,6 +47,17 @@ func (cfg *Root) GetTextures() textures.ITextures {
 	return cfg.textures
 }
 
+// SetTextures replaces the texture collection associated with the Root configuration.
+func (cfg *Root) SetTextures(t textures.ITextures) {
+	cfg.textures = t
+}
+
+// Report records the diagnostics found by a builder while reading the level source, so that Validate returns them
+// with the ones of the IR.
+func (cfg *Root) Report(ds ...*Diagnostic) {
+	cfg.reported = append(cfg.reported, ds...)
+}
+
 // Scale adjusts the dimensions of all entities in the Root object by the specified scale factor. If scale is 0, defaults to 1.
 func (cfg *Root) Scale(scale geometry.XYZ) {
 	if scale.X == 1 && scale.Y == 1 && scale.Z == 1 {
@@ -62,4 +82,22 @@ func (cfg *Root) Scale(scale geometry.XYZ) {
 	for _, light := range cfg.Lights {
 		light.Pos.Scale(scale)
 	}
+	for _, mover := range cfg.Movers {
+		mover.Scale(scale)
+	}
+	for _, trigger := range cfg.Triggers {
+		trigger.Scale(scale)
+	}
+	for _, tv := range cfg.TriggerVolumes {
+		tv.Scale(scale)
+	}
+	for _, elevator := range cfg.Elevators {
+		elevator.Scale(scale)
+	}
+	for _, joint := range cfg.Joints {
+		joint.Scale(scale)
+	}
+	for _, spawner := range cfg.Spawners {
+		spawner.Scale(scale)
+	}
 }
@@ -0,0 +1,68 @@
+package config
+
+import "github.com/markel1974/godoom/mr_tech/geometry"
+
+// SpawnerKind identifies how a Spawner places its things.
+type SpawnerKind int
+
+// SpawnerWaves spawns the Waves in order: the things of a wave appear one every Interval seconds, cycling through the
+// points, and the next wave starts Delay seconds after every thing of the previous one is killed or taken.
+// SpawnerRespawn keeps a thing at each point: when it is killed or taken, a new one appears Delay seconds later, as
+// the items of a deathmatch do.
+const (
+	SpawnerWaves SpawnerKind = iota
+	SpawnerRespawn
+)
+
+// SpawnPoint is a position where a Spawner places its things, facing Angle.
+type SpawnPoint struct {
+	Position geometry.XYZ `json:"position"`
+	Angle    float64      `json:"angle"`
+}
+
+// SpawnWave describes a wave of a SpawnerWaves: Count things, spawned one every Interval seconds, starting Delay
+// seconds after the previous wave is cleared or, for the first wave, after the spawner is started.
+type SpawnWave struct {
+	Count    int     `json:"count"`
+	Delay    float64 `json:"delay"`
+	Interval float64 `json:"interval"`
+}
+
+// Spawner describes a source of things created at runtime from Template, an enemy, an item or an effect, placed at
+// Points. Waves are the waves of a SpawnerWaves; Delay is the respawn time of a SpawnerRespawn. MaxAlive caps the
+// things of the spawner alive at the same time, zero leaves them uncapped. Active starts the spawner with the level;
+// otherwise it waits for a script to start it.
+type Spawner struct {
+	Id       string       `json:"id"`
+	Kind     SpawnerKind  `json:"kind"`
+	Template *Thing       `json:"template"`
+	Points   []SpawnPoint `json:"points"`
+	Waves    []SpawnWave  `json:"waves"`
+	Delay    float64      `json:"delay"`
+	MaxAlive int          `json:"maxAlive"`
+	Active   bool         `json:"active"`
+}
+
+// NewConfigSpawner creates an active Spawner of the given kind placing copies of template at points.
+func NewConfigSpawner(id string, kind SpawnerKind, template *Thing, points []SpawnPoint) *Spawner {
+	return &Spawner{
+		Id:       id,
+		Kind:     kind,
+		Template: template,
+		Points:   points,
+		Waves:    nil,
+		Delay:    0,
+		MaxAlive: 0,
+		Active:   true,
+	}
+}
+
+// Scale applies the scale factor to the template and to the points of the Spawner.
+func (s *Spawner) Scale(scale geometry.XYZ) {
+	if s.Template != nil {
+		s.Template.Scale(scale)
+	}
+	for idx := range s.Points {
+		s.Points[idx].Position.Scale(scale)
+	}
+}
@@ -2,7 +2,7 @@ package config
 
 // Sprite represents a 2D object that is rendered using a Material for visual and animation properties.
 type Sprite struct {
-	Material *Material
+	Material *Material `json:"material"`
 }
 
 // NewConfigSprite creates and returns a new Sprite instance with the specified Material.
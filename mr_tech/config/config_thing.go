@@ -27,29 +27,40 @@ const (
 )
 
 // Thing represents a game entity with physical, visual, and behavior attributes in a simulation environment.
+// Tumble gives a thing the rotational dynamics of a solid box, so that it spins on impacts and settles on a face, and
+// makes it turn around the axis of its hinge joints.
+// Continuous sweeps each step of a fast throwable against the level geometry, so that it cannot pass through thin walls.
+// Layer and Mask are the collision category of the thing and the categories it collides with; LayerNone selects the
+// defaults of the kind (see DefaultThingCollision).
+// Respawn is the time, in seconds, after which the thing reappears at its position once killed or taken, as the items
+// of a deathmatch do; zero never respawns it.
 type Thing struct {
-	Id             string       `json:"id"`
-	Position       geometry.XYZ `json:"position"`
-	Kind           ThingType    `json:"kind"`
-	Angle          float64      `json:"angle"`
-	Mass           float64      `json:"mass"`
-	Restitution    float64      `json:"restitution"`
-	Friction       float64      `json:"friction"`
-	Radius         float64      `json:"radius"`
-	Height         float64      `json:"height"`
-	Speed          float64      `json:"speed"`
-	Acceleration   float64      `json:"acceleration"`
-	JumpForce      float64      `json:"jumpForce"`
-	Pitch          float64      `json:"pitch"`
-	WakeUpDistance float64      `json:"wakeUpDistance"`
-	GForce         float64      `json:"gForce"`
-	MD1            *MD1         `json:"md1"`
-	MultiSprite    *MultiSprite `json:"multiSprite"`
-	Sprite         *Sprite      `json:"sprite"`
-
-	OnThinking  ThinkingFunc
-	OnCollision CollisionFunc
-	OnImpact    ImpactFunc
+	Id             string         `json:"id"`
+	Position       geometry.XYZ   `json:"position"`
+	Kind           ThingType      `json:"kind"`
+	Angle          float64        `json:"angle"`
+	Mass           float64        `json:"mass"`
+	Restitution    float64        `json:"restitution"`
+	Friction       float64        `json:"friction"`
+	Radius         float64        `json:"radius"`
+	Height         float64        `json:"height"`
+	Speed          float64        `json:"speed"`
+	Acceleration   float64        `json:"acceleration"`
+	JumpForce      float64        `json:"jumpForce"`
+	Pitch          float64        `json:"pitch"`
+	WakeUpDistance float64        `json:"wakeUpDistance"`
+	GForce         float64        `json:"gForce"`
+	MD1            *MD1           `json:"md1"`
+	MultiSprite    *MultiSprite   `json:"multiSprite"`
+	Sprite         *Sprite        `json:"sprite"`
+	Behavior       *Behavior      `json:"behavior"`
+	Health         *Health        `json:"health"`
+	Pickups        []*Pickup      `json:"pickups"`
+	Tumble         bool           `json:"tumble"`
+	Continuous     bool           `json:"continuous"`
+	Layer          CollisionLayer `json:"layer"`
+	Mask           CollisionLayer `json:"mask"`
+	Respawn        float64        `json:"respawn"`
 }
 
 // NewConfigThing creates and returns a new Thing instance with the specified ID, position, angle, type, and physical attributes.
@@ -68,7 +79,21 @@ func NewConfigThing(id string, pos geometry.XYZ, angle float64, kind ThingType,
 		JumpForce:      600,
 		Friction:       0.2,
 		GForce:         9.8,
+		Health:         defaultConfigHealth(kind),
+		Tumble:         false,
+		Continuous:     false,
+		Layer:          LayerNone,
+		Mask:           LayerNone,
+		Respawn:        0,
+	}
+}
+
+// defaultConfigHealth returns the default Health of a thing kind: enemies are damageable, the other things are not.
+func defaultConfigHealth(kind ThingType) *Health {
+	if kind == ThingEnemyDef {
+		return NewConfigHealth(100)
 	}
+	return nil
 }
 
 // Scale applies the given scaling factors to the Position of the Thing by modifying its X, Y, and Z coordinates.
@@ -97,8 +122,43 @@ func (t *Thing) Clone() *Thing {
 		MD1:            t.MD1,
 		MultiSprite:    t.MultiSprite,
 		Sprite:         t.Sprite,
-		OnThinking:     t.OnThinking,
-		OnCollision:    t.OnCollision,
-		OnImpact:       t.OnImpact,
+		Behavior:       t.Behavior.Clone(),
+		Health:         t.Health.Clone(),
+		Pickups:        clonePickups(t.Pickups),
+		Tumble:         t.Tumble,
+		Continuous:     t.Continuous,
+		Layer:          t.Layer,
+		Mask:           t.Mask,
+		Respawn:        t.Respawn,
+	}
+}
+
+// IsPickup reports whether the Thing gives something to the player when touched.
+func (t *Thing) IsPickup() bool {
+	return len(t.Pickups) > 0
+}
+
+// GetCollision returns the collision layer and mask of the Thing, replacing the undeclared ones with the defaults of
+// its kind.
+func (t *Thing) GetCollision() (CollisionLayer, CollisionLayer) {
+	layer, mask := DefaultThingCollision(t.Kind, t.IsPickup())
+	if t.Layer != LayerNone {
+		layer = t.Layer
+	}
+	if t.Mask != LayerNone {
+		mask = t.Mask
+	}
+	return layer, mask
+}
+
+// clonePickups returns a copy of the pickups.
+func clonePickups(pickups []*Pickup) []*Pickup {
+	if pickups == nil {
+		return nil
+	}
+	out := make([]*Pickup, 0, len(pickups))
+	for _, p := range pickups {
+		out = append(out, p.Clone())
 	}
+	return out
 }
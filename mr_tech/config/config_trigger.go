@@ -0,0 +1,88 @@
+package config
+
+import "github.com/markel1974/godoom/mr_tech/geometry"
+
+// TriggerActivation identifies how the player activates a Trigger line.
+type TriggerActivation int
+
+// TriggerCross fires when the player walks across the line.
+// TriggerUse fires when the player presses use while facing the line within reach.
+// TriggerShoot fires when a player hitscan strikes a wall lying on the line before anything else.
+// TriggerEnter fires when the player enters the trigger Sector; the line is ignored.
+// TriggerScript fires only when a TriggerVolume or a script references the trigger; the line is ignored.
+const (
+	TriggerCross TriggerActivation = iota
+	TriggerUse
+	TriggerShoot
+	TriggerEnter
+	TriggerScript
+)
+
+// TriggerAction identifies the effect of a Trigger.
+type TriggerAction int
+
+// TriggerActionActivate activates the referenced movers.
+// TriggerActionToggle activates idle movers and sends back the open ones (manual doors).
+// TriggerActionLight sets the light intensity of the referenced sectors.
+// TriggerActionExit ends the level.
+// TriggerActionSecretExit ends the level through the secret exit.
+// TriggerActionMessage sends Message (with Param) to the referenced elevators.
+const (
+	TriggerActionActivate TriggerAction = iota
+	TriggerActionToggle
+	TriggerActionLight
+	TriggerActionExit
+	TriggerActionSecretExit
+	TriggerActionMessage
+)
+
+// Trigger describes a line special: a 2D line (or a sector) that, when activated, fires movers, sends messages to
+// elevators, changes lights or ends the level. Switch is the tag of the wall segments whose texture advances to the
+// next frame at each activation. Key is the inventory key the player needs to activate the trigger, if any.
+type Trigger struct {
+	Id         string            `json:"id"`
+	Start      geometry.XY       `json:"start"`
+	End        geometry.XY       `json:"end"`
+	Sector     string            `json:"sector"`
+	Activation TriggerActivation `json:"activation"`
+	Repeat     bool              `json:"repeat"`
+	Tag        string            `json:"tag"`
+	Action     TriggerAction     `json:"action"`
+	Movers     []string          `json:"movers"`
+	Elevators  []string          `json:"elevators"`
+	Message    string            `json:"message"`
+	Param      int               `json:"param"`
+	Sectors    []string          `json:"sectors"`
+	Light      float64           `json:"light"`
+	Switch     string            `json:"switch"`
+	Key        string            `json:"key"`
+}
+
+// NewConfigTrigger creates a single-shot Trigger on the line from start to end with the given activation and action.
+func NewConfigTrigger(id string, start, end geometry.XY, activation TriggerActivation, action TriggerAction) *Trigger {
+	return &Trigger{
+		Id:         id,
+		Start:      start,
+		End:        end,
+		Sector:     "",
+		Activation: activation,
+		Repeat:     false,
+		Tag:        "",
+		Action:     action,
+		Movers:     nil,
+		Elevators:  nil,
+		Message:    "",
+		Param:      0,
+		Sectors:    nil,
+		Light:      0,
+		Switch:     "",
+		Key:        "",
+	}
+}
+
+// Scale applies the scale factor to the line of the Trigger.
+func (t *Trigger) Scale(scale geometry.XYZ) {
+	xy := geometry.XY{X: scale.X, Y: scale.Y}
+	t.Start.Scale(xy)
+	t.End.Scale(xy)
+}
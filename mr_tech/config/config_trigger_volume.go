@@ -0,0 +1,73 @@
+package config
+
+import "github.com/markel1974/godoom/mr_tech/geometry"
+
+// TriggerVolumeAction identifies the built-in effect of a TriggerVolume when a thing enters it.
+type TriggerVolumeAction int
+
+// TriggerVolumeEvents only delivers the enter, stay and exit events and fires the referenced triggers.
+// TriggerVolumeTeleport moves the entering thing to Destination, facing Angle.
+// TriggerVolumeSecret marks a secret area as found.
+// TriggerVolumeExit ends the level.
+// TriggerVolumeSecretExit ends the level through the secret exit.
+const (
+	TriggerVolumeEvents TriggerVolumeAction = iota
+	TriggerVolumeTeleport
+	TriggerVolumeSecret
+	TriggerVolumeExit
+	TriggerVolumeSecretExit
+)
+
+// TriggerVolume describes a non-solid region of the world that notifies the things entering, staying in and leaving
+// it, without any physical response. The region is the box from Min to Max or, when Sector is set, the sector with
+// that id at any height. Enter and Exit are the ids of the triggers fired when a thing enters or leaves the region.
+// PlayerOnly ignores every thing but the player, Once applies the action and fires the Enter triggers only the first
+// time and Tag groups the volumes addressed together by scripts. Secrets and exits react to the player only.
+type TriggerVolume struct {
+	Id          string              `json:"id"`
+	Min         geometry.XYZ        `json:"min"`
+	Max         geometry.XYZ        `json:"max"`
+	Sector      string              `json:"sector"`
+	Action      TriggerVolumeAction `json:"action"`
+	Destination geometry.XYZ        `json:"destination"`
+	Angle       float64             `json:"angle"`
+	PlayerOnly  bool                `json:"playerOnly"`
+	Once        bool                `json:"once"`
+	Tag         string              `json:"tag"`
+	Enter       []string            `json:"enter"`
+	Exit        []string            `json:"exit"`
+	Message     string              `json:"message"`
+}
+
+// NewConfigTriggerVolume creates a repeatable TriggerVolume covering the box from min to max with the given action.
+func NewConfigTriggerVolume(id string, min, max geometry.XYZ, action TriggerVolumeAction) *TriggerVolume {
+	return &TriggerVolume{
+		Id:          id,
+		Min:         min,
+		Max:         max,
+		Sector:      "",
+		Action:      action,
+		Destination: geometry.XYZ{},
+		Angle:       0,
+		PlayerOnly:  false,
+		Once:        false,
+		Tag:         "",
+		Enter:       nil,
+		Exit:        nil,
+		Message:     "",
+	}
+}
+
+// NewConfigSectorTriggerVolume creates a repeatable TriggerVolume covering the sector with the given id.
+func NewConfigSectorTriggerVolume(id string, sector string, action TriggerVolumeAction) *TriggerVolume {
+	t := NewConfigTriggerVolume(id, geometry.XYZ{}, geometry.XYZ{}, action)
+	t.Sector = sector
+	return t
+}
+
+// Scale applies the scale factor to the box and to the teleport destination of the TriggerVolume.
+func (t *TriggerVolume) Scale(scale geometry.XYZ) {
+	t.Min.Scale(scale)
+	t.Max.Scale(scale)
+	t.Destination.Scale(scale)
+}
@@ -3,10 +3,14 @@ package config
 import "github.com/markel1974/godoom/mr_tech/geometry"
 
 // Volume represents a 3D volume in a configuration, containing faces, lighting information, and a unique identifier.
+// Layer and Mask are the collision category of the volume and the categories it blocks; LayerNone selects LayerWorld
+// and LayerAll.
 type Volume struct {
-	Id    string  `json:"id"`
-	Faces []*Face `json:"faces"`
-	Tag   string  `json:"tag"`
+	Id    string         `json:"id"`
+	Faces []*Face        `json:"faces"`
+	Tag   string         `json:"tag"`
+	Layer CollisionLayer `json:"layer"`
+	Mask  CollisionLayer `json:"mask"`
 }
 
 // NewConfigVolume creates and returns a new instance of Volume with specified ID, light settings, and tag.
@@ -15,6 +19,8 @@ func NewConfigVolume(id string, tag string) *Volume {
 		Id:    id,
 		Faces: make([]*Face, 0),
 		Tag:   tag,
+		Layer: LayerNone,
+		Mask:  LayerNone,
 	}
 }
 
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// WeaponKind identifies how a weapon delivers its damage.
+type WeaponKind int
+
+// WeaponHitscan traces a ray for each pellet and hits the first thing along it.
+// WeaponProjectile launches a copy of the projectile template for each pellet.
+// WeaponMelee hits the first thing within Range in front of the player.
+const (
+	WeaponHitscan WeaponKind = iota
+	WeaponProjectile
+	WeaponMelee
+)
+
+// WeaponViewModel describes the first person animation of a weapon: the idle material, the material played when
+// firing and the duration in seconds of each fire frame.
+type WeaponViewModel struct {
+	Idle      *Material `json:"idle"`
+	Fire      *Material `json:"fire"`
+	FrameTime float64   `json:"frameTime"`
+}
+
+// NewConfigWeaponViewModel creates a WeaponViewModel with the given idle and fire materials and frame duration.
+func NewConfigWeaponViewModel(idle *Material, fire *Material, frameTime float64) *WeaponViewModel {
+	return &WeaponViewModel{
+		Idle:      idle,
+		Fire:      fire,
+		FrameTime: frameTime,
+	}
+}
+
+// Weapon describes a weapon of the player.
+// FireRate is the number of shots per second; each shot fires Pellets rays or projectiles, deviated randomly by up
+// to Spread radians, and uses AmmoPerShot units of the Ammo type (no ammo is used when Ammo is empty).
+// Damage is the impact force of each pellet, converted into damage by the health model of the target, with the
+// DamageType impact id; Force is the knockback applied to the target.
+// Range is the maximum distance of a hitscan or melee hit, in world units.
+// Projectile is the template of the launched things, thrown at ProjectileSpeed world units per second; when nil,
+// copies of the level thing with id ProjectileId are launched instead.
+// Muzzle is the spawn point relative to the eye of the player: X forward and Y right in player widths, Z up as a
+// fraction of the eye height.
+type Weapon struct {
+	Id              string           `json:"id"`
+	Kind            WeaponKind       `json:"kind"`
+	FireRate        float64          `json:"fireRate"`
+	Spread          float64          `json:"spread"`
+	Pellets         int              `json:"pellets"`
+	Damage          float64          `json:"damage"`
+	DamageType      string           `json:"damageType"`
+	Force           float64          `json:"force"`
+	Range           float64          `json:"range"`
+	Ammo            string           `json:"ammo"`
+	AmmoPerShot     float64          `json:"ammoPerShot"`
+	Projectile      *Thing           `json:"projectile"`
+	ProjectileId    string           `json:"projectileId"`
+	ProjectileSpeed float64          `json:"projectileSpeed"`
+	Muzzle          geometry.XYZ     `json:"muzzle"`
+	ViewModel       *WeaponViewModel `json:"viewModel"`
+}
+
+// NewConfigWeapon creates a single pellet Weapon of the given kind that uses no ammo, with the default rate, damage,
+// range and muzzle.
+func NewConfigWeapon(id string, kind WeaponKind) *Weapon {
+	damageType := DamageBullet
+	weaponRange := 4096.0
+	switch kind {
+	case WeaponProjectile:
+		damageType = DamageProjectile
+	case WeaponMelee:
+		damageType = DamageMelee
+		weaponRange = 64.0
+	}
+	return &Weapon{
+		Id:              id,
+		Kind:            kind,
+		FireRate:        2.0,
+		Spread:          0,
+		Pellets:         1,
+		Damage:          20,
+		DamageType:      damageType.String(),
+		Force:           500000,
+		Range:           weaponRange,
+		Ammo:            "",
+		AmmoPerShot:     1,
+		Projectile:      nil,
+		ProjectileId:    "",
+		ProjectileSpeed: 1500,
+		Muzzle:          geometry.XYZ{X: 1.0, Y: 0, Z: -0.5},
+		ViewModel:       nil,
+	}
+}
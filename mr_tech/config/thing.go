@@ -5,12 +5,29 @@ import (
 	"github.com/markel1974/godoom/mr_tech/physics"
 )
 
-type ThinkingFunc func(self IThingConfig, playerX, playerY, playerZ float64)
+// Perception is what a thing perceives of its target, the player, when it thinks. Target is the position of the
+// target and Distance its distance from the thing. Visible reports a clear line of sight through the level geometry
+// and InView that the target is also inside the field of view of the thing. Heard reports that a noise of the target,
+// made at Noise, reached the region of the thing through the open portals in the current step.
+type Perception struct {
+	Target   geometry.XYZ
+	Distance float64
+	Visible  bool
+	InView   bool
+	Heard    bool
+	Noise    geometry.XYZ
+}
+
+type ThinkingFunc func(self IThingConfig, perception Perception)
 
 type CollisionFunc func(self IThingConfig, other IThingConfig)
 
 type ImpactFunc func(self IThingConfig, other IThingConfig, id string, force, closestDist, dirX, dirY, dirZ float64)
 
+type DeathFunc func(self IThingConfig, killer IThingConfig, kind DamageType)
+
+type DestroyFunc func(self IThingConfig)
+
 type IThingConfig interface {
 	GetId() string
 
@@ -58,5 +75,13 @@ type IThingConfig interface {
 
 	LaunchObject(throwableIndex int, cf CollisionFunc, mf ImpactFunc, pos geometry.XYZ, angle, pitch, speed float64)
 
+	FindPath(targetX, targetY, targetZ float64) ([]geometry.XYZ, bool)
+
 	Impact(other IThingConfig, id string, force, closestDist, dirX, dirY, dirZ float64)
+
+	GetHealth() float64
+
+	GetArmor() float64
+
+	IsDead() bool
 }
@@ -0,0 +1,683 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// DiagnosticSeverity classifies a Diagnostic.
+type DiagnosticSeverity int
+
+// DiagnosticWarning marks a problem the compiler can work around (the element is skipped or rendered with defaults).
+// DiagnosticError marks a problem that prevents the IR from being compiled.
+const (
+	DiagnosticWarning DiagnosticSeverity = iota
+	DiagnosticError
+)
+
+// String returns the textual representation of the severity.
+func (s DiagnosticSeverity) String() string {
+	if s == DiagnosticError {
+		return "error"
+	}
+	return "warning"
+}
+
+// DiagnosticCode identifies the kind of problem reported by a Diagnostic.
+type DiagnosticCode string
+
+// DiagSectorEmpty reports a sector without segments.
+// DiagSectorOpenLoop reports a sector whose segments do not form closed loops.
+// DiagSectorDegenerate reports a sector with a null area.
+// DiagSectorWinding reports a sector whose winding differs from the rest of the level.
+// DiagSectorHeight reports a sector whose ceiling is not above its floor.
+// DiagSegmentZeroLength reports a segment whose start and end coincide.
+// DiagSegmentParent reports a segment whose parent does not match the owning sector.
+// DiagSegmentNeighbor reports a portal segment not shared with any other sector.
+// DiagMaterialTexture reports a material referencing a texture frame that does not exist.
+// DiagThingMass reports a thing with a non-positive mass.
+// DiagThingBehavior reports a thing whose behavior is not registered.
+// DiagThingPlacement reports a thing located outside the level geometry.
+// DiagThingDuplicateId reports two things sharing the same id.
+// DiagThingPickup reports a pickup of an unknown kind, or an ammo, weapon or key pickup without an id.
+// DiagPlayerMissing reports a Root without a player.
+// DiagPlayerShape reports a player with invalid height, speed or mass.
+// DiagPlayerPlacement reports a player located outside the level geometry.
+// DiagPlayerWeapon reports a weapon without id or with a duplicate id, a non-positive fire rate, or a projectile
+// weapon without a projectile template.
+// DiagVolumeEmpty reports a volume without faces.
+// DiagFaceDegenerate reports a face with less than three points.
+// DiagMoverSector reports a mover referencing a sector that does not exist.
+// DiagMoverSpeed reports a mover with a non-positive speed.
+// DiagTriggerTarget reports a trigger referencing a mover, an elevator or a sector that does not exist.
+// DiagTriggerVolumeShape reports a trigger volume without a sector whose box is empty.
+// DiagTriggerVolumeTarget reports a trigger volume referencing a sector or a trigger that does not exist.
+// DiagElevatorSector reports an elevator referencing a sector that does not exist.
+// DiagElevatorStops reports an elevator without stops or with an invalid start stop.
+// DiagJointThing reports a joint referencing a thing that does not exist, or joining a thing to itself.
+// DiagJointShape reports a joint of unknown kind, with a negative length, stiffness or damping, a spring without
+// stiffness or a hinge without axis.
+// DiagSpawnerTemplate reports a spawner without a template, or whose template has a non-positive mass or an
+// unregistered behavior.
+// DiagSpawnerShape reports a spawner of unknown kind, without points or waves, with an empty wave, with a negative
+// time or cap, or with a point outside the level geometry.
+// DiagSourceUnsupported reports a property of the level source that the builder reads but does not support.
+const (
+	DiagSectorEmpty         DiagnosticCode = "sector.empty"
+	DiagSectorOpenLoop      DiagnosticCode = "sector.open_loop"
+	DiagSectorDegenerate    DiagnosticCode = "sector.degenerate"
+	DiagSectorWinding       DiagnosticCode = "sector.winding"
+	DiagSectorHeight        DiagnosticCode = "sector.height"
+	DiagSegmentZeroLength   DiagnosticCode = "segment.zero_length"
+	DiagSegmentParent       DiagnosticCode = "segment.parent"
+	DiagSegmentNeighbor     DiagnosticCode = "segment.neighbor"
+	DiagMaterialTexture     DiagnosticCode = "material.texture"
+	DiagThingMass           DiagnosticCode = "thing.mass"
+	DiagThingBehavior       DiagnosticCode = "thing.behavior"
+	DiagThingPlacement      DiagnosticCode = "thing.placement"
+	DiagThingDuplicateId    DiagnosticCode = "thing.duplicate_id"
+	DiagThingPickup         DiagnosticCode = "thing.pickup"
+	DiagPlayerMissing       DiagnosticCode = "player.missing"
+	DiagPlayerShape         DiagnosticCode = "player.shape"
+	DiagPlayerPlacement     DiagnosticCode = "player.placement"
+	DiagPlayerWeapon        DiagnosticCode = "player.weapon"
+	DiagVolumeEmpty         DiagnosticCode = "volume.empty"
+	DiagFaceDegenerate      DiagnosticCode = "face.degenerate"
+	DiagMoverSector         DiagnosticCode = "mover.sector"
+	DiagMoverSpeed          DiagnosticCode = "mover.speed"
+	DiagTriggerTarget       DiagnosticCode = "trigger.target"
+	DiagTriggerVolumeShape  DiagnosticCode = "trigger_volume.shape"
+	DiagTriggerVolumeTarget DiagnosticCode = "trigger_volume.target"
+	DiagElevatorSector      DiagnosticCode = "elevator.sector"
+	DiagElevatorStops       DiagnosticCode = "elevator.stops"
+	DiagJointThing          DiagnosticCode = "joint.thing"
+	DiagJointShape          DiagnosticCode = "joint.shape"
+	DiagSpawnerTemplate     DiagnosticCode = "spawner.template"
+	DiagSpawnerShape        DiagnosticCode = "spawner.shape"
+	DiagSourceUnsupported   DiagnosticCode = "source.unsupported"
+)
+
+// validateEpsilon is the tolerance used when comparing IR coordinates.
+const validateEpsilon = 0.01
+
+// Diagnostic describes a single problem found in the IR, with the id and position of the offending element.
+type Diagnostic struct {
+	Severity DiagnosticSeverity `json:"severity"`
+	Code     DiagnosticCode     `json:"code"`
+	Id       string             `json:"id"`
+	Position geometry.XYZ       `json:"position"`
+	Message  string             `json:"message"`
+}
+
+// String returns a human-readable representation of the Diagnostic.
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%s [%s] %s at X: %f Y: %f Z: %f: %s", d.Severity, d.Code, d.Id, d.Position.X, d.Position.Y, d.Position.Z, d.Message)
+}
+
+// Diagnostics is the list of problems returned by Root.Validate.
+type Diagnostics []*Diagnostic
+
+// HasErrors reports whether at least one Diagnostic has error severity.
+func (ds Diagnostics) HasErrors() bool {
+	for _, d := range ds {
+		if d.Severity == DiagnosticError {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the diagnostics matching the given severity.
+func (ds Diagnostics) Filter(severity DiagnosticSeverity) Diagnostics {
+	var out Diagnostics
+	for _, d := range ds {
+		if d.Severity == severity {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// Err returns an error joining every error diagnostic, or nil when there are none.
+func (ds Diagnostics) Err() error {
+	var errs []error
+	for _, d := range ds.Filter(DiagnosticError) {
+		errs = append(errs, errors.New(d.String()))
+	}
+	return errors.Join(errs...)
+}
+
+// validator accumulates diagnostics while walking the IR.
+type validator struct {
+	cfg   *Root
+	diags Diagnostics
+}
+
+// add appends a new Diagnostic.
+func (v *validator) add(severity DiagnosticSeverity, code DiagnosticCode, id string, pos geometry.XYZ, format string, args ...any) {
+	v.diags = append(v.diags, &Diagnostic{Severity: severity, Code: code, Id: id, Position: pos, Message: fmt.Sprintf(format, args...)})
+}
+
+// Validate checks the IR for structural problems (sector loops and winding, dangling portals, invalid things,
+// missing textures and player placement) and returns the list of diagnostics found, after the ones reported by the
+// builder. It never modifies the Root.
+func (cfg *Root) Validate() Diagnostics {
+	v := &validator{cfg: cfg}
+	v.diags = append(v.diags, cfg.reported...)
+	v.validateSectors()
+	v.validateVolumes()
+	v.validateMaterials()
+	v.validatePlayer()
+	v.validateThings()
+	v.validateMovers()
+	v.validateTriggers()
+	v.validateTriggerVolumes()
+	v.validateElevators()
+	v.validateJoints()
+	v.validateSpawners()
+	return v.diags
+}
+
+// validateSectors checks each sector loop for closure, area, winding, heights and portal neighbors.
+func (v *validator) validateSectors() {
+	var ccw, cw []*Sector
+	animated := make(map[string]bool)
+	for _, m := range v.cfg.Movers {
+		animated[m.Sector] = true
+	}
+	for _, s := range v.cfg.Sectors {
+		if len(s.Segments) == 0 {
+			v.add(DiagnosticWarning, DiagSectorEmpty, s.Id, geometry.XYZ{}, "sector has no segments")
+			continue
+		}
+		pos := xyz(s.Segments[0].Start, s.FloorY)
+		// Le porte chiuse hanno altezza nulla finché il mover non le apre
+		if s.CeilY <= s.FloorY && !animated[s.Id] {
+			v.add(DiagnosticWarning, DiagSectorHeight, s.Id, pos, "ceil %f is not above floor %f", s.CeilY, s.FloorY)
+		}
+		// Ogni vertice deve essere inizio di un segmento e fine di un altro
+		balance := make(map[[2]int64]int)
+		var points []geometry.XY
+		for _, seg := range s.Segments {
+			if seg.Parent != "" && seg.Parent != s.Id {
+				v.add(DiagnosticWarning, DiagSegmentParent, seg.Id, xyz(seg.Start, s.FloorY), "segment parent '%s' does not match sector '%s'", seg.Parent, s.Id)
+			}
+			if pointKey(seg.Start) == pointKey(seg.End) {
+				v.add(DiagnosticWarning, DiagSegmentZeroLength, seg.Id, xyz(seg.Start, s.FloorY), "segment of sector '%s' has zero length", s.Id)
+				continue
+			}
+			balance[pointKey(seg.Start)]++
+			balance[pointKey(seg.End)]--
+			points = append(points, seg.Start, seg.End)
+		}
+		for _, pt := range points {
+			if balance[pointKey(pt)] != 0 {
+				balance[pointKey(pt)] = 0
+				v.add(DiagnosticWarning, DiagSectorOpenLoop, s.Id, xyz(pt, s.FloorY), "sector loop is not closed at this vertex")
+			}
+		}
+		area := sectorArea(s)
+		if math.Abs(area) < validateEpsilon {
+			v.add(DiagnosticWarning, DiagSectorDegenerate, s.Id, pos, "sector has a null area")
+			continue
+		}
+		if s.IsCCW() {
+			ccw = append(ccw, s)
+		} else {
+			cw = append(cw, s)
+		}
+	}
+	// La winding attesa è quella della maggioranza dei settori del livello
+	minority, expected := cw, "CCW"
+	if len(cw) > len(ccw) {
+		minority, expected = ccw, "CW"
+	}
+	for _, s := range minority {
+		v.add(DiagnosticWarning, DiagSectorWinding, s.Id, xyz(s.Segments[0].Start, s.FloorY), "sector winding differs from the level winding (%s)", expected)
+	}
+	v.validateNeighbors()
+}
+
+// validateNeighbors reports portal (non-wall) segments that do not overlap a segment of another sector.
+func (v *validator) validateNeighbors() {
+	type owned struct {
+		sector  *Sector
+		segment *Segment
+	}
+	var all []owned
+	edges := make(map[[4]int64]*Sector)
+	for _, s := range v.cfg.Sectors {
+		for _, seg := range s.Segments {
+			all = append(all, owned{sector: s, segment: seg})
+			a, b := pointKey(seg.Start), pointKey(seg.End)
+			edges[[4]int64{a[0], a[1], b[0], b[1]}] = s
+		}
+	}
+	for _, o := range all {
+		seg := o.segment
+		if seg.Kind == SegmentWall || pointKey(seg.Start) == pointKey(seg.End) {
+			continue
+		}
+		a, b := pointKey(seg.Start), pointKey(seg.End)
+		if other, ok := edges[[4]int64{b[0], b[1], a[0], a[1]}]; ok && other != o.sector {
+			continue
+		}
+		found := false
+		for _, c := range all {
+			if c.sector != o.sector && segmentsOverlap(seg, c.segment) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.add(DiagnosticWarning, DiagSegmentNeighbor, seg.Id, xyz(seg.Start, o.sector.FloorY), "portal segment of sector '%s' has no neighbor sector", o.sector.Id)
+		}
+	}
+}
+
+// validateVolumes checks the 3d volumes for empty or degenerate faces.
+func (v *validator) validateVolumes() {
+	for _, vol := range v.cfg.Volumes {
+		if len(vol.Faces) == 0 {
+			v.add(DiagnosticWarning, DiagVolumeEmpty, vol.Id, geometry.XYZ{}, "volume has no faces")
+			continue
+		}
+		for _, f := range vol.Faces {
+			if len(f.Points) < 3 {
+				var pos geometry.XYZ
+				if len(f.Points) > 0 {
+					pos = f.Points[0]
+				}
+				v.add(DiagnosticWarning, DiagFaceDegenerate, f.Id, pos, "face of volume '%s' has %d points", vol.Id, len(f.Points))
+			}
+		}
+	}
+}
+
+// validateMaterials reports texture frames referenced by the IR but missing from the texture collection.
+func (v *validator) validateMaterials() {
+	if isNil(v.cfg.textures) {
+		return
+	}
+	var missing []string
+	for _, name := range v.cfg.collectFrames() {
+		if t := v.cfg.textures.Get([]string{name}); len(t) != 1 || t[0] == nil {
+			missing = append(missing, name)
+		}
+	}
+	for _, name := range missing {
+		v.add(DiagnosticWarning, DiagMaterialTexture, name, geometry.XYZ{}, "texture frame not found")
+	}
+}
+
+// validatePlayer checks the player presence, shape, placement and weapons.
+func (v *validator) validatePlayer() {
+	p := v.cfg.Player
+	if p == nil || p.Thing == nil {
+		v.add(DiagnosticError, DiagPlayerMissing, "", geometry.XYZ{}, "root has no player")
+		return
+	}
+	if p.Height <= 0 || p.Speed <= 0 || p.Mass <= 0 {
+		v.add(DiagnosticError, DiagPlayerShape, p.Id, p.Position, "player height (%f), speed (%f) and mass (%f) must be positive", p.Height, p.Speed, p.Mass)
+	}
+	if !v.isPlaced(p.Position) {
+		v.add(DiagnosticError, DiagPlayerPlacement, p.Id, p.Position, "player is outside the level geometry")
+	}
+	if _, ok := lookupBehavior(p.Thing, ThingPlayerDef); !ok {
+		v.add(DiagnosticError, DiagThingBehavior, p.Id, p.Position, "player behavior is not registered")
+	}
+	weapons := make(map[string]bool)
+	for _, w := range p.Weapons {
+		if w == nil {
+			continue
+		}
+		id := strings.ToLower(strings.TrimSpace(w.Id))
+		if id == "" || weapons[id] {
+			v.add(DiagnosticWarning, DiagPlayerWeapon, p.Id, p.Position, "weapon without id or with duplicate id '%s'", w.Id)
+		}
+		weapons[id] = true
+		if w.FireRate <= 0 {
+			v.add(DiagnosticWarning, DiagPlayerWeapon, p.Id, p.Position, "weapon %s has a non-positive fire rate", w.Id)
+		}
+		if w.Kind == WeaponProjectile && w.Projectile == nil && !v.hasThing(w.ProjectileId) {
+			v.add(DiagnosticWarning, DiagPlayerWeapon, p.Id, p.Position, "projectile weapon %s has no projectile template", w.Id)
+		}
+	}
+}
+
+// validateThings checks each thing for mass, behavior, placement, pickups and id uniqueness.
+func (v *validator) validateThings() {
+	ids := make(map[string]bool)
+	for _, t := range v.cfg.Things {
+		if ids[t.Id] {
+			v.add(DiagnosticWarning, DiagThingDuplicateId, t.Id, t.Position, "thing id is not unique")
+		}
+		ids[t.Id] = true
+		if t.Mass <= 0 {
+			v.add(DiagnosticError, DiagThingMass, t.Id, t.Position, "thing mass %f must be positive", t.Mass)
+		}
+		if id, ok := lookupBehavior(t, t.Kind); !ok {
+			v.add(DiagnosticError, DiagThingBehavior, t.Id, t.Position, "behavior '%s' is not registered", id)
+		}
+		if !v.isPlaced(t.Position) {
+			v.add(DiagnosticWarning, DiagThingPlacement, t.Id, t.Position, "thing is outside the level geometry and will be skipped")
+		}
+		for _, p := range t.Pickups {
+			switch p.Kind {
+			case PickupHealth, PickupArmor:
+			case PickupAmmo, PickupWeapon, PickupKey:
+				if p.Id == "" {
+					v.add(DiagnosticWarning, DiagThingPickup, t.Id, t.Position, "pickup of kind %d without id", p.Kind)
+				}
+			default:
+				v.add(DiagnosticWarning, DiagThingPickup, t.Id, t.Position, "unknown pickup kind %d", p.Kind)
+			}
+		}
+	}
+}
+
+// validateMovers checks each mover for a valid sector reference and speed.
+func (v *validator) validateMovers() {
+	sectors := make(map[string]bool)
+	for _, s := range v.cfg.Sectors {
+		sectors[s.Id] = true
+	}
+	for _, m := range v.cfg.Movers {
+		if !sectors[m.Sector] {
+			v.add(DiagnosticWarning, DiagMoverSector, m.Id, geometry.XYZ{}, "sector '%s' does not exist, mover will be skipped", m.Sector)
+		}
+		if m.Speed <= 0 {
+			v.add(DiagnosticWarning, DiagMoverSpeed, m.Id, geometry.XYZ{}, "mover speed %f must be positive", m.Speed)
+		}
+	}
+}
+
+// validateTriggers checks that each trigger references existing movers, elevators and sectors.
+func (v *validator) validateTriggers() {
+	sectors := make(map[string]bool)
+	for _, s := range v.cfg.Sectors {
+		sectors[s.Id] = true
+	}
+	movers := make(map[string]bool)
+	for _, m := range v.cfg.Movers {
+		movers[m.Id] = true
+	}
+	elevators := make(map[string]bool)
+	for _, e := range v.cfg.Elevators {
+		elevators[e.Id] = true
+	}
+	for _, t := range v.cfg.Triggers {
+		pos := xyz(t.Start, 0)
+		for _, id := range t.Movers {
+			if !movers[id] {
+				v.add(DiagnosticWarning, DiagTriggerTarget, t.Id, pos, "mover '%s' does not exist", id)
+			}
+		}
+		for _, id := range t.Elevators {
+			if !elevators[id] {
+				v.add(DiagnosticWarning, DiagTriggerTarget, t.Id, pos, "elevator '%s' does not exist", id)
+			}
+		}
+		if t.Activation == TriggerEnter && !sectors[t.Sector] {
+			v.add(DiagnosticWarning, DiagTriggerTarget, t.Id, pos, "sector '%s' does not exist", t.Sector)
+		}
+		for _, id := range t.Sectors {
+			if !sectors[id] {
+				v.add(DiagnosticWarning, DiagTriggerTarget, t.Id, pos, "sector '%s' does not exist", id)
+			}
+		}
+	}
+}
+
+// validateTriggerVolumes checks that each trigger volume has a non-empty box or an existing sector, and that it
+// references existing triggers.
+func (v *validator) validateTriggerVolumes() {
+	sectors := make(map[string]bool)
+	for _, s := range v.cfg.Sectors {
+		sectors[s.Id] = true
+	}
+	triggers := make(map[string]bool)
+	for _, t := range v.cfg.Triggers {
+		triggers[t.Id] = true
+	}
+	for _, t := range v.cfg.TriggerVolumes {
+		if t.Sector != "" {
+			if !sectors[t.Sector] {
+				v.add(DiagnosticWarning, DiagTriggerVolumeTarget, t.Id, t.Min, "sector '%s' does not exist, trigger volume will be skipped", t.Sector)
+			}
+		} else if t.Max.X <= t.Min.X || t.Max.Y <= t.Min.Y || t.Max.Z <= t.Min.Z {
+			v.add(DiagnosticWarning, DiagTriggerVolumeShape, t.Id, t.Min, "empty box, trigger volume will be skipped")
+		}
+		for _, id := range append(append([]string(nil), t.Enter...), t.Exit...) {
+			if !triggers[id] {
+				v.add(DiagnosticWarning, DiagTriggerVolumeTarget, t.Id, t.Min, "trigger '%s' does not exist", id)
+			}
+		}
+	}
+}
+
+// validateElevators checks each elevator for valid sectors and stops.
+func (v *validator) validateElevators() {
+	sectors := make(map[string]bool)
+	for _, s := range v.cfg.Sectors {
+		sectors[s.Id] = true
+	}
+	for _, e := range v.cfg.Elevators {
+		for _, id := range e.Sectors {
+			if !sectors[id] {
+				v.add(DiagnosticWarning, DiagElevatorSector, e.Id, geometry.XYZ{}, "sector '%s' does not exist", id)
+			}
+		}
+		if len(e.Stops) == 0 || e.Start < 0 || e.Start >= len(e.Stops) {
+			v.add(DiagnosticWarning, DiagElevatorStops, e.Id, geometry.XYZ{}, "elevator has %d stops and starts at %d, it will be skipped", len(e.Stops), e.Start)
+		}
+	}
+}
+
+// validateJoints checks each joint for existing and distinct things and for a valid shape.
+func (v *validator) validateJoints() {
+	things := make(map[string]bool)
+	for _, t := range v.cfg.Things {
+		things[t.Id] = true
+	}
+	for _, j := range v.cfg.Joints {
+		if !things[j.ThingA] {
+			v.add(DiagnosticWarning, DiagJointThing, j.Id, j.AnchorB, "thing '%s' does not exist, joint will be skipped", j.ThingA)
+		}
+		if j.ThingB != "" && !things[j.ThingB] {
+			v.add(DiagnosticWarning, DiagJointThing, j.Id, j.AnchorB, "thing '%s' does not exist, joint will be skipped", j.ThingB)
+		}
+		if j.ThingA != "" && j.ThingA == j.ThingB {
+			v.add(DiagnosticWarning, DiagJointThing, j.Id, j.AnchorB, "joint binds thing '%s' to itself, it will be skipped", j.ThingA)
+		}
+		switch {
+		case j.Kind < JointDistance || j.Kind > JointFixed:
+			v.add(DiagnosticWarning, DiagJointShape, j.Id, j.AnchorB, "unknown joint kind %d, joint will be skipped", j.Kind)
+		case j.Length < 0 || j.Stiffness < 0 || j.Damping < 0:
+			v.add(DiagnosticWarning, DiagJointShape, j.Id, j.AnchorB, "negative length, stiffness or damping")
+		case j.Kind == JointSpring && j.Stiffness == 0:
+			v.add(DiagnosticWarning, DiagJointShape, j.Id, j.AnchorB, "spring without stiffness")
+		case j.Kind == JointHinge && j.Axis.X == 0 && j.Axis.Y == 0 && j.Axis.Z == 0:
+			v.add(DiagnosticWarning, DiagJointShape, j.Id, j.AnchorB, "hinge without axis, the Z axis will be used")
+		}
+	}
+}
+
+// validateSpawners checks each spawner for a valid template, kind, points and waves.
+func (v *validator) validateSpawners() {
+	for _, s := range v.cfg.Spawners {
+		var pos geometry.XYZ
+		if len(s.Points) > 0 {
+			pos = s.Points[0].Position
+		}
+		switch {
+		case s.Template == nil:
+			v.add(DiagnosticError, DiagSpawnerTemplate, s.Id, pos, "spawner has no template")
+		case s.Template.Mass <= 0:
+			v.add(DiagnosticError, DiagSpawnerTemplate, s.Id, pos, "template mass %f must be positive", s.Template.Mass)
+		default:
+			if id, ok := lookupBehavior(s.Template, s.Template.Kind); !ok {
+				v.add(DiagnosticError, DiagSpawnerTemplate, s.Id, pos, "template behavior '%s' is not registered", id)
+			}
+		}
+		switch {
+		case s.Kind < SpawnerWaves || s.Kind > SpawnerRespawn:
+			v.add(DiagnosticError, DiagSpawnerShape, s.Id, pos, "unknown spawner kind %d", s.Kind)
+		case s.Kind == SpawnerWaves && len(s.Waves) == 0:
+			v.add(DiagnosticError, DiagSpawnerShape, s.Id, pos, "wave spawner has no waves")
+		case s.Delay < 0 || s.MaxAlive < 0:
+			v.add(DiagnosticError, DiagSpawnerShape, s.Id, pos, "negative delay %f or cap %d", s.Delay, s.MaxAlive)
+		}
+		for idx, w := range s.Waves {
+			if w.Count <= 0 || w.Delay < 0 || w.Interval < 0 {
+				v.add(DiagnosticError, DiagSpawnerShape, s.Id, pos, "wave %d has %d things, delay %f and interval %f", idx, w.Count, w.Delay, w.Interval)
+			}
+		}
+		// I punti fuori dal livello vengono scartati, ma ne deve restare almeno uno
+		placed := 0
+		for _, p := range s.Points {
+			if v.isPlaced(p.Position) {
+				placed++
+			} else {
+				v.add(DiagnosticWarning, DiagSpawnerShape, s.Id, p.Position, "spawn point is outside the level geometry, it will be skipped")
+			}
+		}
+		if placed == 0 {
+			v.add(DiagnosticError, DiagSpawnerShape, s.Id, pos, "spawner has no point inside the level geometry")
+		}
+	}
+}
+
+// hasThing reports whether the level has a thing with the given id.
+func (v *validator) hasThing(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, t := range v.cfg.Things {
+		if t.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// isNil reports whether the interface is nil or holds a nil pointer, as a nil texture collection of a builder.
+func isNil(i any) bool {
+	if i == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(i); rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Interface, reflect.Chan:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// isPlaced reports whether the position lies inside a sector (2d levels) or inside the bounds of a volume (3d levels).
+func (v *validator) isPlaced(pos geometry.XYZ) bool {
+	if len(v.cfg.Sectors) == 0 && len(v.cfg.Volumes) == 0 {
+		return false
+	}
+	p := geometry.XY{X: pos.X, Y: pos.Y}
+	for _, s := range v.cfg.Sectors {
+		if sectorContains(s, p) {
+			return true
+		}
+	}
+	for _, vol := range v.cfg.Volumes {
+		if volumeContains(vol, pos) {
+			return true
+		}
+	}
+	return false
+}
+
+// pointKey quantizes a point with validateEpsilon, so that nearly coincident vertices compare equal.
+func pointKey(p geometry.XY) [2]int64 {
+	return [2]int64{int64(math.Round(p.X / validateEpsilon)), int64(math.Round(p.Y / validateEpsilon))}
+}
+
+// xyz lifts a 2d point to 3d using the given height.
+func xyz(p geometry.XY, z float64) geometry.XYZ {
+	return geometry.XYZ{X: p.X, Y: p.Y, Z: z}
+}
+
+// sectorArea returns the signed area term used by Sector.IsCCW.
+func sectorArea(s *Sector) float64 {
+	area := 0.0
+	for _, seg := range s.Segments {
+		area += (seg.End.X - seg.Start.X) * (seg.End.Y + seg.Start.Y)
+	}
+	return area * 0.5
+}
+
+// sectorContains tests the point against all the sector loops with the even-odd rule.
+func sectorContains(s *Sector, p geometry.XY) bool {
+	inside := false
+	for _, seg := range s.Segments {
+		a, b := seg.Start, seg.End
+		if (a.Y > p.Y) != (b.Y > p.Y) {
+			x := a.X + (p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+			if p.X < x {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// volumeContains tests the point against the axis aligned bounds of the volume faces.
+func volumeContains(vol *Volume, p geometry.XYZ) bool {
+	minP := geometry.XYZ{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)}
+	maxP := geometry.XYZ{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)}
+	for _, f := range vol.Faces {
+		for _, pt := range f.Points {
+			minP.X, minP.Y, minP.Z = math.Min(minP.X, pt.X), math.Min(minP.Y, pt.Y), math.Min(minP.Z, pt.Z)
+			maxP.X, maxP.Y, maxP.Z = math.Max(maxP.X, pt.X), math.Max(maxP.Y, pt.Y), math.Max(maxP.Z, pt.Z)
+		}
+	}
+	return p.X >= minP.X-validateEpsilon && p.X <= maxP.X+validateEpsilon &&
+		p.Y >= minP.Y-validateEpsilon && p.Y <= maxP.Y+validateEpsilon &&
+		p.Z >= minP.Z-validateEpsilon && p.Z <= maxP.Z+validateEpsilon
+}
+
+// segmentsOverlap reports whether two segments are collinear and share a portion of positive length.
+func segmentsOverlap(s1, s2 *Segment) bool {
+	dx, dy := s1.End.X-s1.Start.X, s1.End.Y-s1.Start.Y
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return false
+	}
+	// Distanza con segno dei punti di s2 dalla retta di s1
+	cross := func(p geometry.XY) float64 {
+		return (dx*(p.Y-s1.Start.Y) - dy*(p.X-s1.Start.X)) / length
+	}
+	if math.Abs(cross(s2.Start)) > validateEpsilon || math.Abs(cross(s2.End)) > validateEpsilon {
+		return false
+	}
+	proj := func(p geometry.XY) float64 {
+		return (dx*(p.X-s1.Start.X) + dy*(p.Y-s1.Start.Y)) / length
+	}
+	t0, t1 := proj(s2.Start), proj(s2.End)
+	if t0 > t1 {
+		t0, t1 = t1, t0
+	}
+	return math.Min(t1, length)-math.Max(t0, 0) > validateEpsilon
+}
+
+// FormatDiagnostics returns the diagnostics one per line, errors first.
+func FormatDiagnostics(ds Diagnostics) string {
+	var sb strings.Builder
+	for _, severity := range []DiagnosticSeverity{DiagnosticError, DiagnosticWarning} {
+		for _, d := range ds.Filter(severity) {
+			sb.WriteString(d.String())
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// testBehavior is the behavior registered for the things of the test levels.
+const testBehavior = "validator_test"
+
+func init() {
+	RegisterBehavior(testBehavior, func(*Thing, BehaviorParams, int64) *BehaviorHandlers { return &BehaviorHandlers{} })
+}
+
+// newTestSector creates a closed counter-clockwise square sector of the given size, with its corner at (x, y).
+func newTestSector(id string, x, y, size float64) *Sector {
+	s := NewConfigSector(id, 1, LightKindAmbient, 0)
+	s.CeilY = 10
+	corners := []geometry.XY{{X: x, Y: y}, {X: x + size, Y: y}, {X: x + size, Y: y + size}, {X: x, Y: y + size}}
+	for i, c := range corners {
+		s.Segments = append(s.Segments, NewConfigSegment(id, SegmentWall, c, corners[(i+1)%len(corners)]))
+	}
+	return s
+}
+
+// newTestRoot creates a valid level: a single square sector with the player and a thing inside it.
+func newTestRoot(t *BundleTextures) *Root {
+	player := NewConfigPlayer(geometry.XYZ{X: 2, Y: 2}, 0, 10, 10, 1, 2)
+	player.Behavior = NewConfigBehavior(testBehavior, nil)
+	player.SetProjectile("barrel")
+	thing := NewConfigThing("barrel", geometry.XYZ{X: 6, Y: 6}, 0, ThingItemDef, 10, 1, 1, 0)
+	thing.Behavior = NewConfigBehavior(testBehavior, nil)
+	return NewConfigRoot(nil, []*Sector{newTestSector("room", 0, 0, 8)}, player, []*Thing{thing}, geometry.XYZ{X: 1, Y: 1, Z: 1}, t)
+}
+
+// newTestSpawner creates a wave spawner of template with a point inside the sector of newTestRoot.
+func newTestSpawner(template *Thing) *Spawner {
+	s := NewConfigSpawner("spawner", SpawnerWaves, template, []SpawnPoint{{Position: geometry.XYZ{X: 4, Y: 4}}})
+	s.Waves = []SpawnWave{{Count: 2, Delay: 1, Interval: 0.5}}
+	return s
+}
+
+// hasDiagnostic reports whether the diagnostics contain the code with the given severity.
+func hasDiagnostic(ds Diagnostics, severity DiagnosticSeverity, code DiagnosticCode) bool {
+	for _, d := range ds {
+		if d.Severity == severity && d.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateValidRoot(t *testing.T) {
+	if ds := newTestRoot(NewBundleTextures()).Validate(); len(ds) != 0 {
+		t.Fatalf("a valid root reported %d diagnostics, the first: %s", len(ds), ds[0])
+	}
+}
+
+func TestValidateNilTextures(t *testing.T) {
+	cfg := newTestRoot(nil)
+	cfg.Sectors[0].Floor = NewConfigMaterial([]string{"FLOOR"}, MaterialKindNone, 1, 1, 0, 0)
+	// Un builder senza texture passa un puntatore nullo, che l'interfaccia non vede come nil
+	if ds := cfg.Validate(); len(ds) != 0 {
+		t.Fatalf("a root without textures reported %d diagnostics, the first: %s", len(ds), ds[0])
+	}
+}
+
+func TestValidateValidSpawner(t *testing.T) {
+	cfg := newTestRoot(NewBundleTextures())
+	s := newTestSpawner(cfg.Things[0])
+	// Un punto fuori dal livello viene scartato, gli altri restano
+	s.Points = append(s.Points, SpawnPoint{Position: geometry.XYZ{X: 100, Y: 100}})
+	cfg.Spawners = append(cfg.Spawners, s)
+	ds := cfg.Validate()
+	if ds.HasErrors() {
+		t.Fatalf("a valid spawner reported errors: %v", ds.Err())
+	}
+	if !hasDiagnostic(ds, DiagnosticWarning, DiagSpawnerShape) {
+		t.Fatal("the point outside the level has not been reported")
+	}
+}
+
+func TestValidateMissingTexture(t *testing.T) {
+	cfg := newTestRoot(NewBundleTextures())
+	cfg.Sectors[0].Floor = NewConfigMaterial([]string{"FLOOR"}, MaterialKindNone, 1, 1, 0, 0)
+	ds := cfg.Validate()
+	if !hasDiagnostic(ds, DiagnosticWarning, DiagMaterialTexture) {
+		t.Fatal("the missing texture frame has not been reported")
+	}
+	if ds.HasErrors() {
+		t.Fatalf("a missing texture is not an error: %v", ds.Err())
+	}
+}
+
+func TestValidateDiagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		edit     func(cfg *Root)
+		severity DiagnosticSeverity
+		code     DiagnosticCode
+	}{
+		{"open loop", func(cfg *Root) {
+			cfg.Sectors[0].Segments = cfg.Sectors[0].Segments[:3]
+		}, DiagnosticWarning, DiagSectorOpenLoop},
+		{"winding", func(cfg *Root) {
+			cw := newTestSector("cw", 20, 0, 8)
+			for _, seg := range cw.Segments {
+				seg.Start, seg.End = seg.End, seg.Start
+			}
+			cfg.Sectors = append(cfg.Sectors, newTestSector("ccw", 40, 0, 8), cw)
+		}, DiagnosticWarning, DiagSectorWinding},
+		{"height", func(cfg *Root) {
+			cfg.Sectors[0].CeilY = cfg.Sectors[0].FloorY
+		}, DiagnosticWarning, DiagSectorHeight},
+		{"dangling portal", func(cfg *Root) {
+			cfg.Sectors[0].Segments[0].Kind = SegmentUnknown
+		}, DiagnosticWarning, DiagSegmentNeighbor},
+		{"missing player", func(cfg *Root) {
+			cfg.Player = nil
+		}, DiagnosticError, DiagPlayerMissing},
+		{"player outside", func(cfg *Root) {
+			cfg.Player.Position = geometry.XYZ{X: 100, Y: 100}
+		}, DiagnosticError, DiagPlayerPlacement},
+		{"player behavior", func(cfg *Root) {
+			cfg.Player.Behavior = NewConfigBehavior("unknown", nil)
+		}, DiagnosticError, DiagThingBehavior},
+		{"projectile", func(cfg *Root) {
+			cfg.Player.SetProjectile("unknown")
+		}, DiagnosticWarning, DiagPlayerWeapon},
+		{"thing mass", func(cfg *Root) {
+			cfg.Things[0].Mass = 0
+		}, DiagnosticError, DiagThingMass},
+		{"thing outside", func(cfg *Root) {
+			cfg.Things[0].Position = geometry.XYZ{X: -5, Y: 4}
+		}, DiagnosticWarning, DiagThingPlacement},
+		{"duplicate thing", func(cfg *Root) {
+			cfg.Things = append(cfg.Things, cfg.Things[0].Clone())
+		}, DiagnosticWarning, DiagThingDuplicateId},
+		{"spawner template", func(cfg *Root) {
+			cfg.Spawners = append(cfg.Spawners, newTestSpawner(nil))
+		}, DiagnosticError, DiagSpawnerTemplate},
+		{"spawner behavior", func(cfg *Root) {
+			template := cfg.Things[0].Clone()
+			template.Behavior = NewConfigBehavior("unknown", nil)
+			cfg.Spawners = append(cfg.Spawners, newTestSpawner(template))
+		}, DiagnosticError, DiagSpawnerTemplate},
+		{"spawner kind", func(cfg *Root) {
+			s := newTestSpawner(cfg.Things[0])
+			s.Kind = SpawnerRespawn + 1
+			cfg.Spawners = append(cfg.Spawners, s)
+		}, DiagnosticError, DiagSpawnerShape},
+		{"spawner waves", func(cfg *Root) {
+			s := newTestSpawner(cfg.Things[0])
+			s.Waves = nil
+			cfg.Spawners = append(cfg.Spawners, s)
+		}, DiagnosticError, DiagSpawnerShape},
+		{"spawner empty wave", func(cfg *Root) {
+			s := newTestSpawner(cfg.Things[0])
+			s.Waves[0].Count = 0
+			cfg.Spawners = append(cfg.Spawners, s)
+		}, DiagnosticError, DiagSpawnerShape},
+		{"spawner points", func(cfg *Root) {
+			s := newTestSpawner(cfg.Things[0])
+			s.Points = []SpawnPoint{{Position: geometry.XYZ{X: 100, Y: 100}}}
+			cfg.Spawners = append(cfg.Spawners, s)
+		}, DiagnosticError, DiagSpawnerShape},
+		{"mover sector", func(cfg *Root) {
+			cfg.Movers = append(cfg.Movers, NewConfigMover("lift", "unknown", MoverKindFloor, 0, 10, 1))
+		}, DiagnosticWarning, DiagMoverSector},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := newTestRoot(NewBundleTextures())
+			test.edit(cfg)
+			ds := cfg.Validate()
+			if !hasDiagnostic(ds, test.severity, test.code) {
+				t.Fatalf("%s %s not reported, got %d diagnostics", test.severity, test.code, len(ds))
+			}
+			if got := ds.Err() != nil; got != (test.severity == DiagnosticError) {
+				t.Fatalf("Err returned an error: %t, want %t", got, test.severity == DiagnosticError)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package engine
+
+import "time"
+
+// Clock is an accumulator-based fixed-timestep simulation clock, decoupled from the render frame rate.
+type Clock struct {
+	dt          float64
+	maxSteps    int
+	accumulator float64
+	alpha       float64
+	last        time.Time
+	started     bool
+	steps       uint64
+}
+
+// NewClock creates a Clock that advances the simulation at the given rate (steps per second).
+// maxSteps limits the catch-up steps executed per frame; the remaining backlog is dropped to avoid a spiral of death.
+func NewClock(rate float64, maxSteps int) *Clock {
+	c := &Clock{}
+	c.SetRate(rate, maxSteps)
+	return c
+}
+
+// SetRate updates the simulation rate and the maximum number of steps per frame, resetting the accumulator.
+func (c *Clock) SetRate(rate float64, maxSteps int) {
+	if rate <= 0 {
+		rate = 60
+	}
+	if maxSteps <= 0 {
+		maxSteps = 1
+	}
+	c.dt = 1.0 / rate
+	c.maxSteps = maxSteps
+	c.accumulator = 0
+	c.alpha = 1.0
+}
+
+// GetDt returns the fixed simulation time step in seconds.
+func (c *Clock) GetDt() float64 {
+	return c.dt
+}
+
+// GetAlpha returns the interpolation factor between the last two simulation states, in the range [0, 1].
+func (c *Clock) GetAlpha() float64 {
+	return c.alpha
+}
+
+// GetSteps returns the total number of fixed steps executed since the clock was created.
+func (c *Clock) GetSteps() uint64 {
+	return c.steps
+}
+
+// Tick measures the wall-clock time elapsed since the previous call and returns the number of fixed steps to execute.
+func (c *Clock) Tick() int {
+	now := time.Now()
+	if !c.started {
+		c.started = true
+		c.last = now
+		// The very first frame always advances the simulation by one step
+		return c.Advance(c.dt)
+	}
+	elapsed := now.Sub(c.last).Seconds()
+	c.last = now
+	return c.Advance(elapsed)
+}
+
+// Advance accumulates the elapsed time (in seconds) and returns the number of fixed steps to execute,
+// capped at maxSteps. It also refreshes the interpolation alpha with the leftover time.
+func (c *Clock) Advance(elapsed float64) int {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	c.accumulator += elapsed
+	steps := 0
+	for c.accumulator >= c.dt && steps < c.maxSteps {
+		c.accumulator -= c.dt
+		steps++
+	}
+	if c.accumulator >= c.dt {
+		// Catch-up limit reached: drop the backlog and keep only the fractional part
+		c.accumulator = c.accumulator - float64(int(c.accumulator/c.dt))*c.dt
+	}
+	c.alpha = c.accumulator / c.dt
+	c.steps += uint64(steps)
+	return steps
+}
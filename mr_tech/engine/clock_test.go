@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+func TestClockAdvance(t *testing.T) {
+	c := NewClock(4, 8)
+	tests := []struct {
+		elapsed float64
+		steps   int
+		alpha   float64
+	}{
+		{0.1, 0, 0.4},
+		{0.2, 1, 0.2},
+		{0.45, 2, 0},
+		{-1, 0, 0},
+		{0.25, 1, 0},
+	}
+	for idx, test := range tests {
+		if steps := c.Advance(test.elapsed); steps != test.steps {
+			t.Fatalf("frame %d: %d steps, want %d", idx, steps, test.steps)
+		}
+		if alpha := c.GetAlpha(); math.Abs(alpha-test.alpha) > 1e-9 {
+			t.Fatalf("frame %d: alpha %f, want %f", idx, alpha, test.alpha)
+		}
+	}
+	if c.GetSteps() != 4 {
+		t.Fatalf("%d steps executed, want 4", c.GetSteps())
+	}
+}
+
+func TestClockDropsBacklog(t *testing.T) {
+	c := NewClock(4, 3)
+	// Un frame lungo esegue solo maxSteps passi e scarta il resto, tenendo la parte frazionaria
+	if steps := c.Advance(10.1); steps != 3 {
+		t.Fatalf("%d steps after a long frame, want 3", steps)
+	}
+	if alpha := c.GetAlpha(); math.Abs(alpha-0.4) > 1e-9 {
+		t.Fatalf("alpha %f after a long frame, want 0.4", alpha)
+	}
+	if steps := c.Advance(0.2); steps != 1 {
+		t.Fatalf("%d steps after the long frame, want 1", steps)
+	}
+}
+
+func TestClockSetRate(t *testing.T) {
+	c := NewClock(0, 0)
+	if math.Abs(c.GetDt()-1.0/60) > 1e-12 {
+		t.Fatalf("dt %f for an invalid rate, want the default 1/60", c.GetDt())
+	}
+	if steps := c.Advance(1); steps != 1 {
+		t.Fatalf("%d steps for an invalid cap, want 1", steps)
+	}
+	c.Advance(0.01)
+	c.SetRate(10, 5)
+	// Il cambio di frequenza azzera l'accumulatore
+	if c.GetDt() != 0.1 || c.GetAlpha() != 1 {
+		t.Fatalf("dt %f and alpha %f after SetRate, want 0.1 and 1", c.GetDt(), c.GetAlpha())
+	}
+	if steps := c.Advance(0.05); steps != 0 {
+		t.Fatalf("%d steps after SetRate, want 0", steps)
+	}
+}
+
+func TestClockFirstTick(t *testing.T) {
+	c := NewClock(60, 4)
+	if steps := c.Tick(); steps != 1 {
+		t.Fatalf("the first tick executed %d steps, want 1", steps)
+	}
+}
@@ -2,22 +2,42 @@ package engine
 
 import (
 	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
 	"github.com/markel1974/godoom/mr_tech/model"
 	"github.com/markel1974/godoom/mr_tech/physics"
 	"github.com/markel1974/godoom/mr_tech/portal"
 	"github.com/markel1974/godoom/mr_tech/textures"
 )
 
+// defaultTickRate is the default number of fixed simulation steps per second.
+// defaultMaxSteps is the default maximum number of catch-up steps executed in a single frame.
+const (
+	defaultTickRate = 60.0
+	defaultMaxSteps = 5
+)
+
 // Engine represents a core game simulation system, managing things, volumes, player, and rendering configurations.
 type Engine struct {
-	portal      *portal.Portal
-	maxQueue    int
-	viewFactor  float64
-	things      *model.Things
-	player      *model.ThingPlayer
-	volumes     *model.Volumes
-	lights      *model.Lights
-	calibration *model.Calibration
+	portal         *portal.Portal
+	maxQueue       int
+	viewFactor     float64
+	things         *model.Things
+	movers         *model.Movers
+	elevators      *model.Elevators
+	specials       *model.Specials
+	triggerVolumes *model.TriggerVolumes
+	joints         *model.Joints
+	spawners       *model.Spawners
+	query          *model.Query
+	navMesh        *model.NavMesh
+	perception     *model.Perception
+	player         *model.ThingPlayer
+	volumes        *model.Volumes
+	lights         *model.Lights
+	calibration    *model.Calibration
+	clock          *Clock
+	deterministic  bool
+	serialResolve  bool
 }
 
 // NewEngine creates and initializes a new Engine instance with the specified width, height, and maximum queue size.
@@ -30,9 +50,45 @@ func NewEngine(maxQueue int, viewFactor float64) *Engine {
 		volumes:    nil,
 		player:     nil,
 		lights:     nil,
+		clock:      NewClock(defaultTickRate, defaultMaxSteps),
 	}
 }
 
+// SetTickRate configures the fixed simulation rate (steps per second) and the maximum catch-up steps per frame.
+func (e *Engine) SetTickRate(rate float64, maxSteps int) {
+	e.clock.SetRate(rate, maxSteps)
+	if e.things != nil {
+		e.things.SetTimeStep(e.clock.GetDt())
+	}
+}
+
+// SetDeterministic enables or disables the deterministic mode of the things: with the same level, seed and input,
+// every Step produces bit-identical states.
+func (e *Engine) SetDeterministic(deterministic bool) {
+	e.deterministic = deterministic
+	if e.things != nil {
+		e.things.SetDeterministic(deterministic)
+	}
+}
+
+// SetSerialResolve forces the resolution of the contacts on a single goroutine, as a reference for the parallel solver.
+func (e *Engine) SetSerialResolve(serial bool) {
+	e.serialResolve = serial
+	if e.things != nil {
+		e.things.SetParallelResolve(!serial)
+	}
+}
+
+// GetClock returns the fixed-timestep simulation clock driving the engine.
+func (e *Engine) GetClock() *Clock {
+	return e.clock
+}
+
+// GetAlpha returns the interpolation factor between the last two physics states, for renderers blending positions.
+func (e *Engine) GetAlpha() float64 {
+	return e.clock.GetAlpha()
+}
+
 // GetPlayer returns the current player instance managed by the engine.
 func (e *Engine) GetPlayer() *model.ThingPlayer {
 	return e.player
@@ -43,6 +99,46 @@ func (e *Engine) GetTextures() textures.ITextures {
 	return e.things.GetTextures()
 }
 
+// GetMovers returns the sector movers (doors, lifts, crushers) managed by the Engine.
+func (e *Engine) GetMovers() *model.Movers {
+	return e.movers
+}
+
+// GetElevators returns the scripted sector elevators managed by the Engine.
+func (e *Engine) GetElevators() *model.Elevators {
+	return e.elevators
+}
+
+// GetSpecials returns the line specials interpreter managed by the Engine.
+func (e *Engine) GetSpecials() *model.Specials {
+	return e.specials
+}
+
+// GetTriggerVolumes returns the non-solid trigger volumes managed by the Engine.
+func (e *Engine) GetTriggerVolumes() *model.TriggerVolumes {
+	return e.triggerVolumes
+}
+
+// GetJoints returns the physics joints managed by the Engine.
+func (e *Engine) GetJoints() *model.Joints {
+	return e.joints
+}
+
+// GetSpawners returns the thing spawners managed by the Engine.
+func (e *Engine) GetSpawners() *model.Spawners {
+	return e.spawners
+}
+
+// GetNavMesh returns the navigation mesh of the level managed by the Engine.
+func (e *Engine) GetNavMesh() *model.NavMesh {
+	return e.navMesh
+}
+
+// GetPerception returns the perception of the AI managed by the Engine.
+func (e *Engine) GetPerception() *model.Perception {
+	return e.perception
+}
+
 // GetThings returns the Things instance managed by the Engine.
 func (e *Engine) GetThings() *model.Things {
 	return e.things
@@ -68,6 +164,31 @@ func (e *Engine) QueryMultiFrustum(front, rear *physics.Frustum, callback func(o
 	e.volumes.QueryMultiFrustum(front, rear, callback)
 }
 
+// Raycast returns the first face or thing hit by the ray from origin along dir within maxDistance.
+func (e *Engine) Raycast(origin, dir geometry.XYZ, maxDistance float64, filter model.QueryFilter) (model.QueryHit, bool) {
+	return e.query.Raycast(origin, dir, maxDistance, filter)
+}
+
+// RaycastAll returns every face and thing hit by the ray from origin along dir within maxDistance, ordered by distance.
+func (e *Engine) RaycastAll(origin, dir geometry.XYZ, maxDistance float64, filter model.QueryFilter) []model.QueryHit {
+	return e.query.RaycastAll(origin, dir, maxDistance, filter)
+}
+
+// SweepBox moves the box of the given half extents from center by delta and returns the first face or thing it hits.
+func (e *Engine) SweepBox(center, half, delta geometry.XYZ, filter model.QueryFilter) (model.QueryHit, bool) {
+	return e.query.SweepBox(center, half, delta, filter)
+}
+
+// OverlapSphere returns the faces and the things within radius from center, ordered by distance.
+func (e *Engine) OverlapSphere(center geometry.XYZ, radius float64, filter model.QueryFilter) []model.QueryHit {
+	return e.query.OverlapSphere(center, radius, filter)
+}
+
+// OverlapBox returns the faces and the things intersecting the box of the given half extents around center.
+func (e *Engine) OverlapBox(center, half geometry.XYZ, filter model.QueryFilter) []model.QueryHit {
+	return e.query.OverlapBox(center, half, filter)
+}
+
 // PortalLen returns the number of volumes currently managed by the Engine.
 func (e *Engine) PortalLen() int {
 	return e.portal.Len()
@@ -81,9 +202,21 @@ func (e *Engine) Setup(cfg *config.Root) error {
 	}
 	e.player = compiler.GetPlayer()
 	e.things = compiler.GetThings()
+	e.movers = compiler.GetMovers()
+	e.elevators = compiler.GetElevators()
+	e.specials = compiler.GetSpecials()
+	e.triggerVolumes = compiler.GetTriggerVolumes()
+	e.joints = compiler.GetJoints()
+	e.spawners = compiler.GetSpawners()
 	e.lights = compiler.GetLights()
 	e.calibration = compiler.GetCalibration()
 	e.volumes = compiler.GetVolumes()
+	e.query = compiler.GetQuery()
+	e.navMesh = compiler.GetNavMesh()
+	e.perception = compiler.GetPerception()
+	e.things.SetTimeStep(e.clock.GetDt())
+	e.things.SetDeterministic(e.deterministic)
+	e.things.SetParallelResolve(!e.serialResolve)
 	e.portal = portal.NewPortal(e.maxQueue, e.viewFactor)
 
 	var sectors []*model.Sector
@@ -98,14 +231,47 @@ func (e *Engine) Setup(cfg *config.Root) error {
 	return nil
 }
 
-// Compute updates the game state by synchronizing the player, processing AI, applying physics, and updating the view matrix.
+// Close destroys the things of the level, stopping their goroutines. The Engine can't be stepped anymore.
+func (e *Engine) Close() {
+	if e.things != nil {
+		e.things.Close()
+	}
+}
+
+// Compute advances the simulation by as many fixed steps as the elapsed wall-clock time requires,
+// then updates the view matrix interpolating between the last two physics states.
 func (e *Engine) Compute(player *model.ThingPlayer, vi *model.ViewMatrix) {
+	steps := e.clock.Tick()
+	for x := 0; x < steps; x++ {
+		e.step(player)
+	}
+	// Post-Sync ViewMatrix
+	vi.Update(player, e.clock.GetAlpha())
+}
+
+// Step advances the simulation by exactly one fixed step, regardless of the wall-clock time, and updates the view matrix.
+func (e *Engine) Step(player *model.ThingPlayer, vi *model.ViewMatrix) {
+	e.step(player)
+	vi.Update(player, 1.0)
+}
+
+// step runs a single fixed simulation step: weapons, AI, dynamic solver, trigger volumes and animation ticks.
+func (e *Engine) step(player *model.ThingPlayer) {
 	// AI & External Forces: Wake up things BEFORE physics calculation
 	pX, pY, pZ := player.GetEntity().GetCenter()
+	// Weapons: shots are fired before the specials, so that a shoot special reacts in the same step
+	player.ComputeWeapons(e.clock.GetDt())
+	// Line Specials: player actions (cross, use, shoot, enter) fire movers, elevators, lights and exits
+	e.specials.Compute(player)
+	// Sector Movers and Elevators: geometry is updated before the solver queries the volumes
+	e.movers.Compute(e.clock.GetDt())
+	e.elevators.Compute(e.clock.GetDt())
+	// Spawners: the spawned things join the pending list before the solver step
+	e.spawners.Compute(e.clock.GetDt())
 	// Dynamic Solver
 	e.things.Compute(pX, pY, pZ)
-	// Post-Sync ViewMatrix
-	vi.Update(player)
+	// Trigger Volumes: enter, stay and exit events of the things moved by the solver
+	e.triggerVolumes.Compute()
 	// Update Textures
 	textures.Tick()
 }
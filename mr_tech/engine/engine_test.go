@@ -0,0 +1,215 @@
+package engine
+
+import (
+	"math"
+	"runtime"
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/generators/wolfstein"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+	"github.com/markel1974/godoom/mr_tech/model"
+)
+
+// newTestEngine builds the first Wolfenstein level, lets edit change its configuration and sets up an Engine on it.
+func newTestEngine(tb testing.TB, edit func(cfg *config.Root)) *Engine {
+	tb.Helper()
+	cfg, err := wolfstein.NewBuilder().Build(1)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if edit != nil {
+		edit(cfg)
+	}
+	e := NewEngine(32, 3.0)
+	if err = e.Setup(cfg); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(e.Close)
+	return e
+}
+
+// stepEngine advances the Engine by the given number of fixed steps.
+func stepEngine(e *Engine, steps int) {
+	vi := model.NewViewMatrix()
+	for x := 0; x < steps; x++ {
+		e.Step(e.GetPlayer(), vi)
+	}
+}
+
+func TestPlayerDeathKeepsCorpse(t *testing.T) {
+	e := newTestEngine(t, nil)
+	p := e.GetPlayer()
+	count := e.GetThings().Len()
+	p.Impact(nil, "bullet", 1000, 0, 1, 0, 0)
+	if !p.IsDead() {
+		t.Fatal("the player survived a lethal damage")
+	}
+	stepEngine(e, 30)
+	if p.IsDestroyed() {
+		t.Fatal("the dead player has been destroyed")
+	}
+	if !p.IsCorpse() {
+		t.Fatal("the dead player is not a corpse")
+	}
+	if layer, _ := p.GetCollision(); layer != config.LayerCorpse {
+		t.Fatalf("the dead player is on layer %d, want the corpse layer", layer)
+	}
+	if got := e.GetThings().Len(); got != count {
+		t.Fatalf("things after the death of the player: %d, want %d", got, count)
+	}
+	x, y, z := p.GetEntity().GetCenter()
+	found := false
+	for _, hit := range e.OverlapSphere(geometry.XYZ{X: x, Y: y, Z: z}, 1, model.NewQueryFilter(config.LayerAll, config.LayerAll)) {
+		if hit.Thing == model.IThing(p) {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("the dead player is not found by the world queries")
+	}
+}
+
+func TestHitscanStopsAtWalls(t *testing.T) {
+	e := newTestEngine(t, nil)
+	stepEngine(e, 1)
+	walls := model.NewQueryFilter(config.LayerProjectile, config.LayerAll)
+	walls.Things = false
+	var enemies []model.IThing
+	active, count := e.GetThings().GetActive()
+	for x := 0; x < count; x++ {
+		if active[x].GetBase().GetKind() == config.ThingEnemyDef {
+			enemies = append(enemies, active[x])
+		}
+	}
+	hidden := 0
+	for _, shooter := range enemies {
+		sX, sY, sZ := shooter.GetEntity().GetCenter()
+		origin := geometry.XYZ{X: sX, Y: sY, Z: sZ}
+		for _, target := range enemies {
+			if target == shooter || target.IsDead() {
+				continue
+			}
+			tX, tY, tZ := target.GetEntity().GetCenter()
+			dir := geometry.XYZ{X: tX - sX, Y: tY - sY, Z: tZ - sZ}
+			distance := math.Sqrt(dir.X*dir.X + dir.Y*dir.Y + dir.Z*dir.Z)
+			dir = geometry.XYZ{X: dir.X / distance, Y: dir.Y / distance, Z: dir.Z / distance}
+			wall, ok := e.Raycast(origin, dir, distance, walls)
+			if !ok {
+				continue
+			}
+			hidden++
+			health := target.GetHealth()
+			hit, ok := shooter.GetBase().FireHitscan("bullet", origin, 1000, 0, distance*2, dir.X, dir.Y, dir.Z)
+			if !ok || hit.Thing == target || hit.Distance > wall.Distance+1e-6 {
+				t.Fatalf("the shot of %s at %s passed the wall at %f", shooter.GetId(), target.GetId(), wall.Distance)
+			}
+			if target.GetHealth() != health || target.IsDead() {
+				t.Fatalf("%s has been hit through a wall", target.GetId())
+			}
+		}
+	}
+	if hidden == 0 {
+		t.Fatal("no enemy hidden behind a wall")
+	}
+}
+
+// wallSegment returns the 2D segment spanned by the wall face.
+func wallSegment(face *model.Face) (geometry.XY, geometry.XY) {
+	points := face.GetPoints()
+	a := geometry.XY{X: points[0].X, Y: points[0].Y}
+	b, bestDist := a, 0.0
+	for _, p := range points[1:] {
+		if dist := geometry.DistanceSq(a, geometry.XY{X: p.X, Y: p.Y}); dist > bestDist {
+			b, bestDist = geometry.XY{X: p.X, Y: p.Y}, dist
+		}
+	}
+	return a, b
+}
+
+func TestShootSpecialNeedsTheStruckWall(t *testing.T) {
+	probe := newTestEngine(t, nil)
+	p := probe.GetPlayer()
+	x, y, z := p.GetEntity().GetCenter()
+	walls := model.NewQueryFilter(config.LayerProjectile, config.LayerAll)
+	walls.Things = false
+	dir := geometry.XYZ{X: math.Cos(p.GetAngle()), Y: math.Sin(p.GetAngle())}
+	hits := probe.RaycastAll(geometry.XYZ{X: x, Y: y, Z: z}, dir, 1e6, walls)
+	if len(hits) < 2 {
+		t.Fatalf("the player faces %d walls, want at least 2", len(hits))
+	}
+	nearStart, nearEnd := wallSegment(hits[0].Face)
+	farStart, farEnd := wallSegment(hits[1].Face)
+
+	// Lo special dietro il muro è il solo sulla traiettoria: un raggio che attraversa i muri lo attiverebbe
+	for _, near := range []bool{false, true} {
+		e := newTestEngine(t, func(cfg *config.Root) {
+			// Le decorazioni della cella di partenza avvolgono il player e fermerebbero lo sparo
+			cfg.Things = nil
+			// Le linee sono nelle unità della configurazione, prima della scala
+			sX, sY := cfg.ScaleFactor.X, cfg.ScaleFactor.Y
+			far := config.NewConfigTrigger("far", geometry.XY{X: farStart.X / sX, Y: farStart.Y / sY}, geometry.XY{X: farEnd.X / sX, Y: farEnd.Y / sY}, config.TriggerShoot, config.TriggerActionActivate)
+			cfg.Triggers = append(cfg.Triggers, far)
+			if near {
+				cfg.Triggers = append(cfg.Triggers, config.NewConfigTrigger("near", geometry.XY{X: nearStart.X / sX, Y: nearStart.Y / sY}, geometry.XY{X: nearEnd.X / sX, Y: nearEnd.Y / sY}, config.TriggerShoot, config.TriggerActionActivate))
+			}
+		})
+		e.GetPlayer().Fire()
+		stepEngine(e, 1)
+		if near && !e.GetSpecials().GetSpecial("near").IsFired() {
+			t.Fatal("the special of the struck wall has not been fired")
+		}
+		if e.GetSpecials().GetSpecial("far").IsFired() {
+			t.Fatal("the special behind the struck wall has been fired")
+		}
+	}
+}
+
+func TestProjectilesDoNotLeak(t *testing.T) {
+	const shots = 4000
+	e := newTestEngine(t, nil)
+	h := NewHeadless(0, nil)
+	if err := h.Setup(e); err != nil {
+		t.Fatal(err)
+	}
+	things := e.GetThings()
+	baseline := runtime.NumGoroutine() - things.CountEntities()
+	if err := h.LeakCheck(shots); err != nil {
+		t.Fatal(err)
+	}
+	if flying := things.GetProjectiles().Len(); flying != 0 {
+		t.Fatalf("%d projectiles still flying", flying)
+	}
+	if leaked := runtime.NumGoroutine() - things.CountEntities() - baseline; leaked > 0 {
+		t.Fatalf("%d goroutines leaked", leaked)
+	}
+	pool := things.GetPool()
+	created, reused := pool.GetStats()
+	if created+reused != shots {
+		t.Fatalf("the pool served %d throwables, want %d", created+reused, shots)
+	}
+	if reused == 0 || created >= shots/2 {
+		t.Fatalf("the pool created %d throwables and reused %d", created, reused)
+	}
+	if n := pool.Len(); n == 0 || uint64(n) > created {
+		t.Fatalf("the pool holds %d throwables, %d created", n, created)
+	}
+
+	// Un thing distrutto più volte chiude il suo done una volta sola
+	active, count := things.GetActive()
+	if count == 0 {
+		t.Fatal("no active thing")
+	}
+	thing := active[count-1]
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("destroying %s twice panicked: %v", thing.GetId(), r)
+			}
+		}()
+		thing.Destroy()
+		thing.Destroy()
+	}()
+	e.Close()
+	e.Close()
+}
@@ -0,0 +1,219 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"time"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+	"github.com/markel1974/godoom/mr_tech/model"
+)
+
+// leakBurst is the number of projectiles fired at each step by the leak check, spread around the player.
+// leakMaxSteps is the maximum number of steps the leak check waits for the projectiles to hit something.
+// leakWait is the time the leak check gives the goroutines of the removed things to stop.
+const (
+	leakBurst    = 64
+	leakMaxSteps = 3600
+	leakWait     = 2 * time.Second
+)
+
+// HeadlessInput represents the scripted player commands applied during a single headless simulation tick.
+type HeadlessInput struct {
+	Impulse float64
+	Up      bool
+	Down    bool
+	Left    bool
+	Right   bool
+	Yaw     float64
+	Pitch   float64
+	Jump    bool
+	Duck    bool
+	Fire    bool
+	Throw   bool
+	Switch  bool
+	Use     bool
+}
+
+// HeadlessScript returns the player input for the given tick, or nil when the player stays idle.
+type HeadlessScript func(tick int) *HeadlessInput
+
+// DefaultHeadlessScript walks the player forward, turning periodically, firing, switching weapon and using, to exercise physics, AI,
+// portals and line specials.
+func DefaultHeadlessScript(tick int) *HeadlessInput {
+	in := &HeadlessInput{Impulse: 0.06, Up: true}
+	switch {
+	case tick%240 < 20:
+		in.Yaw = 0.05
+	case tick%600 == 300:
+		in.Jump = true
+	case tick%120 == 60:
+		in.Fire = true
+	case tick%120 == 90:
+		in.Use = true
+	case tick%600 == 510:
+		in.Switch = true
+	}
+	return in
+}
+
+// Headless advances an Engine without any window or GL context, stepping the simulation for a fixed number of ticks.
+type Headless struct {
+	engine  *Engine
+	vi      *model.ViewMatrix
+	player  *model.ThingPlayer
+	script  HeadlessScript
+	ticks   int
+	tick    int
+	elapsed time.Duration
+}
+
+// NewHeadless creates a headless runner that executes the given number of ticks, feeding the player with the script.
+func NewHeadless(ticks int, script HeadlessScript) *Headless {
+	if script == nil {
+		script = DefaultHeadlessScript
+	}
+	return &Headless{
+		vi:     model.NewViewMatrix(),
+		script: script,
+		ticks:  ticks,
+		tick:   0,
+	}
+}
+
+// Setup binds the headless runner to an already configured Engine.
+func (h *Headless) Setup(engine *Engine) error {
+	if engine == nil || engine.GetPlayer() == nil {
+		return fmt.Errorf("headless: engine is not configured")
+	}
+	h.engine = engine
+	h.player = engine.GetPlayer()
+	return nil
+}
+
+// Start runs all the configured ticks and prints a short summary of the simulation.
+func (h *Headless) Start() {
+	for h.Step() {
+	}
+	x, y, z := h.player.GetEntity().GetCenter()
+	_, active := h.engine.GetThings().GetActive()
+	fmt.Printf("headless: %d ticks in %s, active things: %d, player at X: %f Y: %f Z: %f health: %.1f state: %016x\n", h.tick, h.elapsed, active, x, y, z, h.player.GetHealth(), h.engine.GetThings().StateHash())
+}
+
+// Step applies the scripted input for the current tick and advances the simulation, returning false when done.
+func (h *Headless) Step() bool {
+	if h.tick >= h.ticks {
+		return false
+	}
+	started := time.Now()
+	if in := h.script(h.tick); in != nil {
+		h.apply(in)
+	}
+	h.engine.Step(h.player, h.vi)
+	h.elapsed += time.Since(started)
+	h.tick++
+	return true
+}
+
+// GetTick returns the number of ticks executed so far.
+func (h *Headless) GetTick() int {
+	return h.tick
+}
+
+// GetViewMatrix returns the ViewMatrix updated by the last executed tick.
+func (h *Headless) GetViewMatrix() *model.ViewMatrix {
+	return h.vi
+}
+
+// LeakCheck fires shots projectiles of the first projectile weapon of the player, a burst around the player at each
+// step, and steps the simulation until every projectile is gone. The goroutines beside the ones of the things must
+// then be as many as before the shots: the removed projectiles must have stopped theirs. It prints the statistics of
+// the throwable pool and returns an error on a leak.
+func (h *Headless) LeakCheck(shots int) error {
+	things := h.engine.GetThings()
+	weapon, src := h.projectileWeapon()
+	if src == nil {
+		return fmt.Errorf("leak check: the player has no projectile weapon")
+	}
+	baseline := runtime.NumGoroutine() - things.CountEntities()
+	x, y, z := h.player.GetEntity().GetCenter()
+	pos := geometry.XYZ{X: x, Y: y, Z: z}
+	for fired := 0; fired < shots; {
+		burst := min(leakBurst, shots-fired)
+		for i := 0; i < burst; i++ {
+			angle := 2 * math.Pi * float64(fired+i) / leakBurst
+			projectile := &model.Projectile{Owner: h.player, DamageType: weapon.DamageType, Damage: weapon.Damage, Force: weapon.Force}
+			things.CreateProjectile(src, projectile, h.player.GetLocation(), pos, angle, 0, weapon.ProjectileSpeed)
+		}
+		fired += burst
+		h.engine.Step(h.player, h.vi)
+	}
+	for step := 0; things.GetProjectiles().Len() > 0 && step < leakMaxSteps; step++ {
+		h.engine.Step(h.player, h.vi)
+	}
+	// I proiettili fermati nell'ultimo passo escono dalla simulazione in quello successivo
+	h.engine.Step(h.player, h.vi)
+	leaked := runtime.NumGoroutine() - things.CountEntities() - baseline
+	for deadline := time.Now().Add(leakWait); leaked > 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+		leaked = runtime.NumGoroutine() - things.CountEntities() - baseline
+	}
+	flying := things.GetProjectiles().Len()
+	created, reused := things.GetPool().GetStats()
+	fmt.Printf("leak check: %d projectiles, %d still flying, %d throwables created, %d reused, %d goroutines leaked\n", shots, flying, created, reused, max(leaked, 0))
+	if flying > 0 {
+		return fmt.Errorf("leak check: %d projectiles never left the simulation", flying)
+	}
+	if leaked > 0 {
+		return fmt.Errorf("leak check: %d goroutines leaked", leaked)
+	}
+	return nil
+}
+
+// projectileWeapon returns the first projectile weapon of the player and the template of its projectile.
+func (h *Headless) projectileWeapon() (*config.Weapon, *config.Thing) {
+	for _, w := range h.player.GetArsenal().GetWeapons() {
+		cfg := w.GetConfig()
+		if cfg.Kind != config.WeaponProjectile {
+			continue
+		}
+		if cfg.Projectile != nil {
+			return cfg, cfg.Projectile
+		}
+		if src := h.engine.GetThings().GetConfigById(cfg.ProjectileId); src != nil {
+			return cfg, src
+		}
+	}
+	return nil, nil
+}
+
+// apply translates a HeadlessInput into the same player commands issued by the interactive renderers.
+func (h *Headless) apply(in *HeadlessInput) {
+	if in.Yaw != 0 {
+		h.player.AddAngle(in.Yaw)
+	}
+	if in.Pitch != 0 {
+		h.player.SetPitch(in.Pitch)
+	}
+	if in.Duck {
+		h.player.SetDucking()
+	}
+	if in.Jump {
+		h.player.SetJump(false)
+	}
+	h.player.Move(in.Impulse, in.Up, in.Down, in.Right, in.Left)
+	if in.Throw {
+		h.player.Throw()
+	}
+	if in.Switch {
+		h.player.NextWeapon()
+	}
+	if in.Fire {
+		h.player.Fire()
+	}
+	if in.Use {
+		h.player.Use()
+	}
+}
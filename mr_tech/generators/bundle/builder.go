@@ -0,0 +1,21 @@
+package bundle
+
+import (
+	"github.com/markel1974/godoom/mr_tech/config"
+	_ "github.com/markel1974/godoom/mr_tech/generators/common"
+)
+
+// Builder loads a compiled level previously saved as a self-contained IR bundle.
+type Builder struct {
+}
+
+// NewBuilder creates and returns a new instance of Builder.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Build loads the bundle at the given path. Behaviors are referenced by id in the IR and
+// resolved against the registry (populated by the common package) when the things are instantiated.
+func (b *Builder) Build(path string) (*config.Root, error) {
+	return config.LoadBundle(path)
+}
@@ -0,0 +1,84 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+// BehaviorPlayer is the registry id of the default player logic.
+// BehaviorEnemy is the registry id of the default enemy logic.
+// BehaviorItem is the registry id of the default item logic.
+const (
+	BehaviorPlayer = "player"
+	BehaviorEnemy  = "enemy"
+	BehaviorItem   = "item"
+)
+
+// behaviorParamActions is the enemy parameter holding the ordered MD1 action names.
+// behaviorParamWakeUpDistance is the enemy parameter holding the activation distance.
+const (
+	behaviorParamActions        = "actions"
+	behaviorParamWakeUpDistance = "wakeUpDistance"
+)
+
+func init() {
+	config.RegisterBehavior(BehaviorPlayer, newPlayerHandlers)
+	config.RegisterBehavior(BehaviorEnemy, newEnemyHandlers)
+	config.RegisterBehavior(BehaviorItem, newItemHandlers)
+
+	config.SetDefaultBehavior(config.ThingPlayerDef, BehaviorPlayer)
+	config.SetDefaultBehavior(config.ThingEnemyDef, BehaviorEnemy)
+	config.SetDefaultBehavior(config.ThingUnknownDef, BehaviorItem)
+	config.SetDefaultBehavior(config.ThingWeaponDef, BehaviorItem)
+	config.SetDefaultBehavior(config.ThingBulletDef, BehaviorItem)
+	config.SetDefaultBehavior(config.ThingThrowableDef, BehaviorItem)
+	config.SetDefaultBehavior(config.ThingKeyDef, BehaviorItem)
+	config.SetDefaultBehavior(config.ThingItemDef, BehaviorItem)
+}
+
+// NewPlayerBehavior returns the Behavior reference for the default player logic.
+func NewPlayerBehavior() *config.Behavior {
+	return config.NewConfigBehavior(BehaviorPlayer, nil)
+}
+
+// NewEnemyBehavior returns the Behavior reference for the default enemy logic with the given actions and wake-up distance.
+// When actions is nil the enemy falls back to the action definitions of its MD1 model, if any.
+func NewEnemyBehavior(actions []string, wakeUpDistance float64) *config.Behavior {
+	params := config.BehaviorParams{behaviorParamWakeUpDistance: wakeUpDistance}
+	if actions != nil {
+		params[behaviorParamActions] = actions
+	}
+	return config.NewConfigBehavior(BehaviorEnemy, params)
+}
+
+// NewItemBehavior returns the Behavior reference for the default item logic.
+func NewItemBehavior() *config.Behavior {
+	return config.NewConfigBehavior(BehaviorItem, nil)
+}
+
+// newPlayerHandlers instantiates the player logic for a single thing.
+func newPlayerHandlers(_ *config.Thing, _ config.BehaviorParams, _ int64) *config.BehaviorHandlers {
+	p := NewPlayer()
+	return &config.BehaviorHandlers{OnCollision: p.OnCollision, OnImpact: p.OnImpact}
+}
+
+// newEnemyHandlers instantiates the enemy logic for a single thing, reading actions and wake-up distance from params.
+func newEnemyHandlers(cfg *config.Thing, params config.BehaviorParams, seed int64) *config.BehaviorHandlers {
+	actions := params.GetStrings(behaviorParamActions)
+	if actions == nil && cfg.MD1 != nil {
+		actions = cfg.MD1.ActionDefinitions
+	}
+	e, err := NewEnemy(actions, params.GetFloat(behaviorParamWakeUpDistance, cfg.WakeUpDistance), seed)
+	if err != nil {
+		fmt.Printf("Warning enemy %s: %v\n", cfg.Id, err)
+		return nil
+	}
+	return &config.BehaviorHandlers{OnThinking: e.OnThinking, OnCollision: e.OnCollision, OnImpact: e.OnImpact, OnDeath: e.OnDeath}
+}
+
+// newItemHandlers instantiates the item logic for a single thing.
+func newItemHandlers(_ *config.Thing, _ config.BehaviorParams, _ int64) *config.BehaviorHandlers {
+	i := NewItem()
+	return &config.BehaviorHandlers{OnCollision: i.OnCollision, OnImpact: i.OnImpact}
+}
@@ -4,41 +4,50 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
-	"strings"
 
 	"github.com/markel1974/godoom/mr_tech/config"
 	"github.com/markel1974/godoom/mr_tech/geometry"
 )
 
-// Enemy represents an in-game enemy with activation, attack cooldown, and wake-up behavior based on distance.
+// enemyStates is the state table of the enemy: asleep until it perceives the player, then chasing it, interrupted by
+// the pain of the impacts, until its death.
+var enemyStates = []StateDef{
+	{Name: "idle"},
+	{Name: "awake"},
+	{Name: "chase", Parent: "awake", Action: "run"},
+	{Name: "pain", Parent: "awake", Action: "pain", Duration: 0.5, Next: "chase"},
+	{Name: "dead"},
+}
+
+// Enemy represents an in-game enemy driven by a state machine, with attack cooldown and wake-up behavior based on
+// perception.
 type Enemy struct {
-	active         bool
-	isDead         bool
-	health         float64 // Add health
+	machine        *StateMachine
+	perception     config.Perception
 	throwCooldown  float64
 	throwMin       float64
 	wakeUpDistance float64
-	actions        map[string]int
-	painCooldown   float64 // Timer to prevent pain animation spam
 }
 
-// NewEnemy creates and initializes a new Enemy instance with the specified wake-up distance.
-func NewEnemy(actions []string, wakeUpDistance float64) *Enemy {
+// NewEnemy creates and initializes a new Enemy instance with the specified wake-up distance. The throw interval is
+// drawn from a generator initialized with seed.
+func NewEnemy(actions []string, wakeUpDistance float64, seed int64) (*Enemy, error) {
 	const throwMin, throwMax = 5, 10
+	machine, err := NewStateMachine(actions, enemyStates)
+	if err != nil {
+		return nil, err
+	}
+	rnd := rand.New(rand.NewSource(seed))
 	e := &Enemy{
-		actions:        make(map[string]int),
-		active:         false,
-		isDead:         false,
-		health:         100.0, // Set default health
-		throwMin:       float64(rand.Intn(throwMax-throwMin+1) + throwMin),
+		machine:        machine,
+		throwMin:       float64(rnd.Intn(throwMax-throwMin+1) + throwMin),
 		throwCooldown:  0.0,
 		wakeUpDistance: wakeUpDistance,
-		painCooldown:   0.0,
-	}
-	for idx, action := range actions {
-		e.actions[strings.ToLower(strings.TrimSpace(action))] = idx
 	}
-	return e
+	e.machine.GetState("idle").AddTransition("chase", e.wakeUp)
+	e.machine.GetState("awake").OnEnter(func(config.IThingConfig) { e.throwCooldown = e.throwMin })
+	e.machine.GetState("chase").OnUpdate(e.chase)
+	return e, nil
 }
 
 // OnCollision is triggered when the enemy collides with another object, handling interaction logic between entities.
@@ -50,117 +59,114 @@ func (e *Enemy) OnCollision(self config.IThingConfig, other config.IThingConfig)
 	//fmt.Println("Enemy.OnCollision:", self.GetId(), otherId)
 }
 
+// OnImpact reacts to an impact already applied to the health of the thing: knockback, aggro and pain.
 func (e *Enemy) OnImpact(self config.IThingConfig, other config.IThingConfig, id string, force, closestDist, dirX, dirY, dirZ float64) {
-	if e.isDead {
+	if e.machine.In("dead") || self.IsDead() {
 		return // Dead enemies don't react to new impacts
 	}
 
-	// 1. Calculate and apply damage
-	// Assuming 'force' correlates to damage. Adjust the multiplier as needed.
-	damage := force * 0.5
-	e.health -= damage
-
-	// 2. Apply Knockback Physics
+	// 1. Apply Knockback Physics
 	// Push the enemy back along the impact direction vector
 	knockbackMultiplier := 50.0 // Adjust based on your physics scale
 	entity := self.GetEntity()
 	entity.AddForce(dirX*force*knockbackMultiplier, dirY*force*knockbackMultiplier, dirZ*force*knockbackMultiplier)
 
-	// 3. Instant Aggro
-	if !e.active {
-		e.active = true
-		e.throwCooldown = e.throwMin
-	}
-
-	// 4. Handle Death or Pain
-	if e.health <= 0 {
-		e.handleDeath(self)
-	} else {
-		e.handlePain(self)
+	// 2. Handle Pain, or Instant Aggro without a pain animation
+	if !e.handlePain(self) && !e.machine.In("awake") {
+		e.machine.Change(self, "chase")
 	}
 }
 
-func (e *Enemy) handleDeath(self config.IThingConfig) {
-	e.isDead = true
+// OnDeath stops the enemy logic: the death action, the drops and the corpse are handled by the health model.
+func (e *Enemy) OnDeath(self config.IThingConfig, killer config.IThingConfig, kind config.DamageType) {
+	e.machine.Change(self, "dead")
 	fmt.Println("ENEMY DEAD!!!!")
-
-	// Set death animation
-	if actionIdx, ok := e.actions["death"]; ok {
-		self.SetAction(actionIdx)
-	} else if actionIdx, ok = e.actions["die"]; ok {
-		self.SetAction(actionIdx)
-	}
-
-	// Optional: Disable collisions or change collision group so player walks over the corpse
-	// self.SetSolid(false)
 }
 
-func (e *Enemy) handlePain(self config.IThingConfig) {
+// handlePain moves the enemy to the pain state, reporting whether it is in pain.
+func (e *Enemy) handlePain(self config.IThingConfig) bool {
 	// Don't restart the pain animation if we are already in pain
-	if e.painCooldown > 0 {
-		return
+	if e.machine.In("pain") {
+		return true
 	}
 
-	fmt.Println("ENEMY IN PAIN!!!! Health:", e.health)
+	fmt.Println("ENEMY IN PAIN!!!! Health:", self.GetHealth())
 
-	// Set pain animation
-	if actionIdx, ok := e.actions["pain"]; ok {
-		self.SetAction(actionIdx)
-		e.painCooldown = 0.5 // Lock in pain state for 0.5 seconds
+	// Lock in pain state for the duration of the state
+	if !e.machine.HasAction("pain") {
+		return false
 	}
+	e.machine.Change(self, "pain")
+	return true
 }
 
-// OnThinking handles the logic for enemy behavior, including activation, movement, aiming, and attack decision-making.
-func (e *Enemy) OnThinking(self config.IThingConfig, playerX, playerY, playerZ float64) {
-	if e.isDead {
+// OnThinking advances the state machine of the enemy with what it perceives of the player.
+func (e *Enemy) OnThinking(self config.IThingConfig, perception config.Perception) {
+	if e.machine.In("dead") {
 		return // Stop thinking if dead
 	}
-
-	// Process pain timer
-	if e.painCooldown > 0 {
-		e.painCooldown -= 1.0 / 60.0 // Assuming 60 ticks per second
-		if e.painCooldown <= 0 {
-			// Pain finished, return to running/chasing
-			if actionIdx, ok := e.actions["run"]; ok {
-				self.SetAction(actionIdx)
-			}
-		}
-		return
+	if e.machine.Current() == "" {
+		e.machine.Start(self, "idle")
 	}
+	e.perception = perception
+	e.machine.Update(self, self.GetEntity().GetDt())
+}
+
+// wakeUp reports whether the enemy perceives the player: seen in front of it, or within the wake-up distance, or
+// heard.
+func (e *Enemy) wakeUp(self config.IThingConfig) bool {
+	p := e.perception
+	if p.Heard || p.InView {
+		return true
+	}
+	selfX, selfY, selfZ := self.GetEntity().GetBottomCenter()
+	dx := p.Target.X - selfX
+	dy := p.Target.Y - selfY
+	dz := p.Target.Z + (self.GetEntity().GetDepth() / 2) - selfZ
+	return p.Visible && math.Sqrt(dx*dx+dy*dy+dz*dz) < e.wakeUpDistance
+}
 
+// chase handles the logic of the awake enemy, including movement, aiming, and attack decision-making: it attacks
+// only with a clear line of sight.
+func (e *Enemy) chase(self config.IThingConfig) {
+	perception := e.perception
 	entity := self.GetEntity()
+	dt := entity.GetDt()
+	playerX, playerY, playerZ := perception.Target.X, perception.Target.Y, perception.Target.Z
 	// Il target Z deve essere circa a metà altezza del giocatore (es. petto) per mirare bene
 	targetZ := playerZ + (entity.GetDepth() / 2)
 	selfX, selfY, selfZ := entity.GetBottomCenter()
 	dx := playerX - selfX
 	dy := playerY - selfY
 	dz := targetZ - selfZ
-	// 1. Attivazione (Aggro): Utilizza la distanza sferica 3D
 	playerDist3d := math.Sqrt(dx*dx + dy*dy + dz*dz)
-	if !e.active {
-		if playerDist3d < e.wakeUpDistance { // Raggio di risveglio
-			e.active = true
-			e.throwCooldown = e.throwMin
-			action := e.actions["run"]
-			self.SetAction(action)
-		}
-		return
-	}
-	// Aggiornamento timer armi (assumendo dt fisso a 1/60)
+	// Aggiornamento timer armi (dt fisso del clock di simulazione)
 	if e.throwCooldown > 0 {
-		e.throwCooldown -= 1.0 / 60.0
+		e.throwCooldown -= dt
 	}
 	// Inseguimento Terrestre
 	playerDist2d := math.Sqrt(dx*dx + dy*dy)
 	if playerDist2d <= 0.001 {
 		return
 	}
-	// Aggiorniamo l'angolo del nemico affinché lo material o il modello si giri verso il bersaglio
+	// Il giocatore fuori dalla regione del nemico si raggiunge seguendo il percorso della navmesh
+	moveX, moveY, moveDist := dx, dy, playerDist2d
+	if path, ok := self.FindPath(playerX, playerY, playerZ); ok {
+		reached := entity.GetWidth() * 0.25
+		for _, waypoint := range path[:len(path)-1] {
+			wx, wy := waypoint.X-selfX, waypoint.Y-selfY
+			if wDist := math.Sqrt(wx*wx + wy*wy); wDist > reached {
+				moveX, moveY, moveDist = wx, wy, wDist
+				break
+			}
+		}
+	}
+	// Aggiorniamo l'angolo del nemico affinché lo material o il modello si giri verso la direzione di marcia
 	angle := math.Atan2(dy, dx)
-	self.SetAngle(angle)
-	invDist := 1.0 / playerDist2d
-	nx := dx * invDist
-	ny := dy * invDist
+	invDist := 1.0 / moveDist
+	nx := moveX * invDist
+	ny := moveY * invDist
+	self.SetAngle(math.Atan2(ny, nx))
 	impulse := 1.0
 	// Distanza di stop (es. somma dei raggi per non compenetrare)
 	stopDistance := (entity.GetWidth() * 0.5) + 5.0
@@ -179,8 +185,8 @@ func (e *Enemy) OnThinking(self config.IThingConfig, playerX, playerY, playerZ f
 	}
 
 	const throwableIndex = 2
-	const throwableSpeed = 100
-	if e.throwCooldown <= 0 && playerDist3d < 20.0 {
+	const throwableSpeed = 500
+	if e.throwCooldown <= 0 && playerDist3d < 20.0 && perception.Visible {
 		weaponForward := entity.GetWidth()
 		spawnX := selfX + (math.Cos(angle) * weaponForward)
 		spawnY := selfY + (math.Sin(angle) * weaponForward)
@@ -0,0 +1,251 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+// StateDef describes a state of a StateMachine as data, so that Doom STATES and Quake monster frame tables can be
+// written as lists of StateDef. Parent nests the state inside another one; Action names the animation action set when
+// the state is entered; a positive Duration moves the machine to Next when the time in the state runs out.
+type StateDef struct {
+	Name     string  `json:"name"`
+	Parent   string  `json:"parent"`
+	Action   string  `json:"action"`
+	Duration float64 `json:"duration"`
+	Next     string  `json:"next"`
+}
+
+// StateHook is the code bound to the enter, exit and update of a State.
+type StateHook func(self config.IThingConfig)
+
+// StateGuard is the condition of a Transition.
+type StateGuard func(self config.IThingConfig) bool
+
+// Transition moves the StateMachine to the state To when When holds.
+type Transition struct {
+	To   string
+	When StateGuard
+}
+
+// State is a state of a StateMachine, created from a StateDef and completed with its code: the hooks run when the
+// state is entered, left and updated, and the transitions are checked at each update.
+type State struct {
+	def         StateDef
+	parent      *State
+	action      int
+	onEnter     StateHook
+	onExit      StateHook
+	onUpdate    StateHook
+	transitions []Transition
+}
+
+// GetName returns the name of the State.
+func (s *State) GetName() string {
+	return s.def.Name
+}
+
+// GetParent returns the State containing the State, or nil for a top level state.
+func (s *State) GetParent() *State {
+	return s.parent
+}
+
+// OnEnter binds the hook run when the State is entered, after its action is set.
+func (s *State) OnEnter(hook StateHook) *State {
+	s.onEnter = hook
+	return s
+}
+
+// OnExit binds the hook run when the State is left.
+func (s *State) OnExit(hook StateHook) *State {
+	s.onExit = hook
+	return s
+}
+
+// OnUpdate binds the hook run at each update while the State, or one of its children, is active.
+func (s *State) OnUpdate(hook StateHook) *State {
+	s.onUpdate = hook
+	return s
+}
+
+// AddTransition adds a transition to the state to, taken at an update when guard holds. The transitions of a child
+// are checked before the ones of its parents, in the order they were added.
+func (s *State) AddTransition(to string, guard StateGuard) *State {
+	s.transitions = append(s.transitions, Transition{To: to, When: guard})
+	return s
+}
+
+// isChildOf reports whether the State is nested, at any depth, inside parent.
+func (s *State) isChildOf(parent *State) bool {
+	for p := s.parent; p != nil; p = p.parent {
+		if p == parent {
+			return true
+		}
+	}
+	return false
+}
+
+// update runs the update hooks of the parents of the State, from the outermost, and then its own.
+func (s *State) update(self config.IThingConfig) {
+	if s.parent != nil {
+		s.parent.update(self)
+	}
+	if s.onUpdate != nil {
+		s.onUpdate(self)
+	}
+}
+
+// StateMachine is a hierarchical finite state machine driving the behavior of a thing. A state nested in a parent
+// shares its hooks and its transitions: the parents are entered before and left after their children, updated before
+// them, and their transitions are checked after the ones of the children. The actions of the states are resolved by
+// name once, against the ordered action names of the thing.
+type StateMachine struct {
+	actions   map[string]int
+	states    map[string]*State
+	current   *State
+	remaining float64
+}
+
+// NewStateMachine creates a StateMachine with the states described by defs, resolving their actions against the
+// ordered action names. Parents must be described before their children. It returns an error when a state is
+// duplicated or references an unknown parent or Next state.
+func NewStateMachine(actions []string, defs []StateDef) (*StateMachine, error) {
+	m := &StateMachine{
+		actions: make(map[string]int),
+		states:  make(map[string]*State),
+	}
+	for idx, action := range actions {
+		m.actions[strings.ToLower(strings.TrimSpace(action))] = idx
+	}
+	for _, def := range defs {
+		if _, err := m.AddState(def); err != nil {
+			return nil, err
+		}
+	}
+	for _, def := range defs {
+		if def.Next != "" && m.states[def.Next] == nil {
+			return nil, fmt.Errorf("state %s: unknown next state %s", def.Name, def.Next)
+		}
+	}
+	return m, nil
+}
+
+// AddState adds the state described by def and returns it, to bind its code. It returns an error when the state
+// already exists or its parent does not; an unknown action leaves the current action of the thing untouched.
+func (m *StateMachine) AddState(def StateDef) (*State, error) {
+	if _, ok := m.states[def.Name]; ok {
+		return nil, fmt.Errorf("state %s already exists", def.Name)
+	}
+	s := &State{def: def, action: -1}
+	if idx, ok := m.actions[strings.ToLower(strings.TrimSpace(def.Action))]; ok && def.Action != "" {
+		s.action = idx
+	}
+	if def.Parent != "" {
+		if s.parent = m.states[def.Parent]; s.parent == nil {
+			return nil, fmt.Errorf("state %s: unknown parent state %s", def.Name, def.Parent)
+		}
+	}
+	m.states[def.Name] = s
+	return s, nil
+}
+
+// GetState returns the State with the given name, or nil if it does not exist.
+func (m *StateMachine) GetState(name string) *State {
+	return m.states[name]
+}
+
+// HasAction reports whether the action with the given name is defined for the thing.
+func (m *StateMachine) HasAction(name string) bool {
+	_, ok := m.actions[strings.ToLower(strings.TrimSpace(name))]
+	return ok
+}
+
+// Current returns the name of the active state, or an empty string before Start.
+func (m *StateMachine) Current() string {
+	if m.current == nil {
+		return ""
+	}
+	return m.current.def.Name
+}
+
+// In reports whether the state with the given name, or one of its children, is active.
+func (m *StateMachine) In(name string) bool {
+	for s := m.current; s != nil; s = s.parent {
+		if s.def.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Start enters the state with the given name, and its parents, without leaving any state.
+func (m *StateMachine) Start(self config.IThingConfig, name string) {
+	m.current = nil
+	m.Change(self, name)
+}
+
+// Change moves the machine to the state with the given name: the active states that are not parents of the target
+// are left, from the innermost, and the target and its parents not yet active are entered, from the outermost.
+// Changing to the active state re-enters it. Change panics on an unknown state: the transitions are code, and a
+// wrong target is a bug of the behavior.
+func (m *StateMachine) Change(self config.IThingConfig, name string) {
+	target, ok := m.states[name]
+	if !ok {
+		panic(fmt.Sprintf("state machine: unknown state %s", name))
+	}
+	// Antenato comune: gli stati attivi che contengono anche il bersaglio restano attivi
+	common := m.current
+	for ; common != nil; common = common.parent {
+		if common != target && target.isChildOf(common) {
+			break
+		}
+	}
+	for s := m.current; s != common; s = s.parent {
+		if s.onExit != nil {
+			s.onExit(self)
+		}
+	}
+	var entering []*State
+	for s := target; s != common; s = s.parent {
+		entering = append(entering, s)
+	}
+	m.current = target
+	m.remaining = target.def.Duration
+	for x := len(entering) - 1; x >= 0; x-- {
+		s := entering[x]
+		if s.action >= 0 {
+			self.SetAction(s.action)
+		}
+		if s.onEnter != nil {
+			s.onEnter(self)
+		}
+	}
+}
+
+// Update advances the active state by dt. A timed state whose time runs out moves to its Next state; otherwise the
+// first transition whose guard holds, from the innermost state outwards, is taken. Without a change of state the
+// update hooks run, from the outermost state inwards. Update reports whether the state changed.
+func (m *StateMachine) Update(self config.IThingConfig, dt float64) bool {
+	if m.current == nil {
+		return false
+	}
+	if m.current.def.Duration > 0 {
+		m.remaining -= dt
+		if m.remaining <= 0 && m.current.def.Next != "" {
+			m.Change(self, m.current.def.Next)
+			return true
+		}
+	}
+	for s := m.current; s != nil; s = s.parent {
+		for _, t := range s.transitions {
+			if t.When(self) {
+				m.Change(self, t.To)
+				return true
+			}
+		}
+	}
+	m.current.update(self)
+	return false
+}
@@ -0,0 +1,104 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+func TestStateMachineRejectsUnknownStates(t *testing.T) {
+	tests := []struct {
+		name string
+		defs []StateDef
+		want string
+	}{
+		{"parent", []StateDef{{Name: "chase", Parent: "awake"}}, "unknown parent"},
+		{"parent after child", []StateDef{{Name: "chase", Parent: "awake"}, {Name: "awake"}}, "unknown parent"},
+		{"next", []StateDef{{Name: "pain", Duration: 1, Next: "chase"}}, "unknown next"},
+		{"duplicate", []StateDef{{Name: "idle"}, {Name: "idle"}}, "already exists"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := NewStateMachine(nil, test.defs)
+			if err == nil || m != nil {
+				t.Fatal("the invalid state table has been accepted")
+			}
+			if !strings.Contains(err.Error(), test.want) {
+				t.Fatalf("error %q, want %q", err, test.want)
+			}
+		})
+	}
+	if _, err := NewStateMachine(nil, enemyStates); err != nil {
+		t.Fatalf("the enemy state table is invalid: %v", err)
+	}
+}
+
+func TestStateMachineUnknownTargetPanics(t *testing.T) {
+	m, err := NewStateMachine(nil, []StateDef{{Name: "idle"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.GetState("idle").AddTransition("chase", func(config.IThingConfig) bool { return true })
+	m.Start(nil, "idle")
+	defer func() {
+		if recover() == nil {
+			t.Fatal("the transition to an unknown state did not panic")
+		}
+	}()
+	m.Update(nil, 0.1)
+}
+
+func TestStateMachineHierarchy(t *testing.T) {
+	m, err := NewStateMachine(nil, []StateDef{
+		{Name: "idle"},
+		{Name: "awake"},
+		{Name: "chase", Parent: "awake"},
+		{Name: "pain", Parent: "awake", Duration: 0.5, Next: "chase"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log []string
+	for _, name := range []string{"idle", "awake", "chase", "pain"} {
+		name := name
+		m.GetState(name).
+			OnEnter(func(config.IThingConfig) { log = append(log, "+"+name) }).
+			OnExit(func(config.IThingConfig) { log = append(log, "-"+name) })
+	}
+	woken := false
+	m.GetState("idle").AddTransition("chase", func(config.IThingConfig) bool { return woken })
+	check := func(want ...string) {
+		t.Helper()
+		if got := strings.Join(log, " "); got != strings.Join(want, " ") {
+			t.Fatalf("hooks %q, want %q", got, strings.Join(want, " "))
+		}
+		log = nil
+	}
+
+	m.Start(nil, "idle")
+	check("+idle")
+	if m.Update(nil, 0.1) {
+		t.Fatal("the machine changed state without a transition")
+	}
+	woken = true
+	if !m.Update(nil, 0.1) || m.Current() != "chase" || !m.In("awake") {
+		t.Fatalf("state %s after the wake up, want chase inside awake", m.Current())
+	}
+	// Il genitore viene attivato prima del figlio
+	check("-idle", "+awake", "+chase")
+
+	// Lo stato fratello lascia attivo il genitore comune
+	m.Change(nil, "pain")
+	check("-chase", "+pain")
+	if m.Update(nil, 0.3) || m.Current() != "pain" {
+		t.Fatalf("state %s before the end of the pain, want pain", m.Current())
+	}
+	if !m.Update(nil, 0.3) || m.Current() != "chase" {
+		t.Fatalf("state %s after the pain, want chase", m.Current())
+	}
+	check("-pain", "+chase")
+
+	m.Change(nil, "idle")
+	check("-chase", "-awake", "+idle")
+}
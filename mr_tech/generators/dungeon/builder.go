@@ -102,9 +102,7 @@ func (b *Builder) createCube(x float64, y float64, max float64, floor float64, c
 // GenerateSimple creates a new game configuration with sectors, a player, and randomized structures based on grid dimensions.
 func (b *Builder) generateSimple(t *Textures, maxX int, maxY int) (*config.Root, error) {
 	player := config.NewConfigPlayer(geometry.XYZ{}, 0, 20, 90, 1, 10)
-	playerLogic := common.NewPlayer()
-	player.OnCollision = playerLogic.OnCollision
-	player.OnImpact = playerLogic.OnImpact
+	player.Behavior = common.NewPlayerBehavior()
 	cal := config.NewConfigCalibration(0, 0, 0, 0, 0, 0, true)
 	scaleFactor := geometry.XYZ{X: 1, Y: 1, Z: 1}
 	cfg := config.NewConfigRoot(cal, nil, player, nil, scaleFactor, t)
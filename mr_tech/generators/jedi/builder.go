@@ -115,16 +115,14 @@ func (b *Builder) Build(mode int, dir string, levelNumber int) (*config.Root, er
 	}
 	globalVertices := make(geometry.Polygon, 0, totalVertices)
 	var lights []*config.Light
+	built := make(map[string]*config.Sector)
 
 	for _, sector := range level.Sectors {
 		if len(sector.Id) == 0 {
 			continue
 		}
 
-		lightLevel := sector.LightLevel * scaleLight
-		if lightLevel < 2.2 {
-			lightLevel = 2.2
-		}
+		lightLevel := sectorLight(sector.LightLevel)
 		lightFalloff := lightLevel * scaleLightFalloff
 
 		cSector := config.NewConfigSector(sector.Id, lightLevel, config.LightKindAmbient, lightFalloff)
@@ -231,6 +229,35 @@ func (b *Builder) Build(mode int, dir string, levelNumber int) (*config.Root, er
 			cSector.SlopedCeilingGradient = sector.SlopedCeiling.GetGradient()
 		}
 		configSectors = append(configSectors, cSector)
+		built[sector.Id] = cSector
+	}
+
+	var elevators []*config.Elevator
+	var triggers []*config.Trigger
+	var triggerVolumes []*config.TriggerVolume
+	var infDiags config.Diagnostics
+	if data, err := archive.GetPayload(level.LevelName + ".INF"); err != nil {
+		fmt.Printf("Warning: could not load INF %s: %v\n", level.LevelName, err)
+	} else {
+		inf := NewInf()
+		if err = inf.Parse(bytes.NewReader(data)); err != nil {
+			fmt.Printf("Error parsing INF %s: %v\n", level.LevelName, err)
+		} else {
+			elevators, triggers, triggerVolumes = b.buildInf(inf, level, built)
+		}
+		infDiags = inf.Diagnostics
+	}
+	for _, sector := range level.Sectors {
+		if sector == nil || !sector.IsSecret() {
+			continue
+		}
+		if _, ok := built[sector.Id]; !ok {
+			continue
+		}
+		tv := config.NewConfigSectorTriggerVolume("secret_"+sector.Id, sector.Id, config.TriggerVolumeSecret)
+		tv.PlayerOnly = true
+		tv.Once = true
+		triggerVolumes = append(triggerVolumes, tv)
 	}
 
 	var configThings []*config.Thing
@@ -245,7 +272,7 @@ func (b *Builder) Build(mode int, dir string, levelNumber int) (*config.Root, er
 		switch key {
 		case "SPIRIT", "PLAYER":
 			if configPlayer == nil {
-				configPlayer = b.buildPlayer(pos)
+				configPlayer = b.buildPlayer(pos, archive)
 			}
 		case "STARTPOS": //TODO
 		case "BALLSTAR": //TODO
@@ -254,37 +281,16 @@ func (b *Builder) Build(mode int, dir string, levelNumber int) (*config.Root, er
 		case "SIGNODD2": //TODO
 		case "GBPLATE": //TODO
 		default:
-			data, err := archive.GetPayload(obj.Name + ".ITM")
+			cThing, err := b.ItemToThing(obj.Name, archive, pos)
 			if err != nil {
-				fmt.Printf("Warning: could not load ITM %s: %v\n", obj.Name, err)
+				fmt.Printf("Warning: %v\n", err)
 				continue
 			}
-			item := NewItem()
-			err = item.Parse(bytes.NewReader(data))
-			if err != nil {
-				fmt.Printf("Error parsing ITM %s: %v\n", obj.Name, err)
+			if cThing == nil {
 				continue
 			}
-			if len(item.Anim) == 0 {
-				continue
-			}
-			targetName := strings.ToUpper(item.Anim)
-			if strings.Contains(targetName, ".NWX") {
-				cThing, err := b.NWXToThing(item.Anim, archive, pos)
-				if err != nil {
-					fmt.Printf("Error parsing %s: %v\n", item.Anim, err)
-					continue
-				}
-				configThings = append(configThings, cThing)
-			} else if strings.Contains(targetName, ".3DO") {
-				cThing, err := b.ThreedoToThing(targetName, pos, archive)
-				if err != nil {
-					fmt.Printf("Error parsing %s: %v\n", item.Anim, err)
-					continue
-				}
-				configThings = append(configThings, cThing)
-			}
-
+			cThing.Pickups = buildPickups(key)
+			configThings = append(configThings, cThing)
 		}
 	}
 
@@ -300,7 +306,7 @@ func (b *Builder) Build(mode int, dir string, levelNumber int) (*config.Root, er
 		switch key {
 		case "SPIRIT", "PLAYER":
 			if configPlayer == nil {
-				configPlayer = b.buildPlayer(pos)
+				configPlayer = b.buildPlayer(pos, archive)
 			}
 		case "SPRITE":
 			dataIdx, _ := strconv.Atoi(obj.Data)
@@ -346,16 +352,30 @@ func (b *Builder) Build(mode int, dir string, levelNumber int) (*config.Root, er
 	cr.Things = configThings
 	cr.Vertices = globalVertices
 	cr.Lights = lights
+	cr.Elevators = elevators
+	cr.Triggers = triggers
+	cr.TriggerVolumes = triggerVolumes
+	cr.Report(infDiags...)
 
 	return cr, nil
 }
 
-// buildPlayer initializes and returns a configured Player instance with specified position and predefined attributes.
-func (b *Builder) buildPlayer(pos geometry.XYZ) *config.Player {
+// sectorLight converts a Dark Forces light level into the light intensity of a sector.
+func sectorLight(level float64) float64 {
+	lightLevel := level * scaleLight
+	if lightLevel < 2.2 {
+		lightLevel = 2.2
+	}
+	return lightLevel
+}
+
+// buildPlayer initializes and returns a configured Player instance with specified position and predefined attributes,
+// armed with the Dark Forces arsenal.
+func (b *Builder) buildPlayer(pos geometry.XYZ, archive IArchive) *config.Player {
 	player := config.NewConfigPlayer(pos, 1.0, playerMass, playerSpeed, playerRadius, playerHeight)
-	playerLogic := common.NewPlayer()
-	player.OnCollision = playerLogic.OnCollision
-	player.OnImpact = playerLogic.OnImpact
+	player.Behavior = common.NewPlayerBehavior()
+	player.Inventory = buildInventory()
+	player.Weapons = b.buildWeapons(archive)
 	player.GForce = gForce
 	player.JumpForce = 1000
 
@@ -394,19 +414,41 @@ func (b *Builder) createConfigThing(classname string, pos geometry.XYZ, kind con
 		if thingCfg.MD1 != nil {
 			actions = thingCfg.MD1.ActionDefinitions
 		}
-		enemyLogic := common.NewEnemy(actions, 300)
-		thingCfg.OnThinking = enemyLogic.OnThinking
-		thingCfg.OnCollision = enemyLogic.OnCollision
-		thingCfg.OnImpact = enemyLogic.OnImpact
+		thingCfg.Behavior = common.NewEnemyBehavior(actions, 300)
 		thingCfg.WakeUpDistance = 400
 	} else {
-		itemLogic := common.NewItem()
-		thingCfg.OnCollision = itemLogic.OnCollision
-		thingCfg.OnImpact = itemLogic.OnImpact
+		thingCfg.Behavior = common.NewItemBehavior()
 	}
 	return thingCfg
 }
 
+// ItemToThing converts the animation of the named ITM item into a Thing object at the given position. It returns nil
+// when the item has no NWX or 3DO animation.
+func (b *Builder) ItemToThing(name string, archive IArchive, pos geometry.XYZ) (*config.Thing, error) {
+	data, err := archive.GetPayload(name + ".ITM")
+	if err != nil {
+		return nil, fmt.Errorf("could not load ITM %s: %v", name, err)
+	}
+	item := NewItem()
+	if err = item.Parse(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("error parsing ITM %s: %v", name, err)
+	}
+	if len(item.Anim) == 0 {
+		return nil, nil
+	}
+	targetName := strings.ToUpper(item.Anim)
+	var cThing *config.Thing
+	if strings.Contains(targetName, ".NWX") {
+		cThing, err = b.NWXToThing(item.Anim, archive, pos)
+	} else if strings.Contains(targetName, ".3DO") {
+		cThing, err = b.ThreedoToThing(targetName, pos, archive)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", item.Anim, err)
+	}
+	return cThing, nil
+}
+
 // ThreedoToThing converts a 3DO model file into a Thing object using the provided position and archive for resources.
 func (b *Builder) ThreedoToThing(fileName string, pos geometry.XYZ, archive IArchive) (*config.Thing, error) {
 	threedoData, err := archive.GetPayload(fileName)
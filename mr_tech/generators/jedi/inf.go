@@ -0,0 +1,281 @@
+package jedi
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+// InfItemSector identifies an INF item attached to a sector.
+// InfItemLine identifies an INF item attached to a wall of a sector.
+// InfItemLevel identifies an INF item attached to the whole level.
+const (
+	InfItemSector = "sector"
+	InfItemLine   = "line"
+	InfItemLevel  = "level"
+)
+
+// InfClassElevator identifies an INF class animating sectors.
+// InfClassTrigger identifies an INF class sending messages to its clients.
+// InfClassTeleporter identifies an INF class moving the player to another sector.
+const (
+	InfClassElevator   = "elevator"
+	InfClassTrigger    = "trigger"
+	InfClassTeleporter = "teleporter"
+)
+
+// InfMessage represents a message sent to Target when the Stop of an elevator is reached.
+type InfMessage struct {
+	Stop    int
+	Target  string
+	Message string
+	Params  []string
+}
+
+// InfStop represents a stop of an INF elevator: a value (absolute, @relative or a sector name) and an action
+// (wait seconds, hold, terminate or complete).
+type InfStop struct {
+	Value    string
+	Action   string
+	Messages []*InfMessage
+}
+
+// InfClass represents a class declaration inside an INF item sequence, with its properties.
+type InfClass struct {
+	Class     string
+	SubClass  string
+	Stops     []*InfStop
+	Speed     float64
+	Start     int
+	Slaves    []string
+	Clients   []string
+	Message   string
+	Params    []string
+	EventMask int
+	Key       string
+	Master    bool
+}
+
+// NewInfClass creates an InfClass with the given class and subclass and the Dark Forces defaults.
+func NewInfClass(class string, subClass string) *InfClass {
+	return &InfClass{
+		Class:     class,
+		SubClass:  subClass,
+		Speed:     -1,
+		EventMask: -1,
+		Master:    true,
+	}
+}
+
+// InfItem represents an item of an INF file: the sector (or the Num wall of the sector) Name and its classes.
+type InfItem struct {
+	Kind    string
+	Name    string
+	Num     int
+	Classes []*InfClass
+}
+
+// Inf represents a parsed Dark Forces INF script. Diagnostics lists, once per keyword, the properties of the script
+// that have been ignored.
+type Inf struct {
+	Version     string
+	LevelName   string
+	Items       []*InfItem
+	Diagnostics config.Diagnostics
+	ignored     map[string][]string
+}
+
+// NewInf creates and returns an empty Inf.
+func NewInf() *Inf {
+	return &Inf{ignored: make(map[string][]string)}
+}
+
+// Parse reads an INF script from the reader. Unsupported and unknown properties are ignored, and reported as warning
+// diagnostics.
+func (inf *Inf) Parse(r io.Reader) error {
+	var item *InfItem
+	var class *InfClass
+	inSeq := false
+	comment := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		line, comment = infStripComments(line, comment)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, prop := range infProperties(strings.Fields(line)) {
+			key, values := prop[0], prop[1:]
+			switch key {
+			case "INF":
+				inf.Version, _ = GetTokenStringAt(values, 0)
+			case "LEVELNAME":
+				inf.LevelName, _ = GetTokenStringAt(values, 0)
+			case "ITEMS":
+			case "ITEM:":
+				kind, _ := GetTokenStringAt(values, 0)
+				item = &InfItem{Kind: strings.ToLower(kind), Num: -1}
+				inf.Items = append(inf.Items, item)
+				class = nil
+			case "NAME:":
+				if item != nil {
+					item.Name, _ = GetTokenStringAt(values, 0)
+				}
+			case "NUM:":
+				if item != nil {
+					item.Num, _ = GetTokenIntAt(values, 0)
+				}
+			case "SEQ":
+				inSeq = true
+			case "SEQEND":
+				inSeq = false
+				item, class = nil, nil
+			default:
+				if !inSeq || item == nil {
+					fmt.Printf("Warning: INF property %s outside of a sequence\n", key)
+					continue
+				}
+				var err error
+				if class, err = inf.parseProperty(item, class, key, values); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	inf.report()
+	return scanner.Err()
+}
+
+// ignore records a property of the item that the parser does not support.
+func (inf *Inf) ignore(item *InfItem, key string) {
+	inf.ignored[key] = append(inf.ignored[key], item.Name)
+}
+
+// report turns the ignored properties into warning diagnostics, one for each keyword.
+func (inf *Inf) report() {
+	keys := make([]string, 0, len(inf.ignored))
+	for key := range inf.ignored {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		items := inf.ignored[key]
+		inf.Diagnostics = append(inf.Diagnostics, &config.Diagnostic{
+			Severity: config.DiagnosticWarning,
+			Code:     config.DiagSourceUnsupported,
+			Id:       items[0],
+			Message:  fmt.Sprintf("INF property %s is not supported, ignored %d times", key, len(items)),
+		})
+	}
+	clear(inf.ignored)
+}
+
+// parseProperty applies a property of a sequence to the current class, returning the (possibly new) current class.
+func (inf *Inf) parseProperty(item *InfItem, class *InfClass, key string, values []string) (*InfClass, error) {
+	if key == "CLASS:" {
+		c, _ := GetTokenStringAt(values, 0)
+		sc, _ := GetTokenStringAt(values, 1)
+		class = NewInfClass(strings.ToLower(c), strings.ToLower(sc))
+		item.Classes = append(item.Classes, class)
+		return class, nil
+	}
+	if class == nil {
+		return nil, fmt.Errorf("INF item %s: property %s before class", item.Name, key)
+	}
+	switch key {
+	case "STOP:":
+		value, _ := GetTokenStringAt(values, 0)
+		action, _ := GetTokenStringAt(values, 1)
+		class.Stops = append(class.Stops, &InfStop{Value: value, Action: strings.ToLower(action)})
+	case "SPEED:":
+		class.Speed, _ = GetTokenFloatAt(values, 0)
+	case "START:":
+		class.Start, _ = GetTokenIntAt(values, 0)
+	case "SLAVE:":
+		if v, err := GetTokenStringAt(values, 0); err == nil {
+			class.Slaves = append(class.Slaves, v)
+		}
+	case "CLIENT:":
+		if v, err := GetTokenStringAt(values, 0); err == nil {
+			class.Clients = append(class.Clients, v)
+		}
+	case "MESSAGE:":
+		if class.Class == InfClassElevator {
+			// message: <stop> <target> <message> [params]
+			stop, err := GetTokenIntAt(values, 0)
+			if err != nil || len(values) < 3 {
+				fmt.Printf("Warning: invalid INF stop message %v\n", values)
+				return class, nil
+			}
+			if stop < 0 || stop >= len(class.Stops) {
+				fmt.Printf("Warning: INF message for unknown stop %d of %s\n", stop, item.Name)
+				return class, nil
+			}
+			m := &InfMessage{Stop: stop, Target: values[1], Message: strings.ToLower(values[2]), Params: values[3:]}
+			class.Stops[stop].Messages = append(class.Stops[stop].Messages, m)
+			return class, nil
+		}
+		if len(values) > 0 {
+			class.Message = strings.ToLower(values[0])
+			class.Params = values[1:]
+		}
+	case "EVENT_MASK:":
+		if mask, err := GetTokenIntAt(values, 0); err == nil {
+			class.EventMask = mask
+		}
+	case "KEY:":
+		class.Key, _ = GetTokenStringAt(values, 0)
+	case "MASTER:":
+		v, _ := GetTokenStringAt(values, 0)
+		class.Master = !strings.EqualFold(v, "off")
+	default:
+		// Suoni, flag, rotazioni, teletrasporti e testi non hanno ancora un equivalente nel motore
+		inf.ignore(item, key)
+	}
+	return class, nil
+}
+
+// infProperties splits the tokens of a line into properties: each keyword (the first token, a "key:" token, seq or
+// seqend) followed by its values. A line may contain several properties, as "item: sector name: door1 seq".
+func infProperties(tokens []string) [][]string {
+	var out [][]string
+	for _, token := range tokens {
+		upper := strings.ToUpper(token)
+		switch {
+		case len(out) == 0, strings.HasSuffix(upper, ":"), upper == "SEQ", upper == "SEQEND":
+			out = append(out, []string{upper})
+		default:
+			out[len(out)-1] = append(out[len(out)-1], token)
+		}
+	}
+	return out
+}
+
+// infStripComments removes the /* */ comments from the line, reporting whether a comment is still open at the end.
+func infStripComments(line string, comment bool) (string, bool) {
+	var sb strings.Builder
+	for len(line) > 0 {
+		if comment {
+			end := strings.Index(line, "*/")
+			if end < 0 {
+				return strings.TrimSpace(sb.String()), true
+			}
+			line, comment = line[end+2:], false
+			continue
+		}
+		start := strings.Index(line, "/*")
+		if start < 0 {
+			sb.WriteString(line)
+			break
+		}
+		sb.WriteString(line[:start])
+		sb.WriteString(" ")
+		line, comment = line[start+2:], true
+	}
+	return strings.TrimSpace(sb.String()), comment
+}
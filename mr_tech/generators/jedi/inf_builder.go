@@ -0,0 +1,401 @@
+package jedi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// infEventCrossFront, infEventCrossBack fire when the player crosses a line.
+// infEventEnter fires when the player enters a sector.
+// infEventLeave fires when the player leaves a sector.
+// infEventNudgeFront, infEventNudgeBack fire when the player presses use against a line or a sector.
+// infEventExplosion fires on an explosion.
+// infEventShoot fires when the player shoots a line.
+const (
+	infEventCrossFront = 1
+	infEventCrossBack  = 2
+	infEventEnter      = 4
+	infEventLeave      = 8
+	infEventNudgeFront = 16
+	infEventNudgeBack  = 32
+	infEventExplosion  = 64
+	infEventShoot      = 256
+)
+
+// infDefaultSpeed is the speed (INF units per second) of the elevators without a speed property.
+// infDoorWait is the time (seconds) a door elevator stays open.
+const (
+	infDefaultSpeed = 20.0
+	infDoorWait     = 4.0
+)
+
+// infBuilder converts the items of an INF script into elevators and triggers of the configuration.
+type infBuilder struct {
+	level     *Level
+	sectors   map[string]*config.Sector
+	names     map[string][]string
+	nudges    map[string][]string
+	keys      map[string]string
+	elevators []*config.Elevator
+	triggers  []*config.Trigger
+	volumes   []*config.TriggerVolume
+}
+
+// buildInf converts the INF script into the elevators, triggers and trigger volumes of the level. sectors maps the
+// sector ids to the built configuration sectors, whose segments are tagged when a wall is a switch.
+func (b *Builder) buildInf(inf *Inf, level *Level, sectors map[string]*config.Sector) ([]*config.Elevator, []*config.Trigger, []*config.TriggerVolume) {
+	ib := &infBuilder{
+		level:   level,
+		sectors: sectors,
+		names:   make(map[string][]string),
+		nudges:  make(map[string][]string),
+		keys:    make(map[string]string),
+	}
+	// I messaggi possono riferirsi a elevatori definiti più avanti: prima si assegnano gli id
+	for _, item := range inf.Items {
+		if item.Kind != InfItemSector {
+			continue
+		}
+		for k, class := range item.Classes {
+			if class.Class == InfClassElevator {
+				key := strings.ToLower(item.Name)
+				ib.names[key] = append(ib.names[key], infElevatorId(item.Name, k))
+			}
+		}
+	}
+	for _, item := range inf.Items {
+		for k, class := range item.Classes {
+			switch class.Class {
+			case InfClassElevator:
+				if item.Kind == InfItemSector {
+					ib.buildElevator(item, k, class)
+				}
+			case InfClassTrigger:
+				ib.buildTrigger(item, k, class)
+			default:
+				fmt.Printf("Warning: unsupported INF class %s %s for %s\n", class.Class, class.SubClass, item.Name)
+			}
+		}
+	}
+	ib.buildNudges()
+	return ib.elevators, ib.triggers, ib.volumes
+}
+
+// infElevatorId returns the identifier of the k-th class of the sector item.
+func infElevatorId(name string, k int) string {
+	return fmt.Sprintf("inf_%s_%d", name, k)
+}
+
+// targets resolves an INF client or message target (a sector name, or "name(wall)" for a line) into elevator ids.
+func (ib *infBuilder) targets(name string) []string {
+	if idx := strings.Index(name, "("); idx >= 0 {
+		name = name[:idx]
+	}
+	return ib.names[strings.ToLower(name)]
+}
+
+// buildElevator converts the k-th elevator class of a sector item.
+func (ib *infBuilder) buildElevator(item *InfItem, k int, class *InfClass) {
+	masters := ib.level.GetSectorsByName(item.Name)
+	if len(masters) == 0 {
+		fmt.Printf("Warning: INF elevator %s references an unknown sector\n", item.Name)
+		return
+	}
+	sectors := masters
+	for _, slave := range class.Slaves {
+		sectors = append(sectors, ib.level.GetSectorsByName(slave)...)
+	}
+	var ids []string
+	for _, s := range sectors {
+		ids = append(ids, s.Id)
+	}
+	master := masters[0]
+
+	var kind config.ElevatorKind
+	mask := 0
+	switch class.SubClass {
+	case "basic", "move_floor", "basic_auto":
+		kind = config.ElevatorKindFloor
+	case "inv", "move_ceiling", "door":
+		kind = config.ElevatorKindCeil
+	case "change_light":
+		kind = config.ElevatorKindLight
+	default:
+		fmt.Printf("Warning: unsupported INF elevator %s for %s\n", class.SubClass, item.Name)
+		return
+	}
+	switch class.SubClass {
+	case "basic", "inv", "door":
+		mask = infEventNudgeFront | infEventNudgeBack
+	}
+	if class.EventMask >= 0 {
+		mask = class.EventMask
+	}
+
+	speed := class.Speed
+	if speed < 0 {
+		speed = infDefaultSpeed
+	}
+	if kind == config.ElevatorKindLight {
+		speed *= scaleLight
+	} else {
+		speed *= scaleSectorH
+	}
+
+	id := infElevatorId(item.Name, k)
+	e := config.NewConfigElevator(id, kind, ids, speed)
+	if class.SubClass == "door" {
+		// La porta parte chiusa (soffitto sul pavimento) e si apre fino al soffitto originale
+		e.AddStop(-master.FloorY*scaleSectorH, config.ElevatorStopHold, 0)
+		e.AddStop(-master.CeilingY*scaleSectorH, config.ElevatorStopWait, infDoorWait)
+	}
+	for _, cs := range class.Stops {
+		value, ok := ib.stopValue(master, kind, cs.Value)
+		if !ok {
+			// Si mantiene la stop per non alterare la numerazione usata dai messaggi
+			fmt.Printf("Warning: invalid INF stop %s for %s\n", cs.Value, item.Name)
+			value, _ = ib.stopValue(master, kind, "@0")
+		}
+		action, wait := infStopAction(cs.Action)
+		stop := e.AddStop(value, action, wait)
+		for _, m := range cs.Messages {
+			param := infParam(m.Params)
+			targets := ib.targets(m.Target)
+			if len(targets) == 0 && m.Message == config.ElevatorMessageComplete {
+				targets = []string{m.Target}
+			}
+			for _, target := range targets {
+				stop.Messages = append(stop.Messages, &config.ElevatorMessage{Target: target, Message: m.Message, Param: param})
+			}
+		}
+	}
+	if len(e.Stops) == 0 {
+		fmt.Printf("Warning: INF elevator %s has no stops\n", item.Name)
+		return
+	}
+	if class.Start >= 0 && class.Start < len(e.Stops) {
+		e.Start = class.Start
+	}
+	ib.elevators = append(ib.elevators, e)
+
+	if mask&(infEventNudgeFront|infEventNudgeBack) != 0 {
+		ib.nudges[master.Id] = append(ib.nudges[master.Id], id)
+		ib.keys[id] = strings.ToLower(class.Key)
+	}
+	if mask&infEventEnter != 0 {
+		t := config.NewConfigTrigger(id+"_enter", geometry.XY{}, geometry.XY{}, config.TriggerEnter, config.TriggerActionMessage)
+		t.Sector = master.Id
+		t.Repeat = true
+		t.Elevators = []string{id}
+		t.Message = config.ElevatorMessageTrigger
+		ib.triggers = append(ib.triggers, t)
+	}
+}
+
+// stopValue converts an INF stop value (absolute, @relative or a sector name) into a configuration height or light.
+func (ib *infBuilder) stopValue(master *Sector, kind config.ElevatorKind, value string) (float64, bool) {
+	base := master.FloorY
+	switch kind {
+	case config.ElevatorKindCeil:
+		base = master.CeilingY
+	case config.ElevatorKindLight:
+		base = master.LightLevel
+	}
+	var v float64
+	if strings.HasPrefix(value, "@") {
+		offset, err := strconv.ParseFloat(value[1:], 64)
+		if err != nil {
+			return 0, false
+		}
+		v = base + offset
+	} else if abs, err := strconv.ParseFloat(value, 64); err == nil {
+		v = abs
+	} else {
+		others := ib.level.GetSectorsByName(value)
+		if len(others) == 0 {
+			return 0, false
+		}
+		switch kind {
+		case config.ElevatorKindCeil:
+			v = others[0].CeilingY
+		case config.ElevatorKindLight:
+			v = others[0].LightLevel
+		default:
+			v = others[0].FloorY
+		}
+	}
+	if kind == config.ElevatorKindLight {
+		return sectorLight(v), true
+	}
+	return -v * scaleSectorH, true
+}
+
+// infStopAction converts an INF stop action (wait seconds, hold, terminate, complete) into a configuration action.
+func infStopAction(action string) (config.ElevatorStopAction, float64) {
+	switch action {
+	case "", "hold":
+		return config.ElevatorStopHold, 0
+	case "terminate":
+		return config.ElevatorStopTerminate, 0
+	case "complete":
+		return config.ElevatorStopComplete, 0
+	}
+	wait, err := strconv.ParseFloat(action, 64)
+	if err != nil {
+		fmt.Printf("Warning: invalid INF stop action %s\n", action)
+		return config.ElevatorStopHold, 0
+	}
+	return config.ElevatorStopWait, wait
+}
+
+// infParam returns the first message parameter as an integer, or 0.
+func infParam(params []string) int {
+	if len(params) == 0 {
+		return 0
+	}
+	v, _ := strconv.Atoi(params[0])
+	return v
+}
+
+// buildTrigger converts the k-th trigger class of an item into configuration triggers, one per activation.
+func (ib *infBuilder) buildTrigger(item *InfItem, k int, class *InfClass) {
+	message := class.Message
+	if message == "" {
+		message = config.ElevatorMessageTrigger
+	}
+	var elevators []string
+	for _, client := range class.Clients {
+		elevators = append(elevators, ib.targets(client)...)
+	}
+	if len(elevators) == 0 {
+		if message != config.ElevatorMessageComplete {
+			fmt.Printf("Warning: INF trigger %s has no known clients\n", item.Name)
+			return
+		}
+		elevators = class.Clients
+	}
+	sectors := ib.level.GetSectorsByName(item.Name)
+	if len(sectors) == 0 {
+		fmt.Printf("Warning: INF trigger %s references an unknown sector\n", item.Name)
+		return
+	}
+	sector := sectors[0]
+
+	mask := class.EventMask
+	var activations []config.TriggerActivation
+	if item.Kind == InfItemLine {
+		if mask < 0 {
+			mask = infEventNudgeFront | infEventNudgeBack
+		}
+		if mask&(infEventCrossFront|infEventCrossBack) != 0 {
+			activations = append(activations, config.TriggerCross)
+		}
+		if mask&(infEventNudgeFront|infEventNudgeBack) != 0 {
+			activations = append(activations, config.TriggerUse)
+		}
+		if mask&infEventShoot != 0 {
+			activations = append(activations, config.TriggerShoot)
+		}
+	} else {
+		if mask < 0 {
+			mask = infEventEnter
+		}
+		if mask&(infEventEnter|infEventLeave) != 0 {
+			// Il settore diventa un trigger volume che attiva il trigger all'ingresso e/o all'uscita
+			activations = append(activations, config.TriggerScript)
+		}
+	}
+	if len(activations) == 0 {
+		fmt.Printf("Warning: unsupported INF event mask %d for trigger %s\n", mask, item.Name)
+		return
+	}
+
+	id := fmt.Sprintf("inf_%s_%d", item.Name, k)
+	if item.Kind == InfItemLine {
+		id = fmt.Sprintf("inf_%s_%d_%d", item.Name, item.Num, k)
+	}
+	var start, end geometry.XY
+	switchTag := ""
+	if item.Kind == InfItemLine {
+		if item.Num < 0 || item.Num >= len(sector.Walls) || !sector.Walls[item.Num].IsValid(len(sector.Vertices)) {
+			fmt.Printf("Warning: INF trigger %s references an unknown wall %d\n", item.Name, item.Num)
+			return
+		}
+		wall := sector.Walls[item.Num]
+		start, end = sector.Vertices[wall.LeftVertex], sector.Vertices[wall.RightVertex]
+		switch class.SubClass {
+		case "switch1", "toggle":
+			if cSector, ok := ib.sectors[sector.Id]; ok && item.Num < len(cSector.Segments) && cSector.Segments[item.Num] != nil {
+				switchTag = id
+				cSector.Segments[item.Num].Tag = switchTag
+			}
+		}
+	}
+	for _, activation := range activations {
+		tId := id
+		if len(activations) > 1 {
+			tId = fmt.Sprintf("%s_%d", id, activation)
+		}
+		t := config.NewConfigTrigger(tId, start, end, activation, config.TriggerActionMessage)
+		t.Sector = sector.Id
+		t.Repeat = class.SubClass != "single"
+		t.Elevators = elevators
+		t.Message = message
+		t.Param = infParam(class.Params)
+		t.Switch = switchTag
+		t.Key = strings.ToLower(class.Key)
+		ib.triggers = append(ib.triggers, t)
+		if activation == config.TriggerScript {
+			tv := config.NewConfigSectorTriggerVolume(tId+"_volume", sector.Id, config.TriggerVolumeEvents)
+			tv.PlayerOnly = true
+			if mask&infEventEnter != 0 {
+				tv.Enter = []string{tId}
+			}
+			if mask&infEventLeave != 0 {
+				tv.Exit = []string{tId}
+			}
+			ib.volumes = append(ib.volumes, tv)
+		}
+	}
+}
+
+// buildNudges creates the use triggers on the adjoined walls of the sectors whose elevators respond to nudges: a
+// single trigger per wall and key sends m_trigger to all the elevators of the sector that need that key.
+func (ib *infBuilder) buildNudges() {
+	for _, sector := range ib.level.Sectors {
+		if sector == nil {
+			continue
+		}
+		var keys []string
+		byKey := make(map[string][]string)
+		for _, id := range ib.nudges[sector.Id] {
+			key := ib.keys[id]
+			if _, ok := byKey[key]; !ok {
+				keys = append(keys, key)
+			}
+			byKey[key] = append(byKey[key], id)
+		}
+		for wallIdx, wall := range sector.Walls {
+			if wall.Adjoin < 0 || !wall.IsValid(len(sector.Vertices)) {
+				continue
+			}
+			v1, v2 := sector.Vertices[wall.LeftVertex], sector.Vertices[wall.RightVertex]
+			for _, key := range keys {
+				id := fmt.Sprintf("inf_nudge_%s_%d", sector.Id, wallIdx)
+				if key != "" {
+					id += "_" + key
+				}
+				t := config.NewConfigTrigger(id, v1, v2, config.TriggerUse, config.TriggerActionMessage)
+				t.Repeat = true
+				t.Elevators = byKey[key]
+				t.Message = config.ElevatorMessageTrigger
+				t.Key = key
+				ib.triggers = append(ib.triggers, t)
+			}
+		}
+	}
+}
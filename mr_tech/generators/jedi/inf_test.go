@@ -0,0 +1,124 @@
+package jedi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// testInf is a script with an elevator door, a switch triggering it, and properties the parser does not support.
+const testInf = `INF 1.0
+LEVELNAME SECBASE
+/* a door
+   and its switch */
+items 2
+item: sector name: door1 seq
+  class: elevator change_light
+  class: elevator move_ceiling
+    speed: 20
+    start: 1
+    stop: 0 hold
+    stop: @16 2.5
+    message: 1 switch1(3) done
+    slave: door2
+    key: red
+    sound: 1 door.voc
+  seqend
+item: line name: switch1 num: 3 seq
+  class: trigger switch1
+    client: door1
+    event_mask: 16
+    master: off
+    sound: 1 switch.voc
+    flags: 4
+  seqend
+`
+
+func TestInfParse(t *testing.T) {
+	inf := NewInf()
+	if err := inf.Parse(strings.NewReader(testInf)); err != nil {
+		t.Fatal(err)
+	}
+	if inf.Version != "1.0" || inf.LevelName != "SECBASE" {
+		t.Fatalf("header %s %s, want 1.0 SECBASE", inf.Version, inf.LevelName)
+	}
+	if len(inf.Items) != 2 {
+		t.Fatalf("%d items, want 2", len(inf.Items))
+	}
+
+	door := inf.Items[0]
+	if door.Kind != InfItemSector || door.Name != "door1" || door.Num != -1 || len(door.Classes) != 2 {
+		t.Fatalf("door item %+v", door)
+	}
+	light := door.Classes[0]
+	if light.Class != InfClassElevator || light.SubClass != "change_light" || light.Speed != -1 || len(light.Stops) != 0 {
+		t.Fatalf("light class %+v", light)
+	}
+	elevator := door.Classes[1]
+	if elevator.SubClass != "move_ceiling" || elevator.Speed != 20 || elevator.Start != 1 || elevator.Key != "red" {
+		t.Fatalf("elevator class %+v", elevator)
+	}
+	if len(elevator.Slaves) != 1 || elevator.Slaves[0] != "door2" {
+		t.Fatalf("elevator slaves %v", elevator.Slaves)
+	}
+	if len(elevator.Stops) != 2 || elevator.Stops[0].Value != "0" || elevator.Stops[0].Action != "hold" || elevator.Stops[1].Value != "@16" || elevator.Stops[1].Action != "2.5" {
+		t.Fatalf("elevator stops %+v %+v", elevator.Stops[0], elevator.Stops[1])
+	}
+	messages := elevator.Stops[1].Messages
+	if len(messages) != 1 || messages[0].Target != "switch1(3)" || messages[0].Message != "done" {
+		t.Fatalf("messages of the second stop %+v", messages)
+	}
+
+	sw := inf.Items[1]
+	if sw.Kind != InfItemLine || sw.Name != "switch1" || sw.Num != 3 || len(sw.Classes) != 1 {
+		t.Fatalf("switch item %+v", sw)
+	}
+	trigger := sw.Classes[0]
+	if trigger.Class != InfClassTrigger || trigger.SubClass != "switch1" || trigger.EventMask != 16 || trigger.Master {
+		t.Fatalf("trigger class %+v", trigger)
+	}
+	if len(trigger.Clients) != 1 || trigger.Clients[0] != "door1" {
+		t.Fatalf("trigger clients %v", trigger.Clients)
+	}
+}
+
+func TestInfReportsUnsupportedProperties(t *testing.T) {
+	inf := NewInf()
+	if err := inf.Parse(strings.NewReader(testInf)); err != nil {
+		t.Fatal(err)
+	}
+	if len(inf.Diagnostics) != 2 {
+		t.Fatalf("%d diagnostics, want one for FLAGS: and one for SOUND:", len(inf.Diagnostics))
+	}
+	for idx, want := range []string{"FLAGS:", "SOUND:"} {
+		d := inf.Diagnostics[idx]
+		if d.Severity != config.DiagnosticWarning || d.Code != config.DiagSourceUnsupported || !strings.Contains(d.Message, want) {
+			t.Fatalf("diagnostic %d: %s, want a warning about %s", idx, d, want)
+		}
+	}
+	if !strings.Contains(inf.Diagnostics[1].Message, "2 times") {
+		t.Fatalf("the two SOUND: properties are not counted: %s", inf.Diagnostics[1])
+	}
+
+	// I warning del sorgente arrivano al validatore insieme a quelli dell'IR
+	cfg := config.NewConfigRoot(nil, nil, nil, nil, geometry.XYZ{X: 1, Y: 1, Z: 1}, nil)
+	cfg.Report(inf.Diagnostics...)
+	found := 0
+	for _, d := range cfg.Validate() {
+		if d.Code == config.DiagSourceUnsupported {
+			found++
+		}
+	}
+	if found != len(inf.Diagnostics) {
+		t.Fatalf("the validator returned %d of the %d reported diagnostics", found, len(inf.Diagnostics))
+	}
+}
+
+func TestInfPropertyBeforeClass(t *testing.T) {
+	inf := NewInf()
+	if err := inf.Parse(strings.NewReader("item: sector name: door1 seq\n  speed: 10\nseqend\n")); err == nil {
+		t.Fatal("a property before the class has been accepted")
+	}
+}
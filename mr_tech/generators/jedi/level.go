@@ -40,6 +40,17 @@ func NewLevel() *Level {
 	}
 }
 
+// GetSectorsByName returns the sectors with the given (case-insensitive) name, as referenced by the INF scripts.
+func (p *Level) GetSectorsByName(name string) []*Sector {
+	var out []*Sector
+	for _, sector := range p.Sectors {
+		if sector != nil && len(sector.Name) > 0 && strings.EqualFold(sector.Name, name) {
+			out = append(out, sector)
+		}
+	}
+	return out
+}
+
 // GetTexture retrieves the texture string by its ID from the level's texture list.
 // If the ID is out of bounds, it logs an error and returns an empty string.
 func (p *Level) GetTexture(id int) string {
@@ -97,7 +108,9 @@ func (p *Level) Parse(r io.Reader) error {
 			}
 			p.Sectors = make([]*Sector, sCount)
 		case "NAME":
-			//TODO IMPLEMENT
+			if sector != nil && len(tokens) >= 2 {
+				sector.Name = tokens[1]
+			}
 		case "SECOND":
 			//TODO IMPLEMENT
 		case "LAYER":
@@ -0,0 +1,74 @@
+package jedi
+
+import (
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+// ammoEnergy, ammoPower, ammoPlasma, ammoDetonators, ammoMines and ammoMissiles are the inventory ids of the Dark
+// Forces ammo types.
+const (
+	ammoEnergy     = "energy"
+	ammoPower      = "power"
+	ammoPlasma     = "plasma"
+	ammoDetonators = "detonators"
+	ammoMines      = "mines"
+	ammoMissiles   = "missiles"
+)
+
+// keyRed, keyYellow and keyBlue are the inventory ids of the Dark Forces keys, as referenced by the INF key property.
+const (
+	keyRed    = "red"
+	keyYellow = "yellow"
+	keyBlue   = "blue"
+)
+
+// _pickupDictionary maps the Dark Forces item names to what they give to the player when touched.
+var _pickupDictionary = map[string][]config.Pickup{
+	"RED":        {{Kind: config.PickupKey, Id: keyRed}},
+	"YELLOW":     {{Kind: config.PickupKey, Id: keyYellow}},
+	"BLUE":       {{Kind: config.PickupKey, Id: keyBlue}},
+	"MEDKIT":     {{Kind: config.PickupHealth, Amount: 20, Limit: 100}},
+	"SHIELD":     {{Kind: config.PickupArmor, Amount: 20, Limit: 200}},
+	"ENERGY":     {{Kind: config.PickupAmmo, Id: ammoEnergy, Amount: 15}},
+	"POWER":      {{Kind: config.PickupAmmo, Id: ammoPower, Amount: 10}},
+	"PLASMA":     {{Kind: config.PickupAmmo, Id: ammoPlasma, Amount: 20}},
+	"DETONATOR":  {{Kind: config.PickupWeapon, Id: "detonator"}, {Kind: config.PickupAmmo, Id: ammoDetonators, Amount: 1}},
+	"DETONATORS": {{Kind: config.PickupWeapon, Id: "detonator"}, {Kind: config.PickupAmmo, Id: ammoDetonators, Amount: 3}},
+	"MINE":       {{Kind: config.PickupAmmo, Id: ammoMines, Amount: 1}},
+	"MINES":      {{Kind: config.PickupAmmo, Id: ammoMines, Amount: 4}},
+	"MISSILE":    {{Kind: config.PickupAmmo, Id: ammoMissiles, Amount: 1}},
+	"MISSILES":   {{Kind: config.PickupAmmo, Id: ammoMissiles, Amount: 5}},
+	"RIFLE":      {{Kind: config.PickupWeapon, Id: "rifle"}, {Kind: config.PickupAmmo, Id: ammoEnergy, Amount: 15}},
+	"AUTOGUN":    {{Kind: config.PickupWeapon, Id: "autogun"}, {Kind: config.PickupAmmo, Id: ammoPower, Amount: 30}},
+	"MORTAR":     {{Kind: config.PickupWeapon, Id: "mortar"}, {Kind: config.PickupAmmo, Id: ammoDetonators, Amount: 3}},
+	"FUSION":     {{Kind: config.PickupWeapon, Id: "fusion"}, {Kind: config.PickupAmmo, Id: ammoPower, Amount: 50}},
+	"CONCUSSION": {{Kind: config.PickupWeapon, Id: "concussion"}, {Kind: config.PickupAmmo, Id: ammoPower, Amount: 100}},
+	"CANNON":     {{Kind: config.PickupWeapon, Id: "cannon"}, {Kind: config.PickupAmmo, Id: ammoPlasma, Amount: 30}},
+}
+
+// buildPickups returns a copy of the pickups of the named item, or nil if the item is not a pickup.
+func buildPickups(name string) []*config.Pickup {
+	src, ok := _pickupDictionary[name]
+	if !ok {
+		return nil
+	}
+	out := make([]*config.Pickup, 0, len(src))
+	for _, p := range src {
+		out = append(out, config.NewConfigPickup(p.Kind, p.Id, p.Amount, p.Limit))
+	}
+	return out
+}
+
+// buildInventory returns the starting inventory of the Dark Forces player: fists, the Bryar pistol and 100 energy units.
+func buildInventory() *config.Inventory {
+	inv := config.NewConfigInventory()
+	inv.Weapons = []string{"fists", "pistol"}
+	inv.Ammo[ammoEnergy] = 100
+	inv.MaxAmmo[ammoEnergy] = 500
+	inv.MaxAmmo[ammoPower] = 500
+	inv.MaxAmmo[ammoPlasma] = 400
+	inv.MaxAmmo[ammoDetonators] = 50
+	inv.MaxAmmo[ammoMines] = 30
+	inv.MaxAmmo[ammoMissiles] = 20
+	return inv
+}
@@ -25,6 +25,7 @@ func (s *Slope) GetGradient() float64 {
 // Sector represents a spatial region with geometry, textures, physical properties, and relationships to walls and vertices.
 type Sector struct {
 	Id             string
+	Name           string
 	Index          int
 	FloorY         float64
 	CeilingY       float64
@@ -316,6 +317,14 @@ func (s *Sector) IsAbyss() bool {
 	return (s.Flags[0] & 2) != 0
 }
 
+// IsSecret determines if the sector has the "secret area" flag set, based on its Flags property.
+func (s *Sector) IsSecret() bool {
+	if len(s.Flags) < 1 {
+		return false
+	}
+	return (s.Flags[0] & 0x80000) != 0
+}
+
 func (s *Sector) IsCCW() bool {
 	area := 0.0
 	for _, w := range s.Walls {
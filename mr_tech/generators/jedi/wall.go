@@ -47,6 +47,11 @@ func NewWall() *Wall {
 	}
 }
 
+// IsValid reports whether both vertices of the Wall are inside a sector with vertexCount vertices.
+func (w *Wall) IsValid(vertexCount int) bool {
+	return w.LeftVertex >= 0 && w.RightVertex >= 0 && w.LeftVertex < vertexCount && w.RightVertex < vertexCount
+}
+
 // Parse processes a list of tokens to populate the fields of a Wall instance based on recognized attributes.
 func (w *Wall) Parse(tokens []string) {
 	for i := 0; i < len(tokens); i++ {
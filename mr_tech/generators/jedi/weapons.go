@@ -0,0 +1,91 @@
+package jedi
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// weaponRange is the range of the Dark Forces hitscan weapons, in world units.
+const weaponRange = 4096 * scaleX
+
+// meleeRange is the reach of the Dark Forces fists, in world units.
+const meleeRange = playerRadius * 4
+
+// WeaponDef describes a weapon of the Dark Forces arsenal. The blaster bolts are fast enough to be mapped on hitscan
+// weapons; the thrown weapons launch a copy of the model of Item, the ITM of their pickup. Rate is the number of
+// shots per second, Damage the damage of a bolt, Spread the deviation of the bolts in degrees and Speed the launch
+// speed of the thrown weapons in world units per second.
+type WeaponDef struct {
+	Id      string
+	Kind    config.WeaponKind
+	Rate    float64
+	Pellets int
+	Damage  float64
+	Spread  float64
+	Ammo    string
+	PerShot float64
+	Item    string
+	Speed   float64
+}
+
+// _weaponDictionary lists the Dark Forces arsenal in selection order.
+var _weaponDictionary = []WeaponDef{
+	{Id: "fists", Kind: config.WeaponMelee, Rate: 2.5, Pellets: 1, Damage: 10},
+	{Id: "pistol", Kind: config.WeaponHitscan, Rate: 2, Pellets: 1, Damage: 10, Spread: 0.5, Ammo: ammoEnergy, PerShot: 1},
+	{Id: "rifle", Kind: config.WeaponHitscan, Rate: 5, Pellets: 1, Damage: 10, Spread: 2, Ammo: ammoEnergy, PerShot: 2},
+	{Id: "detonator", Kind: config.WeaponProjectile, Rate: 1, Pellets: 1, Damage: 60, Ammo: ammoDetonators, PerShot: 1, Item: "DETONATOR", Speed: 1000},
+	{Id: "autogun", Kind: config.WeaponHitscan, Rate: 3, Pellets: 3, Damage: 10, Spread: 3, Ammo: ammoPower, PerShot: 1},
+	{Id: "fusion", Kind: config.WeaponHitscan, Rate: 4, Pellets: 1, Damage: 15, Spread: 1, Ammo: ammoPower, PerShot: 1},
+	{Id: "mortar", Kind: config.WeaponProjectile, Rate: 1, Pellets: 1, Damage: 50, Ammo: ammoDetonators, PerShot: 1, Item: "DETONATOR", Speed: 1500},
+	{Id: "concussion", Kind: config.WeaponHitscan, Rate: 1, Pellets: 1, Damage: 60, Ammo: ammoPower, PerShot: 4},
+	{Id: "cannon", Kind: config.WeaponHitscan, Rate: 4, Pellets: 1, Damage: 30, Spread: 1, Ammo: ammoPlasma, PerShot: 1},
+}
+
+// buildWeapons maps the Dark Forces arsenal onto the weapon definitions of the player. The damage of the
+// definitions is the impact force, which the default damage factor of the health model halves.
+func (b *Builder) buildWeapons(archive IArchive) []*config.Weapon {
+	templates := make(map[string]*config.Thing)
+	out := make([]*config.Weapon, 0, len(_weaponDictionary))
+	for _, wd := range _weaponDictionary {
+		w := config.NewConfigWeapon(wd.Id, wd.Kind)
+		w.FireRate = wd.Rate
+		w.Pellets = wd.Pellets
+		w.Damage = wd.Damage * 2
+		w.Spread = wd.Spread * math.Pi / 180.0
+		w.Ammo = wd.Ammo
+		w.AmmoPerShot = wd.PerShot
+		switch wd.Kind {
+		case config.WeaponMelee:
+			w.Range = meleeRange
+		case config.WeaponHitscan:
+			w.Range = weaponRange
+		case config.WeaponProjectile:
+			template, ok := templates[wd.Item]
+			if !ok {
+				template = b.buildProjectile(wd.Item, archive)
+				templates[wd.Item] = template
+			}
+			w.Projectile = template
+			w.ProjectileSpeed = wd.Speed
+		}
+		out = append(out, w)
+	}
+	return out
+}
+
+// buildProjectile returns the projectile template made from the model of the named item, or nil if the item can't
+// be loaded.
+func (b *Builder) buildProjectile(item string, archive IArchive) *config.Thing {
+	cThing, err := b.ItemToThing(item, archive, geometry.XYZ{})
+	if err != nil || cThing == nil {
+		fmt.Printf("Warning: can't build the projectile %s: %v\n", item, err)
+		return nil
+	}
+	cThing.Id = item + "_projectile"
+	cThing.Kind = config.ThingThrowableDef
+	cThing.Continuous = true
+	return cThing
+}
@@ -84,6 +84,11 @@ func (p *Builder) Setup(pakPath string, lev int) (*config.Root, error) {
 
 	scaleFactor := geometry.XYZ{X: 1, Y: 1, Z: 1}
 	root := config.NewConfigRoot(cal, nil, nil, nil, scaleFactor, texManager)
+	bspModels, err := reader.GetModels()
+	if err != nil {
+		return nil, err
+	}
+	triggerVolumes := newTriggers(bspModels)
 
 	for _, ent := range entities {
 		classname := ent.Properties["classname"]
@@ -106,6 +111,9 @@ func (p *Builder) Setup(pakPath string, lev int) (*config.Root, error) {
 		}
 
 		if modelProp := ent.Properties["model"]; strings.HasPrefix(modelProp, "*") {
+			if baseClass == "trigger" {
+				triggerVolumes.addTrigger(ent)
+			}
 			continue
 		}
 
@@ -115,6 +123,7 @@ func (p *Builder) Setup(pakPath string, lev int) (*config.Root, error) {
 				fmt.Printf("Warning External BModel: %s (Errore: %v)\n", classname, err)
 				continue
 			}
+			cThing.Pickups = buildPickups(classname)
 			root.Things = append(root.Things, cThing)
 			continue
 		}
@@ -128,8 +137,10 @@ func (p *Builder) Setup(pakPath string, lev int) (*config.Root, error) {
 				if err != nil {
 					fmt.Printf("Warning: %s\n", err.Error())
 				}
+			} else if classname == "info_teleport_destination" {
+				triggerVolumes.addDestination(ent, pos, angle)
 			} else {
-				// Marker invisibili: teletrasporti, spawn point deathmatch, nodi di pattuglia.
+				// Marker invisibili: spawn point deathmatch, nodi di pattuglia.
 				// TODO: Salvarli in una lista di waypoint/spawnpoint gameplay.
 			}
 		case "light":
@@ -159,7 +170,7 @@ func (p *Builder) Setup(pakPath string, lev int) (*config.Root, error) {
 		case "func":
 			// TODO:
 		case "trigger":
-		// TODO:
+		// Le entità trigger_* sono brush model, raccolti sopra come trigger volume
 		//case "trap":
 		//TODO
 		default:
@@ -168,6 +179,7 @@ func (p *Builder) Setup(pakPath string, lev int) (*config.Root, error) {
 				fmt.Printf("Warning: %s\n", err.Error())
 				continue
 			}
+			cThing.Pickups = buildPickups(classname)
 			root.Things = append(root.Things, cThing)
 		}
 	}
@@ -205,10 +217,12 @@ func (p *Builder) Setup(pakPath string, lev int) (*config.Root, error) {
 		}
 	}
 
+	root.TriggerVolumes = triggerVolumes.build()
+
 	root.Player = config.NewConfigPlayer(playerPos, playerAngle, 100, 1200, 15, 40)
-	playerLogic := common.NewPlayer()
-	root.Player.OnCollision = playerLogic.OnCollision
-	root.Player.OnImpact = playerLogic.OnImpact
+	root.Player.Behavior = common.NewPlayerBehavior()
+	root.Player.Inventory = buildInventory()
+	root.Player.Weapons = p.buildWeapons(pk, reader)
 	root.Player.GForce = gForce
 	root.Player.JumpForce = 1000
 
@@ -341,9 +355,22 @@ func (p *Builder) createThing(pos geometry.XYZ, classname string, pk *lumps.Pak,
 		return nil, fmt.Errorf("unknown thing %s", classname)
 	}
 
-	rsMd1, err := pk.Open(thingPath)
+	cModel, err := p.loadModel(thingPath, classname, skinTargetIndex, pk, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	thingCfg := p.createConfigThing(classname, pos, kind, cModel, 0, 30.0, 16.0, 56, 600.0)
+
+	return thingCfg, nil
+}
+
+// loadModel loads the MDL model at modelPath as an MD1, skinned with the skin at skinTargetIndex registered under the
+// classname.
+func (p *Builder) loadModel(modelPath string, classname string, skinTargetIndex int, pk *lumps.Pak, reader lumps.IBSPReader) (*config.MD1, error) {
+	rsMd1, err := pk.Open(modelPath)
 	if err != nil {
-		return nil, fmt.Errorf("can't open %s: %s", thingPath, err.Error())
+		return nil, fmt.Errorf("can't open %s: %s", modelPath, err.Error())
 	}
 	md1 := lumps.NewMD1Resource()
 	if err = md1.Parse(rsMd1); err != nil {
@@ -383,10 +410,7 @@ func (p *Builder) createThing(pos geometry.XYZ, classname string, pk *lumps.Pak,
 		cFrame := config.NewMD1Frame(triangles)
 		cModel.Frames[idx] = cFrame
 	}
-
-	thingCfg := p.createConfigThing(classname, pos, kind, cModel, 0, 30.0, 16.0, 56, 600.0)
-
-	return thingCfg, nil
+	return cModel, nil
 }
 
 // createThingBSP constructs a Thing instance using external BSP model data, applying positions, textures, and materials.
@@ -488,15 +512,10 @@ func (p *Builder) createConfigThing(classname string, pos geometry.XYZ, kind con
 		if thingCfg.MD1 != nil {
 			actions = thingCfg.MD1.ActionDefinitions
 		}
-		enemyLogic := common.NewEnemy(actions, 300)
-		thingCfg.OnThinking = enemyLogic.OnThinking
-		thingCfg.OnCollision = enemyLogic.OnCollision
-		thingCfg.OnImpact = enemyLogic.OnImpact
+		thingCfg.Behavior = common.NewEnemyBehavior(actions, 300)
 		thingCfg.WakeUpDistance = 400
 	} else {
-		itemLogic := common.NewItem()
-		thingCfg.OnCollision = itemLogic.OnCollision
-		thingCfg.OnImpact = itemLogic.OnImpact
+		thingCfg.Behavior = common.NewItemBehavior()
 	}
 	return thingCfg
 }
@@ -0,0 +1,76 @@
+package quake
+
+import (
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+// ammoShells, ammoNails, ammoRockets and ammoCells are the inventory ids of the Quake ammo types.
+const (
+	ammoShells  = "shells"
+	ammoNails   = "nails"
+	ammoRockets = "rockets"
+	ammoCells   = "cells"
+)
+
+// keySilver and keyGold are the inventory ids of the Quake keys.
+const (
+	keySilver = "silver"
+	keyGold   = "gold"
+)
+
+// _pickupDictionary maps the Quake classnames to what they give to the player when touched.
+var _pickupDictionary = map[string][]config.Pickup{
+	// --- CURE E ARMATURE ---
+	"item_health":       {{Kind: config.PickupHealth, Amount: 25, Limit: 100}},
+	"item_health_large": {{Kind: config.PickupHealth, Amount: 100, Limit: 250}},
+	"item_armor1":       {{Kind: config.PickupArmor, Amount: 100, Limit: 100}},
+	"item_armor2":       {{Kind: config.PickupArmor, Amount: 150, Limit: 150}},
+	"item_armorInv":     {{Kind: config.PickupArmor, Amount: 200, Limit: 200}},
+
+	// --- MUNIZIONI ---
+	"item_shells":        {{Kind: config.PickupAmmo, Id: ammoShells, Amount: 20}},
+	"item_shells_large":  {{Kind: config.PickupAmmo, Id: ammoShells, Amount: 40}},
+	"item_spikes":        {{Kind: config.PickupAmmo, Id: ammoNails, Amount: 25}},
+	"item_spikes_large":  {{Kind: config.PickupAmmo, Id: ammoNails, Amount: 50}},
+	"item_rockets":       {{Kind: config.PickupAmmo, Id: ammoRockets, Amount: 5}},
+	"item_rockets_large": {{Kind: config.PickupAmmo, Id: ammoRockets, Amount: 10}},
+	"item_cells":         {{Kind: config.PickupAmmo, Id: ammoCells, Amount: 6}},
+	"item_cells_large":   {{Kind: config.PickupAmmo, Id: ammoCells, Amount: 12}},
+
+	// --- ARMI ---
+	"weapon_supershotgun":    {{Kind: config.PickupWeapon, Id: "supershotgun"}, {Kind: config.PickupAmmo, Id: ammoShells, Amount: 5}},
+	"weapon_nailgun":         {{Kind: config.PickupWeapon, Id: "nailgun"}, {Kind: config.PickupAmmo, Id: ammoNails, Amount: 30}},
+	"weapon_supernailgun":    {{Kind: config.PickupWeapon, Id: "supernailgun"}, {Kind: config.PickupAmmo, Id: ammoNails, Amount: 30}},
+	"weapon_grenadelauncher": {{Kind: config.PickupWeapon, Id: "grenadelauncher"}, {Kind: config.PickupAmmo, Id: ammoRockets, Amount: 5}},
+	"weapon_rocketlauncher":  {{Kind: config.PickupWeapon, Id: "rocketlauncher"}, {Kind: config.PickupAmmo, Id: ammoRockets, Amount: 5}},
+	"weapon_lightning":       {{Kind: config.PickupWeapon, Id: "lightning"}, {Kind: config.PickupAmmo, Id: ammoCells, Amount: 15}},
+
+	// --- CHIAVI ---
+	"item_key1": {{Kind: config.PickupKey, Id: keySilver}},
+	"item_key2": {{Kind: config.PickupKey, Id: keyGold}},
+}
+
+// buildPickups returns a copy of the pickups of the classname, or nil if the thing is not a pickup item.
+func buildPickups(classname string) []*config.Pickup {
+	src, ok := _pickupDictionary[classname]
+	if !ok {
+		return nil
+	}
+	out := make([]*config.Pickup, 0, len(src))
+	for _, p := range src {
+		out = append(out, config.NewConfigPickup(p.Kind, p.Id, p.Amount, p.Limit))
+	}
+	return out
+}
+
+// buildInventory returns the starting inventory of the Quake player: axe, shotgun and 25 shells.
+func buildInventory() *config.Inventory {
+	inv := config.NewConfigInventory()
+	inv.Weapons = []string{"axe", "shotgun"}
+	inv.Ammo[ammoShells] = 25
+	inv.MaxAmmo[ammoShells] = 100
+	inv.MaxAmmo[ammoNails] = 200
+	inv.MaxAmmo[ammoRockets] = 100
+	inv.MaxAmmo[ammoCells] = 100
+	return inv
+}
@@ -0,0 +1,93 @@
+package quake
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/generators/quake/lumps"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// teleportPlayerOnly is the spawnflag of trigger_teleport that ignores the monsters.
+const teleportPlayerOnly = 1
+
+// teleportDestination is an info_teleport_destination marker, addressed by its targetname.
+type teleportDestination struct {
+	pos   geometry.XYZ
+	angle float64
+}
+
+// triggers collects the trigger_* brush entities of a level and the teleport destinations they reference.
+type triggers struct {
+	models       []*lumps.Model
+	entities     []*lumps.Entity
+	destinations map[string]teleportDestination
+}
+
+// newTriggers creates a trigger collector bound to the brush models of the level.
+func newTriggers(models []*lumps.Model) *triggers {
+	return &triggers{
+		models:       models,
+		destinations: make(map[string]teleportDestination),
+	}
+}
+
+// addDestination records an info_teleport_destination marker.
+func (t *triggers) addDestination(ent *lumps.Entity, pos geometry.XYZ, angle float64) {
+	if name := ent.Properties["targetname"]; name != "" {
+		t.destinations[name] = teleportDestination{pos: pos, angle: angle * (math.Pi / 180.0)}
+	}
+}
+
+// addTrigger records a trigger_* brush entity, converted once all the destinations are known.
+func (t *triggers) addTrigger(ent *lumps.Entity) {
+	t.entities = append(t.entities, ent)
+}
+
+// build converts the recorded trigger entities into trigger volumes covering the bounds of their brush models.
+// Teleports, secrets and level changes get the matching action; the others only deliver events.
+func (t *triggers) build() []*config.TriggerVolume {
+	var out []*config.TriggerVolume
+	for idx, ent := range t.entities {
+		classname := ent.Properties["classname"]
+		modelIdx, err := strconv.Atoi(strings.TrimPrefix(ent.Properties["model"], "*"))
+		if err != nil || modelIdx <= 0 || modelIdx >= len(t.models) {
+			fmt.Printf("Warning: %s references an unknown brush model %s\n", classname, ent.Properties["model"])
+			continue
+		}
+		m := t.models[modelIdx]
+		min := lumps.CreateXYZ(float64(m.Mins[0]), float64(m.Mins[1]), float64(m.Mins[2]))
+		max := lumps.CreateXYZ(float64(m.Maxs[0]), float64(m.Maxs[1]), float64(m.Maxs[2]))
+		spawnFlags, _ := strconv.Atoi(ent.Properties["spawnflags"])
+
+		tv := config.NewConfigTriggerVolume(fmt.Sprintf("%s_%d", classname, idx), min, max, config.TriggerVolumeEvents)
+		tv.Tag = ent.Properties["target"]
+		tv.Message = ent.Properties["message"]
+		tv.PlayerOnly = true
+		switch classname {
+		case "trigger_teleport":
+			dst, ok := t.destinations[ent.Properties["target"]]
+			if !ok {
+				fmt.Printf("Warning: %s has no destination '%s'\n", tv.Id, ent.Properties["target"])
+				continue
+			}
+			tv.Action = config.TriggerVolumeTeleport
+			tv.Destination = dst.pos
+			tv.Angle = dst.angle
+			tv.PlayerOnly = spawnFlags&teleportPlayerOnly != 0
+		case "trigger_secret":
+			tv.Action = config.TriggerVolumeSecret
+			tv.Once = true
+		case "trigger_changelevel":
+			tv.Action = config.TriggerVolumeExit
+			tv.Tag = ent.Properties["map"]
+		case "trigger_once":
+			tv.Once = true
+		}
+		out = append(out, tv)
+	}
+	return out
+}
@@ -0,0 +1,86 @@
+package quake
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/generators/quake/lumps"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// WeaponDef describes a weapon of the Quake arsenal. Delay is the time between two shots in seconds, Damage the
+// damage of a pellet, Spread the deviation of the pellets in degrees and Missile the classname of the launched
+// model (when the weapon is a projectile weapon), thrown at Speed units per second under Gravity.
+type WeaponDef struct {
+	Id      string
+	Kind    config.WeaponKind
+	Delay   float64
+	Pellets int
+	Damage  float64
+	Spread  float64
+	Range   float64
+	Ammo    string
+	PerShot float64
+	Missile string
+	Speed   float64
+	Gravity bool
+}
+
+// _weaponDictionary lists the Quake arsenal in selection order.
+var _weaponDictionary = []WeaponDef{
+	{Id: "axe", Kind: config.WeaponMelee, Delay: 0.5, Pellets: 1, Damage: 20, Range: 64},
+	{Id: "shotgun", Kind: config.WeaponHitscan, Delay: 0.5, Pellets: 6, Damage: 4, Spread: 2.3, Ammo: ammoShells, PerShot: 1},
+	{Id: "supershotgun", Kind: config.WeaponHitscan, Delay: 0.7, Pellets: 14, Damage: 4, Spread: 8, Ammo: ammoShells, PerShot: 2},
+	{Id: "nailgun", Kind: config.WeaponProjectile, Delay: 0.1, Pellets: 1, Damage: 9, Ammo: ammoNails, PerShot: 1, Missile: "spike", Speed: 1000},
+	{Id: "supernailgun", Kind: config.WeaponProjectile, Delay: 0.1, Pellets: 1, Damage: 18, Ammo: ammoNails, PerShot: 2, Missile: "s_spike", Speed: 1000},
+	{Id: "grenadelauncher", Kind: config.WeaponProjectile, Delay: 0.6, Pellets: 1, Damage: 120, Ammo: ammoRockets, PerShot: 1, Missile: "grenade", Speed: 600, Gravity: true},
+	{Id: "rocketlauncher", Kind: config.WeaponProjectile, Delay: 0.8, Pellets: 1, Damage: 120, Ammo: ammoRockets, PerShot: 1, Missile: "rocket", Speed: 1000},
+	{Id: "lightning", Kind: config.WeaponHitscan, Delay: 0.1, Pellets: 1, Damage: 30, Range: 600, Ammo: ammoCells, PerShot: 1},
+}
+
+// buildWeapons maps the Quake arsenal onto the weapon definitions of the player. The damage of the definitions is
+// the impact force, which the default damage factor of the health model halves.
+func (p *Builder) buildWeapons(pk *lumps.Pak, reader lumps.IBSPReader) []*config.Weapon {
+	templates := make(map[string]*config.Thing)
+	out := make([]*config.Weapon, 0, len(_weaponDictionary))
+	for _, wd := range _weaponDictionary {
+		w := config.NewConfigWeapon(wd.Id, wd.Kind)
+		w.FireRate = 1.0 / wd.Delay
+		w.Pellets = wd.Pellets
+		w.Damage = wd.Damage * 2
+		w.Spread = wd.Spread * math.Pi / 180.0
+		w.Ammo = wd.Ammo
+		w.AmmoPerShot = wd.PerShot
+		if wd.Range > 0 {
+			w.Range = wd.Range
+		}
+		if wd.Kind == config.WeaponProjectile {
+			template, ok := templates[wd.Missile]
+			if !ok {
+				template = p.createProjectile(wd, pk, reader)
+				templates[wd.Missile] = template
+			}
+			w.Projectile = template
+			w.ProjectileSpeed = wd.Speed
+		}
+		out = append(out, w)
+	}
+	return out
+}
+
+// createProjectile returns the projectile template made from the model of the weapon missile, or nil if the model
+// can't be loaded.
+func (p *Builder) createProjectile(wd WeaponDef, pk *lumps.Pak, reader lumps.IBSPReader) *config.Thing {
+	cModel, err := p.loadModel(GetModelFileName(wd.Missile), wd.Missile, 0, pk, reader)
+	if err != nil {
+		fmt.Printf("Warning: can't build the projectile %s: %s\n", wd.Missile, err.Error())
+		return nil
+	}
+	thingCfg := p.createConfigThing(wd.Missile, geometry.XYZ{}, config.ThingThrowableDef, cModel, 0, 1.0, 4.0, 8.0, 0)
+	if !wd.Gravity {
+		thingCfg.GForce = 0
+	}
+	thingCfg.Continuous = true
+	return thingCfg
+}
@@ -52,7 +52,8 @@ const playerMass = 50
 const SkyPicture = "F_SKY1"
 
 // openAllDoors determines whether all doors in the level should be opened automatically during sector configuration.
-const openAllDoors = true
+// When false, doors are closed and animated at runtime by the linedef specials.
+const openAllDoors = false
 
 // Edge represents a line in a 2D space connecting two points, with metadata about its relationship to sectors and sidedefs.
 type Edge struct {
@@ -100,6 +101,7 @@ func (bld *Builder) Build(wadFile string, levelNumber int) (*config.Root, error)
 
 	sectorsEdges := bld.createSectorsEdges(level, vertexes)
 	var sectors []*config.Sector
+	built := make(map[int]*config.Sector)
 	for secIdx, edges := range sectorsEdges {
 		if edges == nil {
 			continue
@@ -121,7 +123,10 @@ func (bld *Builder) Build(wadFile string, levelNumber int) (*config.Root, error)
 			cSector.Segments = append(cSector.Segments, cSeg)
 		}
 		sectors = append(sectors, cSector)
+		built[secIdx] = cSector
 	}
+	triggers, movers := bld.buildSpecials(level, vertexes, built)
+	triggerVolumes := bld.buildSectorTriggers(level, built)
 
 	var things []*config.Thing
 	for i, lThing := range level.Things {
@@ -130,12 +135,15 @@ func (bld *Builder) Build(wadFile string, levelNumber int) (*config.Root, error)
 		}
 	}
 
-	player := bld.buildPlayer(level)
+	player := bld.buildPlayer(level, texHandler)
 	cal := config.NewConfigCalibration(0, 0, 0, 0, 0, 0, true)
 	cal.AspectRatio = AspectRatio
 	scaleFactor := geometry.XYZ{X: 1.0, Y: 1.0, Z: 1.0}
 	cr := config.NewConfigRoot(cal, sectors, player, things, scaleFactor, texHandler)
 	cr.Vertices = vertexes
+	cr.Movers = movers
+	cr.Triggers = triggers
+	cr.TriggerVolumes = triggerVolumes
 
 	return cr, nil
 }
@@ -213,23 +221,19 @@ func (bld *Builder) buildThings(t *lumps.Thing, i int, texHandler *Textures) *co
 		cfgThing.MultiSprite = texHandler.BuildSprite(sd.Sprite)
 	}
 	if cfgThing.Kind == config.ThingEnemyDef {
-		enemyLogic := common.NewEnemy(nil, 100)
-		cfgThing.OnThinking = enemyLogic.OnThinking
-		cfgThing.OnCollision = enemyLogic.OnCollision
-		cfgThing.OnImpact = enemyLogic.OnImpact
+		cfgThing.Behavior = common.NewEnemyBehavior(nil, 100)
 	} else {
-		itemLogic := common.NewItem()
-		cfgThing.OnCollision = itemLogic.OnCollision
-		cfgThing.OnImpact = itemLogic.OnImpact
+		cfgThing.Behavior = common.NewItemBehavior()
 	}
+	cfgThing.Pickups = buildPickups(int(t.Type))
 	cfgThing.GForce = GForce
 	cfgThing.WakeUpDistance = 500
 	cfgThing.JumpForce = 400
 	return cfgThing
 }
 
-// buildPlayer creates and returns a Player object configured with position, angle, mass, speed, radius, and height.
-func (bld *Builder) buildPlayer(level *Level) *config.Player {
+// buildPlayer creates and returns a Player object configured with position, angle, mass, speed, radius, and height, armed with the Doom arsenal.
+func (bld *Builder) buildPlayer(level *Level, texHandler *Textures) *config.Player {
 	pX, pY, pAngle := float64(0), float64(0), float64(0)
 	for _, t := range level.Things {
 		if t.Type == 1 {
@@ -239,9 +243,9 @@ func (bld *Builder) buildPlayer(level *Level) *config.Player {
 	}
 
 	player := config.NewConfigPlayer(geometry.XYZ{X: pX, Y: pY, Z: 0}, pAngle, playerMass, playerSpeed, playerRadius, playerHeight)
-	playerLogic := common.NewPlayer()
-	player.OnCollision = playerLogic.OnCollision
-	player.OnImpact = playerLogic.OnImpact
+	player.Behavior = common.NewPlayerBehavior()
+	player.Inventory = buildInventory()
+	player.Weapons = buildWeapons(texHandler)
 	player.GForce = GForce
 	player.JumpForce = 1800
 
@@ -278,7 +282,7 @@ func (bld *Builder) calculateOpenDoorCeil(level *Level, secIdx uint16, sector *l
 
 	for _, e := range edges {
 		// Check if the segment has a typical Doom door Action Special
-		if _, ok := _doors[e.LineDef.Function]; ok {
+		if sp, ok := _specials[e.LineDef.Function]; ok && sp.isDoor() {
 			isDoor = true
 		}
 		// Navigate the segment sides to find the adjacent sector
@@ -0,0 +1,87 @@
+package wad
+
+import (
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+// ammoBullets, ammoShells, ammoRockets and ammoCells are the inventory ids of the Doom ammo types.
+const (
+	ammoBullets = "bullets"
+	ammoShells  = "shells"
+	ammoRockets = "rockets"
+	ammoCells   = "cells"
+)
+
+// _pickupDictionary maps the Doom thing types to what they give to the player when touched.
+var _pickupDictionary = map[int][]config.Pickup{
+	// --- CURE E ARMATURE ---
+	2011: {{Kind: config.PickupHealth, Amount: 10, Limit: 100}},  // Stimpack
+	2012: {{Kind: config.PickupHealth, Amount: 25, Limit: 100}},  // Medikit
+	2014: {{Kind: config.PickupHealth, Amount: 1, Limit: 200}},   // Health Bonus
+	2015: {{Kind: config.PickupArmor, Amount: 1, Limit: 200}},    // Armor Bonus
+	2018: {{Kind: config.PickupArmor, Amount: 100, Limit: 100}},  // Green Armor
+	2019: {{Kind: config.PickupArmor, Amount: 200, Limit: 200}},  // Blue Armor
+	2013: {{Kind: config.PickupHealth, Amount: 100, Limit: 200}}, // Soulsphere
+	83: { // Megasphere
+		{Kind: config.PickupHealth, Amount: 200, Limit: 200},
+		{Kind: config.PickupArmor, Amount: 200, Limit: 200},
+	},
+
+	// --- MUNIZIONI ---
+	2007: {{Kind: config.PickupAmmo, Id: ammoBullets, Amount: 10}}, // Ammo clip
+	2048: {{Kind: config.PickupAmmo, Id: ammoBullets, Amount: 50}}, // Box of Ammo
+	2008: {{Kind: config.PickupAmmo, Id: ammoShells, Amount: 4}},   // 4 Shells
+	2049: {{Kind: config.PickupAmmo, Id: ammoShells, Amount: 20}},  // Box of Shells
+	2010: {{Kind: config.PickupAmmo, Id: ammoRockets, Amount: 1}},  // 1 Rocket
+	2046: {{Kind: config.PickupAmmo, Id: ammoRockets, Amount: 5}},  // Box of Rockets
+	2047: {{Kind: config.PickupAmmo, Id: ammoCells, Amount: 20}},   // Energy Cell
+	17:   {{Kind: config.PickupAmmo, Id: ammoCells, Amount: 100}},  // Energy Cell Pack
+	8: { // Backpack
+		{Kind: config.PickupAmmo, Id: ammoBullets, Amount: 10},
+		{Kind: config.PickupAmmo, Id: ammoShells, Amount: 4},
+		{Kind: config.PickupAmmo, Id: ammoRockets, Amount: 1},
+		{Kind: config.PickupAmmo, Id: ammoCells, Amount: 20},
+	},
+
+	// --- ARMI ---
+	2001: {{Kind: config.PickupWeapon, Id: "shotgun"}, {Kind: config.PickupAmmo, Id: ammoShells, Amount: 8}},         // Shotgun
+	82:   {{Kind: config.PickupWeapon, Id: "supershotgun"}, {Kind: config.PickupAmmo, Id: ammoShells, Amount: 8}},    // Super Shotgun
+	2002: {{Kind: config.PickupWeapon, Id: "chaingun"}, {Kind: config.PickupAmmo, Id: ammoBullets, Amount: 20}},      // Chaingun
+	2003: {{Kind: config.PickupWeapon, Id: "rocketlauncher"}, {Kind: config.PickupAmmo, Id: ammoRockets, Amount: 2}}, // Rocket Launcher
+	2004: {{Kind: config.PickupWeapon, Id: "plasmarifle"}, {Kind: config.PickupAmmo, Id: ammoCells, Amount: 40}},     // Plasma Rifle
+	2005: {{Kind: config.PickupWeapon, Id: "chainsaw"}},                                                              // Chainsaw
+	2006: {{Kind: config.PickupWeapon, Id: "bfg"}, {Kind: config.PickupAmmo, Id: ammoCells, Amount: 40}},             // BFG9000
+
+	// --- CHIAVI ---
+	5:  {{Kind: config.PickupKey, Id: keyBlue}},   // Blue Keycard
+	13: {{Kind: config.PickupKey, Id: keyRed}},    // Red Keycard
+	6:  {{Kind: config.PickupKey, Id: keyYellow}}, // Yellow Keycard
+	40: {{Kind: config.PickupKey, Id: keyBlue}},   // Blue Skull Key
+	38: {{Kind: config.PickupKey, Id: keyRed}},    // Red Skull Key
+	39: {{Kind: config.PickupKey, Id: keyYellow}}, // Yellow Skull Key
+}
+
+// buildPickups returns a copy of the pickups of the thing type, or nil if the thing is not a pickup item.
+func buildPickups(thingType int) []*config.Pickup {
+	src, ok := _pickupDictionary[thingType]
+	if !ok {
+		return nil
+	}
+	out := make([]*config.Pickup, 0, len(src))
+	for _, p := range src {
+		out = append(out, config.NewConfigPickup(p.Kind, p.Id, p.Amount, p.Limit))
+	}
+	return out
+}
+
+// buildInventory returns the starting inventory of the Doom player: fist, pistol and 50 bullets.
+func buildInventory() *config.Inventory {
+	inv := config.NewConfigInventory()
+	inv.Weapons = []string{"fist", "pistol"}
+	inv.Ammo[ammoBullets] = 50
+	inv.MaxAmmo[ammoBullets] = 200
+	inv.MaxAmmo[ammoShells] = 50
+	inv.MaxAmmo[ammoRockets] = 50
+	inv.MaxAmmo[ammoCells] = 300
+	return inv
+}
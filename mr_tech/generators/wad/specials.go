@@ -0,0 +1,482 @@
+package wad
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// ticRate is the number of Doom game tics per second, used to convert speeds and delays.
+// doorWaitTics is the time a door stays open before closing.
+// doorCloseWaitTics is the time a "close and open" door stays closed before opening again.
+// liftWaitTics is the time a lift stays down before returning.
+const (
+	ticRate           = 35.0
+	doorWaitTics      = 150.0
+	doorCloseWaitTics = 1050.0
+	liftWaitTics      = 105.0
+)
+
+// sectorSecret is the sector special of the secret areas.
+const sectorSecret = 9
+
+// specialKind identifies the effect of a linedef special.
+type specialKind int
+
+// specialDoorOpenWaitClose opens a door, waits and closes it.
+// specialDoorOpen opens a door and keeps it open.
+// specialDoorClose closes a door and keeps it closed.
+// specialDoorCloseWaitOpen closes a door, waits and opens it again.
+// specialLift lowers a lift, waits and raises it back.
+// specialFloor moves a floor to the target height and keeps it there.
+// specialCeil moves a ceiling to the target height and keeps it there.
+// specialLight changes the light level of the tagged sectors.
+// specialExit ends the level.
+// specialSecretExit ends the level through the secret exit.
+const (
+	specialDoorOpenWaitClose specialKind = iota
+	specialDoorOpen
+	specialDoorClose
+	specialDoorCloseWaitOpen
+	specialLift
+	specialFloor
+	specialCeil
+	specialLight
+	specialExit
+	specialSecretExit
+)
+
+// specialTarget identifies the height or the light level reached by a special.
+type specialTarget int
+
+// targetNone means the special has no target, or the target is implied by its kind (doors).
+// targetLowestFloor is the lowest floor among the sector and its neighbors.
+// targetHighestNeighborFloor is the highest floor among the neighbors.
+// targetHighestNeighborFloorPlus8 is the highest floor among the neighbors raised by 8 units.
+// targetNextHigherFloor is the lowest neighbor floor above the sector floor.
+// targetLowestNeighborCeil is the lowest ceiling among the neighbors.
+// targetHighestNeighborCeil is the highest ceiling among the neighbors.
+// targetFloor is the floor of the sector.
+// targetFloorPlus8 is the floor of the sector raised by 8 units.
+// targetRaise24 is the floor of the sector raised by 24 units.
+// targetBrightestNeighbor is the highest light level among the neighbors.
+// targetDimmestNeighbor is the lowest light level among the neighbors.
+// targetLevel is the absolute light level stored in the special.
+const (
+	targetNone specialTarget = iota
+	targetLowestFloor
+	targetHighestNeighborFloor
+	targetHighestNeighborFloorPlus8
+	targetNextHigherFloor
+	targetLowestNeighborCeil
+	targetHighestNeighborCeil
+	targetFloor
+	targetFloorPlus8
+	targetRaise24
+	targetBrightestNeighbor
+	targetDimmestNeighbor
+	targetLevel
+)
+
+// special describes a linedef action special: how it is activated, what it moves and how fast (map units per tic).
+// Manual specials (D1/DR) act on the sector behind the line instead of the tagged sectors.
+type special struct {
+	activation config.TriggerActivation
+	repeat     bool
+	manual     bool
+	kind       specialKind
+	target     specialTarget
+	speed      float64
+	level      int16
+}
+
+// isDoor reports whether the special moves the ceiling of a door.
+func (s special) isDoor() bool {
+	switch s.kind {
+	case specialDoorOpenWaitClose, specialDoorOpen, specialDoorClose, specialDoorCloseWaitOpen:
+		return true
+	}
+	return false
+}
+
+// _specials maps the vanilla Doom linedef action specials to their runtime description.
+var _specials = map[int16]special{
+	// Manual doors
+	1:   {config.TriggerUse, true, true, specialDoorOpenWaitClose, targetNone, 2, 0},
+	26:  {config.TriggerUse, true, true, specialDoorOpenWaitClose, targetNone, 2, 0},
+	27:  {config.TriggerUse, true, true, specialDoorOpenWaitClose, targetNone, 2, 0},
+	28:  {config.TriggerUse, true, true, specialDoorOpenWaitClose, targetNone, 2, 0},
+	117: {config.TriggerUse, true, true, specialDoorOpenWaitClose, targetNone, 8, 0},
+	31:  {config.TriggerUse, false, true, specialDoorOpen, targetNone, 2, 0},
+	32:  {config.TriggerUse, false, true, specialDoorOpen, targetNone, 2, 0},
+	33:  {config.TriggerUse, false, true, specialDoorOpen, targetNone, 2, 0},
+	34:  {config.TriggerUse, false, true, specialDoorOpen, targetNone, 2, 0},
+	118: {config.TriggerUse, false, true, specialDoorOpen, targetNone, 8, 0},
+	// Remote doors
+	2:   {config.TriggerCross, false, false, specialDoorOpen, targetNone, 2, 0},
+	3:   {config.TriggerCross, false, false, specialDoorClose, targetNone, 2, 0},
+	4:   {config.TriggerCross, false, false, specialDoorOpenWaitClose, targetNone, 2, 0},
+	16:  {config.TriggerCross, false, false, specialDoorCloseWaitOpen, targetNone, 2, 0},
+	29:  {config.TriggerUse, false, false, specialDoorOpenWaitClose, targetNone, 2, 0},
+	42:  {config.TriggerUse, true, false, specialDoorClose, targetNone, 2, 0},
+	46:  {config.TriggerShoot, true, false, specialDoorOpen, targetNone, 2, 0},
+	50:  {config.TriggerUse, false, false, specialDoorClose, targetNone, 2, 0},
+	61:  {config.TriggerUse, true, false, specialDoorOpen, targetNone, 2, 0},
+	63:  {config.TriggerUse, true, false, specialDoorOpenWaitClose, targetNone, 2, 0},
+	75:  {config.TriggerCross, true, false, specialDoorClose, targetNone, 2, 0},
+	76:  {config.TriggerCross, true, false, specialDoorCloseWaitOpen, targetNone, 2, 0},
+	86:  {config.TriggerCross, true, false, specialDoorOpen, targetNone, 2, 0},
+	90:  {config.TriggerCross, true, false, specialDoorOpenWaitClose, targetNone, 2, 0},
+	103: {config.TriggerUse, false, false, specialDoorOpen, targetNone, 2, 0},
+	105: {config.TriggerCross, true, false, specialDoorOpenWaitClose, targetNone, 8, 0},
+	106: {config.TriggerCross, true, false, specialDoorOpen, targetNone, 8, 0},
+	107: {config.TriggerCross, true, false, specialDoorClose, targetNone, 8, 0},
+	108: {config.TriggerCross, false, false, specialDoorOpenWaitClose, targetNone, 8, 0},
+	109: {config.TriggerCross, false, false, specialDoorOpen, targetNone, 8, 0},
+	110: {config.TriggerCross, false, false, specialDoorClose, targetNone, 8, 0},
+	111: {config.TriggerUse, false, false, specialDoorOpenWaitClose, targetNone, 8, 0},
+	112: {config.TriggerUse, false, false, specialDoorOpen, targetNone, 8, 0},
+	113: {config.TriggerUse, false, false, specialDoorClose, targetNone, 8, 0},
+	114: {config.TriggerUse, true, false, specialDoorOpenWaitClose, targetNone, 8, 0},
+	115: {config.TriggerUse, true, false, specialDoorOpen, targetNone, 8, 0},
+	116: {config.TriggerUse, true, false, specialDoorClose, targetNone, 8, 0},
+	// Locked remote doors
+	99:  {config.TriggerUse, true, false, specialDoorOpen, targetNone, 8, 0},
+	133: {config.TriggerUse, false, false, specialDoorOpen, targetNone, 8, 0},
+	134: {config.TriggerUse, true, false, specialDoorOpen, targetNone, 8, 0},
+	135: {config.TriggerUse, false, false, specialDoorOpen, targetNone, 8, 0},
+	136: {config.TriggerUse, true, false, specialDoorOpen, targetNone, 8, 0},
+	137: {config.TriggerUse, false, false, specialDoorOpen, targetNone, 8, 0},
+	// Lifts
+	10:  {config.TriggerCross, false, false, specialLift, targetLowestFloor, 4, 0},
+	21:  {config.TriggerUse, false, false, specialLift, targetLowestFloor, 4, 0},
+	62:  {config.TriggerUse, true, false, specialLift, targetLowestFloor, 4, 0},
+	88:  {config.TriggerCross, true, false, specialLift, targetLowestFloor, 4, 0},
+	120: {config.TriggerCross, true, false, specialLift, targetLowestFloor, 8, 0},
+	121: {config.TriggerCross, false, false, specialLift, targetLowestFloor, 8, 0},
+	122: {config.TriggerUse, false, false, specialLift, targetLowestFloor, 8, 0},
+	123: {config.TriggerUse, true, false, specialLift, targetLowestFloor, 8, 0},
+	// Floors
+	5:   {config.TriggerCross, false, false, specialFloor, targetLowestNeighborCeil, 1, 0},
+	24:  {config.TriggerShoot, false, false, specialFloor, targetLowestNeighborCeil, 1, 0},
+	64:  {config.TriggerUse, true, false, specialFloor, targetLowestNeighborCeil, 1, 0},
+	91:  {config.TriggerCross, true, false, specialFloor, targetLowestNeighborCeil, 1, 0},
+	101: {config.TriggerUse, false, false, specialFloor, targetLowestNeighborCeil, 1, 0},
+	19:  {config.TriggerCross, false, false, specialFloor, targetHighestNeighborFloor, 1, 0},
+	45:  {config.TriggerUse, true, false, specialFloor, targetHighestNeighborFloor, 1, 0},
+	83:  {config.TriggerCross, true, false, specialFloor, targetHighestNeighborFloor, 1, 0},
+	102: {config.TriggerUse, false, false, specialFloor, targetHighestNeighborFloor, 1, 0},
+	36:  {config.TriggerCross, false, false, specialFloor, targetHighestNeighborFloorPlus8, 4, 0},
+	70:  {config.TriggerUse, true, false, specialFloor, targetHighestNeighborFloorPlus8, 4, 0},
+	71:  {config.TriggerUse, false, false, specialFloor, targetHighestNeighborFloorPlus8, 4, 0},
+	98:  {config.TriggerCross, true, false, specialFloor, targetHighestNeighborFloorPlus8, 4, 0},
+	23:  {config.TriggerUse, false, false, specialFloor, targetLowestFloor, 1, 0},
+	38:  {config.TriggerCross, false, false, specialFloor, targetLowestFloor, 1, 0},
+	60:  {config.TriggerUse, true, false, specialFloor, targetLowestFloor, 1, 0},
+	82:  {config.TriggerCross, true, false, specialFloor, targetLowestFloor, 1, 0},
+	18:  {config.TriggerUse, false, false, specialFloor, targetNextHigherFloor, 1, 0},
+	47:  {config.TriggerShoot, false, false, specialFloor, targetNextHigherFloor, 1, 0},
+	69:  {config.TriggerUse, true, false, specialFloor, targetNextHigherFloor, 1, 0},
+	119: {config.TriggerCross, false, false, specialFloor, targetNextHigherFloor, 1, 0},
+	128: {config.TriggerCross, true, false, specialFloor, targetNextHigherFloor, 1, 0},
+	129: {config.TriggerCross, true, false, specialFloor, targetNextHigherFloor, 4, 0},
+	130: {config.TriggerCross, false, false, specialFloor, targetNextHigherFloor, 4, 0},
+	131: {config.TriggerUse, false, false, specialFloor, targetNextHigherFloor, 4, 0},
+	132: {config.TriggerUse, true, false, specialFloor, targetNextHigherFloor, 4, 0},
+	58:  {config.TriggerCross, false, false, specialFloor, targetRaise24, 1, 0},
+	92:  {config.TriggerCross, true, false, specialFloor, targetRaise24, 1, 0},
+	// Ceilings
+	40: {config.TriggerCross, false, false, specialCeil, targetHighestNeighborCeil, 1, 0},
+	41: {config.TriggerUse, false, false, specialCeil, targetFloor, 1, 0},
+	43: {config.TriggerUse, true, false, specialCeil, targetFloor, 1, 0},
+	44: {config.TriggerCross, false, false, specialCeil, targetFloorPlus8, 1, 0},
+	72: {config.TriggerCross, true, false, specialCeil, targetFloorPlus8, 1, 0},
+	// Lights
+	12:  {config.TriggerCross, false, false, specialLight, targetBrightestNeighbor, 0, 0},
+	80:  {config.TriggerCross, true, false, specialLight, targetBrightestNeighbor, 0, 0},
+	104: {config.TriggerCross, false, false, specialLight, targetDimmestNeighbor, 0, 0},
+	13:  {config.TriggerCross, false, false, specialLight, targetLevel, 0, 255},
+	81:  {config.TriggerCross, true, false, specialLight, targetLevel, 0, 255},
+	138: {config.TriggerUse, true, false, specialLight, targetLevel, 0, 255},
+	35:  {config.TriggerCross, false, false, specialLight, targetLevel, 0, 35},
+	79:  {config.TriggerCross, true, false, specialLight, targetLevel, 0, 35},
+	139: {config.TriggerUse, true, false, specialLight, targetLevel, 0, 35},
+	// Exits
+	11:  {config.TriggerUse, false, false, specialExit, targetNone, 0, 0},
+	52:  {config.TriggerCross, false, false, specialExit, targetNone, 0, 0},
+	51:  {config.TriggerUse, false, false, specialSecretExit, targetNone, 0, 0},
+	124: {config.TriggerCross, false, false, specialSecretExit, targetNone, 0, 0},
+}
+
+// keyBlue, keyRed and keyYellow are the inventory keys given by the keycards and the skull keys of the same color.
+const (
+	keyBlue   = "blue"
+	keyRed    = "red"
+	keyYellow = "yellow"
+)
+
+// _specialKeys maps the locked linedef specials to the key needed to activate them.
+var _specialKeys = map[int16]string{
+	26:  keyBlue,
+	32:  keyBlue,
+	99:  keyBlue,
+	133: keyBlue,
+	28:  keyRed,
+	33:  keyRed,
+	134: keyRed,
+	135: keyRed,
+	27:  keyYellow,
+	34:  keyYellow,
+	136: keyYellow,
+	137: keyYellow,
+}
+
+// buildSpecials converts the linedef action specials of the level into IR triggers and the movers they drive.
+// Heights are read from the built sectors, so that doors opened at build time are not animated again.
+func (bld *Builder) buildSpecials(level *Level, vertexes geometry.Polygon, sectors map[int]*config.Sector) ([]*config.Trigger, []*config.Mover) {
+	neighbors := bld.createSectorsNeighbors(level)
+	tagged := make(map[int16][]int)
+	for secIdx, lSector := range level.Sectors {
+		if lSector.Tag != 0 {
+			tagged[lSector.Tag] = append(tagged[lSector.Tag], secIdx)
+		}
+	}
+	var triggers []*config.Trigger
+	var movers []*config.Mover
+	moverIds := make(map[string]bool)
+	for lineIdx, ld := range level.LineDefs {
+		if ld.Function == 0 {
+			continue
+		}
+		sp, ok := _specials[ld.Function]
+		if !ok {
+			continue
+		}
+		var targets []int
+		switch {
+		case sp.kind == specialExit || sp.kind == specialSecretExit:
+		case sp.manual:
+			if ld.SideDefLeft < 0 || int(ld.SideDefLeft) >= len(level.SideDefs) {
+				fmt.Printf("WARNING: manual special %d on one-sided line %d\n", ld.Function, lineIdx)
+				continue
+			}
+			targets = []int{int(level.SideDefs[ld.SideDefLeft].SectorRef)}
+		default:
+			targets = tagged[ld.Tag]
+		}
+		action := config.TriggerActionActivate
+		switch sp.kind {
+		case specialExit:
+			action = config.TriggerActionExit
+		case specialSecretExit:
+			action = config.TriggerActionSecretExit
+		case specialLight:
+			action = config.TriggerActionLight
+		case specialDoorOpenWaitClose:
+			if sp.manual {
+				action = config.TriggerActionToggle
+			}
+		}
+		trigger := config.NewConfigTrigger("line_"+strconv.Itoa(lineIdx), vertexes[ld.VertexStart], vertexes[ld.VertexEnd], sp.activation, action)
+		trigger.Repeat = sp.repeat
+		trigger.Tag = strconv.Itoa(int(ld.Tag))
+		trigger.Key = _specialKeys[ld.Function]
+		for _, secIdx := range targets {
+			cs, ok := sectors[secIdx]
+			if !ok {
+				continue
+			}
+			if sp.kind == specialLight {
+				trigger.Sectors = append(trigger.Sectors, cs.Id)
+				trigger.Light = float64(bld.specialLightLevel(level, sp, secIdx, neighbors[secIdx])) * ScaleLight
+				continue
+			}
+			mover := bld.buildSpecialMover(ld.Function, sp, cs, secIdx, sectors, neighbors[secIdx])
+			if !moverIds[mover.Id] {
+				moverIds[mover.Id] = true
+				movers = append(movers, mover)
+			}
+			trigger.Movers = append(trigger.Movers, mover.Id)
+		}
+		triggers = append(triggers, trigger)
+	}
+	return triggers, movers
+}
+
+// buildSpecialMover creates the mover animating the sector for the given special. Movers are shared by all the lines
+// with the same special acting on the same sector.
+func (bld *Builder) buildSpecialMover(function int16, sp special, cs *config.Sector, secIdx int, sectors map[int]*config.Sector, neighbors []int) *config.Mover {
+	id := fmt.Sprintf("special_%d_%s", function, cs.Id)
+	speed := sp.speed * ticRate * ScaleSectorH
+	var mover *config.Mover
+	switch sp.kind {
+	case specialDoorOpenWaitClose, specialDoorOpen:
+		open := bld.neighborsHeight(sectors, neighbors, math.Inf(1), func(ns *config.Sector, acc float64) float64 { return math.Min(acc, ns.CeilY) })
+		if math.IsInf(open, 1) {
+			open = cs.CeilY
+		} else if open-4*ScaleSectorH >= cs.FloorY {
+			open -= 4 * ScaleSectorH
+		}
+		mover = config.NewConfigMover(id, cs.Id, config.MoverKindCeil, cs.CeilY, math.Max(open, cs.CeilY), speed)
+		mover.Wait = doorWaitTics / ticRate
+		if sp.kind == specialDoorOpen {
+			mover.Wait = -1
+		}
+	case specialDoorClose, specialDoorCloseWaitOpen:
+		mover = config.NewConfigMover(id, cs.Id, config.MoverKindCeil, cs.CeilY, cs.FloorY, speed)
+		mover.Wait = -1
+		if sp.kind == specialDoorCloseWaitOpen {
+			mover.Wait = doorCloseWaitTics / ticRate
+		}
+	case specialLift:
+		low := bld.neighborsHeight(sectors, neighbors, cs.FloorY, func(ns *config.Sector, acc float64) float64 { return math.Min(acc, ns.FloorY) })
+		mover = config.NewConfigMover(id, cs.Id, config.MoverKindFloor, cs.FloorY, low, speed)
+		mover.Wait = liftWaitTics / ticRate
+	case specialCeil:
+		end := bld.specialHeight(sp.target, cs, sectors, neighbors)
+		mover = config.NewConfigMover(id, cs.Id, config.MoverKindCeil, cs.CeilY, math.Max(end, cs.FloorY), speed)
+		mover.Wait = -1
+	default:
+		end := bld.specialHeight(sp.target, cs, sectors, neighbors)
+		if end > cs.FloorY {
+			end = math.Min(end, cs.CeilY)
+		}
+		mover = config.NewConfigMover(id, cs.Id, config.MoverKindFloor, cs.FloorY, end, speed)
+		mover.Wait = -1
+	}
+	return mover
+}
+
+// specialHeight computes the target height of a floor or ceiling special for the given sector.
+func (bld *Builder) specialHeight(target specialTarget, cs *config.Sector, sectors map[int]*config.Sector, neighbors []int) float64 {
+	switch target {
+	case targetLowestFloor:
+		return bld.neighborsHeight(sectors, neighbors, cs.FloorY, func(ns *config.Sector, acc float64) float64 { return math.Min(acc, ns.FloorY) })
+	case targetHighestNeighborFloor, targetHighestNeighborFloorPlus8:
+		h := bld.neighborsHeight(sectors, neighbors, math.Inf(-1), func(ns *config.Sector, acc float64) float64 { return math.Max(acc, ns.FloorY) })
+		if math.IsInf(h, -1) {
+			return cs.FloorY
+		}
+		if target == targetHighestNeighborFloorPlus8 && h != cs.FloorY {
+			h += 8 * ScaleSectorH
+		}
+		return h
+	case targetNextHigherFloor:
+		h := bld.neighborsHeight(sectors, neighbors, math.Inf(1), func(ns *config.Sector, acc float64) float64 {
+			if ns.FloorY > cs.FloorY {
+				return math.Min(acc, ns.FloorY)
+			}
+			return acc
+		})
+		if math.IsInf(h, 1) {
+			return cs.FloorY
+		}
+		return h
+	case targetLowestNeighborCeil:
+		h := bld.neighborsHeight(sectors, neighbors, math.Inf(1), func(ns *config.Sector, acc float64) float64 { return math.Min(acc, ns.CeilY) })
+		if math.IsInf(h, 1) {
+			return cs.CeilY
+		}
+		return h
+	case targetHighestNeighborCeil:
+		h := bld.neighborsHeight(sectors, neighbors, math.Inf(-1), func(ns *config.Sector, acc float64) float64 { return math.Max(acc, ns.CeilY) })
+		if math.IsInf(h, -1) {
+			return cs.CeilY
+		}
+		return h
+	case targetFloor:
+		return cs.FloorY
+	case targetFloorPlus8:
+		return cs.FloorY + 8*ScaleSectorH
+	case targetRaise24:
+		return cs.FloorY + 24*ScaleSectorH
+	}
+	return cs.FloorY
+}
+
+// specialLightLevel computes the light level (0-255) set by a light special on the given sector.
+func (bld *Builder) specialLightLevel(level *Level, sp special, secIdx int, neighbors []int) int16 {
+	current := level.Sectors[secIdx].LightLevel
+	switch sp.target {
+	case targetBrightestNeighbor:
+		for _, n := range neighbors {
+			current = max(current, level.Sectors[n].LightLevel)
+		}
+	case targetDimmestNeighbor:
+		for _, n := range neighbors {
+			current = min(current, level.Sectors[n].LightLevel)
+		}
+	case targetLevel:
+		current = sp.level
+	}
+	return current
+}
+
+// neighborsHeight folds the built neighbor sectors with reduce, starting from init.
+func (bld *Builder) neighborsHeight(sectors map[int]*config.Sector, neighbors []int, init float64, reduce func(ns *config.Sector, acc float64) float64) float64 {
+	acc := init
+	for _, n := range neighbors {
+		if ns, ok := sectors[n]; ok {
+			acc = reduce(ns, acc)
+		}
+	}
+	return acc
+}
+
+// createSectorsNeighbors returns, for each sector index, the indexes of the sectors sharing a two-sided line with it.
+func (bld *Builder) createSectorsNeighbors(level *Level) [][]int {
+	neighbors := make([][]int, len(level.Sectors))
+	seen := make([]map[int]bool, len(level.Sectors))
+	link := func(a, b int) {
+		if a == b {
+			return
+		}
+		if seen[a] == nil {
+			seen[a] = make(map[int]bool)
+		}
+		if !seen[a][b] {
+			seen[a][b] = true
+			neighbors[a] = append(neighbors[a], b)
+		}
+	}
+	for _, ld := range level.LineDefs {
+		if ld.SideDefRight < 0 || ld.SideDefLeft < 0 || int(ld.SideDefRight) >= len(level.SideDefs) || int(ld.SideDefLeft) >= len(level.SideDefs) {
+			continue
+		}
+		right := int(level.SideDefs[ld.SideDefRight].SectorRef)
+		left := int(level.SideDefs[ld.SideDefLeft].SectorRef)
+		if right < 0 || right >= len(level.Sectors) || left < 0 || left >= len(level.Sectors) {
+			continue
+		}
+		link(right, left)
+		link(left, right)
+	}
+	return neighbors
+}
+
+// buildSectorTriggers converts the secret sectors into secret trigger volumes and the tagged sectors into event
+// trigger volumes, carrying the sector tag, that scripts use to react to the things entering and leaving them.
+func (bld *Builder) buildSectorTriggers(level *Level, sectors map[int]*config.Sector) []*config.TriggerVolume {
+	var out []*config.TriggerVolume
+	for secIdx, lSector := range level.Sectors {
+		cs, ok := sectors[secIdx]
+		if !ok {
+			continue
+		}
+		if lSector.SpecialSector == sectorSecret {
+			tv := config.NewConfigSectorTriggerVolume("secret_"+cs.Id, cs.Id, config.TriggerVolumeSecret)
+			tv.PlayerOnly = true
+			tv.Once = true
+			out = append(out, tv)
+		}
+		if lSector.Tag != 0 {
+			tv := config.NewConfigSectorTriggerVolume("tag_"+cs.Id, cs.Id, config.TriggerVolumeEvents)
+			tv.Tag = strconv.Itoa(int(lSector.Tag))
+			out = append(out, tv)
+		}
+	}
+	return out
+}
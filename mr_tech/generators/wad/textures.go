@@ -168,6 +168,18 @@ func (t *Textures) SpriteCreateAnimation(ids []string) []string {
 	return out
 }
 
+// SpriteFrames returns the ids of the given sprite lumps found in the WAD, skipping the missing ones.
+func (t *Textures) SpriteFrames(names ...string) []string {
+	var out []string
+	for _, name := range names {
+		id := SpriteCreateId(name)
+		if _, ok := t.resources[id]; ok {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
 // BuildSprite estrae dal WAD le sequenze fornite e le compatta in un MultiSprite lineare.
 func (t *Textures) BuildSprite(prefix string) *config.MultiSprite {
 	var EnemyStateSequences = [][]byte{
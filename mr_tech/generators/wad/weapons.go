@@ -0,0 +1,108 @@
+package wad
+
+import (
+	"math"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// doomTicRate is the number of game tics per second of Doom, used to convert the weapon timings.
+const doomTicRate = 35.0
+
+// weaponFrameTime is the duration in seconds of each frame of the weapon view models.
+const weaponFrameTime = 4.0 / doomTicRate
+
+// WeaponDef describes a weapon of the Doom arsenal. Tics is the duration of a shot in Doom tics, Damage the average
+// damage of a pellet, Spread the horizontal spread in degrees, Missile the sprite frames of the projectile (when
+// the weapon is a projectile weapon) and Speed its speed in map units per tic. View is the sprite of the view model,
+// whose Idle and Fire frame letters are played in sequence.
+type WeaponDef struct {
+	Id      string
+	Kind    config.WeaponKind
+	Tics    float64
+	Pellets int
+	Damage  float64
+	Spread  float64
+	Range   float64
+	Ammo    string
+	PerShot float64
+	Missile []string
+	Speed   float64
+	View    string
+	Idle    string
+	Fire    string
+}
+
+// _weaponDictionary lists the Doom arsenal in selection order.
+var _weaponDictionary = []WeaponDef{
+	{Id: "fist", Kind: config.WeaponMelee, Tics: 14, Pellets: 1, Damage: 11, Range: 64, View: "PUNG", Idle: "A", Fire: "BCDCB"},
+	{Id: "chainsaw", Kind: config.WeaponMelee, Tics: 4, Pellets: 1, Damage: 11, Range: 65, View: "SAWG", Idle: "C", Fire: "AB"},
+	{Id: "pistol", Kind: config.WeaponHitscan, Tics: 14, Pellets: 1, Damage: 10, Spread: 5.6, Ammo: ammoBullets, PerShot: 1, View: "PISG", Idle: "A", Fire: "BCDE"},
+	{Id: "shotgun", Kind: config.WeaponHitscan, Tics: 37, Pellets: 7, Damage: 10, Spread: 5.6, Ammo: ammoShells, PerShot: 1, View: "SHTG", Idle: "A", Fire: "BCDCB"},
+	{Id: "supershotgun", Kind: config.WeaponHitscan, Tics: 57, Pellets: 20, Damage: 10, Spread: 11.2, Ammo: ammoShells, PerShot: 2, View: "SHT2", Idle: "A", Fire: "BCDEFGHIJ"},
+	{Id: "chaingun", Kind: config.WeaponHitscan, Tics: 4, Pellets: 1, Damage: 10, Spread: 5.6, Ammo: ammoBullets, PerShot: 1, View: "CHGG", Idle: "A", Fire: "AB"},
+	{Id: "rocketlauncher", Kind: config.WeaponProjectile, Tics: 20, Pellets: 1, Damage: 80, Ammo: ammoRockets, PerShot: 1, Missile: []string{"MISLA1"}, Speed: 20, View: "MISG", Idle: "A", Fire: "B"},
+	{Id: "plasmarifle", Kind: config.WeaponProjectile, Tics: 3, Pellets: 1, Damage: 22, Ammo: ammoCells, PerShot: 1, Missile: []string{"PLSSA0", "PLSSB0"}, Speed: 25, View: "PLSG", Idle: "A", Fire: "B"},
+	{Id: "bfg", Kind: config.WeaponProjectile, Tics: 60, Pellets: 1, Damage: 400, Ammo: ammoCells, PerShot: 40, Missile: []string{"BFS1A0", "BFS1B0"}, Speed: 25, View: "BFGG", Idle: "A", Fire: "ABC"},
+}
+
+// buildWeapons maps the Doom arsenal onto the weapon definitions of the player. The damage of the definitions is the
+// impact force, which the default damage factor of the health model halves.
+func buildWeapons(texHandler *Textures) []*config.Weapon {
+	out := make([]*config.Weapon, 0, len(_weaponDictionary))
+	for _, wd := range _weaponDictionary {
+		w := config.NewConfigWeapon(wd.Id, wd.Kind)
+		w.FireRate = doomTicRate / wd.Tics
+		w.Pellets = wd.Pellets
+		w.Damage = wd.Damage * 2
+		w.Spread = wd.Spread * math.Pi / 180.0
+		w.Ammo = wd.Ammo
+		w.AmmoPerShot = wd.PerShot
+		if wd.Range > 0 {
+			w.Range = wd.Range
+		}
+		if wd.Kind == config.WeaponProjectile {
+			w.Projectile = buildMissile(texHandler, wd)
+			w.ProjectileSpeed = wd.Speed * doomTicRate
+		}
+		w.ViewModel = buildViewModel(texHandler, wd)
+		out = append(out, w)
+	}
+	return out
+}
+
+// buildMissile returns the projectile template of the weapon, or nil if its sprites are missing from the WAD.
+func buildMissile(texHandler *Textures, wd WeaponDef) *config.Thing {
+	frames := texHandler.SpriteFrames(wd.Missile...)
+	if len(frames) == 0 {
+		return nil
+	}
+	missile := config.NewConfigThing(wd.Id+"_missile", geometry.XYZ{}, 0, config.ThingThrowableDef, 5, 11, 8, 0)
+	missile.Sprite = config.NewConfigSprite(config.NewConfigMaterial(frames, config.MaterialKindLoop, ScaleWThings, ScaleHThings, 0, 0))
+	missile.GForce = 0
+	missile.Continuous = true
+	return missile
+}
+
+// buildViewModel returns the view model of the weapon, or nil if its sprites are missing from the WAD.
+func buildViewModel(texHandler *Textures, wd WeaponDef) *config.WeaponViewModel {
+	idle := texHandler.SpriteFrames(spriteFrameNames(wd.View, wd.Idle)...)
+	if len(idle) == 0 {
+		return nil
+	}
+	var fire *config.Material
+	if frames := texHandler.SpriteFrames(spriteFrameNames(wd.View, wd.Fire)...); len(frames) > 0 {
+		fire = config.NewConfigMaterial(frames, config.MaterialKindLoop, 1, 1, 0, 0)
+	}
+	return config.NewConfigWeaponViewModel(config.NewConfigMaterial(idle, config.MaterialKindLoop, 1, 1, 0, 0), fire, weaponFrameTime)
+}
+
+// spriteFrameNames returns the lump names of the non-rotated frames of the sprite, one for each frame letter.
+func spriteFrameNames(sprite string, letters string) []string {
+	out := make([]string, 0, len(letters))
+	for _, letter := range letters {
+		out = append(out, sprite+string(letter)+"0")
+	}
+	return out
+}
@@ -14,6 +14,6 @@ func NewBuilder() *Builder {
 // Build constructs a Root configuration by parsing original map data through the Parser, based on the specified level.
 func (b *Builder) Build(level int) (*config.Root, error) {
 	w, h, data := GetOriginalMapData()
-	wp := NewParser(8, 15, true)
+	wp := NewParser(8, 15, false)
 	return wp.Parse(w, h, data)
 }
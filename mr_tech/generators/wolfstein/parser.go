@@ -129,14 +129,9 @@ func (wp *Parser) Parse(width int, height int, md []uint16) (*config.Root, error
 					cfgThing := config.NewConfigThing(id, pos, angle, kind, 10.0, 1, 1, 6)
 					cfgThing.Sprite = config.NewConfigSprite(anim)
 					if cfgThing.Kind == config.ThingEnemyDef {
-						enemyLogic := common.NewEnemy(nil, 100)
-						cfgThing.OnThinking = enemyLogic.OnThinking
-						cfgThing.OnCollision = enemyLogic.OnCollision
-						cfgThing.OnImpact = enemyLogic.OnImpact
+						cfgThing.Behavior = common.NewEnemyBehavior(nil, 100)
 					} else {
-						itemLogic := common.NewItem()
-						cfgThing.OnCollision = itemLogic.OnCollision
-						cfgThing.OnImpact = itemLogic.OnImpact
+						cfgThing.Behavior = common.NewItemBehavior()
 					}
 					root.Things = append(root.Things, cfgThing)
 				}
@@ -170,12 +165,6 @@ func (wp *Parser) Parse(width int, height int, md []uint16) (*config.Root, error
 					wp.addSegment(cs, width, height, pBR, pMidB, x, y+1, cell)
 					wp.addSegment(cs, width, height, pMidB, pBL, x, y+1, cell)
 					wp.addSegment(cs, width, height, pBL, pTL, x-1, y, cell)
-					if !wp.openDoors {
-						doorSeg := config.NewConfigSegment(cs.Id, config.SegmentWall, pMidT, pMidB)
-						anim := config.NewConfigMaterial([]string{"image99.png"}, config.MaterialKindLoop, 1.0, 1.0, 0, 0)
-						doorSeg.Upper, doorSeg.Middle, doorSeg.Lower = anim, anim, anim
-						cs.Segments = append(cs.Segments, doorSeg)
-					}
 					root.Vertices = append(root.Vertices, pTL, pTR, pBR, pBL, pMidT, pMidB)
 				} else {
 					pMidL := geometry.XY{X: x0, Y: y0 + wp.tileSize/2}
@@ -186,14 +175,15 @@ func (wp *Parser) Parse(width int, height int, md []uint16) (*config.Root, error
 					wp.addSegment(cs, width, height, pBR, pBL, x, y+1, cell)
 					wp.addSegment(cs, width, height, pBL, pMidL, x-1, y, cell)
 					wp.addSegment(cs, width, height, pMidL, pTL, x-1, y, cell)
-					if !wp.openDoors {
-						doorSeg := config.NewConfigSegment(cs.Id, config.SegmentWall, pMidL, pMidR)
-						anim := config.NewConfigMaterial([]string{"image98.png"}, config.MaterialKindLoop, 1.0, 1.0, 0, 0)
-						doorSeg.Upper, doorSeg.Middle, doorSeg.Lower = anim, anim, anim
-						cs.Segments = append(cs.Segments, doorSeg)
-					}
 					root.Vertices = append(root.Vertices, pTL, pTR, pBR, pBL, pMidL, pMidR)
 				}
+				if !wp.openDoors {
+					// La porta è un soffitto mobile: chiusa a livello del pavimento, si apre all'avvicinarsi
+					door := config.NewConfigMover("door_"+sid, sid, config.MoverKindCeil, cs.FloorY, cs.CeilY, cs.CeilY-cs.FloorY)
+					door.Trigger = config.MoverTriggerProximity
+					door.Radius = wp.tileSize * 1.5
+					root.Movers = append(root.Movers, door)
+				}
 			} else {
 				wp.addSegment(cs, width, height, pTL, pTR, x, y-1, cell)
 				wp.addSegment(cs, width, height, pTR, pBR, x+1, y, cell)
@@ -213,7 +203,13 @@ func (wp *Parser) Parse(width int, height int, md []uint16) (*config.Root, error
 
 	player := config.NewConfigPlayer(playerPos, 0, 60, 900, 20, playerHeight)
 	root.Player = player
-	playerLogic := common.NewPlayer()
+	// L'arma da lancio scaglia copie del primo nemico del livello
+	for _, t := range root.Things {
+		if t.Kind == config.ThingEnemyDef {
+			player.SetProjectile(t.Id)
+			break
+		}
+	}
 
 	player.GForce = GForce
 	player.JumpForce = 1800
@@ -240,8 +236,7 @@ func (wp *Parser) Parse(width int, height int, md []uint16) (*config.Root, error
 	player.Bobbing.SpringDamping = 0.80
 	player.Bobbing.TiltAmp = 0.05
 
-	root.Player.OnCollision = playerLogic.OnCollision
-	root.Player.OnImpact = playerLogic.OnImpact
+	root.Player.Behavior = common.NewPlayerBehavior()
 	return root, nil
 }
 
@@ -267,10 +262,10 @@ func (wp *Parser) addSegment(cs *config.Sector, width, height int, start, end ge
 	kind := config.SegmentUnknown
 	isAdjDoor := false
 	cell := uint16(1)
-	if _, wall := wp.isWall(width, height, nx, ny); wall {
+	if target, wall := wp.isWall(width, height, nx, ny); wall {
 		kind = config.SegmentWall
 	} else {
-		if isDoor(cell) {
+		if isDoor(wp.mapData[target]) {
 			isAdjDoor = true
 		}
 	}
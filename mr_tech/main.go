@@ -7,14 +7,13 @@ import (
 
 	"github.com/markel1974/godoom/mr_tech/config"
 	"github.com/markel1974/godoom/mr_tech/engine"
+	"github.com/markel1974/godoom/mr_tech/generators/bundle"
 	"github.com/markel1974/godoom/mr_tech/generators/dungeon"
 	"github.com/markel1974/godoom/mr_tech/generators/jedi"
 	"github.com/markel1974/godoom/mr_tech/generators/quake"
 	"github.com/markel1974/godoom/mr_tech/generators/script"
 	"github.com/markel1974/godoom/mr_tech/generators/wad"
 	"github.com/markel1974/godoom/mr_tech/generators/wolfstein"
-	"github.com/markel1974/godoom/mr_tech/renderers/open_gl"
-	"github.com/markel1974/godoom/mr_tech/renderers/software"
 	"github.com/markel1974/godoom/mr_tech/version"
 )
 
@@ -29,22 +28,40 @@ func main() {
 	var showHelp bool
 	var showVersion bool
 	var softwareRender bool
+	var headless bool
+	var leakCheck int
 	var full3d bool
 	var mode int
 	var level int
 	var width int
 	var height int
 	var maxQueue int
+	var ticks int
+	var tickRate float64
+	var bundlePath string
+	var savePath string
+	var deterministic bool
+	var serialResolve bool
+	var seed int64
 
 	flag.BoolVar(&showHelp, "h", false, "show this help")
 	flag.BoolVar(&showVersion, "v", false, "show version")
 	flag.BoolVar(&softwareRender, "s", false, "enable software renderer")
+	flag.BoolVar(&headless, "headless", false, "run the simulation without any window or GL context")
 	flag.BoolVar(&full3d, "d", false, "show this help")
-	flag.IntVar(&mode, "m", 2, "mode 0 = legacy, 1 = Generate, 2 = Doom")
+	flag.IntVar(&mode, "m", 2, "mode 0 = legacy, 1 = Generate, 2 = Doom, 7 = IR bundle")
 	flag.IntVar(&level, "l", 1, "level number")
 	flag.IntVar(&width, "width", 640, "width")
 	flag.IntVar(&height, "height", 480, "height")
 	flag.IntVar(&maxQueue, "queue", 32, "max queue size")
+	flag.IntVar(&ticks, "ticks", 600, "number of simulation ticks in headless mode")
+	flag.StringVar(&bundlePath, "bundle", "level.zip", "IR bundle to load in mode 7 (zip archive or directory)")
+	flag.StringVar(&savePath, "save", "", "convert the level into an IR bundle (zip archive or directory) and exit")
+	flag.Float64Var(&tickRate, "rate", 60, "fixed simulation rate (steps per second)")
+	flag.BoolVar(&deterministic, "deterministic", false, "run the things stages in id order on a single goroutine, for reproducible runs")
+	flag.BoolVar(&serialResolve, "serial", false, "resolve the contacts on a single goroutine, to benchmark the parallel solver")
+	flag.Int64Var(&seed, "seed", 0, "seed of the behaviors random generators (0 = level seed)")
+	flag.IntVar(&leakCheck, "leakcheck", 0, "in headless mode, fire this many projectiles after the run and check for leaked goroutines")
 	flag.Parse()
 
 	if showHelp {
@@ -83,6 +100,9 @@ func main() {
 		quakeFile := "resources" + string(os.PathSeparator) + "quake" + string(os.PathSeparator) + "PAK0.PAK"
 		wb := quake.NewBuilder()
 		cfg, err = wb.Setup(quakeFile, level)
+	case 7:
+		bb := bundle.NewBuilder()
+		cfg, err = bb.Build(bundlePath)
 	default:
 		db := dungeon.NewBuilder()
 		cfg, err = db.Build(level)
@@ -92,24 +112,48 @@ func main() {
 		fmt.Println(err)
 		return
 	}
+	if seed != 0 {
+		cfg.Seed = seed
+	}
+	if savePath != "" {
+		if err = cfg.SaveBundle(savePath); err != nil {
+			fmt.Println(err)
+		}
+		return
+	}
 	//if full3d {
 	//	cfg.Calibration.Full3d = true
 	//}
 	en := engine.NewEngine(maxQueue, 3.0)
+	en.SetTickRate(tickRate, 5)
+	en.SetDeterministic(deterministic)
+	en.SetSerialResolve(serialResolve)
 	if err = en.Setup(cfg); err != nil {
 		fmt.Println(err)
 		return
 	}
 
+	defer en.Close()
+
 	var render IRender
-	if softwareRender {
-		render = software.NewRender(int32(width), int32(height))
-	} else {
-		render = open_gl.NewRender(int32(width), int32(height))
+	var h *engine.Headless
+	if headless {
+		h = engine.NewHeadless(ticks, engine.DefaultHeadlessScript)
+		render = h
+	} else if render, err = newRender(softwareRender, int32(width), int32(height)); err != nil {
+		fmt.Println(err)
+		return
 	}
 	if err = render.Setup(en); err != nil {
 		fmt.Println(err)
 		return
 	}
 	render.Start()
+	if h != nil && leakCheck > 0 {
+		if err = h.LeakCheck(leakCheck); err != nil {
+			fmt.Println(err)
+			en.Close()
+			os.Exit(1)
+		}
+	}
 }
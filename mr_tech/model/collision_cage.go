@@ -113,6 +113,9 @@ type CageEntry struct {
 // GetRemoteFace retrieves the remote Face associated with this CageEntry.
 func (s *CageEntry) GetRemoteFace() *Face { return s.rFace }
 
+// GetRemoteCage returns the cage of the remote thing, or nil for a contact with the level geometry.
+func (s *CageEntry) GetRemoteCage() *CollisionCage { return s.rCage }
+
 // GetDistance returns the distance value of the CageEntry.
 func (s *CageEntry) GetDistance() float64 { return s.dist }
 
@@ -282,6 +285,7 @@ type CollisionCage struct {
 	buckets             [BucketSize]*CollisionBucket
 	ellipsoid           *physics.Entity
 	ellipsoidLocal      [4]*physics.Entity
+	obb                 *physics.OBB
 	cX, cY, cZ          float64
 	dX, dY, dZ          float64
 	tX, tY, tZ          float64
@@ -301,6 +305,7 @@ func NewCollisionCage(object IThing) *CollisionCage {
 		seen:      make(map[*CollisionCage]bool),
 		object:    object,
 		ellipsoid: physics.NewEntity(0, 0, 0, 0),
+		obb:       physics.NewOBB(),
 		volume:    nil,
 		slots:     make([]*CageEntry, TotalSlots),
 		slotsLen:  0,
@@ -328,7 +333,33 @@ func (s *CollisionCage) Rebuild(maxStep float64) {
 	s.cX, s.cY, s.cZ = entity.GetCenter()
 	// Calculate Position
 	s.dX, s.dY, s.dZ = entity.GetDisplacement()
+	s.rebuildSwept()
+
+	for i := 0; i < len(s.buckets); i++ {
+		s.buckets[i].Rebuild()
+	}
+
+	s.volume = nil
+	s.distance = math.MaxFloat64
+
+	s.slotsLen = 0
+
+	for k := range s.seen {
+		delete(s.seen, k)
+	}
+	s.maxStep = maxStep
+}
 
+// Clamp shortens the displacement of the step to the fraction toi (a time of impact in [0, 1]), so that the cage
+// stops where a continuous sweep found the first contact.
+func (s *CollisionCage) Clamp(toi float64) {
+	toi = math.Max(0, math.Min(1, toi))
+	s.dX, s.dY, s.dZ = s.dX*toi, s.dY*toi, s.dZ*toi
+	s.rebuildSwept()
+}
+
+// rebuildSwept updates the target position and the broad-phase swept volume from the center and the displacement.
+func (s *CollisionCage) rebuildSwept() {
 	s.tX, s.tY, s.tZ = s.cX+s.dX, s.cY+s.dY, s.cZ+s.dZ
 
 	// Calculate absolute extremes (Broad-Phase Swept Volume)
@@ -341,20 +372,17 @@ func (s *CollisionCage) Rebuild(maxStep float64) {
 
 	// Canonical mapping for Rect/AABB
 	s.ellipsoid.Rebuild(minX, minY, minZ, maxX-minX, maxY-minY, maxZ-minZ)
+	s.object.GetEntity().SweptOBB(s.obb, s.dX, s.dY, s.dZ)
+}
 
-	for i := 0; i < len(s.buckets); i++ {
-		s.buckets[i].Rebuild()
-	}
-
-	s.volume = nil
-	s.distance = math.MaxFloat64
-
-	s.slotsLen = 0
-
-	for k := range s.seen {
-		delete(s.seen, k)
+// OverlapsOriented is the narrow phase of two cages whose things may be rotated: it tests their oriented boxes swept
+// along the step, where the broad phase tests the AABBs enclosing them. The box of a thing not simulated in the step
+// (rSimulated false) is tested at rest.
+func (s *CollisionCage) OverlapsOriented(rCage *CollisionCage, rSimulated bool) bool {
+	if !rSimulated {
+		rCage.object.GetEntity().SweptOBB(rCage.obb, 0, 0, 0)
 	}
-	s.maxStep = maxStep
+	return s.obb.Overlaps(rCage.obb)
 }
 
 // AddFace adds a face to the CollisionCage, expanding the storage if necessary to accommodate new entries.
@@ -12,12 +12,21 @@ import (
 
 // Compiler represents a core game engine component for managing world, game objects, player interactions, and things.
 type Compiler struct {
-	gScale      geometry.XYZ
-	volumes     *Volumes
-	player      *ThingPlayer
-	lights      *Lights
-	things      *Things
-	calibration *Calibration
+	gScale         geometry.XYZ
+	volumes        *Volumes
+	player         *ThingPlayer
+	lights         *Lights
+	things         *Things
+	movers         *Movers
+	elevators      *Elevators
+	specials       *Specials
+	triggerVolumes *TriggerVolumes
+	joints         *Joints
+	spawners       *Spawners
+	navMesh        *NavMesh
+	query          *Query
+	perception     *Perception
+	calibration    *Calibration
 }
 
 // NewCompiler initializes and returns a new instance of Compiler with default-nil-initialized fields.
@@ -31,7 +40,15 @@ func NewCompiler() *Compiler {
 }
 
 // Compile initializes and processes game data from the provided configuration, returning an error if compilation fails.
+// The IR is validated first: warnings are printed, while error diagnostics abort the compilation.
 func (r *Compiler) Compile(cfg *config.Root) error {
+	diags := cfg.Validate()
+	if len(diags) > 0 {
+		fmt.Print(config.FormatDiagnostics(diags))
+	}
+	if diags.HasErrors() {
+		return fmt.Errorf("invalid configuration: %d errors, %d warnings", len(diags.Filter(config.DiagnosticError)), len(diags.Filter(config.DiagnosticWarning)))
+	}
 	r.gScale = cfg.ScaleFactor
 	if r.gScale.X == 0 {
 		r.gScale.X = 1
@@ -72,6 +89,15 @@ func (r *Compiler) Compile(cfg *config.Root) error {
 			}
 			cfg.Things[idx].Position.Z = tv.GetMinZ()
 		}
+		//spawn points Z
+		for _, spawner := range cfg.Spawners {
+			for idx := range spawner.Points {
+				sx, sy := spawner.Points[idx].Position.X, spawner.Points[idx].Position.Y
+				if sv := locator.QueryPoint(sx, sy); sv != nil {
+					spawner.Points[idx].Position.Z = sv.GetMinZ()
+				}
+			}
+		}
 		//light 2d
 		r.lights.AddLights(r.compileLights2d(locator, true))
 
@@ -86,17 +112,75 @@ func (r *Compiler) Compile(cfg *config.Root) error {
 	r.volumes.Setup()
 
 	r.lights.AddLights(r.compileLights(cfg.Lights))
-	r.things = NewThings(r.gScale, 10, cfg.Things, r.volumes, materials)
+	r.things = NewThings(r.gScale, 10, cfg.Seed, cfg.Things, r.volumes, materials)
+	r.navMesh = NewNavMesh(r.volumes)
+	r.things.SetNavMesh(r.navMesh)
+	r.query = NewQuery(r.volumes, r.things)
+	r.things.SetQuery(r.query)
+	r.perception = NewPerception(r.navMesh, r.query)
+	r.things.SetPerception(r.perception)
 	r.player = NewThingPlayer(r.things, cfg.Player, r.volumes, false)
 	if r.player == nil {
 		return fmt.Errorf("player not found")
 	}
 	r.things.SetPlayer(r.player)
+	r.movers = NewMovers(cfg.Movers, r.volumes, r.things)
+	r.elevators = NewElevators(cfg.Elevators, r.volumes, r.things)
+	r.specials = NewSpecials(cfg.Triggers, r.movers, r.elevators, r.volumes)
+	r.triggerVolumes = NewTriggerVolumes(cfg.TriggerVolumes, r.specials, r.volumes, r.things)
+	r.things.SetTriggerVolumes(r.triggerVolumes)
+	r.joints = NewJoints(cfg.Joints, r.things)
+	r.spawners = NewSpawners(cfg.Spawners, r.volumes, r.things)
 	r.calibration = NewCalibration(cfg.Calibration, r.volumes)
 	fmt.Printf("Scan complete world: %d\n", r.volumes.Len())
 	return nil
 }
 
+// GetMovers returns the sector movers created by the Compiler.
+func (r *Compiler) GetMovers() *Movers {
+	return r.movers
+}
+
+// GetElevators returns the scripted sector elevators created by the Compiler.
+func (r *Compiler) GetElevators() *Elevators {
+	return r.elevators
+}
+
+// GetSpecials returns the line specials interpreter created by the Compiler.
+func (r *Compiler) GetSpecials() *Specials {
+	return r.specials
+}
+
+// GetTriggerVolumes returns the trigger volumes created by the Compiler.
+func (r *Compiler) GetTriggerVolumes() *TriggerVolumes {
+	return r.triggerVolumes
+}
+
+// GetJoints returns the physics joints created by the Compiler.
+func (r *Compiler) GetJoints() *Joints {
+	return r.joints
+}
+
+// GetSpawners returns the spawners created by the Compiler.
+func (r *Compiler) GetSpawners() *Spawners {
+	return r.spawners
+}
+
+// GetNavMesh returns the navigation mesh built by the Compiler.
+func (r *Compiler) GetNavMesh() *NavMesh {
+	return r.navMesh
+}
+
+// GetQuery returns the world query API on the volumes and the things compiled by the Compiler.
+func (r *Compiler) GetQuery() *Query {
+	return r.query
+}
+
+// GetPerception returns the perception of the things built by the Compiler.
+func (r *Compiler) GetPerception() *Perception {
+	return r.perception
+}
+
 // GetThings returns the Things instance managed by the Compiler.
 func (r *Compiler) GetThings() *Things {
 	return r.things
@@ -281,175 +365,20 @@ func (r *Compiler) upgrade3d(sectors []*Sector) []*Volume {
 	var volumes3d []*Volume
 	volMap := make(map[*Sector]*Volume)
 
-	// resolveZ calcola la Z per pavimento e soffitto nel punto (X,Y)
-	resolveZ := func(sector *Sector, p geometry.XYZ, baseF, baseC float64, clamp bool) (float64, float64) {
-		zF := baseF
-		slopeF, slopeC := sector.GetSlopes()
-		if slopeF != nil {
-			slopeX, slopeY := slopeF.Nx*slopeF.Gradient, slopeF.Ny*slopeF.Gradient
-			slopeZ := baseF - (slopeX * slopeF.Start.X) - (slopeY * slopeF.Start.Y)
-			zF = slopeZ + (slopeX * p.X) + (slopeY * p.Y)
-		}
-		zC := baseC
-		if slopeC != nil {
-			slopeX, slopeY := slopeC.Nx*slopeC.Gradient, slopeC.Ny*slopeC.Gradient
-			slopeZ := baseC - (slopeX * slopeC.Start.X) - (slopeY * slopeC.Start.Y)
-			zC = slopeZ + (slopeX * p.X) + (slopeY * p.Y)
-		}
-		if clamp {
-			// Clamping per evitare che il pavimento superi il soffitto
-			if zF > zC {
-				mid := (zF + zC) * 0.5
-				return mid, mid
-			}
-		}
-		return zF, zC
-	}
-
-	// buildQuad ora accetta s (Start) ed e (End) come XY per permettere lo split parametrico
-	buildQuad := func(vol3d *Volume, neighbor *Sector, s, e geometry.XY, zBS, zBE, zTS, zTE float64, tag string, material *textures.Material) {
-		v0 := geometry.XYZ{X: s.X, Y: s.Y, Z: zBS} // Bottom-Start
-		v1 := geometry.XYZ{X: e.X, Y: e.Y, Z: zBE} // Bottom-End
-		v2 := geometry.XYZ{X: e.X, Y: e.Y, Z: zTE} // Top-End
-		v3 := geometry.XYZ{X: s.X, Y: s.Y, Z: zTS} // Top-Start
-
-		faceT1 := NewFace([3]geometry.XYZ{v0, v1, v2}, tag, material)
-		faceT2 := NewFace([3]geometry.XYZ{v0, v2, v3}, tag, material)
-		vol3d.AddFace(faceT1)
-		vol3d.AddFace(faceT2)
-	}
-
 	for _, sector := range sectors {
 		id := fmt.Sprintf("%s_3d", sector.GetId())
 		vol3d := NewVolumeConcrete(sector.GetModelId(), id, sector.GetTag())
 		if sector.light != nil {
 			vol3d.SetLight(sector.light)
 		}
-
-		segments, segmentCount := sector.GetSegments()
-		if segmentCount != 3 {
+		if !buildSectorFaces(vol3d, sector) {
 			fmt.Println("only tringle are supported")
 			continue
 		}
-
-		curFloorY := sector.GetMinZ()
-		curCeilY := sector.GetMaxZ()
-
-		p0, p1, p2 := segments[0].GetStart(), segments[1].GetStart(), segments[2].GetStart()
-
-		zF0, zC0 := resolveZ(sector, p0, curFloorY, curCeilY, false)
-		zF1, zC1 := resolveZ(sector, p1, curFloorY, curCeilY, false)
-		zF2, zC2 := resolveZ(sector, p2, curFloorY, curCeilY, false)
-
-		ceilP := [3]geometry.XYZ{{X: p0.X, Y: p0.Y, Z: zC0}, {X: p1.X, Y: p1.Y, Z: zC1}, {X: p2.X, Y: p2.Y, Z: zC2}}
-		vol3d.AddFace(NewFace(ceilP, sector.GetTag()+"_ceil", sector.GetMaterialIndex(1)))
-
-		floorP := [3]geometry.XYZ{{X: p0.X, Y: p0.Y, Z: zF0}, {X: p2.X, Y: p2.Y, Z: zF2}, {X: p1.X, Y: p1.Y, Z: zF1}}
-		vol3d.AddFace(NewFace(floorP, sector.GetTag()+"_floor", sector.GetMaterialIndex(0)))
-
-		for x := 0; x < segmentCount; x++ {
-			seg := segments[x]
-			s, e := seg.GetStart(), seg.GetEnd()
-
-			curFS, curCS := resolveZ(sector, s, curFloorY, curCeilY, false)
-			curFE, curCE := resolveZ(sector, e, curFloorY, curCeilY, false)
-			neighbor := seg.GetNeighbor()
-
-			if neighbor == nil {
-				s2 := geometry.XY{X: s.X, Y: s.Y}
-				e2 := geometry.XY{X: e.X, Y: e.Y}
-				buildQuad(vol3d, nil, s2, e2, curFS, curFE, curCS, curCE, seg.GetTag(), seg.GetMaterialIndex(1))
-				continue
-			}
-
-			neiFloorY := neighbor.GetMinZ()
-			neiCeilY := neighbor.GetMaxZ()
-
-			neiFS, neiCS := resolveZ(neighbor, s, neiFloorY, neiCeilY, false)
-			neiFE, neiCE := resolveZ(neighbor, e, neiFloorY, neiCeilY, false)
-
-			tagLower := seg.GetTag() + "_lower"
-			tagUpper := seg.GetTag() + "_upper"
-			matLower := seg.GetMaterialIndex(2)
-			matUpper := seg.GetMaterialIndex(0)
-
-			// ==========================================
-			// LOWER WALL: Scontro tra pavimenti inclinati
-			// ==========================================
-			diffFS := neiFS - curFS
-			diffFE := neiFE - curFE
-
-			// Rilevamento Crossover (i piani si incrociano lungo il segmento)
-			if (diffFS > 0 && diffFE < 0) || (diffFS < 0 && diffFE > 0) {
-				t := diffFS / (diffFS - diffFE)
-				midXY := geometry.XY{X: s.X + t*(e.X-s.X), Y: s.Y + t*(e.Y-s.Y)}
-				midZ := curFS + t*(curFE-curFS) // Al punto mid, curZ == neiZ
-
-				// Segmento 1: Da Start a Mid
-				zLowS1, zHighS1 := curFS, max(curFS, neiFS)
-				zLowE1, zHighE1 := midZ, midZ
-				if zHighS1 > zLowS1 || zHighE1 > zLowE1 {
-					s2 := geometry.XY{X: s.X, Y: s.Y}
-					buildQuad(vol3d, seg.GetNeighbor(), s2, midXY, zLowS1, zLowE1, zHighS1, zHighE1, tagLower, matLower)
-				}
-				// Segmento 2: Da Mid a End
-				zLowS2, zHighS2 := midZ, midZ
-				zLowE2, zHighE2 := curFE, max(curFE, neiFE)
-				if zHighS2 > zLowS2 || zHighE2 > zLowE2 {
-					e2 := geometry.XY{X: e.X, Y: e.Y}
-					buildQuad(vol3d, seg.GetNeighbor(), midXY, e2, zLowS2, zLowE2, zHighS2, zHighE2, tagLower, matLower)
-				}
-			} else {
-				// Muro lineare standard
-				zLowS, zHighS := curFS, max(curFS, neiFS)
-				zLowE, zHighE := curFE, max(curFE, neiFE)
-				if zHighS > zLowS || zHighE > zLowE {
-					s2 := geometry.XY{X: s.X, Y: s.Y}
-					e2 := geometry.XY{X: e.X, Y: e.Y}
-					buildQuad(vol3d, seg.GetNeighbor(), s2, e2, zLowS, zLowE, zHighS, zHighE, tagLower, matLower)
-				}
-			}
-
-			// ==========================================
-			// UPPER WALL: Scontro tra soffitti inclinati
-			// ==========================================
-			diffCS := neiCS - curCS
-			diffCE := neiCE - curCE
-
-			// Rilevamento Crossover (i piani si incrociano lungo il segmento)
-			if (diffCS > 0 && diffCE < 0) || (diffCS < 0 && diffCE > 0) {
-				t := diffCS / (diffCS - diffCE)
-				midXY := geometry.XY{X: s.X + t*(e.X-s.X), Y: s.Y + t*(e.Y-s.Y)}
-				midZ := curCS + t*(curCE-curCS)
-				// Segmento 1: Da Start a Mid
-				zTopS1, zBotS1 := curCS, min(curCS, neiCS)
-				zTopE1, zBotE1 := midZ, midZ
-				if zBotS1 < zTopS1 || zBotE1 < zTopE1 {
-					s2 := geometry.XY{X: s.X, Y: s.Y}
-					buildQuad(vol3d, seg.GetNeighbor(), s2, midXY, zBotS1, zBotE1, zTopS1, zTopE1, tagUpper, matUpper)
-				}
-				// Segmento 2: Da Mid a End
-				zTopS2, zBotS2 := midZ, midZ
-				zTopE2, zBotE2 := curCE, min(curCE, neiCE)
-				if zBotS2 < zTopS2 || zBotE2 < zTopE2 {
-					e2 := geometry.XY{X: e.X, Y: e.Y}
-					buildQuad(vol3d, seg.GetNeighbor(), midXY, e2, zBotS2, zBotE2, zTopS2, zTopE2, tagUpper, matUpper)
-				}
-			} else {
-				// Muro lineare standard
-				zTopS, zBotS := curCS, min(curCS, neiCS)
-				zTopE, zBotE := curCE, min(curCE, neiCE)
-				if zBotS < zTopS || zBotE < zTopE {
-					s2 := geometry.XY{X: s.X, Y: s.Y}
-					e2 := geometry.XY{X: e.X, Y: e.Y}
-					buildQuad(vol3d, seg.GetNeighbor(), s2, e2, zBotS, zBotE, zTopS, zTopE, tagUpper, matUpper)
-				}
-			}
-		}
-
 		vol3d.Rebuild()
 		volumes3d = append(volumes3d, vol3d)
 		vol3d.SetSector(sector)
+		sector.SetVolume(vol3d)
 		volMap[sector] = vol3d
 	}
 
@@ -468,6 +397,167 @@ func (r *Compiler) upgrade3d(sectors []*Sector) []*Volume {
 	return volumes3d
 }
 
+// resolveSectorZ computes the floor and ceiling heights of the sector at (X,Y), following the floor and ceiling slopes.
+func resolveSectorZ(sector *Sector, p geometry.XYZ, baseF, baseC float64, clamp bool) (float64, float64) {
+	zF := baseF
+	slopeF, slopeC := sector.GetSlopes()
+	if slopeF != nil {
+		slopeX, slopeY := slopeF.Nx*slopeF.Gradient, slopeF.Ny*slopeF.Gradient
+		slopeZ := baseF - (slopeX * slopeF.Start.X) - (slopeY * slopeF.Start.Y)
+		zF = slopeZ + (slopeX * p.X) + (slopeY * p.Y)
+	}
+	zC := baseC
+	if slopeC != nil {
+		slopeX, slopeY := slopeC.Nx*slopeC.Gradient, slopeC.Ny*slopeC.Gradient
+		slopeZ := baseC - (slopeX * slopeC.Start.X) - (slopeY * slopeC.Start.Y)
+		zC = slopeZ + (slopeX * p.X) + (slopeY * p.Y)
+	}
+	if clamp {
+		// Clamping per evitare che il pavimento superi il soffitto
+		if zF > zC {
+			mid := (zF + zC) * 0.5
+			return mid, mid
+		}
+	}
+	return zF, zC
+}
+
+// addWallQuad adds to the volume the two triangles of the wall quad between s (Start) and e (End), allowing parametric splits.
+func addWallQuad(vol3d *Volume, s, e geometry.XY, zBS, zBE, zTS, zTE float64, tag string, material *textures.Material) {
+	v0 := geometry.XYZ{X: s.X, Y: s.Y, Z: zBS} // Bottom-Start
+	v1 := geometry.XYZ{X: e.X, Y: e.Y, Z: zBE} // Bottom-End
+	v2 := geometry.XYZ{X: e.X, Y: e.Y, Z: zTE} // Top-End
+	v3 := geometry.XYZ{X: s.X, Y: s.Y, Z: zTS} // Top-Start
+
+	vol3d.PutFace([3]geometry.XYZ{v0, v1, v2}, tag, material)
+	vol3d.PutFace([3]geometry.XYZ{v0, v2, v3}, tag, material)
+}
+
+// buildSectorFaces generates the floor, ceiling and wall faces of the triangular sector into vol3d, using the current
+// heights of the sector and of its neighbors. It returns false if the sector is not a triangle.
+func buildSectorFaces(vol3d *Volume, sector *Sector) bool {
+	segments, segmentCount := sector.GetSegments()
+	if segmentCount != 3 {
+		return false
+	}
+
+	curFloorY := sector.GetMinZ()
+	curCeilY := sector.GetMaxZ()
+
+	p0, p1, p2 := segments[0].GetStart(), segments[1].GetStart(), segments[2].GetStart()
+
+	zF0, zC0 := resolveSectorZ(sector, p0, curFloorY, curCeilY, false)
+	zF1, zC1 := resolveSectorZ(sector, p1, curFloorY, curCeilY, false)
+	zF2, zC2 := resolveSectorZ(sector, p2, curFloorY, curCeilY, false)
+
+	ceilP := [3]geometry.XYZ{{X: p0.X, Y: p0.Y, Z: zC0}, {X: p1.X, Y: p1.Y, Z: zC1}, {X: p2.X, Y: p2.Y, Z: zC2}}
+	vol3d.PutFace(ceilP, sector.GetTag()+"_ceil", sector.GetMaterialIndex(1))
+
+	floorP := [3]geometry.XYZ{{X: p0.X, Y: p0.Y, Z: zF0}, {X: p2.X, Y: p2.Y, Z: zF2}, {X: p1.X, Y: p1.Y, Z: zF1}}
+	vol3d.PutFace(floorP, sector.GetTag()+"_floor", sector.GetMaterialIndex(0))
+
+	for x := 0; x < segmentCount; x++ {
+		seg := segments[x]
+		s, e := seg.GetStart(), seg.GetEnd()
+
+		curFS, curCS := resolveSectorZ(sector, s, curFloorY, curCeilY, false)
+		curFE, curCE := resolveSectorZ(sector, e, curFloorY, curCeilY, false)
+		neighbor := seg.GetNeighbor()
+
+		if neighbor == nil {
+			s2 := geometry.XY{X: s.X, Y: s.Y}
+			e2 := geometry.XY{X: e.X, Y: e.Y}
+			addWallQuad(vol3d, s2, e2, curFS, curFE, curCS, curCE, seg.GetTag(), seg.GetMaterialIndex(1))
+			continue
+		}
+
+		neiFloorY := neighbor.GetMinZ()
+		neiCeilY := neighbor.GetMaxZ()
+
+		neiFS, neiCS := resolveSectorZ(neighbor, s, neiFloorY, neiCeilY, false)
+		neiFE, neiCE := resolveSectorZ(neighbor, e, neiFloorY, neiCeilY, false)
+
+		tagLower := seg.GetTag() + "_lower"
+		tagUpper := seg.GetTag() + "_upper"
+		matLower := seg.GetMaterialIndex(2)
+		matUpper := seg.GetMaterialIndex(0)
+
+		// ==========================================
+		// LOWER WALL: Scontro tra pavimenti inclinati
+		// ==========================================
+		diffFS := neiFS - curFS
+		diffFE := neiFE - curFE
+
+		// Rilevamento Crossover (i piani si incrociano lungo il segmento)
+		if (diffFS > 0 && diffFE < 0) || (diffFS < 0 && diffFE > 0) {
+			t := diffFS / (diffFS - diffFE)
+			midXY := geometry.XY{X: s.X + t*(e.X-s.X), Y: s.Y + t*(e.Y-s.Y)}
+			midZ := curFS + t*(curFE-curFS) // Al punto mid, curZ == neiZ
+
+			// Segmento 1: Da Start a Mid
+			zLowS1, zHighS1 := curFS, max(curFS, neiFS)
+			zLowE1, zHighE1 := midZ, midZ
+			if zHighS1 > zLowS1 || zHighE1 > zLowE1 {
+				s2 := geometry.XY{X: s.X, Y: s.Y}
+				addWallQuad(vol3d, s2, midXY, zLowS1, zLowE1, zHighS1, zHighE1, tagLower, matLower)
+			}
+			// Segmento 2: Da Mid a End
+			zLowS2, zHighS2 := midZ, midZ
+			zLowE2, zHighE2 := curFE, max(curFE, neiFE)
+			if zHighS2 > zLowS2 || zHighE2 > zLowE2 {
+				e2 := geometry.XY{X: e.X, Y: e.Y}
+				addWallQuad(vol3d, midXY, e2, zLowS2, zLowE2, zHighS2, zHighE2, tagLower, matLower)
+			}
+		} else {
+			// Muro lineare standard
+			zLowS, zHighS := curFS, max(curFS, neiFS)
+			zLowE, zHighE := curFE, max(curFE, neiFE)
+			if zHighS > zLowS || zHighE > zLowE {
+				s2 := geometry.XY{X: s.X, Y: s.Y}
+				e2 := geometry.XY{X: e.X, Y: e.Y}
+				addWallQuad(vol3d, s2, e2, zLowS, zLowE, zHighS, zHighE, tagLower, matLower)
+			}
+		}
+
+		// ==========================================
+		// UPPER WALL: Scontro tra soffitti inclinati
+		// ==========================================
+		diffCS := neiCS - curCS
+		diffCE := neiCE - curCE
+
+		// Rilevamento Crossover (i piani si incrociano lungo il segmento)
+		if (diffCS > 0 && diffCE < 0) || (diffCS < 0 && diffCE > 0) {
+			t := diffCS / (diffCS - diffCE)
+			midXY := geometry.XY{X: s.X + t*(e.X-s.X), Y: s.Y + t*(e.Y-s.Y)}
+			midZ := curCS + t*(curCE-curCS)
+			// Segmento 1: Da Start a Mid
+			zTopS1, zBotS1 := curCS, min(curCS, neiCS)
+			zTopE1, zBotE1 := midZ, midZ
+			if zBotS1 < zTopS1 || zBotE1 < zTopE1 {
+				s2 := geometry.XY{X: s.X, Y: s.Y}
+				addWallQuad(vol3d, s2, midXY, zBotS1, zBotE1, zTopS1, zTopE1, tagUpper, matUpper)
+			}
+			// Segmento 2: Da Mid a End
+			zTopS2, zBotS2 := midZ, midZ
+			zTopE2, zBotE2 := curCE, min(curCE, neiCE)
+			if zBotS2 < zTopS2 || zBotE2 < zTopE2 {
+				e2 := geometry.XY{X: e.X, Y: e.Y}
+				addWallQuad(vol3d, midXY, e2, zBotS2, zBotE2, zTopS2, zTopE2, tagUpper, matUpper)
+			}
+		} else {
+			// Muro lineare standard
+			zTopS, zBotS := curCS, min(curCS, neiCS)
+			zTopE, zBotE := curCE, min(curCE, neiCE)
+			if zBotS < zTopS || zBotE < zTopE {
+				s2 := geometry.XY{X: s.X, Y: s.Y}
+				e2 := geometry.XY{X: e.X, Y: e.Y}
+				addWallQuad(vol3d, s2, e2, zBotS, zBotE, zTopS, zTopE, tagUpper, matUpper)
+			}
+		}
+	}
+	return true
+}
+
 // compile3d constructs 3D volumes from configurations and materials, linking geometry and calculating adjacency portals.
 func (r *Compiler) compile3d(volumes []*config.Volume, anim *Materials) []*Volume {
 	totalFaces := 0
@@ -478,6 +568,7 @@ func (r *Compiler) compile3d(volumes []*config.Volume, anim *Materials) []*Volum
 	for _, cv := range volumes {
 		// cv.Id and cv.Tag come from the BSP parser
 		volume := NewVolumeConcrete(modelSectorId, cv.Id, cv.Tag)
+		volume.SetCollision(cv.Layer, cv.Mask)
 		modelSectorId++
 		for _, cf := range cv.Faces {
 			pts := cf.Points
@@ -0,0 +1,190 @@
+package model
+
+import (
+	"math"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+// ElevatorState represents the current phase of an Elevator.
+type ElevatorState int
+
+// ElevatorMoving means the elevator is moving towards the current stop.
+// ElevatorWaiting means the elevator waits at the current stop before moving to the next one.
+// ElevatorHolding means the elevator stays at the current stop until it receives a message.
+// ElevatorTerminated means the elevator stays at the current stop forever.
+const (
+	ElevatorMoving ElevatorState = iota
+	ElevatorWaiting
+	ElevatorHolding
+	ElevatorTerminated
+)
+
+// Elevator animates the floor height, the ceiling height or the light of a group of sectors through a cyclic list of
+// stops, as the elevators of the Dark Forces INF scripts. Height elevators drive an internal Mover plane, so volumes
+// rebuild and things riding the floor behave as with doors and lifts; an obstacle simply stops the elevator.
+type Elevator struct {
+	id      string
+	kind    config.ElevatorKind
+	sectors []*Sector
+	plane   *Mover
+	speed   float64
+	stops   []*config.ElevatorStop
+	current int
+	value   float64
+	state   ElevatorState
+	timer   float64
+}
+
+// NewElevator creates an Elevator for the given sectors, placed at its start stop. volumes contains every volume whose
+// faces depend on the sectors heights. A zero speed moves the elevator instantly.
+func NewElevator(cfg *config.Elevator, sectors []*Sector, volumes []*Volume) *Elevator {
+	speed := math.Abs(cfg.Speed)
+	if speed == 0 {
+		speed = math.Inf(1)
+	}
+	e := &Elevator{
+		id:      cfg.Id,
+		kind:    cfg.Kind,
+		sectors: sectors,
+		speed:   speed,
+		stops:   cfg.Stops,
+		current: cfg.Start,
+	}
+	e.value = e.stops[e.current].Value
+	if e.kind != config.ElevatorKindLight {
+		kind := config.MoverKindFloor
+		if e.kind == config.ElevatorKindCeil {
+			kind = config.MoverKindCeil
+		}
+		cm := config.NewConfigMover(cfg.Id, "", kind, e.value, e.value, e.speed)
+		e.plane = NewMover(cm, sectors, volumes)
+	}
+	e.arrive()
+	return e
+}
+
+// GetId returns the identifier of the Elevator.
+func (e *Elevator) GetId() string {
+	return e.id
+}
+
+// GetKind returns the property animated by the Elevator.
+func (e *Elevator) GetKind() config.ElevatorKind {
+	return e.kind
+}
+
+// GetState returns the current phase of the Elevator.
+func (e *Elevator) GetState() ElevatorState {
+	return e.state
+}
+
+// GetStop returns the index of the current (or target) stop.
+func (e *Elevator) GetStop() int {
+	return e.current
+}
+
+// GetValue returns the current height (or light intensity) of the Elevator.
+func (e *Elevator) GetValue() float64 {
+	return e.value
+}
+
+// GetSectors returns the sectors animated by the Elevator.
+func (e *Elevator) GetSectors() []*Sector {
+	return e.sectors
+}
+
+// Goto moves the Elevator towards the stop with the given index. A terminated elevator ignores the request.
+func (e *Elevator) Goto(stop int) {
+	if e.state == ElevatorTerminated || len(e.stops) == 0 {
+		return
+	}
+	n := len(e.stops)
+	e.current = ((stop % n) + n) % n
+	e.state = ElevatorMoving
+}
+
+// Next moves the Elevator towards the next stop, wrapping around the last one.
+func (e *Elevator) Next() {
+	e.Goto(e.current + 1)
+}
+
+// Prev moves the Elevator towards the previous stop, wrapping around the first one.
+func (e *Elevator) Prev() {
+	e.Goto(e.current - 1)
+}
+
+// Trigger moves a waiting or holding Elevator to its next stop; a moving elevator ignores it.
+func (e *Elevator) Trigger() {
+	switch e.state {
+	case ElevatorWaiting, ElevatorHolding:
+		e.Next()
+	}
+}
+
+// Compute advances the Elevator by dt seconds and returns the stop reached during the step, or nil.
+func (e *Elevator) Compute(dt float64, volumes *Volumes, things *Things) *config.ElevatorStop {
+	switch e.state {
+	case ElevatorWaiting:
+		e.timer -= dt
+		if e.timer <= 0 {
+			e.Next()
+		}
+	case ElevatorMoving:
+		if e.move(dt, volumes, things) {
+			return e.arrive()
+		}
+	}
+	return nil
+}
+
+// move advances the Elevator towards the current stop, returning true when the stop has been reached.
+func (e *Elevator) move(dt float64, volumes *Volumes, things *Things) bool {
+	target := e.stops[e.current].Value
+	if e.plane == nil {
+		next := target
+		if step := e.speed * dt; math.Abs(target-e.value) > step {
+			next = e.value + math.Copysign(step, target-e.value)
+		}
+		e.value = next
+		for _, s := range e.sectors {
+			if light := s.GetLight(); light != nil {
+				light.SetIntensity(e.value)
+			}
+		}
+		return e.value == target
+	}
+	// Il piano non deve mai invertire la corsa: un ostacolo ferma l'elevatore
+	e.plane.state = MoverForward
+	done := e.plane.move(target, dt, volumes, things)
+	e.value = e.plane.GetHeight()
+	return done
+}
+
+// arrive applies the action of the current stop and returns it.
+func (e *Elevator) arrive() *config.ElevatorStop {
+	stop := e.stops[e.current]
+	switch stop.Action {
+	case config.ElevatorStopWait:
+		e.state = ElevatorWaiting
+		e.timer = stop.Wait
+	case config.ElevatorStopHold:
+		e.state = ElevatorHolding
+	default:
+		e.state = ElevatorTerminated
+	}
+	return stop
+}
+
+// place writes the current value into the sectors, used once at creation.
+func (e *Elevator) place(volumes *Volumes) {
+	if e.plane != nil {
+		e.plane.apply(volumes)
+		return
+	}
+	for _, s := range e.sectors {
+		if light := s.GetLight(); light != nil {
+			light.SetIntensity(e.value)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+// Elevators manages the scripted sector elevators of the world and dispatches the messages they exchange.
+type Elevators struct {
+	container []*Elevator
+	cache     map[string]*Elevator
+	volumes   *Volumes
+	things    *Things
+	completed bool
+}
+
+// NewElevators creates the elevators described by the configuration, binding them to the compiled sectors with the
+// referenced ids. Elevators without sectors or stops are skipped with a warning. Every elevator is placed at its start stop.
+func NewElevators(cfg []*config.Elevator, volumes *Volumes, things *Things) *Elevators {
+	es := &Elevators{
+		cache:   make(map[string]*Elevator),
+		volumes: volumes,
+		things:  things,
+	}
+	sectors := sectorsById(volumes)
+	for _, ce := range cfg {
+		if len(ce.Stops) == 0 || ce.Start < 0 || ce.Start >= len(ce.Stops) {
+			fmt.Printf("Warning invalid stops for elevator %s\n", ce.Id)
+			continue
+		}
+		var group []*Sector
+		for _, id := range ce.Sectors {
+			group = append(group, sectors[id]...)
+		}
+		if len(group) == 0 {
+			fmt.Printf("Warning can't find sectors for elevator %s\n", ce.Id)
+			continue
+		}
+		e := NewElevator(ce, group, affectedVolumes(group))
+		e.place(volumes)
+		es.container = append(es.container, e)
+		es.cache[e.GetId()] = e
+	}
+	return es
+}
+
+// GetElevator retrieves an Elevator by its identifier, or nil if it does not exist.
+func (es *Elevators) GetElevator(id string) *Elevator {
+	return es.cache[id]
+}
+
+// GetElevators returns all the elevators.
+func (es *Elevators) GetElevators() []*Elevator {
+	return es.container
+}
+
+// Len returns the number of elevators.
+func (es *Elevators) Len() int {
+	return len(es.container)
+}
+
+// IsCompleted reports whether an elevator stop or a message has completed the level.
+func (es *Elevators) IsCompleted() bool {
+	return es.completed
+}
+
+// Send delivers the message to the elevator with the given identifier, returning false if it does not exist.
+// The complete message is accepted with any target.
+func (es *Elevators) Send(id string, message string, param int) bool {
+	if message == config.ElevatorMessageComplete {
+		es.complete(id)
+		return true
+	}
+	e, ok := es.cache[id]
+	if !ok {
+		return false
+	}
+	switch message {
+	case config.ElevatorMessageTrigger:
+		e.Trigger()
+	case config.ElevatorMessageNextStop:
+		e.Next()
+	case config.ElevatorMessagePrevStop:
+		e.Prev()
+	case config.ElevatorMessageGotoStop:
+		e.Goto(param)
+	case config.ElevatorMessageDone:
+	default:
+		fmt.Printf("Warning unknown message '%s' for elevator %s\n", message, id)
+	}
+	return true
+}
+
+// Compute advances all the elevators by dt seconds and delivers the messages of the reached stops. It must run
+// before the things solver, outside the things stages.
+func (es *Elevators) Compute(dt float64) {
+	for _, e := range es.container {
+		stop := e.Compute(dt, es.volumes, es.things)
+		if stop == nil {
+			continue
+		}
+		if stop.Action == config.ElevatorStopComplete {
+			es.complete(e.GetId())
+		}
+		for _, m := range stop.Messages {
+			es.Send(m.Target, m.Message, m.Param)
+		}
+	}
+}
+
+// complete marks the level as completed.
+func (es *Elevators) complete(source string) {
+	if es.completed {
+		return
+	}
+	es.completed = true
+	fmt.Printf("level completed by elevator %s\n", source)
+}
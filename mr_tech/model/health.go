@@ -0,0 +1,111 @@
+package model
+
+import (
+	"math"
+	"sync"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+// Health is the runtime health and armor of a damageable thing. Damage can be applied concurrently by the things
+// stages, so the state is guarded by a mutex.
+type Health struct {
+	mu     sync.Mutex
+	cfg    *config.Health
+	health float64
+	armor  float64
+	dead   bool
+}
+
+// NewHealth creates the runtime Health described by the configuration, or nil for an invulnerable thing.
+func NewHealth(cfg *config.Health) *Health {
+	if cfg == nil {
+		return nil
+	}
+	return &Health{
+		cfg:    cfg,
+		health: cfg.Health,
+		armor:  cfg.Armor,
+	}
+}
+
+// GetHealth returns the current health.
+func (h *Health) GetHealth() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.health
+}
+
+// GetArmor returns the current armor.
+func (h *Health) GetArmor() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.armor
+}
+
+// IsDead reports whether the health has dropped to zero.
+func (h *Health) IsDead() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.dead
+}
+
+// Damage converts the impact force into damage through the damage factor and the resistance to kind, absorbs part of
+// it with the armor and subtracts the rest from the health. It returns the damage taken by the health and whether
+// this damage killed the thing; a dead thing takes no further damage.
+func (h *Health) Damage(kind config.DamageType, force float64) (float64, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.dead {
+		return 0, false
+	}
+	damage := force * h.cfg.DamageFactor * h.cfg.GetResistance(kind)
+	if damage <= 0 {
+		return 0, false
+	}
+	absorbed := math.Min(h.armor, damage*h.cfg.ArmorAbsorb)
+	h.armor -= absorbed
+	taken := damage - absorbed
+	h.health -= taken
+	if h.health <= 0 {
+		h.health = 0
+		h.dead = true
+	}
+	return taken, h.dead
+}
+
+// Heal adds amount to the health, up to limit when positive, returning the health actually added. A dead thing
+// can't be healed.
+func (h *Health) Heal(amount float64, limit float64) float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.dead {
+		return 0
+	}
+	return addLimited(&h.health, amount, limit)
+}
+
+// AddArmor adds amount to the armor, up to limit when positive, returning the armor actually added.
+func (h *Health) AddArmor(amount float64, limit float64) float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.dead {
+		return 0
+	}
+	return addLimited(&h.armor, amount, limit)
+}
+
+// addLimited adds amount to value without exceeding limit (when positive) and returns the amount actually added.
+// A value already above the limit is left unchanged.
+func addLimited(value *float64, amount float64, limit float64) float64 {
+	target := *value + amount
+	if limit > 0 {
+		target = math.Min(target, limit)
+	}
+	if target <= *value {
+		return 0
+	}
+	added := target - *value
+	*value = target
+	return added
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+// newTestHealth returns a health of 100 with 30 armor absorbing half of the damage, converting the force one to one.
+func newTestHealth() *Health {
+	cfg := config.NewConfigHealth(100)
+	cfg.Armor = 30
+	cfg.ArmorAbsorb = 0.5
+	cfg.DamageFactor = 1
+	cfg.Resistances = map[config.DamageType]float64{config.DamageExplosion: 2, config.DamageFall: 0}
+	return NewHealth(cfg)
+}
+
+func TestHealthDamage(t *testing.T) {
+	if NewHealth(nil) != nil {
+		t.Fatal("a thing without health configuration is not invulnerable")
+	}
+	tests := []struct {
+		name          string
+		kind          config.DamageType
+		force         float64
+		taken         float64
+		health, armor float64
+	}{
+		{"armor absorbs", config.DamageBullet, 20, 10, 90, 20},
+		{"resistance", config.DamageExplosion, 10, 10, 90, 20},
+		{"immune", config.DamageFall, 50, 0, 100, 30},
+		// L'armatura assorbe al massimo quanto le resta
+		{"armor runs out", config.DamageBullet, 80, 50, 50, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newTestHealth()
+			taken, killed := h.Damage(test.kind, test.force)
+			if math.Abs(taken-test.taken) > 1e-9 || killed {
+				t.Fatalf("taken %f, killed %t, want %f and alive", taken, killed, test.taken)
+			}
+			if math.Abs(h.GetHealth()-test.health) > 1e-9 || math.Abs(h.GetArmor()-test.armor) > 1e-9 {
+				t.Fatalf("health %f and armor %f, want %f and %f", h.GetHealth(), h.GetArmor(), test.health, test.armor)
+			}
+		})
+	}
+}
+
+func TestHealthDeath(t *testing.T) {
+	h := newTestHealth()
+	if _, killed := h.Damage(config.DamageMelee, 1000); !killed || !h.IsDead() || h.GetHealth() != 0 {
+		t.Fatalf("killed %t, dead %t, health %f after a lethal damage", killed, h.IsDead(), h.GetHealth())
+	}
+	// Un corpo non subisce altri danni, non viene curato e non riceve armatura
+	if taken, killed := h.Damage(config.DamageMelee, 10); taken != 0 || killed {
+		t.Fatalf("the dead thing took %f damage, killed %t", taken, killed)
+	}
+	if h.Heal(50, 0) != 0 || h.AddArmor(50, 0) != 0 || h.GetHealth() != 0 {
+		t.Fatal("the dead thing has been healed")
+	}
+}
+
+func TestHealthHealLimit(t *testing.T) {
+	h := newTestHealth()
+	// L'armatura assorbe 30 dei 60 di danno e si esaurisce
+	h.Damage(config.DamageGeneric, 60)
+	if added := h.Heal(25, 100); added != 25 || h.GetHealth() != 95 {
+		t.Fatalf("added %f, health %f, want 25 and 95", added, h.GetHealth())
+	}
+	if added := h.Heal(25, 100); added != 5 || h.GetHealth() != 100 {
+		t.Fatalf("added %f, health %f, want 5 and 100 at the limit", added, h.GetHealth())
+	}
+	if added := h.Heal(25, 0); added != 25 || h.GetHealth() != 125 {
+		t.Fatalf("added %f, health %f, want 25 and 125 without limit", added, h.GetHealth())
+	}
+	// Oltre il limite il valore non viene ridotto
+	if added := h.Heal(10, 100); added != 0 || h.GetHealth() != 125 {
+		t.Fatalf("added %f, health %f, want 0 and 125 above the limit", added, h.GetHealth())
+	}
+	if added := h.AddArmor(100, 50); added != 50 || h.GetArmor() != 50 {
+		t.Fatalf("added %f armor, armor %f, want 50 and 50", added, h.GetArmor())
+	}
+}
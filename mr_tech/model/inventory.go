@@ -0,0 +1,144 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+// Inventory is the runtime inventory of the player: ammo, weapons and keys. The behaviors can query it from the
+// things stages, so the state is guarded by a mutex.
+type Inventory struct {
+	mu      sync.Mutex
+	ammo    map[string]float64
+	maxAmmo map[string]float64
+	weapons map[string]bool
+	keys    map[string]bool
+}
+
+// NewInventory creates the runtime Inventory from the starting inventory of the configuration, which can be nil.
+func NewInventory(cfg *config.Inventory) *Inventory {
+	inv := &Inventory{
+		ammo:    make(map[string]float64),
+		maxAmmo: make(map[string]float64),
+		weapons: make(map[string]bool),
+		keys:    make(map[string]bool),
+	}
+	if cfg == nil {
+		return inv
+	}
+	for id, amount := range cfg.Ammo {
+		inv.ammo[normalizeItemId(id)] = amount
+	}
+	for id, amount := range cfg.MaxAmmo {
+		inv.maxAmmo[normalizeItemId(id)] = amount
+	}
+	for _, id := range cfg.Weapons {
+		inv.weapons[normalizeItemId(id)] = true
+	}
+	for _, id := range cfg.Keys {
+		inv.keys[normalizeItemId(id)] = true
+	}
+	return inv
+}
+
+// normalizeItemId returns the case-insensitive form of an ammo, weapon or key id.
+func normalizeItemId(id string) string {
+	return strings.ToLower(strings.TrimSpace(id))
+}
+
+// GetAmmo returns the amount of the ammo type.
+func (inv *Inventory) GetAmmo(id string) float64 {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	return inv.ammo[normalizeItemId(id)]
+}
+
+// AddAmmo adds amount of the ammo type, up to limit (when positive) and to the inventory maximum, returning the
+// amount actually added.
+func (inv *Inventory) AddAmmo(id string, amount float64, limit float64) float64 {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	id = normalizeItemId(id)
+	current := inv.ammo[id]
+	target := current + amount
+	if limit > 0 {
+		target = math.Min(target, limit)
+	}
+	if max, ok := inv.maxAmmo[id]; ok {
+		target = math.Min(target, max)
+	}
+	if target <= current {
+		return 0
+	}
+	inv.ammo[id] = target
+	return target - current
+}
+
+// UseAmmo removes amount of the ammo type, returning false (and removing nothing) if there is not enough.
+func (inv *Inventory) UseAmmo(id string, amount float64) bool {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	id = normalizeItemId(id)
+	if inv.ammo[id] < amount {
+		return false
+	}
+	inv.ammo[id] -= amount
+	return true
+}
+
+// HasWeapon reports whether the weapon is owned.
+func (inv *Inventory) HasWeapon(id string) bool {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	return inv.weapons[normalizeItemId(id)]
+}
+
+// AddWeapon adds the weapon, returning false if it was already owned.
+func (inv *Inventory) AddWeapon(id string) bool {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	id = normalizeItemId(id)
+	if inv.weapons[id] {
+		return false
+	}
+	inv.weapons[id] = true
+	return true
+}
+
+// HasKey reports whether the key is owned. The empty key is always owned.
+func (inv *Inventory) HasKey(id string) bool {
+	if id == "" {
+		return true
+	}
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	return inv.keys[normalizeItemId(id)]
+}
+
+// AddKey adds the key, returning false if it was already owned.
+func (inv *Inventory) AddKey(id string) bool {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	id = normalizeItemId(id)
+	if inv.keys[id] {
+		return false
+	}
+	inv.keys[id] = true
+	return true
+}
+
+// GetKeys returns the owned keys, sorted.
+func (inv *Inventory) GetKeys() []string {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	out := make([]string, 0, len(inv.keys))
+	for id := range inv.keys {
+		out = append(out, id)
+	}
+	sort.Strings(out)
+	return out
+}
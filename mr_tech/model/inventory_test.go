@@ -0,0 +1,70 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+func TestInventoryAmmo(t *testing.T) {
+	cfg := config.NewConfigInventory()
+	cfg.Ammo["Bullets"] = 10
+	cfg.MaxAmmo["bullets"] = 50
+	inv := NewInventory(cfg)
+
+	// Gli identificativi non distinguono maiuscole e spazi
+	if inv.GetAmmo(" BULLETS ") != 10 {
+		t.Fatalf("ammo %f, want 10", inv.GetAmmo("bullets"))
+	}
+	if added := inv.AddAmmo("bullets", 30, 0); added != 30 || inv.GetAmmo("bullets") != 40 {
+		t.Fatalf("added %f, ammo %f, want 30 and 40", added, inv.GetAmmo("bullets"))
+	}
+	if added := inv.AddAmmo("bullets", 30, 0); added != 10 || inv.GetAmmo("bullets") != 50 {
+		t.Fatalf("added %f, ammo %f, want 10 and 50 at the inventory maximum", added, inv.GetAmmo("bullets"))
+	}
+	if added := inv.AddAmmo("shells", 30, 8); added != 8 || inv.GetAmmo("shells") != 8 {
+		t.Fatalf("added %f shells, ammo %f, want 8 and 8 at the pickup limit", added, inv.GetAmmo("shells"))
+	}
+
+	if !inv.UseAmmo("bullets", 20) || inv.GetAmmo("bullets") != 30 {
+		t.Fatalf("ammo %f after a shot of 20, want 30", inv.GetAmmo("bullets"))
+	}
+	// Senza munizioni sufficienti non viene tolto nulla
+	if inv.UseAmmo("bullets", 31) || inv.GetAmmo("bullets") != 30 {
+		t.Fatalf("a shot of 31 succeeded or changed the ammo to %f", inv.GetAmmo("bullets"))
+	}
+	if inv.UseAmmo("rockets", 1) {
+		t.Fatal("a missing ammo type has been used")
+	}
+}
+
+func TestInventoryWeaponsAndKeys(t *testing.T) {
+	cfg := config.NewConfigInventory()
+	cfg.Weapons = []string{"Pistol"}
+	cfg.Keys = []string{"Red"}
+	inv := NewInventory(cfg)
+
+	if !inv.HasWeapon("pistol") || inv.HasWeapon("shotgun") {
+		t.Fatal("the starting weapons have not been loaded")
+	}
+	if !inv.AddWeapon("Shotgun") || inv.AddWeapon("shotgun") || !inv.HasWeapon("SHOTGUN") {
+		t.Fatal("a new weapon must be added once")
+	}
+	if !inv.HasKey("") {
+		t.Fatal("the empty key is not owned")
+	}
+	if !inv.HasKey("red") || inv.HasKey("blue") {
+		t.Fatal("the starting keys have not been loaded")
+	}
+	if !inv.AddKey("Blue") || inv.AddKey("blue") {
+		t.Fatal("a new key must be added once")
+	}
+	if keys := inv.GetKeys(); !reflect.DeepEqual(keys, []string{"blue", "red"}) {
+		t.Fatalf("keys %v, want [blue red]", keys)
+	}
+
+	if empty := NewInventory(nil); empty.GetAmmo("bullets") != 0 || empty.HasWeapon("pistol") || len(empty.GetKeys()) != 0 {
+		t.Fatal("an inventory without configuration is not empty")
+	}
+}
@@ -0,0 +1,137 @@
+package model
+
+import "github.com/markel1974/godoom/mr_tech/config"
+
+// sleepSpeed is the speed, linear plus angular, under which a simulated thing is considered at rest.
+// sleepTime is the time, in seconds, a whole contact island must stay at rest before it is put to sleep.
+const (
+	sleepSpeed = 0.5
+	sleepTime  = 0.5
+)
+
+// SleepStats counts the things handled by the sleeping of the solver: the things simulated and the contact islands
+// built in the last step, the things sleeping, and the things put to sleep and woken in the last step.
+type SleepStats struct {
+	Awake    int
+	Islands  int
+	Sleeping int
+	Slept    int
+	Woken    int
+}
+
+// GetSleepStats returns the sleeping counters of the last step.
+func (th *Things) GetSleepStats() SleepStats {
+	return th.sleepStats
+}
+
+// updateSleep advances the sleep timers of the things simulated in the last step and groups them in contact islands,
+// joining the things that touch each other or share a joint; sleeping things touched or joined by them are woken with
+// their islands. An island whose members have all been at rest for sleepTime is put to sleep as a whole, so that a
+// stack does not fall asleep while a thing below it is still moving. The player never sleeps, and neither does its
+// island.
+func (th *Things) updateSleep() {
+	th.sleepStep++
+	if cap(th.islandParent) < th.activeIdx {
+		th.islandParent = make([]int, th.activeIdx*2)
+		th.islandAwake = make([]bool, th.activeIdx*2)
+	}
+	parent := th.islandParent[:th.activeIdx]
+	awake := th.islandAwake[:th.activeIdx]
+	for x := 0; x < th.activeIdx; x++ {
+		base := th.active[x].GetBase()
+		base.islandSlot = x
+		base.islandStep = th.sleepStep
+		parent[x] = x
+		awake[x] = false
+		if base.GetKind() == config.ThingPlayerDef || base.GetEntity().GetSpeedSq() > sleepSpeed*sleepSpeed {
+			base.sleepTimer = 0
+			continue
+		}
+		base.sleepTimer += th.dt
+	}
+	for x := 0; x < th.activeIdx; x++ {
+		cage := th.active[x].GetCage()
+		for i := 0; i < cage.GetSlotsLen(); i++ {
+			rCage := cage.GetSlot(i).GetRemoteCage()
+			if rCage == nil {
+				continue
+			}
+			other := rCage.GetThing().GetBase()
+			if other.islandStep == th.sleepStep {
+				islandUnion(parent, x, other.islandSlot)
+				continue
+			}
+			// Un corpo addormentato toccato da uno sveglio viene svegliato con la sua isola
+			other.Wake()
+		}
+	}
+	for _, j := range th.joints {
+		if j.b == nil {
+			continue
+		}
+		a, b := j.a.GetBase(), j.b.GetBase()
+		switch inA, inB := a.islandStep == th.sleepStep, b.islandStep == th.sleepStep; {
+		case inA && inB:
+			islandUnion(parent, a.islandSlot, b.islandSlot)
+		case inA:
+			b.Wake()
+		case inB:
+			a.Wake()
+		}
+	}
+	// Un'isola resta sveglia se anche un solo membro non è a riposo da abbastanza tempo
+	islands := 0
+	for x := 0; x < th.activeIdx; x++ {
+		root := islandFind(parent, x)
+		if root == x {
+			islands++
+		}
+		if th.active[x].GetBase().sleepTimer < sleepTime {
+			awake[root] = true
+		}
+	}
+	th.sleepStats.Awake = th.activeIdx
+	th.sleepStats.Islands = islands
+	var sleeping map[int][]IThing
+	for x := 0; x < th.activeIdx; x++ {
+		root := islandFind(parent, x)
+		if awake[root] {
+			continue
+		}
+		if sleeping == nil {
+			sleeping = make(map[int][]IThing)
+		}
+		sleeping[root] = append(sleeping[root], th.active[x])
+	}
+	for _, island := range sleeping {
+		for _, member := range island {
+			base := member.GetBase()
+			base.GetEntity().Stop()
+			base.sleeping = true
+			base.island = island
+		}
+		th.sleepStats.Sleeping += len(island)
+		th.sleepStats.Slept += len(island)
+	}
+}
+
+// islandFind returns the root of the island of x, halving the path.
+func islandFind(parent []int, x int) int {
+	for parent[x] != x {
+		parent[x] = parent[parent[x]]
+		x = parent[x]
+	}
+	return x
+}
+
+// islandUnion joins the islands of a and b.
+func islandUnion(parent []int, a, b int) {
+	ra, rb := islandFind(parent, a), islandFind(parent, b)
+	if ra == rb {
+		return
+	}
+	if ra > rb {
+		ra, rb = rb, ra
+	}
+	parent[rb] = ra
+}
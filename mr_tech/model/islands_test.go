@@ -0,0 +1,145 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// newBoxTemplate returns a small box without sprite made from the first item of the level.
+func newBoxTemplate(tb testing.TB, cfg *config.Root) *config.Thing {
+	tb.Helper()
+	for _, ct := range cfg.Things {
+		if ct.Kind == config.ThingItemDef {
+			box := ct.Clone()
+			box.Sprite, box.MultiSprite, box.MD1 = nil, nil, nil
+			box.Radius, box.Height = 8, 16
+			return box
+		}
+	}
+	tb.Fatal("the level has no item")
+	return nil
+}
+
+// newSleepScene compiles the test level without its things and places two boxes side by side, touching with a
+// slight penetration, in a room far from the player. It returns the world, the boxes and their template.
+func newSleepScene(tb testing.TB) (*Compiler, IThing, IThing, *config.Thing) {
+	tb.Helper()
+	var box *config.Thing
+	c := newTestWorld(tb, func(cfg *config.Root) {
+		box = newBoxTemplate(tb, cfg)
+		cfg.Things = nil
+	})
+	things := c.GetThings()
+	things.SetDeterministic(true)
+	pos := farPoint(tb, c)
+	a := things.Spawn(box, pos, 0)
+	if a == nil {
+		tb.Fatal("the first box has not been spawned")
+	}
+	pos.X += a.GetEntity().GetWidth() - satFilterEpsilon*0.5
+	b := things.Spawn(box, pos, 0)
+	if b == nil {
+		tb.Fatal("the second box has not been spawned")
+	}
+	return c, a, b, box
+}
+
+// press pushes the awake boxes against each other slower than sleepSpeed, so that they stay simulated while at rest
+// for the solver, as the members of a stack pressed by gravity.
+func press(a, b IThing) {
+	const creep = sleepSpeed * 0.8
+	if !a.GetBase().IsSleeping() {
+		a.GetEntity().SetV(creep, 0, 0)
+	}
+	if !b.GetBase().IsSleeping() {
+		b.GetEntity().SetV(-creep, 0, 0)
+	}
+}
+
+func TestSleepIsland(t *testing.T) {
+	c, a, b, _ := newSleepScene(t)
+	things := c.GetThings()
+	dt := things.GetTimeStep()
+	// Le cose generate entrano nella simulazione al primo passo
+	press(a, b)
+	stepWorld(c, 1)
+	for step := 1; step <= 5; step++ {
+		press(a, b)
+		stepWorld(c, 1)
+		if timer := a.GetBase().sleepTimer; timer < float64(step)*dt-1e-9 || timer > float64(step+1)*dt+1e-9 {
+			t.Fatalf("step %d: sleep timer %f, want it advanced by %f at each step at rest", step, timer, dt)
+		}
+	}
+	if stats := things.GetSleepStats(); stats.Awake != 3 || stats.Islands != 2 {
+		t.Fatalf("%d things simulated in %d islands, want the player and one island of two boxes", stats.Awake, stats.Islands)
+	}
+
+	// Un membro a riposo da abbastanza tempo non dorme finché l'altro non lo è
+	b.GetBase().sleepTimer = sleepTime
+	press(a, b)
+	stepWorld(c, 1)
+	if b.GetBase().IsSleeping() || a.GetBase().IsSleeping() {
+		t.Fatal("a box has been put to sleep before its whole island")
+	}
+	steps := 0
+	for !a.GetBase().IsSleeping() && steps < int(sleepTime/dt)*2 {
+		press(a, b)
+		stepWorld(c, 1)
+		steps++
+		if a.GetBase().IsSleeping() != b.GetBase().IsSleeping() {
+			t.Fatalf("step %d: sleeping %t and %t, want the island put to sleep as a whole", steps, a.GetBase().IsSleeping(), b.GetBase().IsSleeping())
+		}
+	}
+	if !a.GetBase().IsSleeping() {
+		t.Fatalf("the island at rest is still awake after %d steps", steps)
+	}
+	if stats := things.GetSleepStats(); stats.Slept != 2 || stats.Sleeping != 2 {
+		t.Fatalf("%d things put to sleep, %d sleeping, want the two boxes", stats.Slept, stats.Sleeping)
+	}
+	if a.GetBase().sleepTimer < sleepTime || len(a.GetBase().island) != 2 {
+		t.Fatalf("timer %f and island of %d things, want %f and 2", a.GetBase().sleepTimer, len(a.GetBase().island), sleepTime)
+	}
+	stepWorld(c, 5)
+	if !a.GetBase().IsSleeping() || !b.GetBase().IsSleeping() {
+		t.Fatal("the island has woken up by itself")
+	}
+}
+
+func TestSleepIslandWakeByProjectile(t *testing.T) {
+	c, a, b, box := newSleepScene(t)
+	things := c.GetThings()
+	for steps := 0; !a.GetBase().IsSleeping(); steps++ {
+		if steps > int(sleepTime/things.GetTimeStep())*2 {
+			t.Fatal("the island at rest has not been put to sleep")
+		}
+		press(a, b)
+		stepWorld(c, 1)
+	}
+
+	// Il proiettile colpisce il primo box: si sveglia anche il secondo, che non è stato toccato
+	src := box.Clone()
+	src.Radius, src.Height = 2, 4
+	handlers := &config.BehaviorHandlers{
+		OnCollision: func(config.IThingConfig, config.IThingConfig) {},
+		OnImpact:    func(config.IThingConfig, config.IThingConfig, string, float64, float64, float64, float64, float64) {},
+	}
+	entity := a.GetEntity()
+	_, cY, cZ := entity.GetCenter()
+	pos := geometry.XYZ{X: entity.GetAABB().GetMinX() - 15, Y: cY - src.Radius, Z: cZ - src.Height*0.5}
+	if things.projectiles.launch(src, handlers, nil, a.GetBase().GetLocation(), pos, 0, 0, 300) == nil {
+		t.Fatal("the projectile has not been launched")
+	}
+	woken := 0
+	for step := 0; step < 30 && a.GetBase().IsSleeping(); step++ {
+		stepWorld(c, 1)
+		woken += things.GetSleepStats().Woken
+	}
+	if a.GetBase().IsSleeping() || b.GetBase().IsSleeping() {
+		t.Fatalf("sleeping %t and %t after the hit, want the whole island awake", a.GetBase().IsSleeping(), b.GetBase().IsSleeping())
+	}
+	if woken != 2 {
+		t.Fatalf("%d things woken, want the two boxes", woken)
+	}
+}
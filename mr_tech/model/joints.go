@@ -0,0 +1,150 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/physics"
+)
+
+// Joint is a physics constraint between two things, or between a thing and a point of the world, solved by the
+// Things solver together with the contacts.
+type Joint struct {
+	id         string
+	a          IThing
+	b          IThing
+	constraint *physics.Constraint
+}
+
+// NewJoint creates the Joint described by the configuration between a and b, nil for a world anchor.
+func NewJoint(cfg *config.Joint, a, b IThing) *Joint {
+	var kind physics.ConstraintKind
+	switch cfg.Kind {
+	case config.JointRope:
+		kind = physics.ConstraintRope
+	case config.JointSpring:
+		kind = physics.ConstraintSpring
+	case config.JointHinge:
+		kind = physics.ConstraintHinge
+	case config.JointFixed:
+		kind = physics.ConstraintFixed
+	default:
+		kind = physics.ConstraintDistance
+	}
+	var bEntity *physics.Entity
+	if b != nil {
+		bEntity = b.GetEntity()
+	}
+	c := physics.NewConstraint(kind, a.GetEntity(), bEntity, cfg.AnchorA.X, cfg.AnchorA.Y, cfg.AnchorA.Z, cfg.AnchorB.X, cfg.AnchorB.Y, cfg.AnchorB.Z)
+	if cfg.Length > 0 {
+		c.SetLength(cfg.Length)
+	}
+	c.SetSpring(cfg.Stiffness, cfg.Damping)
+	c.SetAxis(cfg.Axis.X, cfg.Axis.Y, cfg.Axis.Z)
+	return &Joint{id: cfg.Id, a: a, b: b, constraint: c}
+}
+
+// GetId returns the identifier of the Joint.
+func (j *Joint) GetId() string {
+	return j.id
+}
+
+// GetThings returns the things joined by the Joint; the second one is nil for a world anchor.
+func (j *Joint) GetThings() (IThing, IThing) {
+	return j.a, j.b
+}
+
+// GetConstraint returns the physics constraint of the Joint.
+func (j *Joint) GetConstraint() *physics.Constraint {
+	return j.constraint
+}
+
+// isSimulated reports whether the Joint is solved in the current step: both things are alive in the simulation and
+// at least one of them has been stamped as simulated in the given resolve step.
+func (j *Joint) isSimulated(step uint64) bool {
+	a := j.a.GetBase()
+	if !a.IsActive() {
+		return false
+	}
+	if j.b == nil {
+		return a.resolveStep == step
+	}
+	b := j.b.GetBase()
+	return b.IsActive() && (a.resolveStep == step || b.resolveStep == step)
+}
+
+// Joints manages the joints of the world, created from the configuration and registered in the Things solver.
+type Joints struct {
+	container []*Joint
+	cache     map[string]*Joint
+	things    *Things
+}
+
+// NewJoints creates the joints described by the configuration between the things with the referenced ids. Joints
+// referencing missing things are skipped with a warning.
+func NewJoints(cfg []*config.Joint, things *Things) *Joints {
+	js := &Joints{
+		cache:  make(map[string]*Joint),
+		things: things,
+	}
+	byId := make(map[string]IThing)
+	for _, t := range things.ordered {
+		byId[t.GetBase().GetId()] = t
+	}
+	for _, cj := range cfg {
+		a, ok := byId[cj.ThingA]
+		if !ok {
+			fmt.Printf("Warning can't find thing %s for joint %s\n", cj.ThingA, cj.Id)
+			continue
+		}
+		var b IThing
+		if cj.ThingB != "" {
+			if b, ok = byId[cj.ThingB]; !ok || b == a {
+				fmt.Printf("Warning can't find thing %s for joint %s\n", cj.ThingB, cj.Id)
+				continue
+			}
+		}
+		j := NewJoint(cj, a, b)
+		js.container = append(js.container, j)
+		js.cache[j.GetId()] = j
+		things.addJoint(j)
+	}
+	return js
+}
+
+// GetJoint retrieves a Joint by its identifier, or nil if it does not exist.
+func (js *Joints) GetJoint(id string) *Joint {
+	return js.cache[id]
+}
+
+// GetJoints returns all the joints.
+func (js *Joints) GetJoints() []*Joint {
+	return js.container
+}
+
+// Len returns the number of joints.
+func (js *Joints) Len() int {
+	return len(js.container)
+}
+
+// Break removes the Joint with the given identifier from the solver, returning false if it does not exist. The
+// joined things are woken, so that they react to the release.
+func (js *Joints) Break(id string) bool {
+	j, ok := js.cache[id]
+	if !ok {
+		return false
+	}
+	delete(js.cache, id)
+	for idx, other := range js.container {
+		if other == j {
+			js.container = append(js.container[:idx], js.container[idx+1:]...)
+			break
+		}
+	}
+	js.things.removeJoint(j)
+	j.a.GetBase().Wake()
+	if j.b != nil {
+		j.b.GetBase().Wake()
+	}
+	return true
+}
@@ -0,0 +1,140 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// jointGap is the distance between the facing sides of the two boxes of the joint tests.
+const jointGap = 8.0
+
+// newJointScene compiles the test level without its things and places two boxes in a room far from the player, the
+// second one jointGap beyond the first along +X. It returns the world and the boxes, already simulated.
+func newJointScene(tb testing.TB) (*Compiler, IThing, IThing) {
+	tb.Helper()
+	var box *config.Thing
+	c := newTestWorld(tb, func(cfg *config.Root) {
+		box = newBoxTemplate(tb, cfg)
+		cfg.Things = nil
+	})
+	things := c.GetThings()
+	things.SetDeterministic(true)
+	pos := farPoint(tb, c)
+	a := things.Spawn(box, pos, 0)
+	if a == nil {
+		tb.Fatal("the first box has not been spawned")
+	}
+	pos.X += a.GetEntity().GetWidth() + jointGap
+	b := things.Spawn(box, pos, 0)
+	if b == nil {
+		tb.Fatal("the second box has not been spawned")
+	}
+	stepWorld(c, 1)
+	return c, a, b
+}
+
+// anchorDistance returns the distance between the two anchors of the joint.
+func anchorDistance(j *Joint) float64 {
+	aX, aY, aZ, bX, bY, bZ := j.GetConstraint().GetAnchors()
+	return math.Sqrt((aX-bX)*(aX-bX) + (aY-bY)*(aY-bY) + (aZ-bZ)*(aZ-bZ))
+}
+
+// pull applies to the thing a force along X accelerating it by accel.
+func pull(thing IThing, accel float64) {
+	entity := thing.GetEntity()
+	entity.AddForce(accel*entity.GetMass(), 0, 0)
+}
+
+func TestJointsHoldConstraint(t *testing.T) {
+	const steps = 40
+	// Il box tirato si allontana dall'altro nella prima metà dei passi e gli va incontro nella seconda
+	const accel = 300.0
+	tests := []struct {
+		name  string
+		kind  config.JointKind
+		world bool
+		// check verifica il vincolo: length è la lunghezza del giunto, dist la distanza tra le ancore
+		check func(length, dist float64) bool
+	}{
+		{"distance", config.JointDistance, false, func(length, dist float64) bool {
+			return math.Abs(dist-length) < 0.5
+		}},
+		// La fune non supera la lunghezza massima ma si allenta quando i box si avvicinano
+		{"rope", config.JointRope, false, func(length, dist float64) bool {
+			return dist < length+0.5
+		}},
+		{"hinge", config.JointHinge, false, func(_, dist float64) bool {
+			return dist < 0.5
+		}},
+		{"fixed", config.JointFixed, false, func(_, dist float64) bool {
+			return dist < 0.5
+		}},
+		{"fixed anchor", config.JointFixed, true, func(_, dist float64) bool {
+			return dist < 0.5
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, a, b := newJointScene(t)
+			aX, aY, aZ := a.GetEntity().GetCenter()
+			bX, _, _ := b.GetEntity().GetCenter()
+			cfg := config.NewConfigJoint("joint", test.kind, a.GetBase().GetId(), b.GetBase().GetId(), geometry.XYZ{}, geometry.XYZ{})
+			pulled := b
+			switch {
+			case test.world:
+				// Il primo box è ancorato al mondo nel proprio centro
+				cfg.ThingB = ""
+				cfg.AnchorB = geometry.XYZ{X: aX, Y: aY, Z: aZ}
+				pulled = a
+			case test.kind == config.JointHinge || test.kind == config.JointFixed:
+				// Le ancore coincidono nel punto di mezzo tra i due box
+				cfg.AnchorA = geometry.XYZ{X: (bX - aX) * 0.5}
+				cfg.AnchorB = geometry.XYZ{X: (aX - bX) * 0.5}
+			}
+			j := NewJoints([]*config.Joint{cfg}, c.GetThings()).GetJoint("joint")
+			if j == nil {
+				t.Fatal("the joint has not been created")
+			}
+			length := j.GetConstraint().GetLength()
+			minDist := math.MaxFloat64
+			for step := 0; step < steps; step++ {
+				if step < steps/2 {
+					pull(pulled, accel)
+				} else {
+					pull(pulled, -accel)
+				}
+				stepWorld(c, 1)
+				dist := anchorDistance(j)
+				if !test.check(length, dist) {
+					t.Fatalf("step %d: anchors %f apart, length %f", step, dist, length)
+				}
+				minDist = math.Min(minDist, dist)
+			}
+			if test.kind == config.JointRope && minDist > length-1 {
+				t.Fatalf("the rope never slackened: the anchors stayed at least %f apart, length %f", minDist, length)
+			}
+		})
+	}
+}
+
+func TestJointSpringRestLength(t *testing.T) {
+	c, a, b := newJointScene(t)
+	cfg := config.NewConfigJoint("spring", config.JointSpring, a.GetBase().GetId(), b.GetBase().GetId(), geometry.XYZ{}, geometry.XYZ{})
+	cfg.Stiffness, cfg.Damping = 2000, 200
+	j := NewJoints([]*config.Joint{cfg}, c.GetThings()).GetJoint("spring")
+	if j == nil {
+		t.Fatal("the spring has not been created")
+	}
+	rest := j.GetConstraint().GetLength()
+	// Il secondo box viene allontanato e lasciato andare: la molla lo riporta alla lunghezza a riposo
+	pos := positionOf(b)
+	pos.X += jointGap * 0.5
+	moveThing(c, b, pos)
+	stepWorld(c, 60)
+	if dist := anchorDistance(j); math.Abs(dist-rest) > 0.5 {
+		t.Fatalf("anchors %f apart after the release, rest length %f", dist, rest)
+	}
+}
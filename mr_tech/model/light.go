@@ -112,6 +112,11 @@ func (cl *Light) GetIntensity() float64 {
 	return cl.intensity
 }
 
+// SetIntensity replaces the base intensity of the light.
+func (cl *Light) SetIntensity(intensity float64) {
+	cl.intensity = intensity
+}
+
 // GetIntensityStyled calculates the styled intensity of the light at
 func (cl *Light) GetIntensityStyled(tick uint64) float64 {
 	const groupSize = 6.0
@@ -0,0 +1,331 @@
+package model
+
+import (
+	"math"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/physics"
+)
+
+// MoverState represents the current phase of a Mover.
+type MoverState int
+
+// MoverIdle means the plane rests at the start height.
+// MoverForward means the plane is moving towards the end height.
+// MoverWaiting means the plane is waiting at the end height before returning.
+// MoverBackward means the plane is returning to the start height.
+// MoverHold means the plane stays at the end height until it is activated again.
+const (
+	MoverIdle MoverState = iota
+	MoverForward
+	MoverWaiting
+	MoverBackward
+	MoverHold
+)
+
+// moverCrushInterval is the minimum time (seconds) between two crush damages applied to the same blocking thing.
+// moverStandEpsilon is the vertical tolerance used to detect things standing on a moving floor.
+// moverContactEpsilon is the horizontal tolerance below which a thing merely touching the sectors is not considered over them.
+// moverProbeHeight is the vertical extent of the boxes used to query things above and below the sectors.
+const (
+	moverCrushInterval  = 0.5
+	moverStandEpsilon   = 0.5
+	moverContactEpsilon = 0.01
+	moverProbeHeight    = 1e6
+)
+
+// Mover animates the floor or ceiling height of a group of sectors (all the triangles of an IR sector), rebuilding the
+// derived 3D volumes and pushing, crushing or bouncing on the things standing in its way.
+type Mover struct {
+	id          string
+	kind        config.MoverKind
+	sectors     []*Sector
+	volumes     []*Volume
+	start       float64
+	end         float64
+	speed       float64
+	wait        float64
+	crush       bool
+	crushDamage float64
+	trigger     config.MoverTrigger
+	radius      float64
+	height      float64
+	state       MoverState
+	timer       float64
+	crushTimer  float64
+	centerX     float64
+	centerY     float64
+	bounds      *physics.BoundingBox
+	probe       *physics.BoundingBox
+	pushed      []IThing
+}
+
+// NewMover creates a Mover for the given sectors. volumes contains every volume whose faces depend on the sectors
+// heights: the sectors own volumes and the volumes of their neighbors.
+func NewMover(cfg *config.Mover, sectors []*Sector, volumes []*Volume) *Mover {
+	m := &Mover{
+		id:          cfg.Id,
+		kind:        cfg.Kind,
+		sectors:     sectors,
+		volumes:     volumes,
+		start:       cfg.Start,
+		end:         cfg.End,
+		speed:       math.Abs(cfg.Speed),
+		wait:        cfg.Wait,
+		crush:       cfg.Crush,
+		crushDamage: cfg.CrushDamage,
+		trigger:     cfg.Trigger,
+		radius:      cfg.Radius,
+		height:      cfg.Start,
+		state:       MoverIdle,
+	}
+	minX, minY := math.MaxFloat64, math.MaxFloat64
+	maxX, maxY := -math.MaxFloat64, -math.MaxFloat64
+	for _, s := range sectors {
+		aabb := s.GetAABB()
+		minX, minY = math.Min(minX, aabb.GetMinX()), math.Min(minY, aabb.GetMinY())
+		maxX, maxY = math.Max(maxX, aabb.GetMaxX()), math.Max(maxY, aabb.GetMaxY())
+	}
+	m.centerX, m.centerY = (minX+maxX)*0.5, (minY+maxY)*0.5
+	m.bounds = physics.NewBoundingBox(minX, minY, maxX-minX, maxY-minY, -moverProbeHeight*0.5, moverProbeHeight)
+	m.probe = physics.NewBoundingBox(m.centerX-m.radius, m.centerY-m.radius, m.radius*2, m.radius*2, -moverProbeHeight*0.5, moverProbeHeight)
+	return m
+}
+
+// GetId returns the identifier of the Mover.
+func (m *Mover) GetId() string {
+	return m.id
+}
+
+// GetKind returns the plane animated by the Mover.
+func (m *Mover) GetKind() config.MoverKind {
+	return m.kind
+}
+
+// GetState returns the current phase of the Mover.
+func (m *Mover) GetState() MoverState {
+	return m.state
+}
+
+// GetHeight returns the current height of the animated plane.
+func (m *Mover) GetHeight() float64 {
+	return m.height
+}
+
+// GetSectors returns the sectors animated by the Mover.
+func (m *Mover) GetSectors() []*Sector {
+	return m.sectors
+}
+
+// IsMoving reports whether the plane is currently moving.
+func (m *Mover) IsMoving() bool {
+	return m.state == MoverForward || m.state == MoverBackward
+}
+
+// Activate starts the movement towards the end height. A waiting mover restarts its wait timer.
+func (m *Mover) Activate() {
+	switch m.state {
+	case MoverIdle:
+		// Più mover possono condividere lo stesso settore: si riparte dall'altezza corrente
+		m.height = m.planeHeight()
+		m.state = MoverForward
+	case MoverBackward:
+		m.state = MoverForward
+	case MoverWaiting:
+		m.timer = m.wait
+	}
+}
+
+// Return sends the plane back to the start height.
+func (m *Mover) Return() {
+	switch m.state {
+	case MoverForward, MoverWaiting, MoverHold:
+		m.state = MoverBackward
+	}
+}
+
+// Toggle activates an idle or returning mover and returns an open or opening one.
+func (m *Mover) Toggle() {
+	switch m.state {
+	case MoverIdle, MoverBackward:
+		m.Activate()
+	default:
+		m.Return()
+	}
+}
+
+// Compute advances the Mover by dt seconds.
+func (m *Mover) Compute(dt float64, volumes *Volumes, things *Things) {
+	if m.crushTimer > 0 {
+		m.crushTimer -= dt
+	}
+	if m.trigger == config.MoverTriggerProximity && m.isApproached(things) {
+		m.Activate()
+	}
+	switch m.state {
+	case MoverForward:
+		if m.move(m.end, dt, volumes, things) {
+			if m.wait < 0 {
+				m.state = MoverHold
+			} else {
+				m.state = MoverWaiting
+				m.timer = m.wait
+			}
+		}
+	case MoverWaiting:
+		m.timer -= dt
+		if m.timer <= 0 {
+			m.state = MoverBackward
+		}
+	case MoverBackward:
+		if m.move(m.start, dt, volumes, things) {
+			m.state = MoverIdle
+		}
+	}
+}
+
+// isApproached reports whether the player or an enemy is within the trigger radius of the sectors.
+func (m *Mover) isApproached(things *Things) bool {
+	found := false
+	things.QueryAABB(m.probe, func(thing IThing) bool {
+		switch thing.GetKind() {
+		case config.ThingPlayerDef, config.ThingEnemyDef:
+		default:
+			return false
+		}
+		if thing.IsDead() {
+			return false
+		}
+		x, y, _ := thing.GetEntity().GetCenter()
+		if math.Hypot(x-m.centerX, y-m.centerY) <= m.radius {
+			found = true
+			return true
+		}
+		return false
+	})
+	return found
+}
+
+// move advances the plane towards target, returning true when the target height has been reached.
+func (m *Mover) move(target float64, dt float64, volumes *Volumes, things *Things) bool {
+	next := target
+	if step := m.speed * dt; math.Abs(target-m.height) > step {
+		next = m.height + math.Copysign(step, target-m.height)
+	}
+	if next == m.height {
+		return true
+	}
+	if m.isBlocked(next, things) {
+		if m.crush {
+			// Il crusher resta fermo finché il bersaglio non viene eliminato o si sposta
+			return false
+		}
+		if m.state == MoverBackward {
+			// Come le porte di Doom: un ostacolo fa riaprire la porta
+			m.state = MoverForward
+		}
+		return false
+	}
+	for _, thing := range m.pushed {
+		// Le cataste addormentate sopra il piano devono seguirlo
+		thing.GetBase().Wake()
+		entity := thing.GetEntity()
+		if m.kind == config.MoverKindFloor {
+			entity.MoveToZ(next)
+		}
+		things.Update(thing)
+	}
+	m.height = next
+	m.apply(volumes)
+	return m.height == target
+}
+
+// isBlocked collects the things carried by a moving floor into pushed and reports whether a thing prevents the plane
+// from reaching the next height. Blocking things are damaged when the mover is a crusher.
+func (m *Mover) isBlocked(next float64, things *Things) bool {
+	m.pushed = m.pushed[:0]
+	blocked := false
+	things.QueryAABB(m.bounds, func(thing IThing) bool {
+		if thing.GetBase().IsCorpse() {
+			return false
+		}
+		aabb := thing.GetEntity().GetAABB()
+		if !m.overlaps(aabb) {
+			return false
+		}
+		bottom, top := aabb.GetMinZ(), aabb.GetMaxZ()
+		switch m.kind {
+		case config.MoverKindCeil:
+			if next < m.height && top > next && bottom < m.height {
+				blocked = true
+				m.crushThing(thing)
+			}
+		case config.MoverKindFloor:
+			if math.Abs(bottom-m.height) > moverStandEpsilon {
+				return false
+			}
+			if next > m.height && next+(top-bottom) > m.ceilHeight() {
+				blocked = true
+				m.crushThing(thing)
+				return false
+			}
+			m.pushed = append(m.pushed, thing)
+		}
+		return false
+	})
+	return blocked
+}
+
+// crushThing applies the crush damage to the thing, limited to one hit every moverCrushInterval seconds.
+func (m *Mover) crushThing(thing IThing) {
+	if !m.crush || m.crushTimer > 0 {
+		return
+	}
+	m.crushTimer = moverCrushInterval
+	thing.Impact(thing, config.DamageCrush.String(), m.crushDamage, 0, 0, 0, 0)
+}
+
+// overlaps reports whether the 2D footprint of the AABB overlaps at least one of the animated sectors.
+func (m *Mover) overlaps(aabb *physics.AABB) bool {
+	for _, s := range m.sectors {
+		sAABB := s.GetAABB()
+		if aabb.GetMaxX() > sAABB.GetMinX()+moverContactEpsilon && aabb.GetMinX() < sAABB.GetMaxX()-moverContactEpsilon &&
+			aabb.GetMaxY() > sAABB.GetMinY()+moverContactEpsilon && aabb.GetMinY() < sAABB.GetMaxY()-moverContactEpsilon {
+			return true
+		}
+	}
+	return false
+}
+
+// planeHeight returns the current height of the animated plane as stored in the sectors.
+func (m *Mover) planeHeight() float64 {
+	if len(m.sectors) == 0 {
+		return m.height
+	}
+	if m.kind == config.MoverKindFloor {
+		return m.sectors[0].GetMinZ()
+	}
+	return m.sectors[0].GetMaxZ()
+}
+
+// ceilHeight returns the lowest ceiling of the animated sectors.
+func (m *Mover) ceilHeight() float64 {
+	c := math.MaxFloat64
+	for _, s := range m.sectors {
+		c = math.Min(c, s.GetMaxZ())
+	}
+	return c
+}
+
+// apply writes the current height into the sectors and rebuilds the faces, bounds and tree entries of the affected volumes.
+func (m *Mover) apply(volumes *Volumes) {
+	for _, s := range m.sectors {
+		if m.kind == config.MoverKindFloor {
+			s.SetMinZ(m.height)
+		} else {
+			s.SetMaxZ(m.height)
+		}
+		s.Rebuild()
+	}
+	rebuildVolumes(m.volumes, volumes)
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+// Movers manages the runtime sector movers (doors, lifts, crushers) of the world.
+type Movers struct {
+	container []*Mover
+	cache     map[string]*Mover
+	volumes   *Volumes
+	things    *Things
+}
+
+// NewMovers creates the movers described by the configuration, binding them to the compiled sectors with the same id.
+// Movers referencing unknown sectors are skipped with a warning. Every mover is placed at its start height.
+func NewMovers(cfg []*config.Mover, volumes *Volumes, things *Things) *Movers {
+	ms := &Movers{
+		cache:   make(map[string]*Mover),
+		volumes: volumes,
+		things:  things,
+	}
+	sectors := sectorsById(volumes)
+	for _, cm := range cfg {
+		group := sectors[cm.Sector]
+		if len(group) == 0 {
+			fmt.Printf("Warning can't find sector '%s' for mover %s\n", cm.Sector, cm.Id)
+			continue
+		}
+		m := NewMover(cm, group, affectedVolumes(group))
+		m.apply(volumes)
+		ms.container = append(ms.container, m)
+		ms.cache[m.GetId()] = m
+	}
+	return ms
+}
+
+// sectorsById groups the compiled sectors by their IR id: a single IR sector is split into several compiled sectors.
+func sectorsById(volumes *Volumes) map[string][]*Sector {
+	sectors := make(map[string][]*Sector)
+	for _, vol := range volumes.GetVolumes() {
+		if sector := vol.GetSector(); sector != nil {
+			sectors[sector.GetId()] = append(sectors[sector.GetId()], sector)
+		}
+	}
+	return sectors
+}
+
+// affectedVolumes returns the volumes of the sectors and of their neighbors, whose faces depend on the sectors heights.
+func affectedVolumes(sectors []*Sector) []*Volume {
+	seen := make(map[*Volume]bool)
+	var out []*Volume
+	add := func(s *Sector) {
+		if s == nil || s.GetVolume() == nil || seen[s.GetVolume()] {
+			return
+		}
+		seen[s.GetVolume()] = true
+		out = append(out, s.GetVolume())
+	}
+	for _, s := range sectors {
+		add(s)
+		segments, segmentCount := s.GetSegments()
+		for x := 0; x < segmentCount; x++ {
+			add(segments[x].GetNeighbor())
+		}
+	}
+	return out
+}
+
+// rebuildVolumes updates in place the faces, bounds and tree entries of the given volumes after their sectors changed.
+func rebuildVolumes(affected []*Volume, volumes *Volumes) {
+	for _, vol := range affected {
+		vol.ClearFaces()
+		buildSectorFaces(vol, vol.GetSector())
+		vol.Refit()
+		volumes.Update(vol)
+	}
+}
+
+// GetMover retrieves a Mover by its identifier, or nil if it does not exist.
+func (ms *Movers) GetMover(id string) *Mover {
+	return ms.cache[id]
+}
+
+// GetMovers returns all the movers.
+func (ms *Movers) GetMovers() []*Mover {
+	return ms.container
+}
+
+// Len returns the number of movers.
+func (ms *Movers) Len() int {
+	return len(ms.container)
+}
+
+// Activate starts the mover with the given identifier, returning false if it does not exist.
+func (ms *Movers) Activate(id string) bool {
+	m, ok := ms.cache[id]
+	if !ok {
+		return false
+	}
+	m.Activate()
+	return true
+}
+
+// Toggle activates or sends back the mover with the given identifier, returning false if it does not exist.
+func (ms *Movers) Toggle(id string) bool {
+	m, ok := ms.cache[id]
+	if !ok {
+		return false
+	}
+	m.Toggle()
+	return true
+}
+
+// Compute advances all the movers by dt seconds. It must run before the things solver, outside the things stages.
+func (ms *Movers) Compute(dt float64) {
+	for _, m := range ms.container {
+		m.Compute(dt, ms.volumes, ms.things)
+	}
+}
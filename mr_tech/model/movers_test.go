@@ -0,0 +1,60 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/physics"
+)
+
+// findFace reports whether the faces tree of the volume returns face for a query at the centroid of its triangle.
+func findFace(vol *Volume, face *Face) bool {
+	p := face.GetPoints()
+	found := false
+	vol.QueryPoint((p[0].X+p[1].X+p[2].X)/3, (p[0].Y+p[1].Y+p[2].Y)/3, (p[0].Z+p[1].Z+p[2].Z)/3, func(object physics.IAABB) bool {
+		if object == face {
+			found = true
+			return true
+		}
+		return false
+	})
+	return found
+}
+
+func TestMoverUpdatesFacesInPlace(t *testing.T) {
+	c := newTestWorld(t, nil)
+	movers := c.GetMovers().GetMovers()
+	if len(movers) == 0 {
+		t.Skip("the level has no mover")
+	}
+	m := movers[0]
+	before := make(map[*Volume][]*Face)
+	for _, vol := range m.volumes {
+		faces, count := vol.GetFaces()
+		before[vol] = append([]*Face(nil), (*faces)[:count]...)
+	}
+	start := m.GetHeight()
+	m.Activate()
+	stepWorld(c, 30)
+	if m.GetHeight() == start {
+		t.Fatal("the mover did not move")
+	}
+
+	for _, vol := range m.volumes {
+		faces, count := vol.GetFaces()
+		old := before[vol]
+		// Le facce nelle stesse posizioni sono aggiornate, non riallocate
+		for x := 0; x < min(count, len(old)); x++ {
+			if (*faces)[x] != old[x] {
+				t.Fatalf("face %d of volume %s has been reallocated", x, vol.GetId())
+			}
+		}
+		if n := len(vol.facesTree.Nodes()); n != count {
+			t.Fatalf("the faces tree of volume %s holds %d faces, want %d", vol.GetId(), n, count)
+		}
+		for x := 0; x < count; x++ {
+			if !findFace(vol, (*faces)[x]) {
+				t.Fatalf("face %s of volume %s is not found at its new bounds", (*faces)[x].GetTag(), vol.GetId())
+			}
+		}
+	}
+}
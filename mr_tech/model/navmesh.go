@@ -0,0 +1,372 @@
+package model
+
+import (
+	"container/heap"
+	"math"
+	"sync"
+
+	"github.com/markel1974/godoom/mr_tech/geometry"
+	"github.com/markel1974/godoom/mr_tech/physics"
+)
+
+// navWalkableNormal is the minimum Z component of the normal of a walkable floor face (slopes up to about 45 degrees).
+// navEdgeSnap is the precision used to match the shared edges of the floor faces.
+// navLocateSlack is the height above a floor within which a point still stands on it.
+const (
+	navWalkableNormal = 0.7
+	navEdgeSnap       = 0.01
+	navLocateSlack    = 1.0
+)
+
+// NavAgent describes the body moving on the NavMesh: it does not cross the portals narrower than its diameter, the
+// passages lower than its Height, the rises higher than MaxStep and the drops deeper than MaxDrop.
+type NavAgent struct {
+	Radius  float64
+	Height  float64
+	MaxStep float64
+	MaxDrop float64
+}
+
+// NavLink is a portal of a NavNode towards an adjacent node: the 2D segment shared by the two regions.
+type NavLink struct {
+	to    *NavNode
+	start geometry.XY
+	end   geometry.XY
+}
+
+// GetTarget returns the node reached through the NavLink.
+func (l *NavLink) GetTarget() *NavNode {
+	return l.to
+}
+
+// GetPortal returns the endpoints of the segment crossed by the NavLink.
+func (l *NavLink) GetPortal() (geometry.XY, geometry.XY) {
+	return l.start, l.end
+}
+
+// traversable reports whether the agent can cross the NavLink from the node from, checking the width of the portal
+// and the current heights of the floors and of the ceilings on its middle point.
+func (l *NavLink) traversable(from *NavNode, agent NavAgent) bool {
+	dx, dy := l.end.X-l.start.X, l.end.Y-l.start.Y
+	if dx*dx+dy*dy < 4*agent.Radius*agent.Radius {
+		return false
+	}
+	mX, mY := (l.start.X+l.end.X)*0.5, (l.start.Y+l.end.Y)*0.5
+	zFrom, cFrom := from.heightsAt(mX, mY)
+	zTo, cTo := l.to.heightsAt(mX, mY)
+	rise := zTo - zFrom
+	if rise > agent.MaxStep || -rise > agent.MaxDrop {
+		return false
+	}
+	return math.Min(cFrom, cTo)-math.Max(zFrom, zTo) >= agent.Height
+}
+
+// isOpen reports whether the portal of the NavLink is currently open from the node from: the ceilings on its middle
+// point are above the floors, as for a door that is not closed.
+func (l *NavLink) isOpen(from *NavNode) bool {
+	mX, mY := (l.start.X+l.end.X)*0.5, (l.start.Y+l.end.Y)*0.5
+	zFrom, cFrom := from.heightsAt(mX, mY)
+	zTo, cTo := l.to.heightsAt(mX, mY)
+	return math.Min(cFrom, cTo) > math.Max(zFrom, zTo)
+}
+
+// NavNode is a walkable region of the NavMesh: a sector of a 2D level or an upward floor face of a 3D volume.
+type NavNode struct {
+	id     int
+	sector *Sector
+	face   *Face
+	aabb   *physics.AABB
+	links  []*NavLink
+}
+
+// newNavNode creates the NavNode of the given sector or face, bounded by aabb.
+func newNavNode(id int, sector *Sector, face *Face, aabb *physics.AABB) *NavNode {
+	n := &NavNode{id: id, sector: sector, face: face, aabb: physics.NewAABB()}
+	n.aabb.Rebuild(aabb.GetMinX(), aabb.GetMinY(), aabb.GetMinZ(), aabb.GetMaxX(), aabb.GetMaxY(), aabb.GetMaxZ())
+	return n
+}
+
+// GetId returns the index of the NavNode in the NavMesh.
+func (n *NavNode) GetId() int {
+	return n.id
+}
+
+// GetSector returns the sector of the NavNode, or nil for a floor face.
+func (n *NavNode) GetSector() *Sector {
+	return n.sector
+}
+
+// GetFace returns the floor face of the NavNode, or nil for a sector.
+func (n *NavNode) GetFace() *Face {
+	return n.face
+}
+
+// GetLinks returns the portals of the NavNode towards the adjacent nodes.
+func (n *NavNode) GetLinks() []*NavLink {
+	return n.links
+}
+
+// GetAABB returns the bounding box of the NavNode.
+func (n *NavNode) GetAABB() *physics.AABB {
+	return n.aabb
+}
+
+// contains reports whether the 2D point (px, py) lies inside the region of the NavNode.
+func (n *NavNode) contains(px, py float64) bool {
+	if n.sector != nil {
+		return n.sector.PointInLineSide(px, py)
+	}
+	return n.face.PointInside2d(px, py)
+}
+
+// heightsAt returns the current floor and ceiling heights of the NavNode at (px, py). Floor faces have no ceiling.
+func (n *NavNode) heightsAt(px, py float64) (float64, float64) {
+	if n.sector != nil {
+		return resolveSectorZ(n.sector, geometry.XYZ{X: px, Y: py}, n.sector.GetMinZ(), n.sector.GetMaxZ(), true)
+	}
+	// Quota sul piano del triangolo
+	p0 := n.face.GetPoints()[0]
+	nX, nY, nZ := n.face.GetNormal()
+	return p0.Z - (nX*(px-p0.X)+nY*(py-p0.Y))/nZ, math.MaxFloat64
+}
+
+// NavMesh is the navigation graph of the level, built at compile time from the adjacency of the sectors and from the
+// shared edges of the floor faces of the 3D volumes. Paths are searched with A*, evaluating the heights of the floors
+// and of the ceilings when the query runs, so that closed doors and raised lifts are taken into account. The queries
+// are safe for concurrent use by the thinking stages of the things.
+type NavMesh struct {
+	mu        sync.Mutex
+	container []*NavNode
+	tree      *physics.AABBTree
+}
+
+// navEdge is an edge of a floor face, keyed by its snapped endpoints regardless of their order.
+type navEdge struct {
+	x0, y0, x1, y1 int64
+}
+
+// newNavEdge creates the key of the edge between a and b.
+func newNavEdge(a, b geometry.XYZ) navEdge {
+	snap := func(v float64) int64 { return int64(math.Round(v / navEdgeSnap)) }
+	e := navEdge{x0: snap(a.X), y0: snap(a.Y), x1: snap(b.X), y1: snap(b.Y)}
+	if e.x0 > e.x1 || e.x0 == e.x1 && e.y0 > e.y1 {
+		e.x0, e.y0, e.x1, e.y1 = e.x1, e.y1, e.x0, e.y0
+	}
+	return e
+}
+
+// NewNavMesh builds the NavMesh of the volumes: a node for each sector, linked through the segments with a neighbor,
+// and a node for each walkable floor face of the volumes without a sector, linked through the edges that match in 2D.
+func NewNavMesh(volumes *Volumes) *NavMesh {
+	n := &NavMesh{tree: physics.NewAABBTree(uint(volumes.Len()), 0.0)}
+	bySector := make(map[*Sector]*NavNode)
+	var faceNodes []*NavNode
+	for _, vol := range volumes.GetVolumes() {
+		if sector := vol.GetSector(); sector != nil {
+			node := newNavNode(len(n.container), sector, nil, sector.GetAABB())
+			n.container = append(n.container, node)
+			bySector[sector] = node
+			continue
+		}
+		faces, faceCount := vol.GetFaces()
+		for x := 0; x < faceCount; x++ {
+			face := (*faces)[x]
+			if _, _, nZ := face.GetNormal(); nZ < navWalkableNormal {
+				continue
+			}
+			node := newNavNode(len(n.container), nil, face, face.GetAABB())
+			n.container = append(n.container, node)
+			faceNodes = append(faceNodes, node)
+		}
+	}
+	// Portali fra settori
+	for _, node := range n.container {
+		if node.sector == nil {
+			continue
+		}
+		segments, segmentCount := node.sector.GetSegments()
+		for x := 0; x < segmentCount; x++ {
+			segment := segments[x]
+			to, ok := bySector[segment.GetNeighbor()]
+			if !ok || to == node {
+				continue
+			}
+			start, end := segment.GetStart(), segment.GetEnd()
+			node.links = append(node.links, &NavLink{to: to, start: geometry.XY{X: start.X, Y: start.Y}, end: geometry.XY{X: end.X, Y: end.Y}})
+		}
+	}
+	// Spigoli condivisi fra facce di pavimento, visitati in ordine per un grafo deterministico
+	edges := make(map[navEdge][]*NavNode)
+	for _, node := range faceNodes {
+		pts := node.face.GetPoints()
+		for x := 0; x < 3; x++ {
+			key := newNavEdge(pts[x], pts[(x+1)%3])
+			edges[key] = append(edges[key], node)
+		}
+	}
+	for _, node := range faceNodes {
+		pts := node.face.GetPoints()
+		for x := 0; x < 3; x++ {
+			a, b := pts[x], pts[(x+1)%3]
+			for _, to := range edges[newNavEdge(a, b)] {
+				if to != node {
+					node.links = append(node.links, &NavLink{to: to, start: geometry.XY{X: a.X, Y: a.Y}, end: geometry.XY{X: b.X, Y: b.Y}})
+				}
+			}
+		}
+	}
+	for _, node := range n.container {
+		n.tree.InsertObject(node)
+	}
+	return n
+}
+
+// GetNodes returns all the nodes of the NavMesh.
+func (n *NavMesh) GetNodes() []*NavNode {
+	return n.container
+}
+
+// Len returns the number of nodes of the NavMesh.
+func (n *NavMesh) Len() int {
+	return len(n.container)
+}
+
+// Locate returns the node under the point (px, py, pz): among the regions containing the point in 2D, the one with the
+// highest floor not above the point. It returns nil if the point is outside the NavMesh.
+func (n *NavMesh) Locate(px, py, pz float64) *NavNode {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var best, first *NavNode
+	bestZ := -math.MaxFloat64
+	n.tree.QueryPoint2d(px, py, func(object physics.IAABB) bool {
+		node := object.(*NavNode)
+		if first == nil {
+			first = node
+		}
+		if !node.contains(px, py) {
+			return false
+		}
+		if floor, _ := node.heightsAt(px, py); floor <= pz+navLocateSlack && floor > bestZ {
+			best, bestZ = node, floor
+		}
+		return false
+	})
+	if best == nil {
+		return first
+	}
+	return best
+}
+
+// FindPath searches the shortest path of the agent from the point from to the point to. It returns the waypoints to
+// follow, one on each portal crossed and the last one on to, or false when to can't be reached.
+func (n *NavMesh) FindPath(from, to geometry.XYZ, agent NavAgent) ([]geometry.XYZ, bool) {
+	start := n.Locate(from.X, from.Y, from.Z)
+	goal := n.Locate(to.X, to.Y, to.Z)
+	if start == nil || goal == nil {
+		return nil, false
+	}
+	if start == goal {
+		return []geometry.XYZ{to}, true
+	}
+	count := len(n.container)
+	cost := make([]float64, count)
+	entry := make([]geometry.XYZ, count)
+	via := make([]*NavLink, count)
+	parent := make([]*NavNode, count)
+	for x := range cost {
+		cost[x] = math.MaxFloat64
+	}
+	cost[start.id] = 0
+	entry[start.id] = from
+	open := &navQueue{}
+	heap.Push(open, navItem{node: start, score: distance(from, to.X, to.Y, to.Z)})
+	for open.Len() > 0 {
+		item := heap.Pop(open).(navItem)
+		node := item.node
+		if node == goal {
+			return n.waypoints(from, to, goal, via, parent, agent), true
+		}
+		if item.cost > cost[node.id] {
+			continue // voce superata da un percorso più breve
+		}
+		for _, link := range node.links {
+			if !link.traversable(node, agent) {
+				continue
+			}
+			mX, mY := (link.start.X+link.end.X)*0.5, (link.start.Y+link.end.Y)*0.5
+			mZ, _ := link.to.heightsAt(mX, mY)
+			p := entry[node.id]
+			g := cost[node.id] + distance(p, mX, mY, mZ)
+			if g >= cost[link.to.id] {
+				continue
+			}
+			id := link.to.id
+			cost[id], via[id], parent[id] = g, link, node
+			entry[id] = geometry.XYZ{X: mX, Y: mY, Z: mZ}
+			heap.Push(open, navItem{node: link.to, cost: g, score: g + distance(entry[id], to.X, to.Y, to.Z)})
+		}
+	}
+	return nil, false
+}
+
+// waypoints converts the chain of portals reaching goal into waypoints: on each portal, the point closest to the
+// previous waypoint, kept at agent.Radius from the ends of the portal.
+func (n *NavMesh) waypoints(from, to geometry.XYZ, goal *NavNode, via []*NavLink, parent []*NavNode, agent NavAgent) []geometry.XYZ {
+	var links []*NavLink
+	for node := goal; via[node.id] != nil; node = parent[node.id] {
+		links = append(links, via[node.id])
+	}
+	path := make([]geometry.XYZ, 0, len(links)+1)
+	prev := from
+	for x := len(links) - 1; x >= 0; x-- {
+		link := links[x]
+		dx, dy := link.end.X-link.start.X, link.end.Y-link.start.Y
+		length := math.Sqrt(dx*dx + dy*dy)
+		t := 0.5
+		if length > 0 {
+			margin := math.Min(agent.Radius/length, 0.5)
+			t = ((prev.X-link.start.X)*dx + (prev.Y-link.start.Y)*dy) / (length * length)
+			t = math.Max(margin, math.Min(1-margin, t))
+		}
+		pX, pY := link.start.X+dx*t, link.start.Y+dy*t
+		pZ, _ := link.to.heightsAt(pX, pY)
+		prev = geometry.XYZ{X: pX, Y: pY, Z: pZ}
+		path = append(path, prev)
+	}
+	return append(path, to)
+}
+
+// navItem is an entry of the A* open set: a node with the cost of its path and the estimated total cost.
+type navItem struct {
+	node  *NavNode
+	cost  float64
+	score float64
+}
+
+// navQueue is the A* open set, ordered by estimated total cost and then by node id for a deterministic search.
+type navQueue []navItem
+
+// Len returns the number of entries of the open set.
+func (q navQueue) Len() int { return len(q) }
+
+// Less orders the entries by estimated total cost, breaking ties by node id.
+func (q navQueue) Less(i, j int) bool {
+	if q[i].score != q[j].score {
+		return q[i].score < q[j].score
+	}
+	return q[i].node.id < q[j].node.id
+}
+
+// Swap swaps the entries at indices i and j.
+func (q navQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+// Push appends an entry to the open set, as required by container/heap.
+func (q *navQueue) Push(x any) { *q = append(*q, x.(navItem)) }
+
+// Pop removes the last entry of the open set, as required by container/heap.
+func (q *navQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
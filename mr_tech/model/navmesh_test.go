@@ -0,0 +1,74 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// sectorCentroid returns the centroid of the starting points of the segments of the sector, on its floor.
+func sectorCentroid(s *Sector) geometry.XYZ {
+	segments, segmentCount := s.GetSegments()
+	var out geometry.XYZ
+	for x := 0; x < segmentCount; x++ {
+		p := segments[x].GetStart()
+		out.X += p.X / float64(segmentCount)
+		out.Y += p.Y / float64(segmentCount)
+	}
+	out.Z = s.GetMinZ()
+	return out
+}
+
+func TestNavMeshFindPathThroughDoor(t *testing.T) {
+	c := newTestWorld(t, nil)
+	var door *Mover
+	for _, m := range c.GetMovers().GetMovers() {
+		if m.GetKind() == config.MoverKindCeil {
+			door = m
+			break
+		}
+	}
+	if door == nil {
+		t.Skip("the level has no door")
+	}
+	navMesh := c.GetNavMesh()
+	doorSector := door.GetSectors()[0]
+	to := sectorCentroid(doorSector)
+	goal := navMesh.Locate(to.X, to.Y, to.Z)
+	if goal == nil || goal.GetSector() != doorSector {
+		t.Fatal("the door sector is not a node of the navigation mesh")
+	}
+	// Si parte da una stanza adiacente che non è a sua volta una porta
+	var from geometry.XYZ
+	found := false
+	for _, link := range goal.GetLinks() {
+		if s := link.GetTarget().GetSector(); s != nil && s.GetMaxZ() > s.GetMinZ() {
+			from, found = sectorCentroid(s), true
+			break
+		}
+	}
+	if !found {
+		t.Fatal("the door has no open room next to it")
+	}
+	height := door.end - door.start
+	agent := NavAgent{Radius: 0.01, Height: height * 0.5, MaxStep: height * 0.1, MaxDrop: height}
+
+	if path, ok := navMesh.FindPath(from, to, agent); ok {
+		t.Fatalf("a path of %d waypoints crosses the closed door", len(path))
+	}
+
+	door.Activate()
+	stepWorld(c, int(1.5/c.GetThings().GetTimeStep()))
+	if door.GetState() != MoverWaiting {
+		t.Fatalf("the door is in state %d, want open and waiting", door.GetState())
+	}
+	// Le quote sono valutate al momento della ricerca: la porta aperta si attraversa
+	path, ok := navMesh.FindPath(from, to, agent)
+	if !ok {
+		t.Fatal("no path crosses the open door")
+	}
+	if last := path[len(path)-1]; last != to || len(path) < 2 {
+		t.Fatalf("path %v, want a portal and then %v", path, to)
+	}
+}
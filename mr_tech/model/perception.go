@@ -0,0 +1,134 @@
+package model
+
+import (
+	"math"
+	"sync"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// perceptionFov is the field of view of the things, centered on their angle: the half plane in front of them.
+const perceptionFov = math.Pi
+
+// noiseMark records the last noise that reached a node of the NavMesh: the flood that carried it, the step in which
+// it was made, its source and its position.
+type noiseMark struct {
+	flood  uint64
+	step   uint64
+	source *ThingBase
+	pos    geometry.XYZ
+}
+
+// Perception answers the sensory questions of the AI. Sight is a raycast against the level geometry, limited by the
+// field of view of the observer; hearing follows the portals of the NavMesh: a noise floods every region reachable
+// through open portals, as the sound of Doom does through the two-sided lines, and is heard in the same step by the
+// things standing there. The queries are safe for concurrent use by the thinking stages of the things.
+type Perception struct {
+	mu      sync.Mutex
+	navMesh *NavMesh
+	query   *Query
+	step    uint64
+	flood   uint64
+	noises  []noiseMark
+	queue   []*NavNode
+}
+
+// NewPerception creates the Perception of a level, hearing through the regions of navMesh and seeing through query.
+func NewPerception(navMesh *NavMesh, query *Query) *Perception {
+	return &Perception{
+		navMesh: navMesh,
+		query:   query,
+		noises:  make([]noiseMark, navMesh.Len()),
+	}
+}
+
+// CanSee reports whether the line from the center of the observer to (x, y, z) crosses no face of the level geometry.
+func (p *Perception) CanSee(observer IThing, x, y, z float64) bool {
+	oX, oY, oZ := observer.GetEntity().GetCenter()
+	dir := geometry.XYZ{X: x - oX, Y: y - oY, Z: z - oZ}
+	length := math.Sqrt(dir.X*dir.X + dir.Y*dir.Y + dir.Z*dir.Z)
+	if length == 0 {
+		return true
+	}
+	// La vista è bloccata dalle stesse superfici che fermano i colpi
+	filter := QueryFilter{Layer: config.LayerProjectile, Mask: config.LayerWorld, Volumes: true, Things: false}
+	_, hit := p.query.Raycast(geometry.XYZ{X: oX, Y: oY, Z: oZ}, dir, length, filter)
+	return !hit
+}
+
+// InFieldOfView reports whether the 2D direction from the observer to (x, y) is within the field of view around the
+// angle of the observer.
+func (p *Perception) InFieldOfView(observer IThing, x, y float64) bool {
+	oX, oY, _ := observer.GetEntity().GetCenter()
+	delta := math.Atan2(y-oY, x-oX) - observer.GetBase().GetAngle()
+	delta = math.Remainder(delta, 2*math.Pi)
+	return math.Abs(delta) <= perceptionFov*0.5
+}
+
+// Emit makes a noise of source at pos: the noise floods the region under pos and every region reachable from it
+// through open portals, where it can be heard until the end of the current step.
+func (p *Perception) Emit(source *ThingBase, pos geometry.XYZ) {
+	start := p.navMesh.Locate(pos.X, pos.Y, pos.Z)
+	if start == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.flood++
+	mark := noiseMark{flood: p.flood, step: p.step, source: source, pos: pos}
+	p.noises[start.id] = mark
+	queue := append(p.queue[:0], start)
+	for head := 0; head < len(queue); head++ {
+		node := queue[head]
+		for _, link := range node.links {
+			if p.noises[link.to.id].flood == p.flood || !link.isOpen(node) {
+				continue
+			}
+			p.noises[link.to.id] = mark
+			queue = append(queue, link.to)
+		}
+	}
+	p.queue = queue[:0]
+}
+
+// Heard returns the position of the noise of source that reached the region of the listener in the current step.
+// The noises of the things owned by source, such as its projectiles, count as its own.
+func (p *Perception) Heard(listener IThing, source IThing) (geometry.XYZ, bool) {
+	lX, lY, lZ := listener.GetEntity().GetCenter()
+	node := p.navMesh.Locate(lX, lY, lZ)
+	if node == nil {
+		return geometry.XYZ{}, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	mark := p.noises[node.id]
+	if mark.step != p.step || mark.source == nil || source == nil {
+		return geometry.XYZ{}, false
+	}
+	if target := source.GetBase(); mark.source != target && mark.source.GetOwner() != target {
+		return geometry.XYZ{}, false
+	}
+	return mark.pos, true
+}
+
+// Perceive returns what the observer perceives of the target, placed at (x, y, z).
+func (p *Perception) Perceive(observer IThing, target IThing, x, y, z float64) config.Perception {
+	oX, oY, oZ := observer.GetEntity().GetCenter()
+	dx, dy, dz := x-oX, y-oY, z-oZ
+	out := config.Perception{
+		Target:   geometry.XYZ{X: x, Y: y, Z: z},
+		Distance: math.Sqrt(dx*dx + dy*dy + dz*dz),
+	}
+	out.Visible = p.CanSee(observer, x, y, z)
+	out.InView = out.Visible && p.InFieldOfView(observer, x, y)
+	out.Noise, out.Heard = p.Heard(observer, target)
+	return out
+}
+
+// advance ends the current step: the noises made so far are no longer heard.
+func (p *Perception) advance() {
+	p.mu.Lock()
+	p.step++
+	p.mu.Unlock()
+}
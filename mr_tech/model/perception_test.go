@@ -0,0 +1,141 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+func TestPerceptionWallBlocksSight(t *testing.T) {
+	c, wallX, free := newThinWallWorld(t)
+	perception := c.GetPerception()
+	player := c.GetPlayer()
+	pX, pY, pZ := player.GetEntity().GetCenter()
+	if !perception.CanSee(player, wallX-1, pY, pZ) {
+		t.Fatal("the point before the thin wall is not visible")
+	}
+	// Oltre il muro sottile, ma ancora nello spazio libero della stanza
+	x := pX + free*0.75
+	if perception.CanSee(player, x, pY, pZ) {
+		t.Fatal("the point beyond the thin wall is visible")
+	}
+	if p := perception.Perceive(player, nil, x, pY, pZ); p.Visible || p.InView {
+		t.Fatalf("perceived visible %t and in view %t through the thin wall", p.Visible, p.InView)
+	}
+}
+
+func TestPerceptionFieldOfView(t *testing.T) {
+	c := newTestWorld(t, nil)
+	perception := c.GetPerception()
+	player := c.GetPlayer()
+	// Il campo visivo è il semipiano davanti all'osservatore, che guarda vicino al salto degli angoli da π a -π
+	const angle = math.Pi * 5 / 6
+	player.GetBase().SetAngle(angle)
+	pX, pY, _ := player.GetEntity().GetCenter()
+	tests := []struct {
+		name  string
+		delta float64
+		want  bool
+	}{
+		{"ahead", 0, true},
+		{"left edge", math.Pi*0.5 - 0.01, true},
+		{"right edge", -math.Pi*0.5 + 0.01, true},
+		{"beyond left", math.Pi*0.5 + 0.01, false},
+		{"beyond right", -math.Pi*0.5 - 0.01, false},
+		{"behind", math.Pi, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			x, y := pX+10*math.Cos(angle+test.delta), pY+10*math.Sin(angle+test.delta)
+			if got := perception.InFieldOfView(player, x, y); got != test.want {
+				t.Fatalf("in view %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPerceptionSoundThroughPortals(t *testing.T) {
+	c := newTestWorld(t, nil)
+	var door *Mover
+	for _, m := range c.GetMovers().GetMovers() {
+		if m.GetKind() == config.MoverKindCeil {
+			door = m
+			break
+		}
+	}
+	if door == nil {
+		t.Skip("the level has no door")
+	}
+	// Le stanze ai lati della porta, raggiunte attraversando tutti i settori che la compongono
+	navMesh := c.GetNavMesh()
+	inDoor := make(map[*Sector]bool)
+	for _, s := range door.GetSectors() {
+		inDoor[s] = true
+	}
+	center := sectorCentroid(door.GetSectors()[0])
+	queue := []*NavNode{navMesh.Locate(center.X, center.Y, center.Z)}
+	seen := map[*NavNode]bool{queue[0]: true}
+	var rooms []*Sector
+	for head := 0; head < len(queue); head++ {
+		for _, link := range queue[head].GetLinks() {
+			node := link.GetTarget()
+			s := node.GetSector()
+			if seen[node] || s == nil {
+				continue
+			}
+			seen[node] = true
+			if inDoor[s] {
+				queue = append(queue, node)
+			} else if s.GetMaxZ() > s.GetMinZ() {
+				rooms = append(rooms, s)
+			}
+		}
+	}
+	if len(rooms) < 2 {
+		t.Fatalf("the door joins %d open rooms, want two", len(rooms))
+	}
+	perception := c.GetPerception()
+	things := c.GetThings()
+	player := c.GetPlayer()
+	var source IThing
+	for _, thing := range things.ordered {
+		if thing != player {
+			source = thing
+			break
+		}
+	}
+	if source == nil {
+		t.Fatal("the level has no thing making noise")
+	}
+	entity := player.GetEntity()
+	listen := sectorCentroid(rooms[1])
+	moveThing(c, player, geometry.XYZ{X: listen.X - entity.GetWidth()*0.5, Y: listen.Y - entity.GetHeight()*0.5, Z: listen.Z})
+	noise := sectorCentroid(rooms[0])
+	noise.Z += 1
+
+	perception.Emit(source.GetBase(), noise)
+	if _, ok := perception.Heard(player, source); ok {
+		t.Fatal("the noise has been heard through the closed door")
+	}
+
+	door.Activate()
+	stepWorld(c, int(1.5/things.GetTimeStep()))
+	if door.GetState() != MoverWaiting {
+		t.Fatalf("the door is in state %d, want open and waiting", door.GetState())
+	}
+	// Il rumore è udito nel passo in cui è fatto, attraverso la porta aperta
+	perception.Emit(source.GetBase(), noise)
+	pos, ok := perception.Heard(player, source)
+	if !ok || pos != noise {
+		t.Fatalf("heard %t at %v through the open door, want the noise at %v", ok, pos, noise)
+	}
+	if _, ok = perception.Heard(player, player); ok {
+		t.Fatal("the noise has been attributed to the listener")
+	}
+	stepWorld(c, 1)
+	if _, ok = perception.Heard(player, source); ok {
+		t.Fatal("the noise is still heard at the next step")
+	}
+}
@@ -0,0 +1,201 @@
+package model
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+	"github.com/markel1974/godoom/mr_tech/physics"
+	"github.com/markel1974/godoom/mr_tech/utils"
+)
+
+// Projectile describes the damage a weapon projectile delivers to the first thing it hits: the impact id and force,
+// converted into damage by the health of the target, and the knockback force. Owner is never hit.
+type Projectile struct {
+	Owner      IThing
+	DamageType string
+	Damage     float64
+	Force      float64
+}
+
+// flyingProjectile is a launched weapon projectile, checked for hits after each solver step. wall is set by the
+// apply stage when the projectile touches a wall.
+type flyingProjectile struct {
+	thing   IThing
+	payload *Projectile
+	wall    atomic.Bool
+}
+
+// Projectiles launches the throwables of the things and tracks the weapon projectiles among them: after each solver
+// step a projectile delivers its damage to the first thing it touches and disappears, as it does when it hits a wall.
+// The step of a continuous projectile is checked as a whole, against the boxes of the things expanded by its half
+// extents, so that a fast projectile does not pass through a thin one. Launching is safe for concurrent use by the
+// thinking stages of the things.
+type Projectiles struct {
+	mu        sync.Mutex
+	things    *Things
+	container []*flyingProjectile
+	area      *physics.BoundingBox
+	hull      *physics.AABB
+}
+
+// NewProjectiles creates the Projectiles launching into things.
+func NewProjectiles(things *Things) *Projectiles {
+	return &Projectiles{
+		things: things,
+		area:   physics.NewBoundingBox(0, 0, 0, 0, 0, 0),
+		hull:   physics.NewAABB(),
+	}
+}
+
+// Len returns the number of weapon projectiles still flying.
+func (ps *Projectiles) Len() int {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return len(ps.container)
+}
+
+// launch adds to the pending list of the things a throwable copy of src bound to the given handlers and owner,
+// returning nil when the pending list is full. The throwable comes from the pool, and goes back to it once removed.
+func (ps *Projectiles) launch(src *config.Thing, handlers *config.BehaviorHandlers, owner *ThingBase, volume *Volume, pos geometry.XYZ, angle, pitch, speed float64) IThing {
+	th := ps.things
+	dst := src.Clone()
+	dst.Id = utils.NextUUId()
+	dst.Kind = config.ThingThrowableDef
+	dst.Position = pos
+	dst.Angle = angle
+	dst.Pitch = pitch
+	dst.Speed = speed
+	slot, ok := th.reserve()
+	if !ok {
+		return nil
+	}
+	throwable := th.pool.acquire(th, dst, volume, handlers)
+	th.place(throwable, dst)
+	throwable.GetEntity().SetOnGround(false)
+	throwable.SetOwner(owner)
+	th.pending[slot] = throwable
+	th.hasPending = true
+	return throwable
+}
+
+// add tracks the launched thing as a weapon projectile delivering the damage of projectile.
+func (ps *Projectiles) add(thing IThing, projectile *Projectile) {
+	fp := &flyingProjectile{thing: thing, payload: projectile}
+	if throwable, ok := thing.(*ThingThrowable); ok {
+		throwable.SetOnWall(func() { fp.wall.Store(true) })
+	}
+	ps.mu.Lock()
+	ps.container = append(ps.container, fp)
+	ps.mu.Unlock()
+}
+
+// Compute applies the damage of the projectiles touching a thing after the last step and removes them, along with
+// the ones that hit a wall. Pickup items and other throwables don't stop a projectile.
+func (ps *Projectiles) Compute() {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	flying := ps.container[:0]
+	for _, fp := range ps.container {
+		if !fp.thing.IsActive() {
+			continue
+		}
+		if target := ps.target(fp); target != nil {
+			fp.thing.SetActive(false)
+			dirX, dirY, dirZ := fp.thing.GetEntity().GetVelocity()
+			if mag := math.Sqrt(dirX*dirX + dirY*dirY + dirZ*dirZ); mag > 0 {
+				dirX, dirY, dirZ = dirX/mag, dirY/mag, dirZ/mag
+			}
+			p := fp.payload
+			target.GetEntity().AddForce(dirX*p.Force, dirY*p.Force, dirZ*p.Force)
+			target.Impact(p.Owner, p.DamageType, p.Damage, 0, dirX, dirY, dirZ)
+			continue
+		}
+		if fp.wall.Load() {
+			fp.thing.SetActive(false)
+			continue
+		}
+		flying = append(flying, fp)
+	}
+	for x := len(flying); x < len(ps.container); x++ {
+		ps.container[x] = nil
+	}
+	ps.container = flying
+}
+
+// target returns the first thing touched by the projectile that can stop it, or nil. The whole step of a continuous
+// projectile is checked, and the thing met first along it is returned.
+func (ps *Projectiles) target(fp *flyingProjectile) IThing {
+	entity := fp.thing.GetEntity()
+	aabb := entity.GetAABB()
+	minX, minY, minZ := aabb.GetMinX(), aabb.GetMinY(), aabb.GetMinZ()
+	maxX, maxY, maxZ := aabb.GetMaxX(), aabb.GetMaxY(), aabb.GetMaxZ()
+	var dX, dY, dZ float64
+	throwable, continuous := fp.thing.(*ThingThrowable)
+	continuous = continuous && throwable.IsContinuous()
+	if continuous {
+		// Il passo va dalla posizione dello snapshot a quella corrente
+		pX, pY, pZ := entity.GetBottomCenterLerp(0)
+		cX, cY, cZ := entity.GetBottomCenter()
+		dX, dY, dZ = cX-pX, cY-pY, cZ-pZ
+		minX, minY, minZ = minX+math.Min(0, -dX), minY+math.Min(0, -dY), minZ+math.Min(0, -dZ)
+		maxX, maxY, maxZ = maxX+math.Max(0, -dX), maxY+math.Max(0, -dY), maxZ+math.Max(0, -dZ)
+	}
+	ps.area.Rebuild(minX, minY, minZ, maxX-minX, maxY-minY, maxZ-minZ)
+	area := ps.area.GetAABB()
+	var target IThing
+	bestToi := math.MaxFloat64
+	ps.things.tree.QueryOverlaps(ps.area, func(object physics.IAABB) bool {
+		other, ok := object.(IThing)
+		if !ok || !other.IsActive() || other.GetKind() == config.ThingThrowableDef {
+			return false
+		}
+		// Owner, cadaveri e pickup sono esclusi da layer e mask del proiettile
+		if !fp.thing.GetBase().CollidesWith(other.GetBase()) {
+			return false
+		}
+		if !area.Overlaps(other.GetEntity().GetAABB()) {
+			return false
+		}
+		if !continuous {
+			target = other
+			return true
+		}
+		if toi, hit := ps.toi(entity, dX, dY, dZ, other); hit && toi < bestToi {
+			bestToi, target = toi, other
+		}
+		return false
+	})
+	return target
+}
+
+// toi returns the time of impact, in [0, 1], of a projectile that moved by (dX, dY, dZ) during the step against the
+// box of other, expanded by the half extents of the projectile.
+func (ps *Projectiles) toi(entity *physics.Entity, dX, dY, dZ float64, other IThing) (float64, bool) {
+	eRadX, eRadY, eRadZ := entity.GetSizeCenter()
+	cX, cY, cZ := entity.GetCenter()
+	oX, oY, oZ := cX-dX, cY-dY, cZ-dZ
+	target := other.GetEntity().GetAABB()
+	ps.hull.Rebuild(target.GetMinX()-eRadX, target.GetMinY()-eRadY, target.GetMinZ()-eRadZ,
+		target.GetMaxX()+eRadX, target.GetMaxY()+eRadY, target.GetMaxZ()+eRadZ)
+	if ps.hull.ContainsPoint3d(oX, oY, oZ) {
+		return 0, true
+	}
+	toi, hit := ps.hull.IntersectRay(oX, oY, oZ, inverseDelta(dX), inverseDelta(dY), inverseDelta(dZ))
+	if !hit || toi > 1.0 {
+		return 0, false
+	}
+	return toi, true
+}
+
+// inverseDelta returns the inverse of a displacement for the slab test of a ray against a box. An axis without motion
+// gets a finite inverse: the infinite one, multiplied by an origin lying on the border of the slab, gives NaN and the
+// axis-aligned moves grazing a box would miss it.
+func inverseDelta(d float64) float64 {
+	if d == 0 {
+		return math.MaxFloat64
+	}
+	return 1.0 / d
+}
@@ -0,0 +1,347 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+	"github.com/markel1974/godoom/mr_tech/physics"
+	"github.com/markel1974/godoom/mr_tech/textures"
+)
+
+// QueryFilter selects what a world query reports: the volumes and the things colliding with an object of the given
+// Layer and Mask (see config.Collides). Ignore is never reported, and neither are the things it owns, so that a
+// shooter and its projectiles are out of its own line of fire. Volumes and Things enable the two halves of the query.
+type QueryFilter struct {
+	Layer   config.CollisionLayer
+	Mask    config.CollisionLayer
+	Ignore  IThing
+	Volumes bool
+	Things  bool
+}
+
+// NewQueryFilter creates a QueryFilter of the given layer and mask reporting both the level geometry and the things.
+func NewQueryFilter(layer, mask config.CollisionLayer) QueryFilter {
+	return QueryFilter{Layer: layer, Mask: mask, Ignore: nil, Volumes: true, Things: true}
+}
+
+// acceptVolume reports whether the filter reports the faces of the volume.
+func (f *QueryFilter) acceptVolume(vol *Volume) bool {
+	if !f.Volumes {
+		return false
+	}
+	layer, mask := vol.GetCollision()
+	return config.Collides(f.Layer, f.Mask, layer, mask)
+}
+
+// acceptThing reports whether the filter reports the thing.
+func (f *QueryFilter) acceptThing(thing IThing) bool {
+	if !f.Things {
+		return false
+	}
+	base := thing.GetBase()
+	if f.Ignore != nil {
+		if ignore := f.Ignore.GetBase(); base == ignore || base.GetOwner() == ignore {
+			return false
+		}
+	}
+	layer, mask := base.GetCollision()
+	return config.Collides(f.Layer, f.Mask, layer, mask)
+}
+
+// QueryHit describes a hit of a world query. Distance is measured from the origin of a ray, from the start of a sweep
+// or from the center of an overlap. Position is the point hit by a ray, the center of the swept box at the time of
+// impact or the point closest to the center of an overlap; Normal is the surface normal there, facing the query. A hit
+// of the level geometry reports the Volume and the Face, with the Tag and the Material of the face; a hit of a thing
+// reports the Thing.
+type QueryHit struct {
+	Distance     float64
+	Position     geometry.XYZ
+	Normal       geometry.XYZ
+	Volume       *Volume
+	Face         *Face
+	Tag          string
+	Material     *textures.Texture
+	MaterialKind config.MaterialKind
+	Thing        IThing
+}
+
+// faceHit creates the QueryHit of a face at the given position, turning the normal of the face towards (dirX, dirY,
+// dirZ) reversed.
+func faceHit(face *Face, distance, px, py, pz, dirX, dirY, dirZ float64) QueryHit {
+	nX, nY, nZ := face.GetNormal()
+	if nX*dirX+nY*dirY+nZ*dirZ > 0 {
+		nX, nY, nZ = -nX, -nY, -nZ
+	}
+	texture, kind := face.GetMaterialDetails()
+	return QueryHit{
+		Distance:     distance,
+		Position:     geometry.XYZ{X: px, Y: py, Z: pz},
+		Normal:       geometry.XYZ{X: nX, Y: nY, Z: nZ},
+		Volume:       face.GetParent(),
+		Face:         face,
+		Tag:          face.GetTag(),
+		Material:     texture,
+		MaterialKind: config.MaterialKind(kind),
+	}
+}
+
+// thingHit creates the QueryHit of a thing at the given position.
+func thingHit(thing IThing, distance, px, py, pz, nX, nY, nZ float64) QueryHit {
+	return QueryHit{
+		Distance: distance,
+		Position: geometry.XYZ{X: px, Y: py, Z: pz},
+		Normal:   geometry.XYZ{X: nX, Y: nY, Z: nZ},
+		Thing:    thing,
+	}
+}
+
+// Query answers the spatial questions of the gameplay code against the level geometry and the things: raycasts, box
+// sweeps and overlaps, filtered by collision layers. Faces are tested exactly, things by their bounding boxes; sky
+// faces are reported too, with their MaterialKind. The
+// queries share the traversal stacks of the spatial trees: they are serialized among themselves and must not run
+// while the solver moves the things.
+type Query struct {
+	mu      sync.Mutex
+	volumes *Volumes
+	things  *Things
+}
+
+// NewQuery creates a Query on the given volumes and things.
+func NewQuery(volumes *Volumes, things *Things) *Query {
+	return &Query{volumes: volumes, things: things}
+}
+
+// Raycast returns the first hit of the ray from origin along dir within maxDistance.
+func (q *Query) Raycast(origin, dir geometry.XYZ, maxDistance float64, filter QueryFilter) (QueryHit, bool) {
+	var best QueryHit
+	found := false
+	q.raycast(origin, dir, maxDistance, filter, func(hit QueryHit) float64 {
+		best, found = hit, true
+		return hit.Distance
+	})
+	return best, found
+}
+
+// RaycastAll returns every hit of the ray from origin along dir within maxDistance, ordered by distance.
+func (q *Query) RaycastAll(origin, dir geometry.XYZ, maxDistance float64, filter QueryFilter) []QueryHit {
+	var hits []QueryHit
+	q.raycast(origin, dir, maxDistance, filter, func(hit QueryHit) float64 {
+		hits = append(hits, hit)
+		return maxDistance
+	})
+	sortHits(hits)
+	return hits
+}
+
+// raycast delivers the hits of the ray to report, which returns the distance the ray is shrunk to.
+func (q *Query) raycast(origin, dir geometry.XYZ, maxDistance float64, filter QueryFilter, report func(hit QueryHit) float64) {
+	length := math.Sqrt(dir.X*dir.X + dir.Y*dir.Y + dir.Z*dir.Z)
+	if length == 0 || maxDistance <= 0 {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	oX, oY, oZ := origin.X, origin.Y, origin.Z
+	dX, dY, dZ := dir.X/length, dir.Y/length, dir.Z/length
+	limit := maxDistance
+	if filter.Volumes {
+		q.volumes.QueryRay(oX, oY, oZ, dX, dY, dZ, limit, filter.Layer, filter.Mask, func(object physics.IAABB, _ float64) (float64, bool) {
+			vol, ok := object.(*Volume)
+			if !ok || !filter.acceptVolume(vol) {
+				return limit, false
+			}
+			vol.QueryRay(oX, oY, oZ, dX, dY, dZ, limit, func(face *Face, _ float64) (float64, bool) {
+				t, hit := face.IntersectRay(oX, oY, oZ, dX, dY, dZ)
+				if !hit || t > limit {
+					return limit, false
+				}
+				limit = report(faceHit(face, t, oX+dX*t, oY+dY*t, oZ+dZ*t, dX, dY, dZ))
+				return limit, true
+			})
+			return limit, true
+		})
+	}
+	if filter.Things {
+		q.things.QueryRay(oX, oY, oZ, dX, dY, dZ, limit, filter.Layer, filter.Mask, func(object physics.IAABB, _ float64) (float64, bool) {
+			thing, ok := object.(IThing)
+			if !ok || !filter.acceptThing(thing) {
+				return limit, false
+			}
+			// Il nodo dell'albero è allargato: il test esatto è sul box della cosa
+			aabb := thing.GetEntity().GetAABB()
+			t, hit := aabb.IntersectRay(oX, oY, oZ, inverseDelta(dX), inverseDelta(dY), inverseDelta(dZ))
+			if !hit || t > limit {
+				return limit, false
+			}
+			nX, nY, nZ := -dX, -dY, -dZ
+			if t > 0 {
+				nX, nY, nZ = aabb.NormalAt(oX+dX*t, oY+dY*t, oZ+dZ*t)
+			} else {
+				t = 0
+			}
+			limit = report(thingHit(thing, t, oX+dX*t, oY+dY*t, oZ+dZ*t, nX, nY, nZ))
+			return limit, true
+		})
+	}
+}
+
+// SweepBox moves the box of the given half extents from center by delta and returns its first hit. The Distance of the
+// hit is the length travelled along delta before the impact: zero when the box already touches at the start.
+func (q *Query) SweepBox(center, half, delta geometry.XYZ, filter QueryFilter) (QueryHit, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	box := queryArea(center.X-half.X, center.Y-half.Y, center.Z-half.Z, center.X+half.X, center.Y+half.Y, center.Z+half.Z).GetAABB()
+	area := queryArea(box.GetMinX()+math.Min(0, delta.X), box.GetMinY()+math.Min(0, delta.Y), box.GetMinZ()+math.Min(0, delta.Z),
+		box.GetMaxX()+math.Max(0, delta.X), box.GetMaxY()+math.Max(0, delta.Y), box.GetMaxZ()+math.Max(0, delta.Z))
+
+	toi := 1.0
+	var best QueryHit
+	found := false
+	if filter.Volumes {
+		q.volumes.QueryAABB(area, func(vol *Volume) {
+			if !filter.acceptVolume(vol) {
+				return
+			}
+			vol.QueryOverlaps(area, func(object physics.IAABB) bool {
+				face := object.(*Face)
+				ft, _, _, _, hit := face.SweepAABB(box, delta.X, delta.Y, delta.Z)
+				if hit && (!found || ft < toi) {
+					toi, found = ft, true
+					best = faceHit(face, 0, center.X+delta.X*ft, center.Y+delta.Y*ft, center.Z+delta.Z*ft, delta.X, delta.Y, delta.Z)
+				}
+				return false
+			})
+		})
+	}
+	if filter.Things {
+		expanded := physics.NewAABB()
+		q.things.QueryAABB(area, func(thing IThing) bool {
+			if !filter.acceptThing(thing) {
+				return false
+			}
+			// Box contro box: raggio del centro contro il box della cosa allargato delle mezze dimensioni
+			aabb := thing.GetEntity().GetAABB()
+			expanded.Rebuild(aabb.GetMinX()-half.X, aabb.GetMinY()-half.Y, aabb.GetMinZ()-half.Z, aabb.GetMaxX()+half.X, aabb.GetMaxY()+half.Y, aabb.GetMaxZ()+half.Z)
+			ft, hit := expanded.IntersectRay(center.X, center.Y, center.Z, inverseDelta(delta.X), inverseDelta(delta.Y), inverseDelta(delta.Z))
+			if !hit || ft > 1.0 || found && ft >= toi {
+				return false
+			}
+			ft = math.Max(ft, 0)
+			pX, pY, pZ := center.X+delta.X*ft, center.Y+delta.Y*ft, center.Z+delta.Z*ft
+			nX, nY, nZ := expanded.NormalAt(pX, pY, pZ)
+			toi, found = ft, true
+			best = thingHit(thing, 0, pX, pY, pZ, nX, nY, nZ)
+			return false
+		})
+	}
+	if found {
+		best.Distance = toi * math.Sqrt(delta.X*delta.X+delta.Y*delta.Y+delta.Z*delta.Z)
+	}
+	return best, found
+}
+
+// OverlapSphere returns the faces and the things within radius from center, ordered by distance.
+func (q *Query) OverlapSphere(center geometry.XYZ, radius float64, filter QueryFilter) []QueryHit {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	area := queryArea(center.X-radius, center.Y-radius, center.Z-radius, center.X+radius, center.Y+radius, center.Z+radius)
+	var hits []QueryHit
+	if filter.Volumes {
+		q.volumes.QueryAABB(area, func(vol *Volume) {
+			if !filter.acceptVolume(vol) {
+				return
+			}
+			vol.QueryOverlaps(area, func(object physics.IAABB) bool {
+				face := object.(*Face)
+				pX, pY, pZ := face.ClosestPoint(center.X, center.Y, center.Z)
+				if d := distance(center, pX, pY, pZ); d <= radius {
+					hits = append(hits, faceHit(face, d, pX, pY, pZ, pX-center.X, pY-center.Y, pZ-center.Z))
+				}
+				return false
+			})
+		})
+	}
+	if filter.Things {
+		q.things.QueryAABB(area, func(thing IThing) bool {
+			if !filter.acceptThing(thing) {
+				return false
+			}
+			if hit, d := overlapThing(thing, center); d <= radius {
+				hits = append(hits, hit)
+			}
+			return false
+		})
+	}
+	sortHits(hits)
+	return hits
+}
+
+// OverlapBox returns the faces and the things intersecting the box of the given half extents around center, ordered
+// by distance from center.
+func (q *Query) OverlapBox(center, half geometry.XYZ, filter QueryFilter) []QueryHit {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	area := queryArea(center.X-half.X, center.Y-half.Y, center.Z-half.Z, center.X+half.X, center.Y+half.Y, center.Z+half.Z)
+	var hits []QueryHit
+	if filter.Volumes {
+		q.volumes.QueryAABB(area, func(vol *Volume) {
+			if !filter.acceptVolume(vol) {
+				return
+			}
+			vol.QueryOverlaps(area, func(object physics.IAABB) bool {
+				face := object.(*Face)
+				if face.OverlapsAABB(area.GetAABB()) {
+					pX, pY, pZ := face.ClosestPoint(center.X, center.Y, center.Z)
+					hits = append(hits, faceHit(face, distance(center, pX, pY, pZ), pX, pY, pZ, pX-center.X, pY-center.Y, pZ-center.Z))
+				}
+				return false
+			})
+		})
+	}
+	if filter.Things {
+		q.things.QueryAABB(area, func(thing IThing) bool {
+			if filter.acceptThing(thing) && thing.GetEntity().GetAABB().Overlaps(area.GetAABB()) {
+				hit, _ := overlapThing(thing, center)
+				hits = append(hits, hit)
+			}
+			return false
+		})
+	}
+	sortHits(hits)
+	return hits
+}
+
+// queryArea creates the bounding box of a query from its bounds.
+func queryArea(minX, minY, minZ, maxX, maxY, maxZ float64) *physics.BoundingBox {
+	return physics.NewBoundingBox(minX, minY, maxX-minX, maxY-minY, minZ, maxZ-minZ)
+}
+
+// overlapThing returns the hit of the point of the thing box closest to center, and its distance from center.
+func overlapThing(thing IThing, center geometry.XYZ) (QueryHit, float64) {
+	aabb := thing.GetEntity().GetAABB()
+	pX := math.Max(aabb.GetMinX(), math.Min(center.X, aabb.GetMaxX()))
+	pY := math.Max(aabb.GetMinY(), math.Min(center.Y, aabb.GetMaxY()))
+	pZ := math.Max(aabb.GetMinZ(), math.Min(center.Z, aabb.GetMaxZ()))
+	d := distance(center, pX, pY, pZ)
+	nX, nY, nZ := 0.0, 0.0, 1.0
+	if d > 0 {
+		nX, nY, nZ = (center.X-pX)/d, (center.Y-pY)/d, (center.Z-pZ)/d
+	}
+	return thingHit(thing, d, pX, pY, pZ, nX, nY, nZ), d
+}
+
+// distance returns the distance between p and (x, y, z).
+func distance(p geometry.XYZ, x, y, z float64) float64 {
+	dx, dy, dz := x-p.X, y-p.Y, z-p.Z
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// sortHits orders the hits by distance.
+func sortHits(hits []QueryHit) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Distance < hits[j].Distance
+	})
+}
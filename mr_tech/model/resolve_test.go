@@ -0,0 +1,141 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// newResolveScene compiles a world with count enemies falling on a grid around the player and steps it, in
+// deterministic mode, until they are all simulated. Each cell of the grid is an island of its own: it holds a single
+// enemy or, when pairs is set, two enemies touching each other.
+func newResolveScene(tb testing.TB, count int, pairs bool) *Compiler {
+	var template *config.Thing
+	c := newTestWorld(tb, func(cfg *config.Root) {
+		for _, ct := range cfg.Things {
+			if ct.Kind == config.ThingEnemyDef {
+				template = ct.Clone()
+				break
+			}
+		}
+		// Le cose del livello unirebbero le isole che toccano
+		cfg.Things = nil
+	})
+	things := c.GetThings()
+	things.SetDeterministic(true)
+	pX, pY, pZ := c.GetPlayer().GetEntity().GetCenter()
+	// Le cose cadono a due larghezze del player di distanza, più del loro lato: ogni cella è un'isola
+	spacing := c.GetPlayer().GetEntity().GetWidth() * 2
+	spacingX, perCell := spacing, 1
+	if pairs {
+		// La cella di una coppia è larga il doppio, per far posto al secondo corpo
+		spacingX, perCell = spacing*2, 2
+	}
+	side := 1
+	for side*side*perCell < count {
+		side++
+	}
+	spawned := 0
+	for x := 0; spawned < count && x < side*4; x++ {
+		for y := 0; spawned < count && y < side*4; y++ {
+			pos := geometry.XYZ{X: pX + float64(x-side*2)*spacingX, Y: pY + float64(y-side*2)*spacing, Z: pZ + 10}
+			if volume, _ := c.GetVolumes().QueryPoint(pos.X, pos.Y, pos.Z); volume == nil {
+				continue
+			}
+			thing := things.Spawn(template, pos, 0)
+			if thing == nil {
+				continue
+			}
+			spawned++
+			if pairs && spawned < count {
+				// Il secondo corpo tocca il primo, con una compenetrazione entro il filtro SAT delle facce
+				pos.X += thing.GetEntity().GetWidth() - satFilterEpsilon*0.5
+				if things.Spawn(template, pos, 0) != nil {
+					spawned++
+				}
+			}
+		}
+	}
+	stepWorld(c, 2)
+	markResolve(things)
+	islands, _ := things.partitionResolve(nil)
+	tb.Logf("%d spawned, %d simulated, %d islands", spawned, things.activeIdx, len(islands))
+	return c
+}
+
+// markResolve marks the simulated things as the start of a step does, so that the contacts can be resolved again.
+func markResolve(th *Things) {
+	th.resolveStep++
+	for x := 0; x < th.activeIdx; x++ {
+		base := th.active[x].GetBase()
+		base.resolveSlot, base.resolveStep = x, th.resolveStep
+	}
+}
+
+func BenchmarkResolve(b *testing.B) {
+	for _, count := range []int{8, 16, 32, 64, 128, 256} {
+		things := newResolveScene(b, count, false).GetThings()
+		b.Run(fmt.Sprintf("serial/%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				markResolve(things)
+				things.resolveIsland(things.active[:things.activeIdx], nil)
+			}
+		})
+		b.Run(fmt.Sprintf("parallel/%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				markResolve(things)
+				things.resolveParallel(nil)
+			}
+		})
+	}
+}
+
+func TestResolveParallelMatchesSerial(t *testing.T) {
+	const count = resolveParallelMin * 2
+	serial := newResolveScene(t, count, true)
+	parallel := newResolveScene(t, count, true)
+	sThings, pThings := serial.GetThings(), parallel.GetThings()
+	if sThings.StateHash() != pThings.StateHash() {
+		t.Fatal("the two scenes differ before the resolution")
+	}
+	if pThings.activeIdx < resolveParallelMin {
+		t.Fatalf("%d simulated things, want at least %d", pThings.activeIdx, resolveParallelMin)
+	}
+	islands, _ := pThings.partitionResolve(nil)
+	touching := 0
+	for _, island := range islands {
+		if len(island) > 1 {
+			touching++
+		}
+	}
+	if len(islands) < 2 || touching == 0 {
+		t.Fatalf("%d islands, %d with touching things: the scene does not exercise the parallel path", len(islands), touching)
+	}
+
+	// Solo la modalità non deterministica risolve le isole in parallelo
+	sThings.SetDeterministic(false)
+	sThings.SetParallelResolve(false)
+	pThings.SetDeterministic(false)
+	pThings.SetParallelResolve(true)
+	// Le coppie si spingono l'una contro l'altra, così a ogni passo il solutore ha contatti da risolvere
+	push := func(th *Things) {
+		speed := th.player.GetEntity().GetWidth()
+		for _, thing := range th.ordered {
+			if thing.GetBase().GetKind() == config.ThingEnemyDef {
+				thing.GetEntity().SetV(speed, 0, 0)
+				speed = -speed
+			}
+		}
+	}
+	for step := 0; step < 10; step++ {
+		push(sThings)
+		stepWorld(serial, 1)
+		push(pThings)
+		stepWorld(parallel, 1)
+		if sThings.StateHash() != pThings.StateHash() {
+			t.Fatalf("step %d: the parallel resolution differs from the serial one", step)
+		}
+	}
+}
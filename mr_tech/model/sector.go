@@ -32,6 +32,7 @@ type Sector struct {
 	segmentsTree *physics.AABBTree
 	slopeF       *Slope
 	slopeC       *Slope
+	volume       *Volume
 }
 
 // NewSector creates and returns a pointer to a new Sector with specified parameters such as id, bounds, and materials.
@@ -96,6 +97,26 @@ func (s *Sector) GetMaxZ() float64 {
 	return s.maxZ
 }
 
+// SetMinZ updates the floor height of the sector. Rebuild must be called to refresh the derived geometry.
+func (s *Sector) SetMinZ(z float64) {
+	s.minZ = z
+}
+
+// SetMaxZ updates the ceiling height of the sector. Rebuild must be called to refresh the derived geometry.
+func (s *Sector) SetMaxZ(z float64) {
+	s.maxZ = z
+}
+
+// GetVolume retrieves the 3D Volume extruded from the sector, or nil if the sector has not been upgraded.
+func (s *Sector) GetVolume() *Volume {
+	return s.volume
+}
+
+// SetVolume assigns the 3D Volume extruded from the sector.
+func (s *Sector) SetVolume(v *Volume) {
+	s.volume = v
+}
+
 // GetLight retrieves the Light instance associated with the Sector. Returns a pointer to the Light object.
 func (s *Sector) GetLight() *Light {
 	return s.light
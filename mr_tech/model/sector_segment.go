@@ -110,6 +110,19 @@ func (s *Segment) GetMaterialIndex(m int) *textures.Material {
 	return s.materials[idx]
 }
 
+// SetMaterialIndex replaces the material at index m, cycling through available materials in the Segment.
+func (s *Segment) SetMaterialIndex(m int, material *textures.Material) {
+	// Il compilatore può condividere la slice tra più segmenti
+	s.materials = append([]*textures.Material(nil), s.materials...)
+	idx := m % len(s.materials)
+	s.materials[idx] = material
+}
+
+// GetMaterialsLen returns the number of materials of the Segment.
+func (s *Segment) GetMaterialsLen() int {
+	return len(s.materials)
+}
+
 // PointInLineSide determines if the point (px, py) is on the positive side of the line segment defined by the segment's start and end.
 func (s *Segment) PointInLineSide(px, py float64) bool {
 	start := s.GetStart()
@@ -0,0 +1,51 @@
+package model
+
+// defaultMaxSpawned is the default cap on the things created by Spawn alive at the same time.
+const defaultMaxSpawned = 256
+
+// SpawnCap limits the things created by Spawn alive at the same time, so that the spawners can't fill the level: a
+// spawned thing stops counting once killed or taken. The things of the level and the launched throwables don't count.
+type SpawnCap struct {
+	spawned    []IThing
+	maxSpawned int
+}
+
+// NewSpawnCap creates a SpawnCap allowing up to maxSpawned things alive; zero removes the cap.
+func NewSpawnCap(maxSpawned int) *SpawnCap {
+	return &SpawnCap{maxSpawned: max(maxSpawned, 0)}
+}
+
+// SetMax sets the cap on the spawned things alive at the same time; zero removes the cap.
+func (sc *SpawnCap) SetMax(maxSpawned int) {
+	sc.maxSpawned = max(maxSpawned, 0)
+}
+
+// GetMax returns the cap on the spawned things alive at the same time, zero if uncapped.
+func (sc *SpawnCap) GetMax() int {
+	return sc.maxSpawned
+}
+
+// Count returns the number of spawned things that are still alive, neither killed nor taken.
+func (sc *SpawnCap) Count() int {
+	alive := sc.spawned[:0]
+	for _, t := range sc.spawned {
+		if isAlive(t) {
+			alive = append(alive, t)
+		}
+	}
+	for x := len(alive); x < len(sc.spawned); x++ {
+		sc.spawned[x] = nil
+	}
+	sc.spawned = alive
+	return len(alive)
+}
+
+// full reports whether the spawned things alive have reached the cap.
+func (sc *SpawnCap) full() bool {
+	return sc.maxSpawned > 0 && sc.Count() >= sc.maxSpawned
+}
+
+// add counts the spawned thing against the cap.
+func (sc *SpawnCap) add(thing IThing) {
+	sc.spawned = append(sc.spawned, thing)
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+func TestSpawnCap(t *testing.T) {
+	var box *config.Thing
+	c := newTestWorld(t, func(cfg *config.Root) {
+		box = newBoxTemplate(t, cfg)
+	})
+	things := c.GetThings()
+	spawnCap := things.GetSpawnCap()
+	spawnCap.SetMax(2)
+	pos := farPoint(t, c)
+	first, second := things.Spawn(box, pos, 0), things.Spawn(box, pos, 0)
+	if first == nil || second == nil {
+		t.Fatal("the things within the cap have not been spawned")
+	}
+	if things.Spawn(box, pos, 0) != nil {
+		t.Fatal("a thing beyond the cap has been spawned")
+	}
+	// Le cose del livello non contano, quelle generate smettono di contare quando escono dalla simulazione
+	first.SetActive(false)
+	if n := spawnCap.Count(); n != 1 {
+		t.Fatalf("%d spawned things alive, want 1", n)
+	}
+	if things.Spawn(box, pos, 0) == nil {
+		t.Fatal("no thing spawned after one of the capped ones has gone")
+	}
+	spawnCap.SetMax(0)
+	for x := 0; x < 3; x++ {
+		if things.Spawn(box, pos, 0) == nil {
+			t.Fatal("a thing has not been spawned without a cap")
+		}
+	}
+}
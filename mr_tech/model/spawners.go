@@ -0,0 +1,301 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+// Spawner creates copies of a template thing at runtime through Things.Spawn: in waves, cycling through its points,
+// or respawning the thing of each point some time after it is killed or taken.
+type Spawner struct {
+	id       string
+	kind     config.SpawnerKind
+	template *config.Thing
+	points   []config.SpawnPoint
+	waves    []config.SpawnWave
+	delay    float64
+	maxAlive int
+	active   bool
+	wave     int
+	count    int
+	next     int
+	timer    float64
+	alive    []IThing
+	slots    []IThing
+	timers   []float64
+}
+
+// NewSpawner creates the Spawner described by the configuration, placing its things at points.
+func NewSpawner(cfg *config.Spawner, points []config.SpawnPoint) *Spawner {
+	s := &Spawner{
+		id:       cfg.Id,
+		kind:     cfg.Kind,
+		template: cfg.Template,
+		points:   points,
+		waves:    cfg.Waves,
+		delay:    cfg.Delay,
+		maxAlive: cfg.MaxAlive,
+		slots:    make([]IThing, len(points)),
+		timers:   make([]float64, len(points)),
+	}
+	if cfg.Active {
+		s.Start()
+	}
+	return s
+}
+
+// GetId returns the identifier of the Spawner.
+func (s *Spawner) GetId() string {
+	return s.id
+}
+
+// IsActive reports whether the Spawner is creating things.
+func (s *Spawner) IsActive() bool {
+	return s.active
+}
+
+// IsCompleted reports whether a wave Spawner has spawned every wave and every thing of the last one is gone.
+func (s *Spawner) IsCompleted() bool {
+	return s.kind == config.SpawnerWaves && s.wave >= len(s.waves)
+}
+
+// GetWave returns the index of the current wave of a wave Spawner.
+func (s *Spawner) GetWave() int {
+	return s.wave
+}
+
+// Alive returns the things of the Spawner that are alive, neither killed nor taken.
+func (s *Spawner) Alive() []IThing {
+	s.prune()
+	if s.kind == config.SpawnerWaves {
+		return s.alive
+	}
+	var out []IThing
+	for _, t := range s.slots {
+		if t != nil {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// Start activates the Spawner. A wave Spawner that is not active restarts from its first wave; the empty points of a
+// respawn Spawner are filled at the next step.
+func (s *Spawner) Start() {
+	if s.active {
+		return
+	}
+	s.active = true
+	if s.kind == config.SpawnerWaves {
+		s.wave, s.count = 0, 0
+		if len(s.waves) > 0 {
+			s.timer = s.waves[0].Delay
+		}
+		return
+	}
+	for idx := range s.timers {
+		s.timers[idx] = 0
+	}
+}
+
+// Stop deactivates the Spawner; the things already spawned stay in the world.
+func (s *Spawner) Stop() {
+	s.active = false
+}
+
+// adopt binds the thing to the index-th point, as if the Spawner had spawned it.
+func (s *Spawner) adopt(index int, thing IThing) {
+	s.slots[index] = thing
+}
+
+// prune forgets the things of the Spawner that are no longer alive, starting the respawn timer of their points.
+func (s *Spawner) prune() {
+	alive := s.alive[:0]
+	for _, t := range s.alive {
+		if isAlive(t) {
+			alive = append(alive, t)
+		}
+	}
+	for x := len(alive); x < len(s.alive); x++ {
+		s.alive[x] = nil
+	}
+	s.alive = alive
+	for idx, t := range s.slots {
+		if t != nil && !isAlive(t) {
+			s.slots[idx] = nil
+			s.timers[idx] = s.delay
+		}
+	}
+}
+
+// compute advances the timers of the Spawner by dt and spawns the things that are due.
+func (s *Spawner) compute(things *Things, dt float64) {
+	s.prune()
+	if !s.active {
+		return
+	}
+	if s.kind == config.SpawnerRespawn {
+		s.computeRespawn(things, dt)
+		return
+	}
+	s.computeWaves(things, dt)
+}
+
+// computeWaves spawns the things of the current wave and moves to the next wave once the current one is cleared.
+func (s *Spawner) computeWaves(things *Things, dt float64) {
+	if s.wave >= len(s.waves) {
+		s.active = false
+		return
+	}
+	if s.count >= s.waves[s.wave].Count {
+		if len(s.alive) > 0 {
+			return
+		}
+		s.wave++
+		s.count = 0
+		if s.wave >= len(s.waves) {
+			s.active = false
+			return
+		}
+		s.timer = s.waves[s.wave].Delay
+	}
+	if s.timer -= dt; s.timer > 0 {
+		return
+	}
+	if s.maxAlive > 0 && len(s.alive) >= s.maxAlive {
+		return
+	}
+	// Lo spawn rifiutato dal limite globale viene ritentato al passo successivo
+	p := s.points[s.next%len(s.points)]
+	thing := things.Spawn(s.template, p.Position, p.Angle)
+	if thing == nil {
+		return
+	}
+	s.alive = append(s.alive, thing)
+	s.count++
+	s.next++
+	s.timer = s.waves[s.wave].Interval
+}
+
+// computeRespawn spawns a thing at each empty point whose respawn timer has run out.
+func (s *Spawner) computeRespawn(things *Things, dt float64) {
+	count := 0
+	for _, t := range s.slots {
+		if t != nil {
+			count++
+		}
+	}
+	for idx, p := range s.points {
+		if s.slots[idx] != nil {
+			continue
+		}
+		if s.timers[idx] -= dt; s.timers[idx] > 0 {
+			continue
+		}
+		if s.maxAlive > 0 && count >= s.maxAlive {
+			return
+		}
+		if s.slots[idx] = things.Spawn(s.template, p.Position, p.Angle); s.slots[idx] != nil {
+			count++
+		}
+	}
+}
+
+// Spawners manages the spawners of the world, created from the configuration, and the respawn of the level things
+// with a respawn time.
+type Spawners struct {
+	container []*Spawner
+	cache     map[string]*Spawner
+	things    *Things
+}
+
+// NewSpawners creates the spawners described by the configuration, whose templates, kinds, waves and points have been
+// checked by the validator; the points outside the level volumes are skipped with a warning. Each level thing with a
+// respawn time gets a respawn Spawner of its own, with the id of the thing, bound to its position.
+func NewSpawners(cfg []*config.Spawner, volumes *Volumes, things *Things) *Spawners {
+	ss := &Spawners{
+		cache:  make(map[string]*Spawner),
+		things: things,
+	}
+	for _, cs := range cfg {
+		var points []config.SpawnPoint
+		for _, p := range cs.Points {
+			if volume, _ := volumes.QueryPoint(p.Position.X, p.Position.Y, p.Position.Z); volume == nil {
+				fmt.Printf("Warning can't find spawn point location at %f, %f, %f for spawner %s\n", p.Position.X, p.Position.Y, p.Position.Z, cs.Id)
+				continue
+			}
+			points = append(points, p)
+		}
+		if len(points) == 0 {
+			fmt.Printf("Warning no points for spawner %s\n", cs.Id)
+			continue
+		}
+		ss.add(NewSpawner(cs, points))
+	}
+	byId := make(map[string]IThing)
+	for _, t := range things.ordered {
+		byId[t.GetBase().GetId()] = t
+	}
+	for _, ct := range things.config {
+		t, ok := byId[ct.Id]
+		if !ok || ct.Respawn <= 0 {
+			continue
+		}
+		// Il template è la configurazione di partenza, il thing del livello occupa già il punto
+		cs := config.NewConfigSpawner(ct.Id, config.SpawnerRespawn, ct.Clone(), []config.SpawnPoint{{Position: ct.Position, Angle: ct.Angle}})
+		cs.Delay = ct.Respawn
+		s := NewSpawner(cs, cs.Points)
+		s.adopt(0, t)
+		ss.add(s)
+	}
+	return ss
+}
+
+// add registers the Spawner.
+func (ss *Spawners) add(s *Spawner) {
+	ss.container = append(ss.container, s)
+	ss.cache[s.GetId()] = s
+}
+
+// GetSpawner retrieves a Spawner by its identifier, or nil if it does not exist.
+func (ss *Spawners) GetSpawner(id string) *Spawner {
+	return ss.cache[id]
+}
+
+// GetSpawners returns all the spawners.
+func (ss *Spawners) GetSpawners() []*Spawner {
+	return ss.container
+}
+
+// Len returns the number of spawners.
+func (ss *Spawners) Len() int {
+	return len(ss.container)
+}
+
+// Start activates the Spawner with the given identifier, returning false if it does not exist.
+func (ss *Spawners) Start(id string) bool {
+	s, ok := ss.cache[id]
+	if !ok {
+		return false
+	}
+	s.Start()
+	return true
+}
+
+// Stop deactivates the Spawner with the given identifier, returning false if it does not exist.
+func (ss *Spawners) Stop(id string) bool {
+	s, ok := ss.cache[id]
+	if !ok {
+		return false
+	}
+	s.Stop()
+	return true
+}
+
+// Compute advances every Spawner by dt. The spawned things join the simulation at the next Things.Compute.
+func (ss *Spawners) Compute(dt float64) {
+	for _, s := range ss.container {
+		s.compute(ss.things, dt)
+	}
+}
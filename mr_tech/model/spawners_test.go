@@ -0,0 +1,90 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+// newTestSpawnerWorld compiles the test level with a spawner of the given kind, cloning the first item of the level
+// at the positions of the first two things, and returns the world and the spawner.
+func newTestSpawnerWorld(tb testing.TB, kind config.SpawnerKind, edit func(s *config.Spawner)) (*Compiler, *Spawner) {
+	tb.Helper()
+	c := newTestWorld(tb, func(cfg *config.Root) {
+		var points []config.SpawnPoint
+		for _, ct := range cfg.Things[:2] {
+			points = append(points, config.SpawnPoint{Position: ct.Position, Angle: ct.Angle})
+		}
+		s := config.NewConfigSpawner("spawner", kind, cfg.Things[0].Clone(), points)
+		edit(s)
+		cfg.Spawners = append(cfg.Spawners, s)
+	})
+	s := c.GetSpawners().GetSpawner("spawner")
+	if s == nil {
+		tb.Fatal("the spawner has not been created")
+	}
+	return c, s
+}
+
+// take removes the thing from the world as a pickup collected by the player.
+func take(thing IThing) {
+	thing.GetBase().SetActive(false)
+}
+
+func TestSpawnerMaxAlive(t *testing.T) {
+	c, s := newTestSpawnerWorld(t, config.SpawnerWaves, func(s *config.Spawner) {
+		s.Waves = []config.SpawnWave{{Count: 5}}
+		s.MaxAlive = 2
+	})
+	stepWorld(c, 10)
+	alive := s.Alive()
+	if len(alive) != 2 {
+		t.Fatalf("%d things alive, want the cap of 2", len(alive))
+	}
+	// Il posto liberato viene occupato al passo successivo, senza superare il limite
+	take(alive[0])
+	stepWorld(c, 10)
+	if n := len(s.Alive()); n != 2 || s.count != 3 {
+		t.Fatalf("%d things alive and %d spawned, want 2 and 3", n, s.count)
+	}
+	for _, thing := range s.Alive() {
+		take(thing)
+	}
+	stepWorld(c, 10)
+	for _, thing := range s.Alive() {
+		take(thing)
+	}
+	stepWorld(c, 10)
+	if !s.IsCompleted() || s.IsActive() {
+		t.Fatalf("completed %t, active %t after the whole wave has been taken", s.IsCompleted(), s.IsActive())
+	}
+}
+
+func TestSpawnerRespawnTimer(t *testing.T) {
+	const delay = 1.0
+	c, s := newTestSpawnerWorld(t, config.SpawnerRespawn, func(s *config.Spawner) {
+		s.Delay = delay
+	})
+	dt := c.GetThings().GetTimeStep()
+	stepWorld(c, 1)
+	alive := s.Alive()
+	if len(alive) != 2 {
+		t.Fatalf("%d things alive, want one on each point", len(alive))
+	}
+	first := alive[0]
+	take(first)
+	stepWorld(c, int(delay*0.5/dt))
+	if n := len(s.Alive()); n != 1 {
+		t.Fatalf("%d things alive before the respawn time, want 1", n)
+	}
+	stepWorld(c, int(delay*0.6/dt))
+	alive = s.Alive()
+	if len(alive) != 2 {
+		t.Fatalf("%d things alive after the respawn time, want 2", len(alive))
+	}
+	for _, thing := range alive {
+		if thing == first {
+			t.Fatal("the taken thing has been brought back instead of respawned")
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package model
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+	"github.com/markel1974/godoom/mr_tech/textures"
+)
+
+// specialsUseReach is the reach of the use action, expressed in player widths.
+// specialsUseSlack is the distance before a trigger line within which a wall hit by the use action lies on the line.
+// specialsWallNormalZ is the largest vertical component of the normal of a wall face struck by a shot.
+const (
+	specialsUseReach    = 2.0
+	specialsUseSlack    = 0.01
+	specialsWallNormalZ = 1e-3
+)
+
+// Special is the runtime state of a line special (trigger).
+type Special struct {
+	id         string
+	start      geometry.XY
+	end        geometry.XY
+	sector     string
+	activation config.TriggerActivation
+	repeat     bool
+	action     config.TriggerAction
+	movers     []string
+	elevators  []string
+	message    string
+	param      int
+	sectors    []*Sector
+	light      float64
+	switches   []*textures.Material
+	key        string
+	fired      bool
+}
+
+// GetKey returns the key needed to activate the Special, or the empty string.
+func (s *Special) GetKey() string {
+	return s.key
+}
+
+// GetId returns the identifier of the Special.
+func (s *Special) GetId() string {
+	return s.id
+}
+
+// IsFired reports whether the Special has been activated at least once.
+func (s *Special) IsFired() bool {
+	return s.fired
+}
+
+// Specials interprets the line specials of the world: it detects when the player crosses, uses or shoots a trigger
+// line or enters a trigger sector, and fires the referenced movers, elevator messages, light changes and exits.
+type Specials struct {
+	container  []*Special
+	cache      map[string]*Special
+	movers     *Movers
+	elevators  *Elevators
+	hasPrev    bool
+	prev       geometry.XY
+	prevSector string
+	exited     bool
+	secret     bool
+}
+
+// NewSpecials creates the runtime specials described by the configuration, binding them to the movers, to the
+// elevators and to the compiled sectors with the referenced ids.
+func NewSpecials(cfg []*config.Trigger, movers *Movers, elevators *Elevators, volumes *Volumes) *Specials {
+	ss := &Specials{
+		cache:     make(map[string]*Special),
+		movers:    movers,
+		elevators: elevators,
+	}
+	sectors := sectorsById(volumes)
+	var switched []*Sector
+	for _, ct := range cfg {
+		s := &Special{
+			id:         ct.Id,
+			start:      ct.Start,
+			end:        ct.End,
+			sector:     ct.Sector,
+			activation: ct.Activation,
+			repeat:     ct.Repeat,
+			action:     ct.Action,
+			movers:     ct.Movers,
+			elevators:  ct.Elevators,
+			message:    ct.Message,
+			param:      ct.Param,
+			light:      ct.Light,
+			key:        ct.Key,
+		}
+		for _, id := range ct.Sectors {
+			s.sectors = append(s.sectors, sectors[id]...)
+		}
+		if ct.Switch != "" {
+			switched = append(switched, s.pinSwitches(ct.Switch, volumes)...)
+		}
+		ss.container = append(ss.container, s)
+		ss.cache[s.id] = s
+	}
+	if len(switched) > 0 {
+		// Le facce puntano ai materiali originali: si ricostruiscono con quelli bloccati
+		rebuildVolumes(affectedVolumes(switched), volumes)
+	}
+	return ss
+}
+
+// pinSwitches replaces the materials of the wall segments tagged as the switch with pinned copies, whose frame advances
+// at each activation, and returns the sectors owning those segments.
+func (s *Special) pinSwitches(tag string, volumes *Volumes) []*Sector {
+	var owners []*Sector
+	for _, vol := range volumes.GetVolumes() {
+		sector := vol.GetSector()
+		if sector == nil {
+			continue
+		}
+		segments, segmentCount := sector.GetSegments()
+		for x := 0; x < segmentCount; x++ {
+			seg := segments[x]
+			if seg.GetTag() != tag {
+				continue
+			}
+			for m := 0; m < seg.GetMaterialsLen(); m++ {
+				material := seg.GetMaterialIndex(m)
+				if material == nil {
+					continue
+				}
+				pinned := material.Clone()
+				pinned.Pin(0)
+				seg.SetMaterialIndex(m, pinned)
+				s.switches = append(s.switches, pinned)
+			}
+			owners = append(owners, sector)
+		}
+	}
+	return owners
+}
+
+// GetSpecial retrieves a Special by its identifier, or nil if it does not exist.
+func (ss *Specials) GetSpecial(id string) *Special {
+	return ss.cache[id]
+}
+
+// Len returns the number of specials.
+func (ss *Specials) Len() int {
+	return len(ss.container)
+}
+
+// IsExited reports whether an exit special or an exit trigger volume has been fired and whether it was the secret exit.
+func (ss *Specials) IsExited() (bool, bool) {
+	return ss.exited, ss.secret
+}
+
+// Fire activates the special with the given identifier regardless of its activation and key, returning false if it does not
+// exist or if it is a single-shot special already fired.
+func (ss *Specials) Fire(id string) bool {
+	s, ok := ss.cache[id]
+	if !ok {
+		return false
+	}
+	return ss.fire(s)
+}
+
+// fireBy fires the special with the given identifier on behalf of the thing: a special needing a key is fired only by
+// the player owning it.
+func (ss *Specials) fireBy(id string, thing IThing) bool {
+	s, ok := ss.cache[id]
+	if !ok {
+		return false
+	}
+	if player, ok := thing.(*ThingPlayer); ok {
+		return ss.activate(s, player)
+	}
+	if s.key != "" {
+		return false
+	}
+	return ss.fire(s)
+}
+
+// Compute checks the player actions of the last step against the trigger lines and fires the activated specials.
+func (ss *Specials) Compute(player *ThingPlayer) {
+	if player == nil || len(ss.container) == 0 {
+		return
+	}
+	x, y, z := player.GetEntity().GetCenter()
+	curr := geometry.XY{X: x, Y: y}
+	if ss.hasPrev && (curr.X != ss.prev.X || curr.Y != ss.prev.Y) {
+		for _, s := range ss.container {
+			if s.activation == config.TriggerCross && geometry.SegmentsCross(ss.prev, curr, s.start, s.end) {
+				ss.activate(s, player)
+			}
+		}
+	}
+	ss.prev, ss.hasPrev = curr, true
+
+	if location := player.GetLocation(); location != nil && location.GetSector() != nil {
+		if id := location.GetSector().GetId(); id != ss.prevSector {
+			ss.prevSector = id
+			for _, s := range ss.container {
+				if s.activation == config.TriggerEnter && s.sector == id {
+					ss.activate(s, player)
+				}
+			}
+		}
+	}
+
+	angle := player.GetAngle()
+	dirX, dirY := math.Cos(angle), math.Sin(angle)
+	if player.ConsumeUse() {
+		reach := player.GetEntity().GetWidth() * specialsUseReach
+		origin := geometry.XYZ{X: x, Y: y, Z: z}
+		if s := ss.closest(config.TriggerUse, origin, dirX, dirY, reach, player.things.query); s != nil {
+			ss.activate(s, player)
+		}
+	}
+	for _, face := range player.ConsumeShots() {
+		if s := ss.struck(face); s != nil {
+			ss.activate(s, player)
+		}
+	}
+}
+
+// struck returns the shoot special of the trigger line the wall face lies on, or nil. Each of the two triangles of a
+// wall quad spans the whole segment of the wall, which lies on the trigger line.
+func (ss *Specials) struck(face *Face) *Special {
+	if _, _, nZ := face.GetNormal(); math.Abs(nZ) > specialsWallNormalZ {
+		// Pavimenti e soffitti: un loro lato può giacere sulla linea
+		return nil
+	}
+	points := face.GetPoints()
+	a := geometry.XY{X: points[0].X, Y: points[0].Y}
+	b, bestDist := a, 0.0
+	for _, p := range points[1:] {
+		if dist := geometry.DistanceSq(a, geometry.XY{X: p.X, Y: p.Y}); dist > bestDist {
+			b, bestDist = geometry.XY{X: p.X, Y: p.Y}, dist
+		}
+	}
+	if bestDist == 0 {
+		return nil
+	}
+	for _, s := range ss.container {
+		if s.activation == config.TriggerShoot && geometry.IsSegmentSubset(a, b, s.start, s.end) {
+			return s
+		}
+	}
+	return nil
+}
+
+// closest returns the nearest special with the given activation hit by the horizontal ray from origin, or nil. When
+// query is not nil, the special is returned only if no face of the level geometry stands before its line.
+func (ss *Specials) closest(activation config.TriggerActivation, origin geometry.XYZ, dirX, dirY, length float64, query *Query) *Special {
+	from := geometry.XY{X: origin.X, Y: origin.Y}
+	target := geometry.XY{X: origin.X + dirX*length, Y: origin.Y + dirY*length}
+	var best *Special
+	bestDist := math.MaxFloat64
+	var bestX, bestY float64
+	for _, s := range ss.container {
+		if s.activation != activation || !geometry.SegmentsIntersect(from, target, s.start, s.end) {
+			continue
+		}
+		ix, iy, ok := geometry.IntersectFn(origin.X, origin.Y, target.X, target.Y, s.start.X, s.start.Y, s.end.X, s.end.Y)
+		if !ok {
+			// Raggio collineare con la linea: si usa l'estremo più vicino
+			ix, iy = s.start.X, s.start.Y
+		}
+		if dist := math.Hypot(ix-origin.X, iy-origin.Y); dist < bestDist {
+			best, bestDist, bestX, bestY = s, dist, ix, iy
+		}
+	}
+	if best == nil || query == nil || bestDist == 0 {
+		return best
+	}
+	// Come lo sparo, l'azione si ferma sul primo muro: l'interruttore al di là non si preme
+	walls := NewQueryFilter(config.LayerProjectile, config.LayerWorld)
+	walls.Things = false
+	dir := geometry.XYZ{X: (bestX - origin.X) / bestDist, Y: (bestY - origin.Y) / bestDist}
+	if hit, ok := query.Raycast(origin, dir, bestDist, walls); ok && hit.Distance < bestDist-specialsUseSlack {
+		return nil
+	}
+	return best
+}
+
+// activate fires the special on behalf of the player, unless it needs a key the player does not own.
+func (ss *Specials) activate(s *Special, player *ThingPlayer) bool {
+	if !player.HasKey(s.key) {
+		return false
+	}
+	return ss.fire(s)
+}
+
+// fire applies the action of the special, returning false if it is a single-shot special already fired.
+func (ss *Specials) fire(s *Special) bool {
+	if s.fired && !s.repeat {
+		return false
+	}
+	s.fired = true
+	for _, material := range s.switches {
+		material.NextFrame()
+	}
+	switch s.action {
+	case config.TriggerActionActivate:
+		for _, id := range s.movers {
+			ss.movers.Activate(id)
+		}
+	case config.TriggerActionToggle:
+		for _, id := range s.movers {
+			ss.movers.Toggle(id)
+		}
+	case config.TriggerActionLight:
+		for _, sector := range s.sectors {
+			if light := sector.GetLight(); light != nil {
+				light.SetIntensity(s.light)
+			}
+		}
+	case config.TriggerActionMessage:
+		for _, id := range s.elevators {
+			ss.elevators.Send(id, s.message, s.param)
+		}
+	case config.TriggerActionExit, config.TriggerActionSecretExit:
+		ss.exit(s.id, s.action == config.TriggerActionSecretExit)
+	}
+	return true
+}
+
+// exit ends the level on behalf of the special or the trigger volume with the given id.
+func (ss *Specials) exit(id string, secret bool) {
+	ss.exited = true
+	ss.secret = secret
+	fmt.Printf("level exit reached by %s (secret: %t)\n", id, ss.secret)
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+func TestUseSpecialStopsAtWalls(t *testing.T) {
+	probe := newTestWorld(t, nil)
+	player := probe.GetPlayer()
+	pX, pY, pZ := player.GetEntity().GetCenter()
+	dir := geometry.XYZ{X: math.Cos(player.GetAngle()), Y: math.Sin(player.GetAngle())}
+	walls := NewQueryFilter(config.LayerProjectile, config.LayerWorld)
+	walls.Things = false
+	hit, ok := probe.GetQuery().Raycast(geometry.XYZ{X: pX, Y: pY, Z: pZ}, dir, 1e6, walls)
+	if !ok {
+		t.Fatal("the player faces no wall")
+	}
+	reach := player.GetEntity().GetWidth() * specialsUseReach
+	// Il player si avvicina al muro fino a un quarto della portata: la linea dietro il muro resta a portata
+	advance := hit.Distance - reach*0.25
+	origin := geometry.XYZ{X: pX + dir.X*advance, Y: pY + dir.Y*advance, Z: pZ}
+	along := geometry.XY{X: -hit.Normal.Y, Y: hit.Normal.X}
+	half := player.GetEntity().GetWidth() * 0.5
+	scale := probe.gScale
+	// La linea, parallela al muro e nelle unità della configurazione, attraversa il raggio alla distanza data
+	line := func(id string, distance float64) *config.Trigger {
+		cX, cY := origin.X+dir.X*distance, origin.Y+dir.Y*distance
+		start := geometry.XY{X: (cX - along.X*half) / scale.X, Y: (cY - along.Y*half) / scale.Y}
+		end := geometry.XY{X: (cX + along.X*half) / scale.X, Y: (cY + along.Y*half) / scale.Y}
+		return config.NewConfigTrigger(id, start, end, config.TriggerUse, config.TriggerActionActivate)
+	}
+
+	tests := []struct {
+		name     string
+		distance float64
+		want     bool
+	}{
+		{"on the wall", reach * 0.25, true},
+		{"behind the wall", reach * 0.75, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := newTestWorld(t, func(cfg *config.Root) {
+				cfg.Things = nil
+				cfg.Triggers = append(cfg.Triggers, line("switch", test.distance))
+			})
+			p := c.GetPlayer()
+			entity := p.GetEntity()
+			moveThing(c, p, geometry.XYZ{X: origin.X - entity.GetWidth()*0.5, Y: origin.Y - entity.GetHeight()*0.5, Z: positionOf(p).Z})
+			p.Use()
+			c.GetSpecials().Compute(p)
+			if got := c.GetSpecials().GetSpecial("switch").IsFired(); got != test.want {
+				t.Fatalf("fired %t, want %t", got, test.want)
+			}
+		})
+	}
+}
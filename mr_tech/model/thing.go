@@ -31,4 +31,6 @@ type IThing interface {
 	StartLoop()
 
 	PostMessage(ec *ThingEvent)
+
+	Destroy()
 }
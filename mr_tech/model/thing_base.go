@@ -1,11 +1,11 @@
 package model
 
 import (
-	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/markel1974/godoom/mr_tech/config"
 	"github.com/markel1974/godoom/mr_tech/geometry"
-	"github.com/markel1974/godoom/mr_tech/physics"
 )
 
 // ThingBase represents the fundamental attributes and behaviors of an object in the system.
@@ -21,20 +21,46 @@ type ThingBase struct {
 	location     *Volume
 	things       *Things
 	isActive     bool
+	isCorpse     bool
 	cage         *CollisionCage
+	health       *Health
+	healthCfg    *config.Health
+	deathAction  int
+	pickups      []*config.Pickup
+	layer        config.CollisionLayer
+	mask         config.CollisionLayer
+	owner        *ThingBase
+	sleeping     bool
+	sleepTimer   float64
+	island       []IThing
+	islandSlot   int
+	islandStep   uint64
+	resolveSlot  int
+	resolveStep  uint64
 
 	inbox       chan *ThingEvent
 	onCollision config.CollisionFunc
 	onImpact    config.ImpactFunc
+	onDeath     config.DeathFunc
+	onDestroy   config.DestroyFunc
 	done        chan struct{}
+	destroyed   bool
 }
 
-// NewThingBase creates a new ThingBase instance with specified configuration, material, sector, world, and things.
-func NewThingBase(thing IThing, things *Things, cfg *config.Thing, location *Volume) *ThingBase {
-	if cfg.OnCollision == nil {
+// NewThingBase creates a new ThingBase instance with specified configuration, behavior handlers, location and things.
+func NewThingBase(thing IThing, things *Things, cfg *config.Thing, location *Volume, handlers *config.BehaviorHandlers) *ThingBase {
+	t := &ThingBase{inbox: make(chan *ThingEvent, 16)}
+	t.setup(thing, things, cfg, location, handlers)
+	return t
+}
+
+// setup initializes the ThingBase from the configuration. A recycled ThingBase keeps its inbox and its collision cage
+// and gets a new done channel for its next loop.
+func (t *ThingBase) setup(thing IThing, things *Things, cfg *config.Thing, location *Volume, handlers *config.BehaviorHandlers) {
+	if handlers.OnCollision == nil {
 		panic("onCollision is nil for thing:" + cfg.Id)
 	}
-	if cfg.OnImpact == nil {
+	if handlers.OnImpact == nil {
 		panic("OnImpact is nil for thing:" + cfg.Id)
 	}
 	if cfg.Mass == 0 {
@@ -42,7 +68,8 @@ func NewThingBase(thing IThing, things *Things, cfg *config.Thing, location *Vol
 	}
 
 	//const cageMargin = 0.001
-	t := &ThingBase{
+	inbox, cage := t.inbox, t.cage
+	*t = ThingBase{
 		IVertices:    VerticesFactory(thing, cfg, things.GetMaterials()),
 		id:           cfg.Id,
 		angle:        cfg.Angle, // * (math.Pi / 180.0),
@@ -54,19 +81,52 @@ func NewThingBase(thing IThing, things *Things, cfg *config.Thing, location *Vol
 		things:       things,
 		maxStep:      0,
 		isActive:     true,
-		inbox:        make(chan *ThingEvent, 16),
+		inbox:        inbox,
 		done:         make(chan struct{}),
 		cage:         nil,
-		onImpact:     cfg.OnImpact,
-		onCollision:  cfg.OnCollision,
+		onImpact:     handlers.OnImpact,
+		onCollision:  handlers.OnCollision,
+		onDeath:      handlers.OnDeath,
+		onDestroy:    handlers.OnDestroy,
+		destroyed:    false,
+		health:       NewHealth(cfg.Health),
+		healthCfg:    cfg.Health,
+		deathAction:  -1,
+		pickups:      cfg.Pickups,
 	}
+	if cfg.Health != nil {
+		t.deathAction = resolveAction(cfg, cfg.Health.DeathAction)
+	}
+	t.layer, t.mask = cfg.GetCollision()
 
 	entity := t.GetEntity()
 	entity.SetOnGround(false)
 	//TODO FROM CONFIG
 	t.maxStep = entity.GetDepth() * 0.5 //cfg.Height * 0.5,
-	t.cage = NewCollisionCage(thing)    //, cageMargin),
-	return t
+	if cage == nil {
+		cage = NewCollisionCage(thing) //, cageMargin),
+	}
+	t.cage = cage
+}
+
+// Destroy ends the life of a thing removed from the simulation: the destroy hook of its behavior runs, its goroutine
+// stops and the references to the other things are released. Destroy runs once; the next calls do nothing.
+func (t *ThingBase) Destroy() {
+	if t.destroyed {
+		return
+	}
+	t.destroyed = true
+	if t.onDestroy != nil {
+		t.onDestroy(t)
+	}
+	close(t.done)
+	t.owner = nil
+	t.island = nil
+}
+
+// IsDestroyed reports whether the thing has been destroyed.
+func (t *ThingBase) IsDestroyed() bool {
+	return t.destroyed
 }
 
 // GetId returns the identifier string of the ThingBase instance.
@@ -124,9 +184,110 @@ func (t *ThingBase) SetActive(active bool) {
 	t.isActive = active
 }
 
+// IsCorpse reports whether the ThingBase is the corpse of a dead thing: rendered, but no longer simulated.
+func (t *ThingBase) IsCorpse() bool {
+	return t.isCorpse
+}
+
+// GetCollision returns the collision layer of the thing and the categories it collides with.
+func (t *ThingBase) GetCollision() (config.CollisionLayer, config.CollisionLayer) {
+	return t.layer, t.mask
+}
+
+// SetCollision sets the collision layer of the thing and the categories it collides with.
+func (t *ThingBase) SetCollision(layer, mask config.CollisionLayer) {
+	t.layer, t.mask = layer, mask
+}
+
+// GetOwner returns the thing that launched this one, or nil.
+func (t *ThingBase) GetOwner() *ThingBase {
+	return t.owner
+}
+
+// SetOwner sets the thing that launched this one: the two never collide.
+func (t *ThingBase) SetOwner(owner *ThingBase) {
+	t.owner = owner
+}
+
+// CollidesWith reports whether the thing collides with other, according to their layers and masks. A launched thing
+// never collides with its owner.
+func (t *ThingBase) CollidesWith(other *ThingBase) bool {
+	if t == other || (t.owner != nil && t.owner == other) || (other.owner != nil && other.owner == t) {
+		return false
+	}
+	return config.Collides(t.layer, t.mask, other.layer, other.mask)
+}
+
+// CollidesWithVolume reports whether the thing collides with the level volume, according to their layers and masks.
+func (t *ThingBase) CollidesWithVolume(volume *Volume) bool {
+	layer, mask := volume.GetCollision()
+	return config.Collides(t.layer, t.mask, layer, mask)
+}
+
+// IsPickup reports whether the thing gives something to the player when touched.
+func (t *ThingBase) IsPickup() bool {
+	return len(t.pickups) > 0
+}
+
+// GetPickups returns what the thing gives to the player when touched.
+func (t *ThingBase) GetPickups() []*config.Pickup {
+	return t.pickups
+}
+
+// GetHealth returns the current health, or 0 for an invulnerable thing.
+func (t *ThingBase) GetHealth() float64 {
+	if t.health == nil {
+		return 0
+	}
+	return t.health.GetHealth()
+}
+
+// GetArmor returns the current armor, or 0 for an invulnerable thing.
+func (t *ThingBase) GetArmor() float64 {
+	if t.health == nil {
+		return 0
+	}
+	return t.health.GetArmor()
+}
+
+// IsDead reports whether the thing has been killed.
+func (t *ThingBase) IsDead() bool {
+	return t.health != nil && t.health.IsDead()
+}
+
+// IsSleeping reports whether the thing has been put to sleep by the solver.
+func (t *ThingBase) IsSleeping() bool {
+	return t.sleeping
+}
+
+// Wake wakes the thing and the other things of its contact island. It must be called outside the things stages.
+func (t *ThingBase) Wake() {
+	if !t.sleeping {
+		return
+	}
+	for _, member := range t.island {
+		base := member.GetBase()
+		if !base.sleeping {
+			continue
+		}
+		base.sleeping = false
+		base.sleepTimer = 0
+		base.island = nil
+		t.things.sleepStats.Sleeping--
+		t.things.sleepStats.Woken++
+	}
+}
+
 // StagePrepare prepares the entity for staging by updating it and rebuilding the cage if the entity is moving.
+// A sleeping thing is skipped, unless a force or a velocity has been applied to it since it fell asleep.
 func (t *ThingBase) StagePrepare() bool {
 	entity := t.GetEntity()
+	if t.sleeping {
+		if !entity.HasForce() && !entity.IsMoving() {
+			return false
+		}
+		t.Wake()
+	}
 	entity.Update()
 	if !entity.IsMoving() {
 		return false
@@ -299,49 +460,48 @@ func (t *ThingBase) Jump(leapX float64, leapY float64, zFactor float64) bool {
 
 // LaunchObject spawns a bullet at the specified position, angle, and pitch using predefined physical parameters.
 func (t *ThingBase) LaunchObject(throwableIndex int, onCollision config.CollisionFunc, onImpact config.ImpactFunc, pos geometry.XYZ, angle, pitch, speed float64) {
-	t.things.CreateThrowable(throwableIndex, onCollision, onImpact, t.location, pos, angle, pitch, speed)
-}
-
-// FireHitscan performs a raycast to detect the first intersecting object within a specified direction and range.
-func (t *ThingBase) FireHitscan(id string, pos geometry.XYZ, force float64, dirX, dirY, dirZ float64) {
-	const maxDistance = 4096.0
-	var closestDist = maxDistance
-	var closestThing IThing
-
-	// Usiamo l'origine (pos) e il vettore direzione (dir) calcolato esternamente.
-	// QueryRay richiede invDir (1.0/dir) che viene calcolato internamente.
-	t.things.QueryRay(pos.X, pos.Y, pos.Z, dirX, dirY, dirZ, maxDistance, func(object physics.IAABB, distance float64) (float64, bool) {
-		// Self-hit culling: l'entità che spara non deve colpire se stessa
-		other, ok := object.(IThing)
-		if !ok {
-			return maxDistance, false
-		}
-		if t == other.GetBase() {
-			return maxDistance, false
-		}
-		//if object == sender {
-		//	return maxDistance, false
-		//}
-		closestThing = other
-		closestDist = distance
-		// Ray Shrinking: restringiamo il raggio d'azione dell'albero alla distanza dell'impatto trovato.
-		return distance, true
-	})
-
-	if closestThing != nil {
-		// 2. Calcolo del punto d'impatto reale (Origine + Direzione * Distanza)
-		impactX := pos.X + (dirX * closestDist)
-		impactY := pos.Y + (dirY * closestDist)
-		impactZ := pos.Z + (dirZ * closestDist)
+	t.things.CreateThrowable(t, throwableIndex, onCollision, onImpact, t.location, pos, angle, pitch, speed)
+}
 
-		fmt.Println("IMPACT: ", force, closestThing.GetId(), impactX, impactY, impactZ)
-		// 3. Risoluzione dell'impatto
-		force *= 100
+// FindPath returns the waypoints leading the thing to the target (targetX, targetY, targetZ) on the navigation mesh of
+// the level: the last waypoint is the target itself. It returns false when the target can't be reached with the step
+// and the size of the thing.
+func (t *ThingBase) FindPath(targetX, targetY, targetZ float64) ([]geometry.XYZ, bool) {
+	navMesh := t.things.navMesh
+	if navMesh == nil {
+		return nil, false
+	}
+	entity := t.GetEntity()
+	x, y, z := entity.GetBottomCenter()
+	// I corpi sono spesso alti quanto le stanze: basta lo spazio oltre il gradino. Si cade al più della propria altezza
+	agent := NavAgent{Radius: entity.GetWidth() * 0.5, Height: entity.GetDepth() - t.maxStep, MaxStep: t.maxStep, MaxDrop: entity.GetDepth()}
+	return navMesh.FindPath(geometry.XYZ{X: x, Y: y, Z: z}, geometry.XYZ{X: targetX, Y: targetY, Z: targetZ}, agent)
+}
 
-		closestThing.GetEntity().AddForce(dirX*force, dirY*force, dirZ*force)
-		closestThing.Impact(closestThing, id, force, closestDist, dirX, dirY, dirZ)
-		t.spawnBulletHole(impactX, impactY, impactZ, closestThing)
+// FireHitscan casts a ray from pos along the given direction, up to maxDistance, against the level geometry and the
+// things: the first thing hit is pushed by force and receives an impact of the given id and damage, while a wall stops
+// the shot. It returns the first hit, either a face or a thing.
+func (t *ThingBase) FireHitscan(id string, pos geometry.XYZ, damage, force, maxDistance float64, dirX, dirY, dirZ float64) (QueryHit, bool) {
+	// Lo sparo si sente attraverso i portali aperti
+	t.things.MakeNoise(t, pos)
+	query := t.things.query
+	if query == nil {
+		return QueryHit{}, false
+	}
+	// Il raggio si comporta come un proiettile del tiratore: attraversa cadaveri e pickup, si ferma sui muri.
+	// Il tiratore (la cosa della sua gabbia) e i suoi proiettili sono esclusi dal filtro
+	filter := NewQueryFilter(config.LayerProjectile, t.mask)
+	filter.Ignore = t.cage.object
+	hit, ok := query.Raycast(pos, geometry.XYZ{X: dirX, Y: dirY, Z: dirZ}, maxDistance, filter)
+	if !ok {
+		return hit, false
+	}
+	if hit.Thing != nil {
+		hit.Thing.GetEntity().AddForce(dirX*force, dirY*force, dirZ*force)
+		hit.Thing.Impact(t, id, damage, hit.Distance, dirX, dirY, dirZ)
 	}
+	t.spawnBulletHole(hit.Position.X, hit.Position.Y, hit.Position.Z, hit.Thing)
+	return hit, true
 }
 
 // Impact handles the interaction logic when this object collides with another object.
@@ -351,9 +511,61 @@ func (t *ThingBase) FireHitscan(id string, pos geometry.XYZ, force float64, dirX
 // closestDist represents the closest penetration between the objects upon collision.
 // dirX, dirY, and dirZ specify the directional vector of the impact in 3D space.
 func (t *ThingBase) Impact(other config.IThingConfig, id string, force, closestDist, dirX, dirY, dirZ float64) {
+	if t.health != nil {
+		kind := config.ParseDamageType(id)
+		if _, killed := t.health.Damage(kind, force); killed {
+			t.die(other, kind)
+		}
+	}
 	t.onImpact(t, other, id, force, closestDist, dirX, dirY, dirZ)
 }
 
+// die switches the thing to its death action, notifies the behavior, spawns the drops and deactivates the thing,
+// which is either removed or kept as a corpse.
+func (t *ThingBase) die(killer config.IThingConfig, kind config.DamageType) {
+	if t.deathAction >= 0 {
+		t.SetAction(t.deathAction)
+	}
+	if t.onDeath != nil {
+		t.onDeath(t, killer, kind)
+	}
+	x, y, z := t.GetEntity().GetCenter()
+	for _, drop := range t.healthCfg.Drops {
+		t.things.CreateDrop(drop, t.location, geometry.XYZ{X: x, Y: y, Z: z})
+	}
+	t.isCorpse = t.healthCfg.Corpse
+	if t.isCorpse {
+		t.SetCollision(config.LayerCorpse, config.LayerWorld)
+	}
+	t.SetActive(false)
+}
+
+// resolveAction returns the index of the named action of the thing: a numeric index, or an MD1 action name matched
+// exactly or, failing that, as a prefix (as "death" for "deatha"). It returns -1 if missing.
+func resolveAction(cfg *config.Thing, name string) int {
+	if name == "" {
+		return -1
+	}
+	if idx, err := strconv.Atoi(name); err == nil {
+		return idx
+	}
+	if cfg.MD1 == nil {
+		return -1
+	}
+	name = strings.ToLower(name)
+	prefix := -1
+	for idx, action := range cfg.MD1.ActionDefinitions {
+		action = strings.ToLower(strings.TrimSpace(action))
+		if action == name {
+			return idx
+		}
+		if prefix < 0 && strings.HasPrefix(action, name) {
+			prefix = idx
+		}
+	}
+	return prefix
+}
+
 // spawnBulletHole creates a temporary visual entity at the specified coordinates to simulate a bullet hole effect.
 // It offsets slightly from the surface to avoid Z-fighting and applies a visual decal for a limited duration.
 func (t *ThingBase) spawnBulletHole(x, y, z float64, target IThing) {
@@ -7,26 +7,26 @@ import (
 // ThingEnemy represents an enemy entity that extends ThingBase and defines behavior through a custom thinking function.
 type ThingEnemy struct {
 	*ThingBase
-	onThinking func(self config.IThingConfig, playerX, playerY, playerZ float64)
+	onThinking config.ThinkingFunc
 }
 
 // NewThingEnemy initializes and returns a new instance of ThingEnemy with the specified configuration and parameters.
 // It ensures that default values are set for speed and acceleration if not provided.
-// The function panics if the OnThinking callback in the config is nil.
-func NewThingEnemy(things *Things, cfg *config.Thing, volume *Volume) *ThingEnemy {
+// The function panics if the OnThinking handler of the resolved behavior is nil.
+func NewThingEnemy(things *Things, cfg *config.Thing, volume *Volume, handlers *config.BehaviorHandlers) *ThingEnemy {
 	if cfg.Speed <= 0 {
 		cfg.Speed = 6
 	}
 	if cfg.Acceleration <= 0 {
 		cfg.Acceleration = 3
 	}
-	if cfg.OnThinking == nil {
+	if handlers.OnThinking == nil {
 		panic("onThinking is nil for enemy:" + cfg.Id)
 	}
 	thing := &ThingEnemy{
-		onThinking: cfg.OnThinking,
+		onThinking: handlers.OnThinking,
 	}
-	thing.ThingBase = NewThingBase(thing, things, cfg, volume)
+	thing.ThingBase = NewThingBase(thing, things, cfg, volume, handlers)
 	return thing
 }
 
@@ -37,10 +37,11 @@ func (t *ThingEnemy) PostMessage(ec *ThingEvent) {
 
 // StartLoop initializes and starts a goroutine to handle ThingEvent messages and process them based on their compute stage.
 func (t *ThingEnemy) StartLoop() {
+	inbox, done := t.inbox, t.done
 	go func() {
 		for {
 			select {
-			case evt := <-t.inbox:
+			case evt := <-inbox:
 				switch evt.GetKind() {
 				case StageThinking:
 					t.StageThinking(evt.GetCoords())
@@ -52,14 +53,14 @@ func (t *ThingEnemy) StartLoop() {
 					t.StageApply(evt.GetSolverJitter())
 				}
 				evt.Done()
-			case <-t.done:
+			case <-done:
 				return
 			}
 		}
 	}()
 }
 
-// StageThinking processes the enemy's thinking phase using the player's coordinates (X, Y, Z) as input.
+// StageThinking processes the enemy's thinking phase with its perception of the player at (X, Y, Z).
 func (t *ThingEnemy) StageThinking(playerX float64, playerY float64, playerZ float64) {
-	t.onThinking(t, playerX, playerY, playerZ)
+	t.onThinking(t, t.things.perceive(t, playerX, playerY, playerZ))
 }
@@ -10,9 +10,9 @@ type ThingItem struct {
 }
 
 // NewThingItem creates a new ThingItem instance by initializing its base properties using the provided configuration.
-func NewThingItem(things *Things, cfg *config.Thing, volume *Volume) *ThingItem {
+func NewThingItem(things *Things, cfg *config.Thing, volume *Volume, handlers *config.BehaviorHandlers) *ThingItem {
 	thing := &ThingItem{}
-	thing.ThingBase = NewThingBase(thing, things, cfg, volume)
+	thing.ThingBase = NewThingBase(thing, things, cfg, volume, handlers)
 	return thing
 }
 
@@ -23,10 +23,11 @@ func (t *ThingItem) PostMessage(ec *ThingEvent) {
 
 // StartLoop begins a goroutine that processes incoming events or signals termination via the 'done' channel.
 func (t *ThingItem) StartLoop() {
+	inbox, done := t.inbox, t.done
 	go func() {
 		for {
 			select {
-			case evt := <-t.inbox:
+			case evt := <-inbox:
 				switch evt.GetKind() {
 				case StageThinking:
 					t.StageThinking(evt.GetCoords())
@@ -38,7 +39,7 @@ func (t *ThingItem) StartLoop() {
 					t.StageApply(evt.GetSolverJitter())
 				}
 				evt.Done()
-			case <-t.done:
+			case <-done:
 				return
 			}
 		}
@@ -19,9 +19,13 @@ type ThingPlayer struct {
 	pitchMax       float64
 	pitchSens      float64
 	ducking        bool
+	using          bool
+	shots          []*Face
 	lightIntensity float64
 	bobbing        *Bobbing
 	flash          *Flash
+	inventory      *Inventory
+	arsenal        *Arsenal
 	debug          bool
 	*ThingBase
 }
@@ -36,16 +40,18 @@ func NewThingPlayer(things *Things, c *config.Player, volumes *Volumes, debug bo
 		return nil
 	}
 	c.Kind = config.ThingPlayerDef
-	if c.Height <= 0 {
-		panic("player height must be positive")
-	}
-	if c.Speed <= 0 {
-		panic("player speed must be positive")
-	}
-	if c.Mass <= 0 {
-		panic("player mass must be positive")
+	if c.Height <= 0 || c.Speed <= 0 || c.Mass <= 0 {
+		fmt.Printf("player height (%f), speed (%f) and mass (%f) must be positive\n", c.Height, c.Speed, c.Mass)
+		return nil
 	}
 	c.Id = "PLAYER"
+	// Il seme del comportamento inizializza anche la dispersione delle armi
+	seed := things.nextSeed()
+	handlers, err := config.ResolveBehavior(c.Thing, seed)
+	if err != nil {
+		fmt.Println(err)
+		return nil
+	}
 
 	c.Position = geometry.XYZ{X: c.Position.X, Y: c.Position.Y, Z: c.Position.Z}
 	thing := &ThingPlayer{
@@ -56,11 +62,13 @@ func NewThingPlayer(things *Things, c *config.Player, volumes *Volumes, debug bo
 		lightIntensity: 0.0039,
 		debug:          debug,
 		flash:          NewFlash(c.Flash),
+		inventory:      NewInventory(c.Inventory),
 		pitchMin:       -5.0,
 		pitchMax:       5.0,
 		pitchSens:      0.05,
 	}
-	thing.ThingBase = NewThingBase(thing, things, c.Thing, location)
+	thing.arsenal = NewArsenal(c.Weapons, thing.inventory, seed)
+	thing.ThingBase = NewThingBase(thing, things, c.Thing, location, handlers)
 	entity := thing.GetEntity()
 	entity.SetOnGround(false)
 	entity.MoveTo(c.Position.X, c.Position.Y, c.Position.Z)
@@ -68,7 +76,8 @@ func NewThingPlayer(things *Things, c *config.Player, volumes *Volumes, debug bo
 	return thing
 }
 
-// IsActive determines whether the ThingPlayer instance is currently active, returning true if active, otherwise false.
+// IsActive reports that the ThingPlayer is always active: a killed player stays in the simulation as a corpse and is
+// never removed from the things.
 func (p *ThingPlayer) IsActive() bool {
 	return true
 }
@@ -80,10 +89,11 @@ func (p *ThingPlayer) PostMessage(ec *ThingEvent) {
 
 // StartLoop initializes and starts a concurrent processing loop for handling incoming events and player state updates.
 func (p *ThingPlayer) StartLoop() {
+	inbox, done := p.inbox, p.done
 	go func() {
 		for {
 			select {
-			case evt := <-p.inbox:
+			case evt := <-inbox:
 				switch evt.GetKind() {
 				case StageThinking:
 					p.StageThinking(evt.GetCoords())
@@ -95,7 +105,7 @@ func (p *ThingPlayer) StartLoop() {
 					p.StageApply(evt.GetSolverJitter())
 				}
 				evt.Done()
-			case <-p.done:
+			case <-done:
 				return
 			}
 		}
@@ -139,7 +149,7 @@ func (p *ThingPlayer) SetPitchOptions(min, max, sens float64) {
 
 // Move applies a directional impulse to the player based on input flags (up, down, left, right) and a given impulse magnitude.
 func (p *ThingPlayer) Move(impulse float64, up, down, left, right bool) {
-	if !up && !down && !left && !right {
+	if p.IsDead() || (!up && !down && !left && !right) {
 		return
 	}
 	var fx, fy float64
@@ -179,6 +189,25 @@ func (p *ThingPlayer) SetJump(multi bool) {
 	}
 }
 
+// Use requests the activation of the line specials in front of the player. The request is consumed by the next simulation step.
+func (p *ThingPlayer) Use() {
+	p.using = true
+}
+
+// ConsumeUse reports whether a use request is pending and clears it.
+func (p *ThingPlayer) ConsumeUse() bool {
+	using := p.using
+	p.using = false
+	return using
+}
+
+// ConsumeShots returns the faces struck first by the hitscan shots of the player since the last call and clears them.
+func (p *ThingPlayer) ConsumeShots() []*Face {
+	shots := p.shots
+	p.shots = nil
+	return shots
+}
+
 // SetDucking toggles the player's ducking state between true and false.
 func (p *ThingPlayer) SetDucking() {
 	entity := p.GetEntity()
@@ -192,6 +221,50 @@ func (p *ThingPlayer) SetDucking() {
 	}
 }
 
+// GetInventory returns the inventory of the player.
+func (p *ThingPlayer) GetInventory() *Inventory {
+	return p.inventory
+}
+
+// HasKey reports whether the player owns the key. The empty key is always owned.
+func (p *ThingPlayer) HasKey(key string) bool {
+	return p.inventory.HasKey(key)
+}
+
+// Pickup gives the player what the touched item provides, returning false if nothing was taken (as a health bonus
+// at the health limit), in which case the item must stay in place.
+func (p *ThingPlayer) Pickup(item IThing) bool {
+	if p.IsDead() {
+		return false
+	}
+	taken := false
+	for _, pickup := range item.GetBase().GetPickups() {
+		switch pickup.Kind {
+		case config.PickupHealth:
+			if p.health != nil && p.health.Heal(pickup.Amount, pickup.Limit) > 0 {
+				taken = true
+			}
+		case config.PickupArmor:
+			if p.health != nil && p.health.AddArmor(pickup.Amount, pickup.Limit) > 0 {
+				taken = true
+			}
+		case config.PickupAmmo:
+			if p.inventory.AddAmmo(pickup.Id, pickup.Amount, pickup.Limit) > 0 {
+				taken = true
+			}
+		case config.PickupWeapon:
+			if p.inventory.AddWeapon(pickup.Id) {
+				taken = true
+			}
+		case config.PickupKey:
+			if p.inventory.AddKey(pickup.Id) {
+				taken = true
+			}
+		}
+	}
+	return taken
+}
+
 // GetFlash retrieves the flash instance associated with the ThingPlayer.
 func (p *ThingPlayer) GetFlash() *Flash {
 	return p.flash
@@ -235,6 +308,17 @@ func (p *ThingPlayer) GetTilt() float64 {
 // GetVisualPosition calculates and returns the player's visual position as X, Y, and Z coordinates.
 func (p *ThingPlayer) GetVisualPosition() (float64, float64, float64) {
 	visualX, visualY, visualZ := p.GetEntity().GetBottomCenter() //p.pos.X, p.pos.Y, p.pos.Z
+	return p.visualPosition(visualX, visualY, visualZ)
+}
+
+// GetVisualPositionLerp returns the player's visual position blended between the last two physics states by alpha.
+func (p *ThingPlayer) GetVisualPositionLerp(alpha float64) (float64, float64, float64) {
+	visualX, visualY, visualZ := p.GetEntity().GetBottomCenterLerp(alpha)
+	return p.visualPosition(visualX, visualY, visualZ)
+}
+
+// visualPosition applies eye height, bobbing and jump offsets to the given bottom-center position.
+func (p *ThingPlayer) visualPosition(visualX, visualY, visualZ float64) (float64, float64, float64) {
 	angleSin, angleCos := p.GetAngleFull()
 	bobX, bobY, _ := p.GetBob()
 	visualZ += p.getEyeHeight() + bobY + p.bobbing.GetJump()
@@ -289,35 +373,33 @@ func (p *ThingPlayer) getEyeHeight() float64 {
 	return entity.GetDepth() * 0.80
 }
 
-// Throw creates and spawns a projectile at a position based on the player's orientation and camera position.
-func (p *ThingPlayer) Throw(throwableIndex int, speed float64) {
-	camX, camY, camZ := p.GetVisualPosition()
-	entity := p.GetEntity()
-	diameter := entity.GetWidth()
-	spawnX := camX + (p.angleCos * diameter)
-	spawnY := camY + (p.angleSin * diameter)
-	spawnZ := camZ - (p.getEyeHeight() * 0.5)
-	spawnPos := geometry.XYZ{X: spawnX, Y: spawnY, Z: spawnZ}
-	p.LaunchObject(throwableIndex, p.onCollision, p.onImpact, spawnPos, p.angle, -p.pitch, speed)
-}
-
-// Fire triggers a hitscan action from the player's position along a calculated direction vector.
-func (p *ThingPlayer) Fire(id string) {
-	// 1. Origine della vista (include Bobbing e Crouch)
-	camX, camY, camZ := p.GetVisualPosition()
-	// 2. Calcolo del vettore direzione (Radianti)
-	// Usiamo lo angle invertito per la telecamera e lo scaliamo per il FOV
-	pitchRad := -p.pitch
-	dirX := p.angleCos * math.Cos(pitchRad)
-	dirY := p.angleSin * math.Cos(pitchRad)
-	dirZ := math.Sin(pitchRad)
-	// 3. Punto di spawn fuori dalla hitbox del player
-	// Usiamo il raggio dinamico per evitare l'auto-collisione nel BVH
-	weaponForward := p.GetEntity().GetWidth() //p.GetRadius() * 2.0
-	weaponForce := 5000.0                     // TODO CONFIG
-	spawnX := camX + (p.angleCos * weaponForward)
-	spawnY := camY + (p.angleSin * weaponForward)
-	spawnZ := camZ - (p.getEyeHeight() * 0.5)
-	spawnPos := geometry.XYZ{X: spawnX, Y: spawnY, Z: spawnZ}
-	p.FireHitscan(id, spawnPos, weaponForce, dirX, dirY, dirZ)
+// Fire requests a shot of the selected weapon. The request is consumed by the next simulation step.
+func (p *ThingPlayer) Fire() {
+	p.arsenal.Trigger()
+}
+
+// Throw requests a shot of the first owned projectile weapon, regardless of the selected one. The request is
+// consumed by the next simulation step.
+func (p *ThingPlayer) Throw() {
+	p.arsenal.TriggerAlternate()
+}
+
+// SelectWeapon switches to the weapon with the given id, returning false if it is unknown or not owned.
+func (p *ThingPlayer) SelectWeapon(id string) bool {
+	return p.arsenal.Select(id, p.inventory)
+}
+
+// NextWeapon switches to the next owned weapon.
+func (p *ThingPlayer) NextWeapon() {
+	p.arsenal.Next(p.inventory)
+}
+
+// GetArsenal returns the weapon state machine of the player.
+func (p *ThingPlayer) GetArsenal() *Arsenal {
+	return p.arsenal
+}
+
+// ComputeWeapons advances the weapon state machine by dt seconds, firing the pending shots.
+func (p *ThingPlayer) ComputeWeapons(dt float64) {
+	p.arsenal.Compute(p, dt)
 }
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"sync"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+// defaultPoolSize is the default number of destroyed throwables kept for reuse.
+const defaultPoolSize = 256
+
+// ThingPool keeps the throwables removed from the simulation to reuse them for the next launches: projectiles and
+// thrown objects live a few steps, and recycling them spares the allocation of the throwable, of its collision cage,
+// of its sweep area and of its inbox; the vertices and the health are rebuilt from the configuration of each launch.
+// A pooled throwable is destroyed, so it holds no goroutine; the throwables released to a full pool are left to the
+// garbage collector. The pool is safe for concurrent use by the thinking stages of the things.
+type ThingPool struct {
+	mu      sync.Mutex
+	free    []*ThingThrowable
+	size    int
+	created uint64
+	reused  uint64
+}
+
+// NewThingPool creates a ThingPool keeping up to size throwables.
+func NewThingPool(size int) *ThingPool {
+	return &ThingPool{size: max(size, 0)}
+}
+
+// SetSize changes the number of throwables kept by the pool, dropping the ones in excess; zero disables the pool.
+func (p *ThingPool) SetSize(size int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.size = max(size, 0)
+	if len(p.free) > p.size {
+		clear(p.free[p.size:])
+		p.free = p.free[:p.size]
+	}
+}
+
+// Len returns the number of throwables waiting in the pool.
+func (p *ThingPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.free)
+}
+
+// GetStats returns the number of throwables created and the number of throwables reused by the pool.
+func (p *ThingPool) GetStats() (uint64, uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.created, p.reused
+}
+
+// acquire returns a throwable described by the configuration, recycled from the pool when one is available.
+func (p *ThingPool) acquire(things *Things, cfg *config.Thing, volume *Volume, handlers *config.BehaviorHandlers) *ThingThrowable {
+	p.mu.Lock()
+	var t *ThingThrowable
+	if n := len(p.free); n > 0 {
+		t = p.free[n-1]
+		p.free[n-1] = nil
+		p.free = p.free[:n-1]
+		p.reused++
+	} else {
+		p.created++
+	}
+	p.mu.Unlock()
+	if t == nil {
+		t = NewThingThrowable(things, cfg, volume, handlers)
+	} else {
+		t.recycle(things, cfg, volume, handlers)
+	}
+	t.pooled = true
+	return t
+}
+
+// release returns a destroyed throwable to the pool, reporting false when the pool is full.
+func (p *ThingPool) release(t *ThingThrowable) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.free) >= p.size {
+		return false
+	}
+	t.onWall = nil
+	p.free = append(p.free, t)
+	return true
+}
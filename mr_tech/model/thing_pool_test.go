@@ -0,0 +1,87 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+)
+
+// newTestThrowable returns the configuration of a throwable copy of the first enemy of the level, launched from the
+// player position along angle.
+func newTestThrowable(tb testing.TB, c *Compiler, id string, angle, speed float64) *config.Thing {
+	tb.Helper()
+	for _, ct := range c.GetThings().config {
+		if ct.Kind != config.ThingEnemyDef {
+			continue
+		}
+		dst := ct.Clone()
+		dst.Id = id
+		dst.Kind = config.ThingThrowableDef
+		x, y, z := c.GetPlayer().GetEntity().GetCenter()
+		dst.Position.X, dst.Position.Y, dst.Position.Z = x, y, z
+		dst.Angle = angle
+		dst.Speed = speed
+		return dst
+	}
+	tb.Fatal("the level has no enemy")
+	return nil
+}
+
+func TestThingPoolRecycleResetsState(t *testing.T) {
+	c := newTestWorld(t, nil)
+	things := c.GetThings()
+	volume := c.GetPlayer().GetLocation()
+	handlers := &config.BehaviorHandlers{
+		OnCollision: func(config.IThingConfig, config.IThingConfig) {},
+		OnImpact:    func(config.IThingConfig, config.IThingConfig, string, float64, float64, float64, float64, float64) {},
+	}
+	pool := NewThingPool(1)
+
+	first := pool.acquire(things, newTestThrowable(t, c, "first", 0, 100), volume, handlers)
+	first.SetOwner(c.GetPlayer().GetBase())
+	first.SetOnWall(func() {})
+	first.impacted = true
+	first.health.Damage(config.DamageBullet, 1e9)
+	if !first.IsDead() {
+		t.Fatal("the throwable survived a lethal damage")
+	}
+	cage, inbox, vertices := first.cage, first.inbox, first.IVertices
+	first.Destroy()
+	if !pool.release(first) {
+		t.Fatal("the pool refused a throwable while empty")
+	}
+	if pool.Len() != 1 {
+		t.Fatalf("the pool holds %d throwables, want 1", pool.Len())
+	}
+
+	second := pool.acquire(things, newTestThrowable(t, c, "second", math.Pi/2, 200), volume, handlers)
+	if second != first {
+		t.Fatal("the pooled throwable has not been reused")
+	}
+	if created, reused := pool.GetStats(); created != 1 || reused != 1 || pool.Len() != 0 {
+		t.Fatalf("the pool created %d and reused %d throwables, and holds %d", created, reused, pool.Len())
+	}
+	if second.GetId() != "second" || second.IsDestroyed() || second.IsDead() || second.impacted {
+		t.Fatalf("the recycled throwable kept its state: id %s, destroyed %t, dead %t, impacted %t", second.GetId(), second.IsDestroyed(), second.IsDead(), second.impacted)
+	}
+	if second.GetOwner() != nil || second.onWall != nil {
+		t.Fatal("the recycled throwable kept the owner or the wall hook of its previous launch")
+	}
+	select {
+	case <-second.done:
+		t.Fatal("the recycled throwable starts with a closed done channel")
+	default:
+	}
+	// L'involucro e la inbox sono riusati, i vertici e la salute ricostruiti
+	if second.cage != cage || second.inbox != inbox {
+		t.Fatal("the collision cage or the inbox of the throwable have been reallocated")
+	}
+	if second.IVertices == vertices {
+		t.Fatal("the vertices of the previous launch have been kept")
+	}
+	entity := second.GetEntity()
+	if vx, vy := entity.GetVx(), entity.GetVy(); math.Abs(vx) > 1e-9 || math.Abs(vy-200) > 1e-9 {
+		t.Fatalf("the recycled throwable moves at (%f, %f), want (0, 200)", vx, vy)
+	}
+}
@@ -4,33 +4,78 @@ import (
 	"math"
 
 	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/physics"
 )
 
+// throwableSpin is the angular speed, in radians per second, given at launch to a tumbling throwable.
+const throwableSpin = 6.0
+
+// sweepImpact is the first impact found by the sweep of a continuous throwable, reported to its behavior once the
+// throwable has been moved to the contact.
+type sweepImpact struct {
+	force       float64
+	closestDist float64
+	dirX        float64
+	dirY        float64
+	dirZ        float64
+}
+
 // ThingThrowable represents a throwable object in the system, extending the base functionality of ThingBase.
+// A pooled throwable is returned to the ThingPool when it leaves the simulation.
 type ThingThrowable struct {
+	onWall     func()
+	continuous bool
+	impacted   bool
+	impact     *sweepImpact
+	sweepArea  *physics.BoundingBox
+	pooled     bool
 	*ThingBase
 }
 
 // NewThingThrowable creates and initializes a new throwable object with specific parameters and assigns its properties.
-func NewThingThrowable(things *Things, cfg *config.Thing, volume *Volume) *ThingThrowable {
-	thing := &ThingThrowable{}
-	thing.ThingBase = NewThingBase(thing, things, cfg, volume)
+func NewThingThrowable(things *Things, cfg *config.Thing, volume *Volume, handlers *config.BehaviorHandlers) *ThingThrowable {
+	thing := &ThingThrowable{
+		continuous: cfg.Continuous,
+		impacted:   false,
+		sweepArea:  physics.NewBoundingBox(0, 0, 0, 0, 0, 0),
+	}
+	thing.ThingBase = NewThingBase(thing, things, cfg, volume, handlers)
+	thing.throw(cfg)
+	return thing
+}
+
+// recycle reinitializes a destroyed throwable from the configuration, reusing its collision cage, its sweep area and
+// its inbox; the base is reset, with new vertices and health.
+func (t *ThingThrowable) recycle(things *Things, cfg *config.Thing, volume *Volume, handlers *config.BehaviorHandlers) {
+	t.onWall = nil
+	t.continuous = cfg.Continuous
+	t.impacted = false
+	t.impact = nil
+	t.ThingBase.setup(t, things, cfg, volume, handlers)
+	t.throw(cfg)
+}
+
+// throw gives the throwable its launch velocity along its angle and pitch and, when it tumbles, its spin.
+func (t *ThingThrowable) throw(cfg *config.Thing) {
 	// Sovrascriviamo il maxStep della base: i proiettili non scavalcano i gradini
-	thing.maxStep = 0.0
+	t.maxStep = 0.0
 	// 1. Normalizzazione del Pitch (da [-5, 5] a radianti)
 	// 2. Vettore Direzionale 3D normalizzato
-	dirX := math.Cos(thing.angle) * math.Cos(cfg.Pitch)
-	dirY := math.Sin(thing.angle) * math.Cos(cfg.Pitch)
+	dirX := math.Cos(t.angle) * math.Cos(cfg.Pitch)
+	dirY := math.Sin(t.angle) * math.Cos(cfg.Pitch)
 	dirZ := math.Sin(cfg.Pitch)
 	// 3. Muzzle Velocity (Iniezione istantanea di velocità)
-	// Essendo il frame 0, impostiamo direttamente la velocità vettoriale.
-	// Seleziona un moltiplicatore appropriato per la velocità dei tuoi proiettili (es. 50.0)
-	muzzleVelocity := thing.speed * 5.0
-	entity := thing.GetEntity()
-	entity.SetVx(dirX * muzzleVelocity)
-	entity.SetVy(dirY * muzzleVelocity)
-	entity.SetVz(dirZ * muzzleVelocity)
-	return thing
+	// Essendo il frame 0, impostiamo direttamente la velocità vettoriale, in unità al secondo.
+	entity := t.GetEntity()
+	entity.SetVx(dirX * t.speed)
+	entity.SetVy(dirY * t.speed)
+	entity.SetVz(dirZ * t.speed)
+	if cfg.Tumble {
+		// Rotazione in avanti attorno all'asse orizzontale perpendicolare al lancio (Z x direzione)
+		entity.EnableRotation()
+		entity.SetOrientation(physics.NewQuaternionAxisAngle(0, 0, 1, t.angle))
+		entity.SetAngularVelocity(-math.Sin(t.angle)*throwableSpin, math.Cos(t.angle)*throwableSpin, 0)
+	}
 }
 
 // PostMessage sends a ThingEvent to the ThingThrowable's inbox channel for processing in the event loop.
@@ -40,10 +85,12 @@ func (t *ThingThrowable) PostMessage(ec *ThingEvent) {
 
 // StartLoop initializes a goroutine to process events from the inbox channel or terminate when signaled via the done channel.
 func (t *ThingThrowable) StartLoop() {
+	// Il loop legge i canali correnti: un thing riciclato ne avvia uno nuovo con un altro done
+	inbox, done := t.inbox, t.done
 	go func() {
 		for {
 			select {
-			case evt := <-t.inbox:
+			case evt := <-inbox:
 				switch evt.GetKind() {
 				case StageThinking:
 					t.StageThinking(evt.GetCoords())
@@ -55,13 +102,130 @@ func (t *ThingThrowable) StartLoop() {
 					t.StageApply(evt.GetSolverJitter())
 				}
 				evt.Done()
-			case <-t.done:
+			case <-done:
 				return
 			}
 		}
 	}()
 }
 
+// SetOnWall sets the callback invoked, from the apply stage, when the throwable touches the level geometry other
+// than the floor.
+func (t *ThingThrowable) SetOnWall(onWall func()) {
+	t.onWall = onWall
+}
+
+// IsContinuous reports whether the steps of the throwable are swept against the level geometry.
+func (t *ThingThrowable) IsContinuous() bool {
+	return t.continuous
+}
+
+// StagePrepare integrates the throwable and rebuilds its cage. The step of a continuous throwable is then swept
+// against the level geometry and stopped at the first contact.
+func (t *ThingThrowable) StagePrepare() bool {
+	if !t.ThingBase.StagePrepare() {
+		return false
+	}
+	if t.continuous {
+		t.sweep()
+	}
+	return true
+}
+
+// sweep finds the first face crossed by the step of the throwable, with the box sweep for the inside of the faces and
+// the ellipsoid sweep for their edges and vertices. On a hit the step is clamped to the time of impact, the impulse
+// of the face is applied and the first impact of the throwable is kept for ReportImpact.
+func (t *ThingThrowable) sweep() {
+	entity := t.GetEntity()
+	dx, dy, dz := t.cage.GetDisplacement()
+	length := math.Sqrt(dx*dx + dy*dy + dz*dz)
+	eRadX, eRadY, eRadZ := entity.GetSizeCenter()
+	// Sotto lo spessore del corpo il passo discreto della gabbia non perde contatti
+	if length < math.Min(eRadX, math.Min(eRadY, eRadZ)) {
+		return
+	}
+	aabb := entity.GetAABB()
+	minX, minY, minZ := aabb.GetMinX()+math.Min(0, dx), aabb.GetMinY()+math.Min(0, dy), aabb.GetMinZ()+math.Min(0, dz)
+	maxX, maxY, maxZ := aabb.GetMaxX()+math.Max(0, dx), aabb.GetMaxY()+math.Max(0, dy), aabb.GetMaxZ()+math.Max(0, dz)
+	t.sweepArea.Rebuild(minX, minY, minZ, maxX-minX, maxY-minY, maxZ-minZ)
+	cX, cY, cZ := entity.GetCenter()
+
+	toi := 1.0
+	var hitFace *Face
+	var nX, nY, nZ float64
+	t.things.volumes.QueryAABB(t.sweepArea, func(vol *Volume) {
+		if !t.CollidesWithVolume(vol) {
+			return
+		}
+		vol.QueryOverlaps(t.sweepArea, func(object physics.IAABB) bool {
+			face := object.(*Face)
+			if _, texKind := face.GetMaterialDetails(); texKind == int(config.MaterialKindSky) {
+				return false
+			}
+			// I contatti già in essere (toi nullo) restano alla gabbia
+			if ft, fx, fy, fz, ok := face.SweepAABB(aabb, dx, dy, dz); ok && ft > 0 && ft < toi {
+				toi, hitFace, nX, nY, nZ = ft, face, fx, fy, fz
+			}
+			if ft, fx, fy, fz, ok := face.SweepTest(cX, cY, cZ, dx, dy, dz, eRadX, eRadY, eRadZ); ok && ft > 0 && ft < toi {
+				toi, hitFace, nX, nY, nZ = ft, face, fx, fy, fz
+			}
+			return false
+		})
+	})
+	if hitFace == nil {
+		return
+	}
+	t.cage.Clamp(toi)
+	if volume := hitFace.GetParent(); volume != nil {
+		entity.ResolveImpact(volume.GetEntity(), nX, nY, nZ, 0.0)
+	}
+	if t.onWall != nil {
+		t.onWall()
+	}
+	if t.impacted {
+		return
+	}
+	t.impacted = true
+	t.impact = &sweepImpact{
+		force:       length / entity.GetDt() * entity.GetMass(),
+		closestDist: length * toi,
+		dirX:        dx / length,
+		dirY:        dy / length,
+		dirZ:        dz / length,
+	}
+}
+
+// ReportImpact notifies the behavior of the impact found by the sweep of the step, if any. It is called after the
+// apply stage, outside the things stages, once the throwable has been moved to the contact.
+func (t *ThingThrowable) ReportImpact() {
+	impact := t.impact
+	if impact == nil {
+		return
+	}
+	t.impact = nil
+	t.onImpact(t, nil, config.DamageProjectile.String(), impact.force, impact.closestDist, impact.dirX, impact.dirY, impact.dirZ)
+}
+
+// StageApply resolves the collisions of the throwable, follows the yaw of a tumbling one and notifies the contacts
+// with walls and ceilings.
+func (t *ThingThrowable) StageApply(solverJitter float64) {
+	t.ThingBase.StageApply(solverJitter)
+	if entity := t.GetEntity(); entity.IsRotational() {
+		// Il renderer orienta i modelli solo in imbardata
+		t.SetAngle(entity.GetOrientation().GetYaw())
+	}
+	if t.onWall == nil {
+		return
+	}
+	for i := 0; i < t.cage.GetSlotsLen(); i++ {
+		slot := t.cage.GetSlot(i)
+		if slot.GetBucket() != BucketFloor && slot.GetRemoteFace().GetParent().GetThing() == nil {
+			t.onWall()
+			return
+		}
+	}
+}
+
 // StageThinking calculates or updates the state of the `ThingThrowable` instance based on the player's coordinates.
 func (t *ThingThrowable) StageThinking(playerX float64, playerY float64, playerZ float64) {
 	// Logica eventuale di homing-missile o timeout qui
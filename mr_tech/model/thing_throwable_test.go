@@ -0,0 +1,113 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// newThinWallWorld compiles the test level with a wall one unit thick across the free space in front of the player,
+// along +X, halfway to the level wall. It returns the world, the X of the near side of the thin wall and the free
+// length in front of the player.
+func newThinWallWorld(tb testing.TB) (*Compiler, float64, float64) {
+	tb.Helper()
+	probe := newTestWorld(tb, nil)
+	pX, pY, pZ := probe.GetPlayer().GetEntity().GetCenter()
+	filter := NewQueryFilter(config.LayerProjectile, config.LayerWorld)
+	filter.Things = false
+	hit, ok := probe.GetQuery().Raycast(geometry.XYZ{X: pX, Y: pY, Z: pZ}, geometry.XYZ{X: 1}, 1e6, filter)
+	if !ok {
+		tb.Fatal("the ray in front of the player hits no wall")
+	}
+	free := hit.Distance
+	wallX := pX + free*0.5
+	scale := probe.gScale
+
+	c := newTestWorld(tb, func(cfg *config.Root) {
+		// Il box è espresso nelle unità della configurazione, scalate dal compilatore
+		minX, maxX := wallX/scale.X, (wallX+1)/scale.X
+		minY, maxY := (pY-100)/scale.Y, (pY+100)/scale.Y
+		minZ, maxZ := (pZ-100)/scale.Z, (pZ+100)/scale.Z
+		quad := func(a, b, c, d geometry.XYZ) *config.Face {
+			return config.NewConfigFace([]geometry.XYZ{a, b, c, d}, cfg.Sectors[0].Floor, "")
+		}
+		v := func(x, y, z float64) geometry.XYZ { return geometry.XYZ{X: x, Y: y, Z: z} }
+		wall := config.NewConfigVolume("thin_wall", "")
+		wall.AddFace(quad(v(minX, minY, minZ), v(minX, maxY, minZ), v(minX, maxY, maxZ), v(minX, minY, maxZ)))
+		wall.AddFace(quad(v(maxX, minY, minZ), v(maxX, minY, maxZ), v(maxX, maxY, maxZ), v(maxX, maxY, minZ)))
+		wall.AddFace(quad(v(minX, minY, minZ), v(minX, minY, maxZ), v(maxX, minY, maxZ), v(maxX, minY, minZ)))
+		wall.AddFace(quad(v(minX, maxY, minZ), v(maxX, maxY, minZ), v(maxX, maxY, maxZ), v(minX, maxY, maxZ)))
+		wall.AddFace(quad(v(minX, minY, minZ), v(maxX, minY, minZ), v(maxX, maxY, minZ), v(minX, maxY, minZ)))
+		wall.AddFace(quad(v(minX, minY, maxZ), v(minX, maxY, maxZ), v(maxX, maxY, maxZ), v(maxX, minY, maxZ)))
+		cfg.Volumes = append(cfg.Volumes, wall)
+	})
+	return c, wallX, free
+}
+
+func TestThrowableThinWall(t *testing.T) {
+	c, wallX, free := newThinWallWorld(t)
+	things := c.GetThings()
+	player := c.GetPlayer()
+	const radius = 2.0
+	if free*0.25 < radius*2 {
+		t.Fatalf("%f units in front of the player, too few for the test", free)
+	}
+	src := newTestThrowable(t, c, "bullet", 0, 0)
+	src.Sprite, src.MultiSprite, src.MD1 = nil, nil, nil
+	src.Radius, src.Height = radius, radius*2
+	src.Continuous = true
+	impacts := 0
+	handlers := &config.BehaviorHandlers{
+		OnCollision: func(config.IThingConfig, config.IThingConfig) {},
+		OnImpact: func(config.IThingConfig, config.IThingConfig, string, float64, float64, float64, float64, float64) {
+			impacts++
+		},
+	}
+	// Un passo porta il proiettile a tre quarti dello spazio libero, oltre il muro che sta a metà
+	speed := free * 0.75 / things.GetTimeStep()
+	cX, cY, cZ := player.GetEntity().GetCenter()
+	pos := geometry.XYZ{X: cX - radius, Y: cY - radius, Z: cZ - radius}
+	thing := things.projectiles.launch(src, handlers, player.GetBase(), player.GetLocation(), pos, 0, 0, speed)
+	if thing == nil {
+		t.Fatal("the throwable has not been launched")
+	}
+	for step := 0; step < 4; step++ {
+		stepWorld(c, 1)
+		if maxX := thing.GetEntity().GetAABB().GetMaxX(); maxX > wallX+0.01 {
+			t.Fatalf("step %d: the throwable reached X %f, beyond the near side %f of the wall", step, maxX, wallX)
+		}
+	}
+	if impacts != 1 {
+		t.Fatalf("%d impacts reported, want the one against the wall", impacts)
+	}
+}
+
+func TestProjectileToiAxisAligned(t *testing.T) {
+	c := newTestWorld(t, nil)
+	things := c.GetThings()
+	var other IThing
+	for _, thing := range things.ordered {
+		if thing.GetBase().GetKind() == config.ThingEnemyDef {
+			other = thing
+			break
+		}
+	}
+	if other == nil {
+		t.Fatal("the level has no enemy")
+	}
+	entity := c.GetPlayer().GetEntity()
+	target := other.GetEntity().GetAABB()
+	eRadX, eRadY, eRadZ := entity.GetSizeCenter()
+	// Il centro scorre lungo X sul bordo dello scafo in Y, senza spostarsi in Y e in Z
+	const dX = 20.0
+	startX := target.GetMinX() - eRadX - dX*0.5
+	entity.MoveTo(startX+dX-eRadX, target.GetMinY()-eRadY*2, (target.GetMinZ()+target.GetMaxZ())*0.5-eRadZ)
+	if _, cY, _ := entity.GetCenter(); cY != target.GetMinY()-eRadY {
+		t.Fatalf("the center is at Y %f, not on the border %f of the hull", cY, target.GetMinY()-eRadY)
+	}
+	toi, hit := things.projectiles.toi(entity, dX, 0, 0, other)
+	if !hit || toi < 0.49 || toi > 0.51 {
+		t.Fatalf("time of impact %f, hit %t, want the box touched halfway", toi, hit)
+	}
+}
@@ -1,7 +1,13 @@
 package model
 
 import (
+	"encoding/binary"
 	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
 	"sync/atomic"
 
 	"github.com/markel1974/godoom/mr_tech/config"
@@ -13,16 +19,41 @@ import (
 
 const solverJitter = 1e-6
 
+// pickupMargin is the distance, expressed in player widths, within which the player touches a pickup item.
+const pickupMargin = 0.1
+
 // Things manages game objects, their spatial partitioning, and contact interactions within a simulation environment.
+// The things are always visited in ascending id order; in deterministic mode the stages also run on the calling
+// goroutine, so that the same input produces bit-identical states at every step.
 type Things struct {
 	gScale           geometry.XYZ
 	config           []*config.Thing
 	volumes          *Volumes
+	navMesh          *NavMesh
+	perception       *Perception
+	query            *Query
+	triggerVolumes   *TriggerVolumes
 	materials        *Materials
 	tree             *physics.AABBTree
 	pending          []IThing
 	pendingIdx       atomic.Int32
 	entities         map[uint64]IThing
+	ordered          []IThing
+	deterministic    bool
+	seedsMu          sync.Mutex
+	seeds            *rand.Rand
+	sleepStats       SleepStats
+	sleepStep        uint64
+	islandParent     []int
+	islandAwake      []bool
+	parallelResolve  bool
+	resolveStep      uint64
+	resolveParent    []int
+	resolveRoots     []int
+	resolveIslands   [][]IThing
+	joints           []*Joint
+	resolveJoints    []*Joint
+	jointIslands     [][]*Joint
 	active           []IThing
 	activeIdx        int
 	inactive         []IThing
@@ -32,18 +63,28 @@ type Things struct {
 	hasPending       bool
 	event            *ThingEvent
 	solverIterations int
+	dt               float64
+	player           *ThingPlayer
+	pickupArea       *physics.BoundingBox
+	projectiles      *Projectiles
+	spawnCap         *SpawnCap
+	pool             *ThingPool
 }
 
-// NewThings initializes and returns an instance of Things with the specified maximum number of things.
-func NewThings(gScale geometry.XYZ, solverIterations int, cfg []*config.Thing, volumes *Volumes, materials *Materials) *Things {
+// NewThings initializes and returns an instance of Things with the specified maximum number of things. The seeds of
+// the behaviors are drawn, in creation order, from a generator initialized with seed.
+func NewThings(gScale geometry.XYZ, solverIterations int, seed int64, cfg []*config.Thing, volumes *Volumes, materials *Materials) *Things {
 	const defaultLen = 1024
 	e := &Things{
 		gScale:           gScale,
 		solverIterations: solverIterations,
 		tree:             physics.NewAABBTree(uint(len(cfg)*2), 4.0),
 		entities:         make(map[uint64]IThing),
+		seeds:            rand.New(rand.NewSource(seed)),
+		parallelResolve:  true,
 		active:           make([]IThing, defaultLen),
 		container:        make([]IThing, defaultLen),
+		inactive:         make([]IThing, defaultLen),
 		pending:          make([]IThing, defaultLen),
 		activeIdx:        0,
 		containerIdx:     0,
@@ -52,7 +93,12 @@ func NewThings(gScale geometry.XYZ, solverIterations int, cfg []*config.Thing, v
 		volumes:          volumes,
 		materials:        materials,
 		event:            NewThingEvent(0, solverJitter),
+		dt:               physics.DefaultDt,
+		pickupArea:       physics.NewBoundingBox(0, 0, 0, 0, 0, 0),
+		spawnCap:         NewSpawnCap(defaultMaxSpawned),
+		pool:             NewThingPool(defaultPoolSize),
 	}
+	e.projectiles = NewProjectiles(e)
 	e.pendingIdx.Store(0)
 
 	const enableThingsCreation = true
@@ -65,7 +111,12 @@ func NewThings(gScale geometry.XYZ, solverIterations int, cfg []*config.Thing, v
 				fmt.Printf("Warning can't find thing location at %f, %f, %f\n", ct.Position.X, ct.Position.Y, ct.Position.Z)
 				continue
 			}
-			t2 := e.createThing(ct, volume)
+			handlers, err := config.ResolveBehavior(ct, e.nextSeed())
+			if err != nil {
+				fmt.Println("Warning", err)
+				continue
+			}
+			t2 := e.createThing(ct, volume, handlers)
 			e.addThing(t2)
 		}
 	}
@@ -77,6 +128,72 @@ func (th *Things) GetMaterials() *Materials {
 	return th.materials
 }
 
+// SetTimeStep updates the fixed simulation time step applied to every managed entity.
+func (th *Things) SetTimeStep(dt float64) {
+	if dt <= 0 || dt == th.dt {
+		return
+	}
+	th.dt = dt
+	for _, t := range th.entities {
+		t.GetEntity().SetDt(dt)
+	}
+	for x := 0; x < int(th.pendingIdx.Load()); x++ {
+		th.pending[x].GetEntity().SetDt(dt)
+	}
+}
+
+// SetDeterministic enables or disables the deterministic mode: the thinking and apply stages run one thing at a
+// time, in id order, on the goroutine calling Compute instead of on the goroutines of the things.
+func (th *Things) SetDeterministic(deterministic bool) {
+	th.deterministic = deterministic
+}
+
+// IsDeterministic reports whether the deterministic mode is enabled.
+func (th *Things) IsDeterministic() bool {
+	return th.deterministic
+}
+
+// StateHash returns a hash of the physical state of the things (position, velocity, health and activity) taken in
+// id order. Two deterministic runs fed with the same input have the same hash at every step.
+func (th *Things) StateHash() uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	write := func(v float64) {
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+		_, _ = h.Write(buf[:])
+	}
+	for _, t := range th.ordered {
+		entity := t.GetEntity()
+		x, y, z := entity.GetCenter()
+		vX, vY, vZ := entity.GetVelocity()
+		write(x)
+		write(y)
+		write(z)
+		write(vX)
+		write(vY)
+		write(vZ)
+		write(t.GetHealth())
+		if t.IsActive() {
+			write(1)
+		} else {
+			write(0)
+		}
+	}
+	return h.Sum64()
+}
+
+// nextSeed returns the seed of the behavior of the next created thing.
+func (th *Things) nextSeed() int64 {
+	th.seedsMu.Lock()
+	defer th.seedsMu.Unlock()
+	return th.seeds.Int63()
+}
+
+// GetTimeStep returns the fixed simulation time step currently applied to the managed entities.
+func (th *Things) GetTimeStep() float64 {
+	return th.dt
+}
+
 // Len returns the number of elements in the container.
 func (th *Things) Len() int {
 	return len(th.container)
@@ -87,7 +204,7 @@ func (th *Things) QueryCollisionCage(lCage *CollisionCage) {
 
 	th.tree.QueryOverlaps(lCage, func(object physics.IAABB) bool {
 		rThing := object.(IThing)
-		if lThing == rThing {
+		if !lThing.GetBase().CollidesWith(rThing.GetBase()) {
 			return false
 		}
 		rCage := rThing.GetCage()
@@ -96,6 +213,12 @@ func (th *Things) QueryCollisionCage(lCage *CollisionCage) {
 		}
 		lCage.Seen(rCage)
 		rCage.Seen(lCage)
+		if lThing.GetEntity().IsOriented() || rThing.GetEntity().IsOriented() {
+			// Gli involucri allineati dei box ruotati si toccano anche quando i box no
+			if !lCage.OverlapsOriented(rCage, rThing.GetBase().resolveStep == th.resolveStep) {
+				return false
+			}
+		}
 
 		lEntityL, deltaX, deltaY, deltaZ := lCage.TranslateCage(0, rCage)
 
@@ -127,8 +250,78 @@ func (th *Things) QueryFrustum(front *physics.Frustum, callback func(object phys
 	th.tree.QueryFrustum(front, callback)
 }
 
+// QueryAABB invokes the callback for each thing whose (fattened) bounding box overlaps the specified AABB.
+// The query stops as soon as the callback returns true.
+func (th *Things) QueryAABB(aabb physics.IAABB, callback func(thing IThing) bool) {
+	th.tree.QueryOverlaps(aabb, func(object physics.IAABB) bool {
+		if thing, ok := object.(IThing); ok {
+			return callback(thing)
+		}
+		return false
+	})
+}
+
+// Update refreshes the position of the thing inside the spatial tree after it has been moved outside the solver.
+func (th *Things) Update(thing IThing) {
+	th.tree.UpdateObject(thing)
+}
+
+// SetNavMesh assigns the navigation mesh searched by the FindPath of the things.
+func (th *Things) SetNavMesh(navMesh *NavMesh) {
+	th.navMesh = navMesh
+}
+
+// GetNavMesh returns the navigation mesh of the things, or nil if the level has none.
+func (th *Things) GetNavMesh() *NavMesh {
+	return th.navMesh
+}
+
+// SetPerception assigns the perception used by the thinking stages of the things.
+func (th *Things) SetPerception(perception *Perception) {
+	th.perception = perception
+}
+
+// GetPerception returns the perception of the things, or nil if the level has none.
+func (th *Things) GetPerception() *Perception {
+	return th.perception
+}
+
+// SetQuery assigns the world query used by the hitscan shots of the things.
+func (th *Things) SetQuery(query *Query) {
+	th.query = query
+}
+
+// GetQuery returns the world query of the things, or nil if the level has none.
+func (th *Things) GetQuery() *Query {
+	return th.query
+}
+
+// SetTriggerVolumes assigns the trigger volumes told about the things removed from the world.
+func (th *Things) SetTriggerVolumes(triggerVolumes *TriggerVolumes) {
+	th.triggerVolumes = triggerVolumes
+}
+
+// MakeNoise makes a noise of source at pos, heard by the things in the regions reached through the open portals.
+func (th *Things) MakeNoise(source *ThingBase, pos geometry.XYZ) {
+	if th.perception != nil {
+		th.perception.Emit(source, pos)
+	}
+}
+
+// perceive returns what the observer perceives of the player, placed at (pX, pY, pZ). Without a perception the
+// player is always seen.
+func (th *Things) perceive(observer IThing, pX, pY, pZ float64) config.Perception {
+	if th.perception == nil {
+		oX, oY, oZ := observer.GetEntity().GetCenter()
+		dx, dy, dz := pX-oX, pY-oY, pZ-oZ
+		return config.Perception{Target: geometry.XYZ{X: pX, Y: pY, Z: pZ}, Distance: math.Sqrt(dx*dx + dy*dy + dz*dz), Visible: true, InView: true}
+	}
+	return th.perception.Perceive(observer, th.player, pX, pY, pZ)
+}
+
 // SetPlayer assigns a ThingPlayer to the Things collection and integrates it into the entity management system.
 func (th *Things) SetPlayer(p *ThingPlayer) {
+	th.player = p
 	th.addThing(p)
 }
 
@@ -152,13 +345,21 @@ func (th *Things) GetActive() ([]IThing, int) {
 // - oX, oY, oZ: Origin coordinates of the ray.
 // - dirX, dirY, dirZ: Direction vector of the ray.
 // - maxDistance: Maximum distance the ray can travel.
+// - layer, mask: Collision layer and mask of the ray; the things that do not collide with them are skipped.
 // - callback: Function invoked for each intersected object, receives the object and its distance as arguments.
-func (th *Things) QueryRay(oX, oY, oZ, dirX, dirY, dirZ float64, maxDistance float64, callback func(object physics.IAABB, distance float64) (float64, bool)) {
-	th.tree.QueryRay(oX, oY, oZ, dirX, dirY, dirZ, maxDistance, callback)
+func (th *Things) QueryRay(oX, oY, oZ, dirX, dirY, dirZ float64, maxDistance float64, layer, mask config.CollisionLayer, callback func(object physics.IAABB, distance float64) (float64, bool)) {
+	th.tree.QueryRay(oX, oY, oZ, dirX, dirY, dirZ, maxDistance, func(object physics.IAABB, distance float64) (float64, bool) {
+		if thing, ok := object.(IThing); ok {
+			if tLayer, tMask := thing.GetBase().GetCollision(); !config.Collides(layer, mask, tLayer, tMask) {
+				return maxDistance, false
+			}
+		}
+		return callback(object, distance)
+	})
 }
 
-// CreateThing creates a new IThing instance based on the provided Thing and adds it to the Things collection.
-func (th *Things) createThing(ct *config.Thing, volume *Volume) IThing {
+// createThing creates a new IThing instance based on the provided Thing, bound to the resolved behavior handlers.
+func (th *Things) createThing(ct *config.Thing, volume *Volume, handlers *config.BehaviorHandlers) IThing {
 	const disableEnemies = false
 	if disableEnemies {
 		if ct.Kind == config.ThingEnemyDef {
@@ -168,56 +369,197 @@ func (th *Things) createThing(ct *config.Thing, volume *Volume) IThing {
 	var thing IThing
 	switch ct.Kind {
 	case config.ThingEnemyDef:
-		thing = NewThingEnemy(th, ct, volume)
+		thing = NewThingEnemy(th, ct, volume, handlers)
 	case config.ThingWeaponDef:
-		thing = NewThingItem(th, ct, volume)
+		thing = NewThingItem(th, ct, volume, handlers)
 	case config.ThingThrowableDef:
-		thing = NewThingThrowable(th, ct, volume)
+		thing = NewThingThrowable(th, ct, volume, handlers)
 	case config.ThingKeyDef:
-		thing = NewThingItem(th, ct, volume)
+		thing = NewThingItem(th, ct, volume, handlers)
 	case config.ThingItemDef:
-		thing = NewThingItem(th, ct, volume)
+		thing = NewThingItem(th, ct, volume, handlers)
 	default:
-		thing = NewThingItem(th, ct, volume)
+		thing = NewThingItem(th, ct, volume, handlers)
 	}
+	th.place(thing, ct)
+	return thing
+}
 
+// place moves the new thing to the position of its configuration.
+func (th *Things) place(thing IThing, ct *config.Thing) {
 	entity := thing.GetEntity()
 	entity.SetOnGround(false)
 	entity.MoveTo(ct.Position.X, ct.Position.Y, ct.Position.Z)
-	return thing
+	if ct.Tumble && !entity.IsRotational() {
+		// Le cose rotanti (porte a cerniera, lampade) ruotano sul proprio box
+		entity.EnableRotation()
+	}
+}
+
+// GetConfig returns the configuration of the index-th thing of the level, or nil if the index is out of range.
+func (th *Things) GetConfig(index int) *config.Thing {
+	if index < 0 || index >= len(th.config) {
+		return nil
+	}
+	return th.config[index]
+}
+
+// GetConfigById returns the configuration of the level thing with the given id, or nil if the level has none.
+func (th *Things) GetConfigById(id string) *config.Thing {
+	if id == "" {
+		return nil
+	}
+	for _, ct := range th.config {
+		if ct.Id == id {
+			return ct
+		}
+	}
+	return nil
 }
 
 // CreateThrowable creates a throwable object with specified position, angle, pitch, mass, radius, and speed, adding it to the pending list.
-func (th *Things) CreateThrowable(throwableIndex int, onCollision config.CollisionFunc, onImpact config.ImpactFunc, volume *Volume, pos geometry.XYZ, angle, pitch, speed float64) {
-	if len(th.config) <= throwableIndex {
+// The throwable never collides with its owner.
+func (th *Things) CreateThrowable(owner *ThingBase, throwableIndex int, onCollision config.CollisionFunc, onImpact config.ImpactFunc, volume *Volume, pos geometry.XYZ, angle, pitch, speed float64) {
+	src := th.GetConfig(throwableIndex)
+	if src == nil {
+		return
+	}
+	// Il proiettile eredita gli handler del lanciatore invece di risolvere un behavior proprio
+	handlers := &config.BehaviorHandlers{OnCollision: onCollision, OnImpact: onImpact}
+	th.projectiles.launch(src, handlers, owner, volume, pos, angle, pitch, speed)
+}
+
+// CreateProjectile launches a copy of the src template that delivers the damage of the projectile to the first thing
+// it touches, other than its owner, and then disappears. The projectile also disappears when it hits a wall.
+func (th *Things) CreateProjectile(src *config.Thing, projectile *Projectile, volume *Volume, pos geometry.XYZ, angle, pitch, speed float64) {
+	handlers := &config.BehaviorHandlers{
+		OnCollision: func(config.IThingConfig, config.IThingConfig) {},
+		OnImpact:    func(config.IThingConfig, config.IThingConfig, string, float64, float64, float64, float64, float64) {},
+	}
+	var owner *ThingBase
+	if projectile.Owner != nil {
+		owner = projectile.Owner.GetBase()
+	}
+	if thing := th.projectiles.launch(src, handlers, owner, volume, pos, angle, pitch, speed); thing != nil {
+		th.projectiles.add(thing, projectile)
+	}
+}
+
+// CreateDrop spawns a copy of the drop template at the given position, adding it to the pending list. The drop keeps
+// its own behavior.
+func (th *Things) CreateDrop(src *config.Thing, volume *Volume, pos geometry.XYZ) {
+	dst := src.Clone()
+	dst.Id = utils.NextUUId()
+	dst.Position = pos
+	handlers, err := config.ResolveBehavior(dst, th.nextSeed())
+	if err != nil {
+		fmt.Println("Warning", err)
 		return
 	}
-	src := th.config[throwableIndex]
+	th.enqueue(dst, volume, handlers)
+}
+
+// Spawn adds to the pending list a copy of the src template placed at pos, facing angle and bound to its own
+// behavior, and returns it. It returns nil when the spawned things alive reach the cap, when the pending list is full
+// or when pos is outside the level. Spawn runs outside the stages of the things, as the spawners do.
+func (th *Things) Spawn(src *config.Thing, pos geometry.XYZ, angle float64) IThing {
+	if th.spawnCap.full() {
+		return nil
+	}
+	volume, _ := th.volumes.QueryPoint(pos.X, pos.Y, pos.Z)
+	if volume == nil {
+		fmt.Printf("Warning can't find spawn location at %f, %f, %f\n", pos.X, pos.Y, pos.Z)
+		return nil
+	}
 	dst := src.Clone()
 	dst.Id = utils.NextUUId()
-	dst.Kind = config.ThingThrowableDef
 	dst.Position = pos
 	dst.Angle = angle
-	dst.Pitch = pitch
-	dst.Speed = speed
-	dst.OnCollision = onCollision
-	dst.OnImpact = onImpact
+	handlers, err := config.ResolveBehavior(dst, th.nextSeed())
+	if err != nil {
+		fmt.Println("Warning", err)
+		return nil
+	}
+	thing := th.enqueue(dst, volume, handlers)
+	if thing != nil {
+		th.spawnCap.add(thing)
+	}
+	return thing
+}
+
+// enqueue creates the thing described by ct and adds it to the pending list, returning nil when the list is full.
+func (th *Things) enqueue(ct *config.Thing, volume *Volume, handlers *config.BehaviorHandlers) IThing {
+	slot, ok := th.reserve()
+	if !ok {
+		return nil
+	}
+	thing := th.createThing(ct, volume, handlers)
+	th.pending[slot] = thing
+	th.hasPending = true
+	return thing
+}
+
+// reserve returns a free slot of the pending list, or false when the list is full.
+func (th *Things) reserve() (int32, bool) {
 	slot := th.pendingIdx.Add(1) - 1
 	if slot >= int32(len(th.pending)) {
 		fmt.Println("max slot reached!")
-		return
+		return 0, false
 	}
-	throwable := th.createThing(dst, volume)
-	throwable.GetEntity().SetOnGround(false)
+	return slot, true
+}
 
-	th.pending[slot] = throwable
-	th.hasPending = true
+// GetPool returns the pool recycling the throwables removed from the simulation.
+func (th *Things) GetPool() *ThingPool {
+	return th.pool
+}
+
+// GetProjectiles returns the launcher of the throwables, tracking the weapon projectiles still flying.
+func (th *Things) GetProjectiles() *Projectiles {
+	return th.projectiles
+}
+
+// GetSpawnCap returns the cap on the things created by Spawn alive at the same time.
+func (th *Things) GetSpawnCap() *SpawnCap {
+	return th.spawnCap
+}
+
+// CountEntities returns the number of things in the simulation, corpses included.
+func (th *Things) CountEntities() int {
+	return len(th.entities)
+}
+
+// Close destroys every thing, in the simulation or still pending, stopping their goroutines. The Things can't be
+// computed anymore.
+func (th *Things) Close() {
+	for _, t := range th.ordered {
+		t.Destroy()
+	}
+	for _, t := range th.pending[:min(int(th.pendingIdx.Load()), len(th.pending))] {
+		t.Destroy()
+	}
+	th.pendingIdx.Store(0)
+	th.hasPending = false
+	th.entities = make(map[uint64]IThing)
+	clear(th.ordered)
+	th.ordered = th.ordered[:0]
+	th.pool.SetSize(0)
+}
+
+// isAlive reports whether the thing is still in the simulation and not dead: a taken item or a corpse is not alive.
+func isAlive(t IThing) bool {
+	return t.IsActive() && !t.IsDead()
 }
 
 // Compute updates the state of all managed entities by computing their active state and processing collisions.
 func (th *Things) Compute(pX float64, pY float64, pZ float64) {
 	th.computeActive(pX, pY, pZ)
 	th.processCollision()
+	th.projectiles.Compute()
+	th.collectPickups()
+	if th.perception != nil {
+		th.perception.advance()
+	}
 }
 
 // Compute updates the state of all IThing objects in the collection using the provided position coordinates (pX, pY).
@@ -225,19 +567,26 @@ func (th *Things) computeActive(pX float64, pY float64, pZ float64) {
 	th.containerIdx = 0
 	th.activeIdx = 0
 	th.inactiveIdx = 0
+	th.sleepStats.Slept = 0
+	th.sleepStats.Woken = 0
 
 	th.event.SetStage(StageThinking)
 	th.event.SetCoords(pX, pY, pZ)
-	for _, t2 := range th.entities {
+	for _, t2 := range th.ordered {
 		if !t2.IsActive() {
+			if t2.GetBase().IsCorpse() {
+				// I cadaveri restano visibili ma escono dalla simulazione
+				th.container[th.containerIdx] = t2
+				th.containerIdx++
+				continue
+			}
 			th.inactive[th.inactiveIdx] = t2
 			th.inactiveIdx++
 			continue
 		}
 		th.container[th.containerIdx] = t2
 		th.containerIdx++
-		th.event.wg.Add(1)
-		t2.PostMessage(th.event)
+		th.post(t2)
 	}
 	th.event.wg.Wait()
 
@@ -249,23 +598,30 @@ func (th *Things) computeActive(pX float64, pY float64, pZ float64) {
 	}
 
 	if th.hasPending {
-		pendingIdx := int(th.pendingIdx.Load())
-		for x := 0; x < pendingIdx; x++ {
-			th.addThing(th.pending[x])
+		// La lista può essere riallocata da addThing: si scorre quella corrente, senza gli slot rifiutati
+		pending := th.pending[:min(int(th.pendingIdx.Load()), len(th.pending))]
+		for _, t := range pending {
+			th.addThing(t)
 		}
 		th.pendingIdx.Store(0)
 		th.hasPending = false
 	}
 
+	th.resolveStep++
 	for x := 0; x < th.containerIdx; x++ {
 		thing := th.container[x]
-		if !thing.StagePrepare() {
+		// Keep the pre-step state for the renderers' interpolation
+		thing.GetEntity().Snapshot()
+		if !thing.IsActive() || !thing.StagePrepare() {
 			continue
 		}
 		//th.tree.UpdateObject(thing)
+		base := thing.GetBase()
+		base.resolveSlot, base.resolveStep = th.activeIdx, th.resolveStep
 		th.active[th.activeIdx] = thing
 		th.activeIdx++
 	}
+	th.activateJoints()
 }
 
 // Compute updates the state of all entities, processes collisions, resolves contacts, and integrates final positions.
@@ -287,25 +643,12 @@ func (th *Things) processCollision() {
 	}
 	//th.event.wg.Wait()
 
-	for si := 0; si < th.solverIterations; si++ {
-		//th.event.SetSolver(si, solverJitter)
-		//th.event.SetStage(StageResolve)
-		for x := 0; x < th.activeIdx; x++ {
-			t2 := th.active[x]
-			//th.event.wg.Add(1)
-			//t2.PostMessage(th.event)
-			//TODO MULTIPLE ACTION INSIDE [SELF -> OTHER]
-			t2.StageResolve(si, solverJitter)
-		}
-		//th.event.wg.Wait()
-	}
+	th.resolve()
 
 	th.event.SetStage(StageApply)
 	// PHYSYCS APPLY
 	for x := 0; x < th.activeIdx; x++ {
-		t2 := th.active[x]
-		th.event.wg.Add(1)
-		t2.PostMessage(th.event)
+		th.post(th.active[x])
 	}
 	th.event.wg.Wait()
 
@@ -313,15 +656,78 @@ func (th *Things) processCollision() {
 	for x := 0; x < th.activeIdx; x++ {
 		t2 := th.active[x]
 		th.tree.UpdateObject(t2)
+		// Impatti dello sweep continuo, notificati da un solo goroutine
+		if throwable, ok := t2.(*ThingThrowable); ok {
+			throwable.ReportImpact()
+		}
+	}
+	th.updateSleep()
+}
+
+// post delivers the current stage of the event to the thing goroutine or, in deterministic mode, runs it inline.
+func (th *Things) post(thing IThing) {
+	if !th.deterministic {
+		th.event.wg.Add(1)
+		thing.PostMessage(th.event)
+		return
+	}
+	switch th.event.GetKind() {
+	case StageThinking:
+		thing.StageThinking(th.event.GetCoords())
+	case StageResolve:
+		thing.StageResolve(th.event.GetSolverIndex(), th.event.GetSolverJitter())
+	case StageApply:
+		thing.StageApply(th.event.GetSolverJitter())
 	}
 }
 
+// collectPickups gives the player the pickup items it touches and removes the taken ones. It runs after the solver,
+// outside the things stages, so the inventory and the items are updated by a single goroutine.
+func (th *Things) collectPickups() {
+	p := th.player
+	if p == nil || p.IsDead() {
+		return
+	}
+	aabb := p.GetEntity().GetAABB()
+	margin := p.GetEntity().GetWidth() * pickupMargin
+	th.pickupArea.Rebuild(aabb.GetMinX()-margin, aabb.GetMinY()-margin, aabb.GetMinZ()-margin,
+		aabb.GetWidth()+margin*2, aabb.GetHeight()+margin*2, aabb.GetDepth()+margin*2)
+	area := th.pickupArea.GetAABB()
+	th.tree.QueryOverlaps(th.pickupArea, func(object physics.IAABB) bool {
+		item, ok := object.(IThing)
+		if !ok || item == IThing(p) || !item.IsActive() || !item.GetBase().IsPickup() {
+			return false
+		}
+		if !area.Overlaps(item.GetEntity().GetAABB()) {
+			return false
+		}
+		if p.Pickup(item) {
+			item.SetActive(false)
+		}
+		return false
+	})
+}
+
 // addThing adds a new IThing to the entity collection, assigns it a unique identifier, and updates related structures.
 func (th *Things) addThing(ent IThing) {
-	th.entities[ent.GetEntity().GetId()] = ent
+	entity := ent.GetEntity()
+	if entity.GetDt() != th.dt {
+		entity.SetDt(th.dt)
+	}
+	entity.Snapshot()
+	id := entity.GetId()
+	th.entities[id] = ent
+	idx := th.indexOf(id)
+	th.ordered = append(th.ordered, nil)
+	copy(th.ordered[idx+1:], th.ordered[idx:])
+	th.ordered[idx] = ent
 	if len(th.entities) > cap(th.active) {
 		th.active = make([]IThing, len(th.entities)*4)
 		th.inactive = make([]IThing, len(th.entities)*4)
+		// Il container è già riempito dallo stage di thinking del passo corrente
+		container := make([]IThing, len(th.entities)*4)
+		copy(container, th.container[:th.containerIdx])
+		th.container = container
 		th.pending = make([]IThing, len(th.entities)*4)
 		//th.contacts = make([]*Contact, len(th.entities)*4)
 		//for idx := range th.contacts {
@@ -332,8 +738,55 @@ func (th *Things) addThing(ent IThing) {
 	ent.StartLoop()
 }
 
-// removeThing removes an IThing instance from the spatial tree and the entities map.
+// removeThing removes an IThing instance from the spatial tree and the entities map, together with its joints, and
+// destroys it: a pooled throwable goes back to the pool.
 func (th *Things) removeThing(ent IThing) {
 	th.tree.RemoveObject(ent)
-	delete(th.entities, ent.GetEntity().GetId())
+	id := ent.GetEntity().GetId()
+	delete(th.entities, id)
+	if ent.GetBase().IsSleeping() {
+		th.sleepStats.Sleeping--
+	}
+	for x := len(th.joints) - 1; x >= 0; x-- {
+		if a, b := th.joints[x].GetThings(); a == ent || b == ent {
+			th.removeJoint(th.joints[x])
+		}
+	}
+	if th.triggerVolumes != nil {
+		// Un proiettile del pool può tornare in gioco nello stesso step: l'uscita non può attendere il Compute
+		th.triggerVolumes.remove(ent)
+	}
+	if idx := th.indexOf(id); idx < len(th.ordered) && th.ordered[idx] == ent {
+		last := len(th.ordered) - 1
+		copy(th.ordered[idx:], th.ordered[idx+1:])
+		th.ordered[last] = nil
+		th.ordered = th.ordered[:last]
+	}
+	ent.Destroy()
+	if throwable, ok := ent.(*ThingThrowable); ok && throwable.pooled {
+		th.pool.release(throwable)
+	}
+}
+
+// addJoint registers the joint in the solver.
+func (th *Things) addJoint(j *Joint) {
+	th.joints = append(th.joints, j)
+}
+
+// removeJoint removes the joint from the solver.
+func (th *Things) removeJoint(j *Joint) {
+	for x, other := range th.joints {
+		if other == j {
+			th.joints = append(th.joints[:x], th.joints[x+1:]...)
+			return
+		}
+	}
+}
+
+// indexOf returns the position of the thing with the given id in the ordered list, or the position where it would be
+// inserted.
+func (th *Things) indexOf(id uint64) int {
+	return sort.Search(len(th.ordered), func(i int) bool {
+		return th.ordered[i].GetEntity().GetId() >= id
+	})
 }
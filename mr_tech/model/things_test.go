@@ -0,0 +1,232 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+	"github.com/markel1974/godoom/mr_tech/physics"
+)
+
+// newLayerScene compiles the test level without its things and places two copies of the first enemy in front of the
+// player, along +X: the second touches the first with a slight penetration.
+func newLayerScene(tb testing.TB) (*Compiler, IThing, IThing) {
+	tb.Helper()
+	var template *config.Thing
+	c := newTestWorld(tb, func(cfg *config.Root) {
+		for _, ct := range cfg.Things {
+			if ct.Kind == config.ThingEnemyDef {
+				template = ct
+				break
+			}
+		}
+		cfg.Things = nil
+	})
+	if template == nil {
+		tb.Fatal("the level has no enemy")
+	}
+	things := c.GetThings()
+	pos := positionOf(c.GetPlayer())
+	pos.X += c.GetPlayer().GetEntity().GetWidth() * 2
+	a, b := things.Spawn(template, pos, 0), things.Spawn(template, pos, 0)
+	if a == nil || b == nil {
+		tb.Fatal("the things have not been spawned")
+	}
+	stepWorld(c, 1)
+	pos.Z = positionOf(a).Z
+	moveThing(c, a, pos)
+	pos.X += a.GetEntity().GetWidth() - satFilterEpsilon*0.5
+	moveThing(c, b, pos)
+	return c, a, b
+}
+
+// touches reports whether the narrow phase of the cage of a finds a contact with b.
+func touches(c *Compiler, a, b IThing) bool {
+	cage := a.GetCage()
+	cage.Rebuild(a.GetBase().maxStep)
+	c.GetThings().QueryCollisionCage(cage)
+	for x := 0; x < cage.GetSlotsLen(); x++ {
+		if cage.GetSlot(x).GetRemoteCage() == b.GetCage() {
+			return true
+		}
+	}
+	return false
+}
+
+// rayReports reports whether a ray of the given layer and mask, cast from the center of a along +X, reports b.
+func rayReports(c *Compiler, a, b IThing, layer, mask config.CollisionLayer) bool {
+	found := false
+	oX, oY, oZ := a.GetEntity().GetCenter()
+	c.GetThings().QueryRay(oX, oY, oZ, 1, 0, 0, 1e6, layer, mask, func(object physics.IAABB, distance float64) (float64, bool) {
+		if object == b {
+			found = true
+		}
+		return 1e6, false
+	})
+	return found
+}
+
+// shoot fires from the center of a along +X a hitscan of the given damage and reports whether it hits b.
+func shoot(a, b IThing, damage float64) bool {
+	x, y, z := a.GetEntity().GetCenter()
+	hit, ok := a.GetBase().FireHitscan("bullet", geometry.XYZ{X: x, Y: y, Z: z}, damage, 0, 1e6, 1, 0, 0)
+	return ok && hit.Thing == b
+}
+
+func TestCollisionLayersCage(t *testing.T) {
+	tests := []struct {
+		name          string
+		layerA, maskA config.CollisionLayer
+		layerB, maskB config.CollisionLayer
+		want          bool
+	}{
+		{"monster monster", config.LayerMonster, config.LayerAll, config.LayerMonster, config.LayerAll, true},
+		{"player monster", config.LayerPlayer, config.LayerAll, config.LayerMonster, config.LayerAll, true},
+		{"monster item", config.LayerMonster, config.LayerAll, config.LayerItem, config.LayerAll, true},
+		{"monster pickup", config.LayerMonster, config.LayerAll, config.LayerPickup, config.LayerWorld, false},
+		{"monster corpse", config.LayerMonster, config.LayerAll, config.LayerCorpse, config.LayerWorld, false},
+		// Basta che una delle due maschere escluda l'altra cosa
+		{"own mask excludes", config.LayerMonster, config.LayerWorld | config.LayerPlayer, config.LayerMonster, config.LayerAll, false},
+		{"other mask excludes", config.LayerPlayer, config.LayerAll, config.LayerMonster, config.LayerWorld | config.LayerMonster, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, a, b := newLayerScene(t)
+			a.GetBase().SetCollision(test.layerA, test.maskA)
+			b.GetBase().SetCollision(test.layerB, test.maskB)
+			if got := touches(c, a, b); got != test.want {
+				t.Fatalf("contact %t, want %t", got, test.want)
+			}
+			if got := touches(c, b, a); got != test.want {
+				t.Fatalf("contact %t from the other thing, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCollisionLayersRay(t *testing.T) {
+	tests := []struct {
+		name          string
+		layer, mask   config.CollisionLayer
+		layerB, maskB config.CollisionLayer
+		want          bool
+	}{
+		{"projectile monster", config.LayerProjectile, config.LayerAll, config.LayerMonster, config.LayerAll, true},
+		{"projectile item", config.LayerProjectile, config.LayerAll, config.LayerItem, config.LayerAll, true},
+		{"projectile pickup", config.LayerProjectile, config.LayerAll, config.LayerPickup, config.LayerWorld, false},
+		{"projectile corpse", config.LayerProjectile, config.LayerAll, config.LayerCorpse, config.LayerWorld, false},
+		{"ray mask excludes", config.LayerProjectile, config.LayerWorld | config.LayerPlayer, config.LayerMonster, config.LayerAll, false},
+		{"thing mask excludes", config.LayerProjectile, config.LayerAll, config.LayerMonster, config.LayerWorld | config.LayerMonster, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, a, b := newLayerScene(t)
+			b.GetBase().SetCollision(test.layerB, test.maskB)
+			if got := rayReports(c, a, b, test.layer, test.mask); got != test.want {
+				t.Fatalf("reported %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCollisionLayersHitscan(t *testing.T) {
+	tests := []struct {
+		name          string
+		maskA         config.CollisionLayer
+		layerB, maskB config.CollisionLayer
+		want          bool
+	}{
+		{"monster", config.LayerAll, config.LayerMonster, config.LayerAll, true},
+		{"item", config.LayerAll, config.LayerItem, config.LayerAll, true},
+		{"pickup", config.LayerAll, config.LayerPickup, config.LayerWorld, false},
+		{"corpse", config.LayerAll, config.LayerCorpse, config.LayerWorld, false},
+		// Il raggio usa la maschera del tiratore
+		{"shooter mask excludes", config.LayerWorld | config.LayerPlayer, config.LayerMonster, config.LayerAll, false},
+		{"target mask excludes", config.LayerAll, config.LayerMonster, config.LayerWorld | config.LayerMonster, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, a, b := newLayerScene(t)
+			a.GetBase().SetCollision(config.LayerMonster, test.maskA)
+			b.GetBase().SetCollision(test.layerB, test.maskB)
+			if got := shoot(a, b, 0); got != test.want {
+				t.Fatalf("hit %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCollisionLayersCorpse(t *testing.T) {
+	c, a, b := newLayerScene(t)
+	if !touches(c, a, b) || !shoot(a, b, 0) {
+		t.Fatal("the living thing does not block the other one and the shot")
+	}
+	if !shoot(a, b, 1e6) || !b.GetBase().IsCorpse() {
+		t.Fatalf("the lethal shot has not left a corpse (corpse %t)", b.GetBase().IsCorpse())
+	}
+	// Il cadavere resta sul pavimento ma non ferma più le cose, i raggi e gli spari
+	if touches(c, a, b) {
+		t.Fatal("the corpse still makes contacts with the living thing")
+	}
+	if rayReports(c, a, b, config.LayerProjectile, config.LayerAll) {
+		t.Fatal("the corpse is still reported to the rays of the projectiles")
+	}
+	if shoot(a, b, 0) {
+		t.Fatal("the corpse still stops the shots")
+	}
+}
+
+// newSeededWorld compiles the test level with the given seed in deterministic mode, the gun of the player firing
+// pellets spread at random at the first enemy, alone in the level right in front of the player.
+func newSeededWorld(tb testing.TB, seed int64) *Compiler {
+	tb.Helper()
+	c := newTestWorld(tb, func(cfg *config.Root) {
+		cfg.Seed = seed
+		for _, w := range cfg.Player.Weapons {
+			w.Spread, w.Pellets, w.Force = 0.2, 4, 500
+		}
+		cfg.Player.Angle = 0
+		for _, ct := range cfg.Things {
+			if ct.Kind == config.ThingEnemyDef {
+				ct.Position = geometry.XYZ{X: cfg.Player.Position.X + 5, Y: cfg.Player.Position.Y, Z: cfg.Player.Position.Z}
+				cfg.Things = []*config.Thing{ct}
+				break
+			}
+		}
+	})
+	c.GetThings().SetDeterministic(true)
+	return c
+}
+
+func TestDeterministicReplay(t *testing.T) {
+	const steps = 120
+	a, b := newSeededWorld(t, 42), newSeededWorld(t, 42)
+	start := a.GetThings().StateHash()
+	for step := 0; step < steps; step++ {
+		// Gli spari, come nel motore, precedono il passo delle cose e consumano il generatore della dispersione
+		for _, c := range []*Compiler{a, b} {
+			c.GetPlayer().Fire()
+			c.GetPlayer().ComputeWeapons(c.GetThings().GetTimeStep())
+			stepWorld(c, 1)
+		}
+		if hA, hB := a.GetThings().StateHash(), b.GetThings().StateHash(); hA != hB {
+			t.Fatalf("step %d: state hash %x and %x of two runs with the same seed", step, hA, hB)
+		}
+	}
+	if a.GetThings().StateHash() == start {
+		t.Fatal("the state has not changed, the replay proves nothing")
+	}
+}
+
+func TestDeterministicWeaponSeed(t *testing.T) {
+	draw := func(c *Compiler) int64 { return c.GetPlayer().GetArsenal().rnd.Int63() }
+	a, b, other := newSeededWorld(t, 42), newSeededWorld(t, 42), newSeededWorld(t, 43)
+	// La dispersione degli spari segue il seme del livello
+	dA, dB, dO := draw(a), draw(b), draw(other)
+	if dA != dB {
+		t.Fatalf("spread draws %d and %d with the same seed", dA, dB)
+	}
+	if dA == dO {
+		t.Fatalf("spread draw %d with two different seeds", dA)
+	}
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+	"github.com/markel1974/godoom/mr_tech/physics"
+)
+
+// TriggerVolume is the runtime state of a non-solid trigger region: it is stored in the AABB tree of the
+// TriggerVolumes and remembers the things currently inside it.
+type TriggerVolume struct {
+	id          string
+	aabb        *physics.AABB
+	sector      string
+	action      config.TriggerVolumeAction
+	destination geometry.XYZ
+	angle       float64
+	playerOnly  bool
+	once        bool
+	tag         string
+	enter       []string
+	exit        []string
+	message     string
+	fired       bool
+	occupants   []IThing
+}
+
+// NewTriggerVolume creates the runtime trigger volume described by the configuration, covering the given box.
+func NewTriggerVolume(cfg *config.TriggerVolume, aabb *physics.AABB) *TriggerVolume {
+	return &TriggerVolume{
+		id:          cfg.Id,
+		aabb:        aabb,
+		sector:      cfg.Sector,
+		action:      cfg.Action,
+		destination: cfg.Destination,
+		angle:       cfg.Angle,
+		playerOnly:  cfg.PlayerOnly,
+		once:        cfg.Once,
+		tag:         cfg.Tag,
+		enter:       cfg.Enter,
+		exit:        cfg.Exit,
+		message:     cfg.Message,
+	}
+}
+
+// GetAABB returns the box covered by the TriggerVolume.
+func (tv *TriggerVolume) GetAABB() *physics.AABB {
+	return tv.aabb
+}
+
+// GetId returns the identifier of the TriggerVolume.
+func (tv *TriggerVolume) GetId() string {
+	return tv.id
+}
+
+// GetTag returns the script tag of the TriggerVolume, or the empty string.
+func (tv *TriggerVolume) GetTag() string {
+	return tv.tag
+}
+
+// GetAction returns the built-in action of the TriggerVolume.
+func (tv *TriggerVolume) GetAction() config.TriggerVolumeAction {
+	return tv.action
+}
+
+// IsFired reports whether a thing has entered the TriggerVolume at least once.
+func (tv *TriggerVolume) IsFired() bool {
+	return tv.fired
+}
+
+// GetOccupants returns the things inside the TriggerVolume after the last step, in order of entrance.
+func (tv *TriggerVolume) GetOccupants() []IThing {
+	return tv.occupants
+}
+
+// Contains reports whether the thing is inside the TriggerVolume: its box overlaps the volume and, for a sector
+// volume, it is located in that sector.
+func (tv *TriggerVolume) Contains(thing IThing) bool {
+	if !tv.aabb.Overlaps(thing.GetEntity().GetAABB()) {
+		return false
+	}
+	if tv.sector == "" {
+		return true
+	}
+	location := thing.GetBase().GetLocation()
+	return location != nil && location.GetSector() != nil && location.GetSector().GetId() == tv.sector
+}
+
+// accepts reports whether the TriggerVolume reacts to the thing.
+func (tv *TriggerVolume) accepts(thing IThing, player *ThingPlayer) bool {
+	if tv.playerOnly && IThing(player) != thing {
+		return false
+	}
+	return !thing.GetBase().IsCorpse()
+}
+
+// indexOf returns the position of the thing among the occupants, or -1.
+func (tv *TriggerVolume) indexOf(thing IThing) int {
+	for i, o := range tv.occupants {
+		if o == thing {
+			return i
+		}
+	}
+	return -1
+}
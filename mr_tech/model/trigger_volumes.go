@@ -0,0 +1,271 @@
+package model
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/physics"
+)
+
+// TriggerFunc is the callback notified when a thing enters, stays in or leaves a TriggerVolume.
+type TriggerFunc func(volume *TriggerVolume, thing IThing)
+
+// triggerTeleport is a teleport requested by a trigger volume during Compute and applied once the events are delivered.
+type triggerTeleport struct {
+	thing  IThing
+	volume *TriggerVolume
+}
+
+// TriggerVolumes manages the non-solid trigger regions of the world. After each solver step it compares the things
+// with the regions they overlap, delivers the enter, stay and exit events and applies the built-in actions
+// (teleports, secrets, exits) and the referenced triggers. Things crossing a region get no physical response.
+type TriggerVolumes struct {
+	container []*TriggerVolume
+	cache     map[string]*TriggerVolume
+	tree      *physics.AABBTree
+	things    *Things
+	volumes   *Volumes
+	specials  *Specials
+	onEnter   []TriggerFunc
+	onStay    []TriggerFunc
+	onExit    []TriggerFunc
+	stamps    map[*TriggerVolume]map[IThing]uint64
+	stamp     uint64
+	secrets   int
+	found     int
+}
+
+// NewTriggerVolumes creates the trigger volumes described by the configuration. A sector volume covers the compiled
+// sectors with the referenced id at any height; volumes with an unknown sector or an empty box are skipped with a
+// warning.
+func NewTriggerVolumes(cfg []*config.TriggerVolume, specials *Specials, volumes *Volumes, things *Things) *TriggerVolumes {
+	tvs := &TriggerVolumes{
+		cache:    make(map[string]*TriggerVolume),
+		tree:     physics.NewAABBTree(uint(len(cfg)*2), 0.0),
+		things:   things,
+		volumes:  volumes,
+		specials: specials,
+		stamps:   make(map[*TriggerVolume]map[IThing]uint64),
+	}
+	sectors := sectorsById(volumes)
+	minZ, maxZ := math.MaxFloat64, -math.MaxFloat64
+	for _, vol := range volumes.GetVolumes() {
+		minZ = math.Min(minZ, vol.GetAABB().GetMinZ())
+		maxZ = math.Max(maxZ, vol.GetAABB().GetMaxZ())
+	}
+	for _, ct := range cfg {
+		aabb := physics.NewAABB()
+		if ct.Sector != "" {
+			group := sectors[ct.Sector]
+			if len(group) == 0 {
+				fmt.Printf("Warning can't find sector '%s' for trigger volume %s\n", ct.Sector, ct.Id)
+				continue
+			}
+			minX, minY := math.MaxFloat64, math.MaxFloat64
+			maxX, maxY := -math.MaxFloat64, -math.MaxFloat64
+			for _, s := range group {
+				box := s.GetVolume().GetAABB()
+				minX, minY = math.Min(minX, box.GetMinX()), math.Min(minY, box.GetMinY())
+				maxX, maxY = math.Max(maxX, box.GetMaxX()), math.Max(maxY, box.GetMaxY())
+			}
+			// I settori cambiano altezza con movers ed elevatori: il volume copre l'intera altezza del mondo
+			aabb.Rebuild(minX, minY, minZ, maxX, maxY, maxZ)
+		} else {
+			if ct.Max.X <= ct.Min.X || ct.Max.Y <= ct.Min.Y || ct.Max.Z <= ct.Min.Z {
+				fmt.Printf("Warning empty box for trigger volume %s\n", ct.Id)
+				continue
+			}
+			aabb.Rebuild(ct.Min.X, ct.Min.Y, ct.Min.Z, ct.Max.X, ct.Max.Y, ct.Max.Z)
+		}
+		tv := NewTriggerVolume(ct, aabb)
+		if tv.action == config.TriggerVolumeSecret {
+			tvs.secrets++
+		}
+		tvs.container = append(tvs.container, tv)
+		tvs.cache[tv.id] = tv
+		tvs.stamps[tv] = make(map[IThing]uint64)
+		tvs.tree.InsertObject(tv)
+	}
+	return tvs
+}
+
+// GetTriggerVolume retrieves a TriggerVolume by its identifier, or nil if it does not exist.
+func (tvs *TriggerVolumes) GetTriggerVolume(id string) *TriggerVolume {
+	return tvs.cache[id]
+}
+
+// GetTriggerVolumes returns all the trigger volumes.
+func (tvs *TriggerVolumes) GetTriggerVolumes() []*TriggerVolume {
+	return tvs.container
+}
+
+// Len returns the number of trigger volumes.
+func (tvs *TriggerVolumes) Len() int {
+	return len(tvs.container)
+}
+
+// GetSecrets returns the number of secret areas found by the player and the total number of secret areas.
+func (tvs *TriggerVolumes) GetSecrets() (int, int) {
+	return tvs.found, tvs.secrets
+}
+
+// OnEnter registers a callback invoked when a thing enters a trigger volume.
+func (tvs *TriggerVolumes) OnEnter(fn TriggerFunc) {
+	tvs.onEnter = append(tvs.onEnter, fn)
+}
+
+// OnStay registers a callback invoked at each step for every thing that remains inside a trigger volume.
+func (tvs *TriggerVolumes) OnStay(fn TriggerFunc) {
+	tvs.onStay = append(tvs.onStay, fn)
+}
+
+// OnExit registers a callback invoked when a thing leaves a trigger volume, dies or is removed from the world.
+func (tvs *TriggerVolumes) OnExit(fn TriggerFunc) {
+	tvs.onExit = append(tvs.onExit, fn)
+}
+
+// QueryAABB invokes the callback for each trigger volume overlapping the specified AABB.
+// The query stops as soon as the callback returns true.
+func (tvs *TriggerVolumes) QueryAABB(aabb physics.IAABB, callback func(volume *TriggerVolume) bool) {
+	tvs.tree.QueryOverlaps(aabb, func(object physics.IAABB) bool {
+		tv := object.(*TriggerVolume)
+		if !tv.aabb.Overlaps(aabb.GetAABB()) {
+			return false
+		}
+		return callback(tv)
+	})
+}
+
+// Compute updates the occupants of the trigger volumes with the things simulated in the last step and delivers the
+// events. It runs after the solver, outside the things stages, so the callbacks are invoked by a single goroutine.
+func (tvs *TriggerVolumes) Compute() {
+	if len(tvs.container) == 0 {
+		return
+	}
+	tvs.stamp++
+	player := tvs.things.player
+	things, count := tvs.things.GetActive()
+	var teleports []*triggerTeleport
+	for x := 0; x < count; x++ {
+		thing := things[x]
+		if !thing.IsActive() {
+			continue
+		}
+		tvs.QueryAABB(thing.GetEntity(), func(tv *TriggerVolume) bool {
+			if !tv.accepts(thing, player) || !tv.Contains(thing) {
+				return false
+			}
+			stamps := tvs.stamps[tv]
+			_, inside := stamps[thing]
+			stamps[thing] = tvs.stamp
+			if inside {
+				tvs.notify(tvs.onStay, tv, thing)
+				return false
+			}
+			tv.occupants = append(tv.occupants, thing)
+			tvs.notify(tvs.onEnter, tv, thing)
+			if t := tvs.enter(tv, thing, player); t != nil {
+				teleports = append(teleports, t)
+			}
+			return false
+		})
+	}
+	for _, tv := range tvs.container {
+		stamps := tvs.stamps[tv]
+		kept := tv.occupants[:0]
+		for _, thing := range tv.occupants {
+			if stamps[thing] == tvs.stamp {
+				kept = append(kept, thing)
+				continue
+			}
+			tvs.leave(tv, thing)
+		}
+		tv.occupants = kept
+	}
+	// I teletrasporti sono applicati al termine, per non alterare le sovrapposizioni già valutate nello step
+	for _, t := range teleports {
+		tvs.teleport(t.thing, t.volume)
+	}
+}
+
+// remove delivers the exit events of the volumes occupied by a thing removed from the world and forgets it. It runs
+// when the thing is removed, since the same thing may come back from the pool before the next Compute, where it must
+// enter again instead of staying.
+func (tvs *TriggerVolumes) remove(thing IThing) {
+	for _, tv := range tvs.container {
+		i := tv.indexOf(thing)
+		if i < 0 {
+			continue
+		}
+		tv.occupants = append(tv.occupants[:i], tv.occupants[i+1:]...)
+		tvs.leave(tv, thing)
+	}
+}
+
+// leave forgets the thing inside the trigger volume, notifies its exit and fires the exit triggers of the volume.
+func (tvs *TriggerVolumes) leave(tv *TriggerVolume, thing IThing) {
+	delete(tvs.stamps[tv], thing)
+	tvs.notify(tvs.onExit, tv, thing)
+	for _, id := range tv.exit {
+		tvs.specials.fireBy(id, thing)
+	}
+}
+
+// notify invokes the callbacks for the trigger volume and the thing.
+func (tvs *TriggerVolumes) notify(callbacks []TriggerFunc, tv *TriggerVolume, thing IThing) {
+	for _, fn := range callbacks {
+		fn(tv, thing)
+	}
+}
+
+// enter applies the built-in action and fires the enter triggers of the volume, unless it is a single-shot volume
+// already fired. Secrets and exits react to the player only. A teleport is returned to be applied later.
+func (tvs *TriggerVolumes) enter(tv *TriggerVolume, thing IThing, player *ThingPlayer) *triggerTeleport {
+	if tv.fired && tv.once {
+		return nil
+	}
+	isPlayer := IThing(player) == thing
+	if tv.action != config.TriggerVolumeEvents && tv.action != config.TriggerVolumeTeleport && !isPlayer {
+		return nil
+	}
+	secretFound := tv.action == config.TriggerVolumeSecret && !tv.fired
+	tv.fired = true
+	if tv.message != "" && isPlayer {
+		fmt.Println(tv.message)
+	}
+	for _, id := range tv.enter {
+		tvs.specials.fireBy(id, thing)
+	}
+	switch tv.action {
+	case config.TriggerVolumeTeleport:
+		return &triggerTeleport{thing: thing, volume: tv}
+	case config.TriggerVolumeSecret:
+		if secretFound {
+			tvs.found++
+			fmt.Printf("secret area found: %d of %d\n", tvs.found, tvs.secrets)
+		}
+	case config.TriggerVolumeExit, config.TriggerVolumeSecretExit:
+		tvs.specials.exit(tv.id, tv.action == config.TriggerVolumeSecretExit)
+	}
+	return nil
+}
+
+// teleport moves the thing to the destination of the volume, stopping it. The interpolation snapshot is reset, so
+// that the renderers do not blend the jump, and the spatial tree is updated.
+func (tvs *TriggerVolumes) teleport(thing IThing, tv *TriggerVolume) {
+	dst := tv.destination
+	location, _ := tvs.volumes.QueryPoint(dst.X, dst.Y, dst.Z)
+	if location == nil {
+		fmt.Printf("Warning teleport destination of %s is outside the world at %f, %f, %f\n", tv.id, dst.X, dst.Y, dst.Z)
+		return
+	}
+	// La destinazione segue la convenzione dei punti di spawn del player
+	entity := thing.GetEntity()
+	entity.MoveTo(dst.X, dst.Y, dst.Z)
+	entity.Stop()
+	entity.Snapshot()
+	thing.SetAngle(tv.angle)
+	thing.GetBase().location = location
+	tvs.things.Update(thing)
+}
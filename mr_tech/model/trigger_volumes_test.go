@@ -0,0 +1,257 @@
+package model
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// newTestTriggerVolumes replaces the trigger volumes of the compiled world with the given ones, whose boxes are in
+// world coordinates.
+func newTestTriggerVolumes(c *Compiler, cfg ...*config.TriggerVolume) *TriggerVolumes {
+	tvs := NewTriggerVolumes(cfg, c.GetSpecials(), c.GetVolumes(), c.GetThings())
+	c.GetThings().SetTriggerVolumes(tvs)
+	return tvs
+}
+
+// stepTriggers advances the world by the given number of steps, delivering the events of the trigger volumes after
+// each one, as the engine does.
+func stepTriggers(c *Compiler, tvs *TriggerVolumes, steps int) {
+	for x := 0; x < steps; x++ {
+		stepWorld(c, 1)
+		tvs.Compute()
+	}
+}
+
+// newTestTriggerBox returns a trigger volume with the given action around the center of the player placed at pos, as
+// tall as the player.
+func newTestTriggerBox(c *Compiler, id string, pos geometry.XYZ, action config.TriggerVolumeAction) *config.TriggerVolume {
+	entity := c.GetPlayer().GetEntity()
+	cX, cY := pos.X+entity.GetWidth()*0.5, pos.Y+entity.GetHeight()*0.5
+	h := entity.GetDepth()
+	return config.NewConfigTriggerVolume(id, geometry.XYZ{X: cX - 5, Y: cY - 5, Z: pos.Z}, geometry.XYZ{X: cX + 5, Y: cY + 5, Z: pos.Z + h}, action)
+}
+
+// farPoint returns the position, with the convention of the spawn points, of a room of the level far enough from the
+// player for its box not to reach a trigger volume around the player.
+func farPoint(tb testing.TB, c *Compiler) geometry.XYZ {
+	tb.Helper()
+	entity := c.GetPlayer().GetEntity()
+	pX, pY, _ := entity.GetCenter()
+	for _, vol := range c.GetVolumes().GetVolumes() {
+		s := vol.GetSector()
+		if s == nil || s.GetMaxZ()-s.GetMinZ() < entity.GetDepth() {
+			continue
+		}
+		p := sectorCentroid(s)
+		if math.Hypot(p.X-pX, p.Y-pY) < entity.GetWidth()*4 {
+			continue
+		}
+		if location, _ := c.GetVolumes().QueryPoint(p.X, p.Y, p.Z+entity.GetDepth()*0.5); location == vol {
+			return geometry.XYZ{X: p.X - entity.GetWidth()*0.5, Y: p.Y - entity.GetHeight()*0.5, Z: p.Z}
+		}
+	}
+	tb.Fatal("the level has no room far from the player")
+	return geometry.XYZ{}
+}
+
+// moveThing moves the thing to pos outside the solver, as a teleport does.
+func moveThing(c *Compiler, thing IThing, pos geometry.XYZ) {
+	entity := thing.GetEntity()
+	entity.MoveTo(pos.X, pos.Y, pos.Z)
+	entity.Stop()
+	entity.Snapshot()
+	cX, cY, cZ := entity.GetCenter()
+	thing.GetBase().location, _ = c.GetVolumes().QueryPoint(cX, cY, cZ)
+	c.GetThings().Update(thing)
+}
+
+// positionOf returns the position of the thing, with the convention of the spawn points.
+func positionOf(thing IThing) geometry.XYZ {
+	x, y, z := thing.GetEntity().GetBottomLeft()
+	return geometry.XYZ{X: x, Y: y, Z: z}
+}
+
+// near reports whether a and b are closer on the floor than the half side of the test trigger boxes.
+func near(a, b geometry.XYZ) bool {
+	return math.Hypot(a.X-b.X, a.Y-b.Y) < 5
+}
+
+// recordTriggers registers callbacks appending to the returned log the events delivered for the thing, as
+// "<event> <volume id>".
+func recordTriggers(tvs *TriggerVolumes, thing IThing) *[]string {
+	var events []string
+	record := func(event string) TriggerFunc {
+		return func(tv *TriggerVolume, t IThing) {
+			if t == thing {
+				events = append(events, event+" "+tv.GetId())
+			}
+		}
+	}
+	tvs.OnEnter(record("enter"))
+	tvs.OnStay(record("stay"))
+	tvs.OnExit(record("exit"))
+	return &events
+}
+
+func TestTriggerVolumeEnterStayExit(t *testing.T) {
+	c := newTestWorld(t, nil)
+	player := c.GetPlayer()
+	home, far := positionOf(player), farPoint(t, c)
+	tvs := newTestTriggerVolumes(c, newTestTriggerBox(c, "box", home, config.TriggerVolumeEvents))
+	events := recordTriggers(tvs, player)
+	box := tvs.GetTriggerVolume("box")
+
+	stepTriggers(c, tvs, 2)
+	if want := []string{"enter box", "stay box"}; !reflect.DeepEqual(*events, want) {
+		t.Fatalf("events %v, want %v", *events, want)
+	}
+	if box.indexOf(player) < 0 || !box.IsFired() {
+		t.Fatalf("the player is not an occupant or the volume is not fired (%t)", box.IsFired())
+	}
+
+	moveThing(c, player, far)
+	stepTriggers(c, tvs, 1)
+	if want := []string{"enter box", "stay box", "exit box"}; !reflect.DeepEqual(*events, want) {
+		t.Fatalf("events %v, want %v", *events, want)
+	}
+	if box.indexOf(player) >= 0 {
+		t.Fatal("the player is still an occupant after the exit")
+	}
+
+	// Un volume ripetibile notifica di nuovo l'ingresso
+	moveThing(c, player, home)
+	stepTriggers(c, tvs, 1)
+	if last := (*events)[len(*events)-1]; last != "enter box" {
+		t.Fatalf("last event %q after coming back, want an enter", last)
+	}
+}
+
+func TestTriggerVolumeTeleportAfterPass(t *testing.T) {
+	c := newTestWorld(t, nil)
+	player := c.GetPlayer()
+	home, far := positionOf(player), farPoint(t, c)
+	gate := newTestTriggerBox(c, "gate", home, config.TriggerVolumeTeleport)
+	gate.Destination = far
+	gate.PlayerOnly = true
+	tvs := newTestTriggerVolumes(c, gate, newTestTriggerBox(c, "arrival", far, config.TriggerVolumeEvents))
+	events := recordTriggers(tvs, player)
+	var seen geometry.XYZ
+	tvs.OnEnter(func(tv *TriggerVolume, thing IThing) {
+		if tv.GetId() == "gate" {
+			seen = positionOf(thing)
+		}
+	})
+
+	stepTriggers(c, tvs, 1)
+	// Il teletrasporto segue la consegna degli eventi: nello stesso passo il player non entra nell'arrivo
+	if !near(seen, home) {
+		t.Fatalf("the enter event saw the player at %v, want it still at %v", seen, home)
+	}
+	if at := positionOf(player); !near(at, far) {
+		t.Fatalf("the player is at %v after the teleport, want %v", at, far)
+	}
+	if want := []string{"enter gate"}; !reflect.DeepEqual(*events, want) {
+		t.Fatalf("events %v in the step of the teleport, want %v", *events, want)
+	}
+
+	stepTriggers(c, tvs, 1)
+	if want := []string{"enter gate", "enter arrival", "exit gate"}; !reflect.DeepEqual(*events, want) {
+		t.Fatalf("events %v after the teleport, want %v", *events, want)
+	}
+}
+
+func TestTriggerVolumeOnce(t *testing.T) {
+	c := newTestWorld(t, nil)
+	player := c.GetPlayer()
+	home, far := positionOf(player), farPoint(t, c)
+	gate := newTestTriggerBox(c, "gate", home, config.TriggerVolumeTeleport)
+	gate.Destination = far
+	gate.PlayerOnly = true
+	gate.Once = true
+	tvs := newTestTriggerVolumes(c, gate)
+	events := recordTriggers(tvs, player)
+
+	stepTriggers(c, tvs, 1)
+	if at := positionOf(player); !near(at, far) {
+		t.Fatalf("the player is at %v after the first entrance, want %v", at, far)
+	}
+	stepTriggers(c, tvs, 1)
+	moveThing(c, player, home)
+	stepTriggers(c, tvs, 2)
+	// Gli eventi sono consegnati a ogni ingresso, l'azione soltanto al primo
+	if want := []string{"enter gate", "exit gate", "enter gate", "stay gate"}; !reflect.DeepEqual(*events, want) {
+		t.Fatalf("events %v, want %v", *events, want)
+	}
+	if at := positionOf(player); !near(at, home) {
+		t.Fatalf("the single-shot teleport moved the player again, to %v", at)
+	}
+}
+
+func TestTriggerVolumeSecrets(t *testing.T) {
+	c := newTestWorld(t, nil)
+	player := c.GetPlayer()
+	home, far := positionOf(player), farPoint(t, c)
+	tvs := newTestTriggerVolumes(c,
+		newTestTriggerBox(c, "found", home, config.TriggerVolumeSecret),
+		newTestTriggerBox(c, "hidden", far, config.TriggerVolumeSecret))
+	if found, total := tvs.GetSecrets(); found != 0 || total != 2 {
+		t.Fatalf("%d of %d secrets found at the start, want 0 of 2", found, total)
+	}
+	stepTriggers(c, tvs, 2)
+	if found, total := tvs.GetSecrets(); found != 1 || total != 2 {
+		t.Fatalf("%d of %d secrets found inside the first one, want 1 of 2", found, total)
+	}
+	// Un segreto già trovato non si conta una seconda volta
+	moveThing(c, player, far)
+	stepTriggers(c, tvs, 1)
+	moveThing(c, player, home)
+	stepTriggers(c, tvs, 1)
+	if found, _ := tvs.GetSecrets(); found != 2 {
+		t.Fatalf("%d secrets found after visiting both twice, want 2", found)
+	}
+}
+
+func TestTriggerVolumeRecycledThrowable(t *testing.T) {
+	c := newTestWorld(t, nil)
+	things := c.GetThings()
+	player := c.GetPlayer()
+	tvs := newTestTriggerVolumes(c, newTestTriggerBox(c, "box", positionOf(player), config.TriggerVolumeEvents))
+	handlers := &config.BehaviorHandlers{
+		OnCollision: func(config.IThingConfig, config.IThingConfig) {},
+		OnImpact:    func(config.IThingConfig, config.IThingConfig, string, float64, float64, float64, float64, float64) {},
+	}
+	launch := func() IThing {
+		thing := things.projectiles.launch(newTestThrowable(t, c, "throwable", 0, 0), handlers, player.GetBase(), player.GetLocation(), positionOf(player), 0, 0, 0)
+		if thing == nil {
+			t.Fatal("the throwable has not been launched")
+		}
+		return thing
+	}
+	first := launch()
+	events := recordTriggers(tvs, first)
+	// Un lancio entra nella simulazione al passo successivo a quello in cui esce dalla lista pending
+	stepTriggers(c, tvs, 2)
+	if want := []string{"enter box"}; !reflect.DeepEqual(*events, want) {
+		t.Fatalf("events %v after the launch, want %v", *events, want)
+	}
+
+	// Tolto, rilanciato dal pool con lo stesso puntatore e di nuovo simulato tra due passate dei volumi
+	first.GetBase().SetActive(false)
+	pX, pY, pZ := player.GetEntity().GetCenter()
+	things.Compute(pX, pY, pZ)
+	if second := launch(); second != first {
+		t.Fatal("the throwable has not been recycled from the pool")
+	}
+	things.Compute(pX, pY, pZ)
+	stepTriggers(c, tvs, 1)
+	if want := []string{"enter box", "exit box", "enter box"}; !reflect.DeepEqual(*events, want) {
+		t.Fatalf("events %v after the recycle, want %v", *events, want)
+	}
+	if tvs.GetTriggerVolume("box").indexOf(first) < 0 {
+		t.Fatal("the recycled throwable is not an occupant of the volume")
+	}
+}
@@ -42,10 +42,11 @@ func NewViewMatrix() *ViewMatrix {
 }
 
 // Update updates the ViewMatrix's position, orientation, sector, and lighting based on the given ThingPlayer's state.
-func (vi *ViewMatrix) Update(player *ThingPlayer) {
+// alpha blends the player's position between the last two physics states (1.0 uses the latest state).
+func (vi *ViewMatrix) Update(player *ThingPlayer, alpha float64) {
 	vi.angleSin, vi.angleCos = player.GetAngleFull()
 	vi.location = player.GetLocation()
-	vi.view.X, vi.view.Y, vi.view.Z = player.GetVisualPosition()
+	vi.view.X, vi.view.Y, vi.view.Z = player.GetVisualPositionLerp(alpha)
 	vi.pitch = player.GetPitch()
 	vi.angle = player.GetAngle()
 	vi.roll = player.GetTilt()
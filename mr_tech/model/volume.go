@@ -3,8 +3,10 @@ package model
 import (
 	"math"
 
+	"github.com/markel1974/godoom/mr_tech/config"
 	"github.com/markel1974/godoom/mr_tech/geometry"
 	"github.com/markel1974/godoom/mr_tech/physics"
+	"github.com/markel1974/godoom/mr_tech/textures"
 )
 
 // Volume represents a 3D navigable space (a region, brush, or room), defined by geometric faces, materials, and associated properties.
@@ -14,12 +16,15 @@ type Volume struct {
 	faces     []*Face
 	facesPtr  *[]*Face
 	faceCount int
+	indexed   int
 	tag       string
 	light     *Light
 	entity    *physics.Entity
 	facesTree *physics.AABBTree
 	thing     IThing
 	sector    *Sector
+	layer     config.CollisionLayer
+	mask      config.CollisionLayer
 }
 
 // NewVolume creates a new 3D Volume instance with specified properties, including position, size, and physics attributes.
@@ -32,6 +37,8 @@ func NewVolume(modelId int, id string, tag string, mass, restitution, friction,
 		faceCount: 0,
 		entity:    physics.NewEntity(mass, restitution, friction, gForce),
 		facesTree: physics.NewAABBTree(64, 0.0),
+		layer:     config.LayerWorld,
+		mask:      config.LayerAll,
 	}
 	v.facesPtr = &v.faces
 	return v
@@ -47,17 +54,41 @@ func NewVolumeConcrete(modelId int, id string, tag string) *Volume {
 
 // Rebuild recalculates the axis-aligned bounding box (AABB) for the location based on its faces and dimensions.
 func (v *Volume) Rebuild() bool {
-	minX, minY, minZ := math.MaxFloat64, math.MaxFloat64, math.MaxFloat64
-	maxX, maxY, maxZ := -math.MaxFloat64, -math.MaxFloat64, -math.MaxFloat64
-
 	v.facesTree.Clear()
-
 	for x := 0; x < v.faceCount; x++ {
 		face := v.faces[x]
 		face.Rebuild()
 		v.facesTree.InsertObject(face)
+	}
+	v.indexed = v.faceCount
+	v.rebuildBounds()
+	return true
+}
 
-		for _, p := range face.GetPoints() {
+// Refit recalculates the bounds of the Volume after its faces have been updated in place with PutFace: the tree
+// entries of the faces still in use are refitted, the new faces are inserted and the ones no longer in use removed.
+func (v *Volume) Refit() {
+	for x := 0; x < v.faceCount; x++ {
+		if x < v.indexed {
+			v.facesTree.RefitObject(v.faces[x])
+		} else {
+			v.facesTree.InsertObject(v.faces[x])
+		}
+	}
+	for x := v.faceCount; x < v.indexed; x++ {
+		v.facesTree.RemoveObject(v.faces[x])
+	}
+	v.indexed = v.faceCount
+	v.rebuildBounds()
+}
+
+// rebuildBounds recalculates the bounds of the entity of the Volume from the points of its faces.
+func (v *Volume) rebuildBounds() {
+	minX, minY, minZ := math.MaxFloat64, math.MaxFloat64, math.MaxFloat64
+	maxX, maxY, maxZ := -math.MaxFloat64, -math.MaxFloat64, -math.MaxFloat64
+
+	for x := 0; x < v.faceCount; x++ {
+		for _, p := range v.faces[x].GetPoints() {
 			if p.X < minX {
 				minX = p.X
 			}
@@ -84,8 +115,6 @@ func (v *Volume) Rebuild() bool {
 
 	v.entity.Rebuild(minX, minY, minZ, w, h, d)
 	//v.entity.GetAABB().Rebuild(minX, minY, minZ, maxX, maxY, maxZ)
-
-	return true
 }
 
 // SetThing sets the IThing instance associated with the Volume.
@@ -98,6 +127,21 @@ func (v *Volume) GetThing() IThing {
 	return v.thing
 }
 
+// GetCollision returns the collision layer of the Volume and the categories it blocks.
+func (v *Volume) GetCollision() (config.CollisionLayer, config.CollisionLayer) {
+	return v.layer, v.mask
+}
+
+// SetCollision sets the collision layer of the Volume and the categories it blocks; LayerNone keeps the current value.
+func (v *Volume) SetCollision(layer, mask config.CollisionLayer) {
+	if layer != config.LayerNone {
+		v.layer = layer
+	}
+	if mask != config.LayerNone {
+		v.mask = mask
+	}
+}
+
 func (v *Volume) GetEntity() *physics.Entity {
 	return v.entity
 }
@@ -135,6 +179,19 @@ func (v *Volume) AddFace(face *Face) {
 	v.faceCount++
 }
 
+// PutFace appends the triangle to the faces of the Volume, reusing the Face held at the same position before the last
+// ClearFaces when there is one, so that the geometry of a moving sector is updated without allocations.
+func (v *Volume) PutFace(tri [3]geometry.XYZ, tag string, material *textures.Material) {
+	if v.faceCount < len(v.faces) {
+		if face := v.faces[v.faceCount]; face != nil {
+			face.Reset(tri, tag, material)
+			v.faceCount++
+			return
+		}
+	}
+	v.AddFace(NewFace(tri, tag, material))
+}
+
 // ClearFaces resets the face count of the Volume to zero, effectively removing all associated faces.
 func (v *Volume) ClearFaces() {
 	v.faceCount = 0
@@ -210,6 +267,14 @@ func (v *Volume) QueryPoint(px, py, pz float64, callback func(object physics.IAA
 	v.facesTree.QueryPoint3d(px, py, pz, callback)
 }
 
+// QueryRay invokes the callback for each face whose bounding box is crossed by the ray within maxDistance, passing the
+// distance of the box; the callback may shrink maxDistance, as in physics.AABBTree.QueryRay.
+func (v *Volume) QueryRay(oX, oY, oZ, dirX, dirY, dirZ float64, maxDistance float64, callback func(face *Face, distance float64) (float64, bool)) {
+	v.facesTree.QueryRay(oX, oY, oZ, dirX, dirY, dirZ, maxDistance, func(object physics.IAABB, distance float64) (float64, bool) {
+		return callback(object.(*Face), distance)
+	})
+}
+
 /*
 // PointInside3d determines if the point (px, py, pz) lies inside the 3D location, considering optional fixed Z bounds.
 func (v *Volume) PointInside3d(px, py, pz float64) bool {
@@ -35,6 +35,15 @@ func NewFace(tri [3]geometry.XYZ, tag string, material *textures.Material) *Face
 	return out
 }
 
+// Reset replaces the triangle, tag and material of the face and recomputes its normal, AABB and UV, as NewFace does.
+func (s *Face) Reset(tri [3]geometry.XYZ, tag string, material *textures.Material) {
+	s.tag = tag
+	s.material = material
+	s.tri = tri
+	s.lockUV = false
+	s.Rebuild()
+}
+
 // LockUV locks or unlocks the UV coordinates of a Face based on the provided staticUV parameter.
 func (s *Face) LockUV(lockUV bool) {
 	s.lockUV = lockUV
@@ -226,7 +235,57 @@ func (s *Face) computeUV() {
 	}
 }
 
-/*
+// IntersectRay returns the distance, in units of the direction (dX, dY, dZ), from the origin (oX, oY, oZ) to the point
+// where the ray crosses the triangle from either side.
+func (s *Face) IntersectRay(oX, oY, oZ, dX, dY, dZ float64) (float64, bool) {
+	p0, p1, p2 := s.trianglePoints()
+	return physics.IntersectRayTriangle(oX, oY, oZ, dX, dY, dZ, p0, p1, p2)
+}
+
+// ClosestPoint returns the point of the triangle closest to (px, py, pz).
+func (s *Face) ClosestPoint(px, py, pz float64) (float64, float64, float64) {
+	p0, p1, p2 := s.trianglePoints()
+	return physics.ClosestPointTriangle(px, py, pz, p0, p1, p2)
+}
+
+// OverlapsAABB reports whether the triangle intersects the box aabb.
+func (s *Face) OverlapsAABB(aabb *physics.AABB) bool {
+	p0, p1, p2 := s.trianglePoints()
+	return aabb.OverlapsTriangle(p0, p1, p2)
+}
+
+// trianglePoints returns the vertices of the triangle as physics points.
+func (s *Face) trianglePoints() (physics.Point, physics.Point, physics.Point) {
+	return physics.NewPoint(s.tri[0].X, s.tri[0].Y, s.tri[0].Z),
+		physics.NewPoint(s.tri[1].X, s.tri[1].Y, s.tri[1].Z),
+		physics.NewPoint(s.tri[2].X, s.tri[2].Y, s.tri[2].Z)
+}
+
+// SweepAABB sweeps the box aabb by (vx, vy, vz) against the triangle. It returns the time of impact in [0, 1] and the
+// face normal, turned against the motion, when the box hits the inside of the triangle.
+func (s *Face) SweepAABB(aabb *physics.AABB, vx, vy, vz float64) (float64, float64, float64, float64, bool) {
+	if s.n.X == 0 && s.n.Y == 0 && s.n.Z == 0 {
+		return 1.0, 0, 0, 0, false // triangolo degenere
+	}
+	p0 := physics.NewPoint(s.tri[0].X, s.tri[0].Y, s.tri[0].Z)
+	p1 := physics.NewPoint(s.tri[1].X, s.tri[1].Y, s.tri[1].Z)
+	p2 := physics.NewPoint(s.tri[2].X, s.tri[2].Y, s.tri[2].Z)
+	normal := physics.NewPoint(s.n.X, s.n.Y, s.n.Z)
+	toi, _, hit := aabb.SweepAABB(vx, vy, vz, p0, p1, p2, normal)
+	if !hit {
+		return 1.0, 0, 0, 0, false
+	}
+	nX, nY, nZ := s.n.X, s.n.Y, s.n.Z
+	if vx*nX+vy*nY+vz*nZ > 0 {
+		nX, nY, nZ = -nX, -nY, -nZ
+	}
+	return toi, nX, nY, nZ, true
+}
+
+// SweepTest performs a swept ellipsoid-vs-triangle test: the ellipsoid of radii (eRadX, eRadY, eRadZ), centered in
+// (viewX, viewY, viewZ), moves by (velX, velY, velZ) during the step. It returns the time of impact in [0, 1],
+// backed off by a small epsilon, and the contact normal, facing the motion, of the first hit of the triangle plane,
+// edges or vertices.
 func (s *Face) SweepTest(viewX, viewY, viewZ, velX, velY, velZ, eRadX, eRadY, eRadZ float64) (float64, float64, float64, float64, bool) {
 	// ==========================================
 	// 1. TRASFORMAZIONE E-SPACE ASSOLUTA
@@ -466,8 +525,6 @@ RESOLVE:
 	return 0, 0, 0, 0, false
 }
 
-*/
-
 /*
 // PointInside3d determina se il punto 3D (px, py, pz) giace all'interno del triangolo.
 // Utilizza il calcolo delle Coordinate Baricentriche per la massima efficienza.
@@ -3,6 +3,7 @@ package model
 import (
 	"math"
 
+	"github.com/markel1974/godoom/mr_tech/config"
 	"github.com/markel1974/godoom/mr_tech/physics"
 )
 
@@ -36,6 +37,11 @@ func (s *Volumes) Setup() {
 	}
 }
 
+// Update refits the entry of the volume inside the spatial tree after its geometry has been updated.
+func (s *Volumes) Update(volume *Volume) {
+	s.tree.RefitObject(volume)
+}
+
 // GetVolume retrieves a Volume from the cache using the provided unique identifier.
 func (s *Volumes) GetVolume(id string) *Volume {
 	return s.cache[id]
@@ -66,9 +72,14 @@ func (s *Volumes) Query(aabb physics.IAABB) []*Volume {
 }
 
 // QueryCollisionCage evaluates 3D collision data within a given cage and applies spatial filters, assigning results into buckets.
+// Volumes whose layer and mask exclude the thing of the cage are skipped.
 func (s *Volumes) QueryCollisionCage(cage *CollisionCage) {
+	base := cage.GetThing().GetBase()
 	s.tree.QueryOverlaps(cage, func(object physics.IAABB) bool {
 		vol := object.(*Volume)
+		if !base.CollidesWithVolume(vol) {
+			return false
+		}
 		vol.QueryOverlaps(cage, func(otherEnt physics.IAABB) bool {
 			rFace := otherEnt.(*Face)
 			cage.AddFace(rFace, nil)
@@ -91,8 +102,16 @@ func (s *Volumes) QueryMultiFrustum(front, rear *physics.Frustum, callback func(
 
 // QueryRay performs a raycasting query starting from origin (oX, oY, oZ) in direction (dirX, dirY, dirZ) up to maxDistance.
 // It invokes the callback for each intersected object, passing the object and intersection distance as arguments.
-func (s *Volumes) QueryRay(oX, oY, oZ, dirX, dirY, dirZ float64, maxDistance float64, callback func(object physics.IAABB, distance float64) (float64, bool)) {
-	s.tree.QueryRay(oX, oY, oZ, dirX, dirY, dirZ, maxDistance, callback)
+// The volumes that do not collide with a ray of the given layer and mask are skipped.
+func (s *Volumes) QueryRay(oX, oY, oZ, dirX, dirY, dirZ float64, maxDistance float64, layer, mask config.CollisionLayer, callback func(object physics.IAABB, distance float64) (float64, bool)) {
+	s.tree.QueryRay(oX, oY, oZ, dirX, dirY, dirZ, maxDistance, func(object physics.IAABB, distance float64) (float64, bool) {
+		if vol, ok := object.(*Volume); ok {
+			if vLayer, vMask := vol.GetCollision(); !config.Collides(layer, mask, vLayer, vMask) {
+				return maxDistance, false
+			}
+		}
+		return callback(object, distance)
+	})
 }
 
 // QueryPoint identifies the 3D location and specific face at the given point (px, py, pz) in world coordinates.
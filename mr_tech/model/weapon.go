@@ -0,0 +1,305 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/geometry"
+)
+
+// WeaponState identifies the state of the weapon state machine of the player.
+type WeaponState int
+
+// WeaponReady accepts a trigger pull.
+// WeaponFiring waits for the cooldown of the last shot (1 / FireRate).
+// WeaponSwitching waits for the current weapon to be raised after a selection.
+// WeaponEmpty is entered when the trigger is pulled without enough ammo, until some ammo is collected.
+const (
+	WeaponReady WeaponState = iota
+	WeaponFiring
+	WeaponSwitching
+	WeaponEmpty
+)
+
+// weaponSwitchTime is the time, in seconds, needed to raise a newly selected weapon.
+const weaponSwitchTime = 0.3
+
+// String returns the name of the weapon state.
+func (s WeaponState) String() string {
+	switch s {
+	case WeaponReady:
+		return "ready"
+	case WeaponFiring:
+		return "firing"
+	case WeaponSwitching:
+		return "switching"
+	case WeaponEmpty:
+		return "empty"
+	}
+	return "unknown"
+}
+
+// Weapon is the runtime state of a weapon definition: the time elapsed since its last shot drives the view model.
+type Weapon struct {
+	cfg      *config.Weapon
+	animTime float64
+}
+
+// NewWeapon creates the runtime Weapon of the given definition.
+func NewWeapon(cfg *config.Weapon) *Weapon {
+	return &Weapon{
+		cfg:      cfg,
+		animTime: math.MaxFloat64,
+	}
+}
+
+// GetId returns the normalized id of the weapon.
+func (w *Weapon) GetId() string {
+	return normalizeItemId(w.cfg.Id)
+}
+
+// GetConfig returns the definition of the weapon.
+func (w *Weapon) GetConfig() *config.Weapon {
+	return w.cfg
+}
+
+// GetViewFrame returns the view model material to display and its frame: the fire animation, played once after
+// each shot, or the idle material. The material is nil when the weapon has no view model.
+func (w *Weapon) GetViewFrame() (*config.Material, int) {
+	vm := w.cfg.ViewModel
+	if vm == nil {
+		return nil, 0
+	}
+	if vm.Fire != nil && vm.FrameTime > 0 {
+		if frame := int(w.animTime / vm.FrameTime); frame < len(vm.Fire.Frames) {
+			return vm.Fire, frame
+		}
+	}
+	return vm.Idle, 0
+}
+
+// Arsenal is the weapon state machine of the player: it owns the weapon definitions, the selected weapon and the
+// pending trigger pulls, and fires the shots through the player at each simulation step.
+type Arsenal struct {
+	container []*Weapon
+	current   int
+	state     WeaponState
+	timer     float64
+	trigger   bool
+	alternate bool
+	rnd       *rand.Rand
+}
+
+// NewArsenal creates the Arsenal of the given weapon definitions, selecting the first one owned in the inventory. The
+// spread of the shots is drawn from a generator initialized with seed, so that the same inputs replay the same shots.
+func NewArsenal(cfg []*config.Weapon, inventory *Inventory, seed int64) *Arsenal {
+	a := &Arsenal{
+		current: -1,
+		state:   WeaponReady,
+		rnd:     rand.New(rand.NewSource(seed)),
+	}
+	for _, wc := range cfg {
+		if wc == nil {
+			continue
+		}
+		a.container = append(a.container, NewWeapon(wc))
+	}
+	for idx, w := range a.container {
+		if inventory.HasWeapon(w.GetId()) {
+			a.current = idx
+			break
+		}
+	}
+	return a
+}
+
+// GetCurrent returns the selected weapon, or nil if the player owns none.
+func (a *Arsenal) GetCurrent() *Weapon {
+	if a.current < 0 {
+		return nil
+	}
+	return a.container[a.current]
+}
+
+// GetState returns the state of the state machine.
+func (a *Arsenal) GetState() WeaponState {
+	return a.state
+}
+
+// GetWeapons returns all the weapon definitions, owned or not.
+func (a *Arsenal) GetWeapons() []*Weapon {
+	return a.container
+}
+
+// Select switches to the weapon with the given id, returning false if it is unknown or not owned.
+func (a *Arsenal) Select(id string, inventory *Inventory) bool {
+	id = normalizeItemId(id)
+	for idx, w := range a.container {
+		if w.GetId() == id && inventory.HasWeapon(id) {
+			a.switchTo(idx)
+			return true
+		}
+	}
+	return false
+}
+
+// Next switches to the next owned weapon, wrapping around.
+func (a *Arsenal) Next(inventory *Inventory) {
+	count := len(a.container)
+	for step := 1; step < count; step++ {
+		idx := (a.current + step + count) % count
+		if inventory.HasWeapon(a.container[idx].GetId()) {
+			a.switchTo(idx)
+			return
+		}
+	}
+}
+
+// switchTo selects the weapon at index, which must be raised before it can fire.
+func (a *Arsenal) switchTo(idx int) {
+	if idx == a.current {
+		return
+	}
+	a.current = idx
+	a.state = WeaponSwitching
+	a.timer = weaponSwitchTime
+	a.trigger = false
+}
+
+// Trigger requests a shot of the selected weapon. The request is consumed by the next simulation step.
+func (a *Arsenal) Trigger() {
+	a.trigger = true
+}
+
+// TriggerAlternate requests a shot of the first owned projectile weapon, regardless of the selected one. The request
+// is consumed by the next simulation step.
+func (a *Arsenal) TriggerAlternate() {
+	a.alternate = true
+}
+
+// Compute advances the state machine by dt seconds and fires the pending trigger pulls through the player.
+func (a *Arsenal) Compute(p *ThingPlayer, dt float64) {
+	for _, w := range a.container {
+		if w.animTime < math.MaxFloat64 {
+			w.animTime += dt
+		}
+	}
+	trigger, alternate := a.trigger, a.alternate
+	a.trigger, a.alternate = false, false
+	if a.timer > 0 {
+		a.timer -= dt
+	}
+	switch a.state {
+	case WeaponFiring, WeaponSwitching:
+		if a.timer > 0 {
+			return
+		}
+		a.state = WeaponReady
+	case WeaponEmpty:
+		if w := a.GetCurrent(); w != nil && a.hasAmmo(p.inventory, w.cfg) {
+			a.state = WeaponReady
+		}
+	}
+	if p.IsDead() || a.state != WeaponReady {
+		return
+	}
+	var w *Weapon
+	if alternate {
+		w = a.alternateWeapon(p.inventory)
+	}
+	if w == nil && trigger {
+		w = a.GetCurrent()
+	}
+	if w == nil {
+		return
+	}
+	if !a.hasAmmo(p.inventory, w.cfg) || !p.inventory.UseAmmo(w.cfg.Ammo, a.ammoPerShot(w.cfg)) {
+		if w == a.GetCurrent() {
+			a.state = WeaponEmpty
+		}
+		return
+	}
+	a.shoot(p, w)
+	w.animTime = 0
+	a.state = WeaponFiring
+	a.timer = 0
+	if w.cfg.FireRate > 0 {
+		a.timer = 1.0 / w.cfg.FireRate
+	}
+}
+
+// alternateWeapon returns the first owned projectile weapon, or nil.
+func (a *Arsenal) alternateWeapon(inventory *Inventory) *Weapon {
+	for _, w := range a.container {
+		if w.cfg.Kind == config.WeaponProjectile && inventory.HasWeapon(w.GetId()) {
+			return w
+		}
+	}
+	return nil
+}
+
+// hasAmmo reports whether the inventory holds the ammo for a shot of the weapon.
+func (a *Arsenal) hasAmmo(inventory *Inventory, cfg *config.Weapon) bool {
+	if cfg.Ammo == "" {
+		return true
+	}
+	return inventory.GetAmmo(cfg.Ammo) >= a.ammoPerShot(cfg)
+}
+
+// ammoPerShot returns the ammo used by a shot of the weapon, none when the weapon has no ammo type.
+func (a *Arsenal) ammoPerShot(cfg *config.Weapon) float64 {
+	if cfg.Ammo == "" {
+		return 0
+	}
+	return cfg.AmmoPerShot
+}
+
+// shoot fires every pellet of the weapon from its muzzle, each deviated randomly by up to the weapon spread.
+func (a *Arsenal) shoot(p *ThingPlayer, w *Weapon) {
+	cfg := w.cfg
+	camX, camY, camZ := p.GetVisualPosition()
+	width := p.GetEntity().GetWidth()
+	// Muzzle: avanti e a destra in larghezze del player, in alto come frazione dell'altezza degli occhi
+	forward := cfg.Muzzle.X * width
+	right := cfg.Muzzle.Y * width
+	pos := geometry.XYZ{
+		X: camX + p.angleCos*forward + p.angleSin*right,
+		Y: camY + p.angleSin*forward - p.angleCos*right,
+		Z: camZ + cfg.Muzzle.Z*p.getEyeHeight(),
+	}
+	pellets := cfg.Pellets
+	if pellets < 1 {
+		pellets = 1
+	}
+	for x := 0; x < pellets; x++ {
+		yaw := p.angle
+		pitch := -p.pitch
+		if cfg.Spread > 0 {
+			yaw += (a.rnd.Float64()*2 - 1) * cfg.Spread
+			pitch += (a.rnd.Float64()*2 - 1) * cfg.Spread
+		}
+		switch cfg.Kind {
+		case config.WeaponProjectile:
+			src := cfg.Projectile
+			if src == nil {
+				src = p.things.GetConfigById(cfg.ProjectileId)
+			}
+			if src == nil {
+				fmt.Printf("Warning weapon %s has no projectile\n", cfg.Id)
+				return
+			}
+			projectile := &Projectile{Owner: p, DamageType: cfg.DamageType, Damage: cfg.Damage, Force: cfg.Force}
+			p.things.CreateProjectile(src, projectile, p.location, pos, yaw, pitch, cfg.ProjectileSpeed)
+		default:
+			dirX := math.Cos(yaw) * math.Cos(pitch)
+			dirY := math.Sin(yaw) * math.Cos(pitch)
+			dirZ := math.Sin(pitch)
+			// La faccia colpita per prima attiva gli special a sparo
+			if hit, ok := p.FireHitscan(cfg.DamageType, pos, cfg.Damage, cfg.Force, cfg.Range, dirX, dirY, dirZ); ok && hit.Face != nil {
+				p.shots = append(p.shots, hit.Face)
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/markel1974/godoom/mr_tech/config"
+	"github.com/markel1974/godoom/mr_tech/generators/wolfstein"
+)
+
+// newTestWorld compiles the first Wolfenstein level, after edit has changed its configuration.
+func newTestWorld(tb testing.TB, edit func(cfg *config.Root)) *Compiler {
+	tb.Helper()
+	cfg, err := wolfstein.NewBuilder().Build(1)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if edit != nil {
+		edit(cfg)
+	}
+	c := NewCompiler()
+	if err = c.Compile(cfg); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(c.GetThings().Close)
+	return c
+}
+
+// stepWorld advances the movers, the elevators, the spawners and the things of the world by the given number of
+// steps, as the engine does.
+func stepWorld(c *Compiler, steps int) {
+	things := c.GetThings()
+	dt := things.GetTimeStep()
+	for x := 0; x < steps; x++ {
+		pX, pY, pZ := c.GetPlayer().GetEntity().GetCenter()
+		c.GetMovers().Compute(dt)
+		c.GetElevators().Compute(dt)
+		c.GetSpawners().Compute(dt)
+		things.Compute(pX, pY, pZ)
+	}
+}
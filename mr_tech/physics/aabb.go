@@ -1,5 +1,7 @@
 package physics
 
+import "math"
+
 // IAABB represents an interface defining objects that can provide an Axis-Aligned Bounding Box (AABB).
 // GetAABB retrieves the AABB associated with the object implementing the IAABB interface.
 type IAABB interface {
@@ -231,7 +233,6 @@ func (a *AABB) IntersectFrustum(f *Frustum) bool {
 	return true
 }
 
-/*
 // SweepAABB performs a Continuous Collision Detection (CCD) sweep of a moving AABB against a 3D triangle.
 // It uses the Minkowski sum approach to expand the triangle by the AABB's half-extents.
 // Returns:
@@ -327,4 +328,3 @@ func (a *AABB) SweepAABB(vx, vy, vz float64, p0, p1, p2, normal Point) (float64,
 	// return frontal hit.
 	return tHit, normal, true
 }
-*/
@@ -106,6 +106,16 @@ func (a *AABBTree) UpdateObject(object IAABB) {
 	}
 }
 
+// RefitObject fits the leaf of object to its current AABB and refits the bounds of its ancestors, keeping the topology
+// of the tree: it suits objects that move within a limited range, like the faces of a moving sector.
+func (a *AABBTree) RefitObject(object IAABB) {
+	if nodeIndex, ok := a.objectNodeIndexMap[object]; ok {
+		node := a.nodes[nodeIndex]
+		node.aabb.ExpandInPlace(object.GetAABB(), a.margin)
+		a.fixUpwardsTree(node.parentNodeIndex)
+	}
+}
+
 // QueryOverlaps identifies and returns all objects in the tree whose AABBs overlap with the given object's AABB.
 func (a *AABBTree) QueryOverlaps(object IAABB, callback func(object IAABB) bool) {
 	testAabb := object.GetAABB()
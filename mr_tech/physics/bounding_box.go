@@ -5,20 +5,26 @@ import "math"
 // BoundingBox represents a 3D rectangular region defined by its position, dimensions, and axis-aligned bounding box (AABB).
 type BoundingBox struct {
 	bottomLeft   Point
+	previous     Point
 	bottomCenter Point
 	center       Point
 	size         Size
 	aabb         *AABB
+	orientation  Quaternion
+	obb          *OBB
 }
 
 // NewBoundingBox initializes and returns a pointer to a BoundingBox with the specified position, dimensions, and depth.
 func NewBoundingBox(x, y, w, h, z, d float64) *BoundingBox {
 	r := &BoundingBox{
 		bottomLeft:   NewPoint(x, y, z),
+		previous:     NewPoint(x, y, z),
 		bottomCenter: NewPoint(0, 0, 0),
 		center:       NewPoint(0, 0, 0),
 		size:         NewSize(w, h, d),
 		aabb:         NewAABB(),
+		orientation:  NewQuaternionIdentity(),
+		obb:          nil,
 	}
 	r.rebuild()
 	return r
@@ -42,12 +48,56 @@ func (r *BoundingBox) rebuild() {
 	maxX := r.bottomLeft.x + r.size.w
 	maxY := r.bottomLeft.y + r.size.h
 	maxZ := r.bottomLeft.z + r.size.d
-	r.aabb.Rebuild(minX, minY, minZ, maxX, maxY, maxZ)
+	if r.obb == nil {
+		r.aabb.Rebuild(minX, minY, minZ, maxX, maxY, maxZ)
+		return
+	}
+	// Box orientato: l'AABB diventa l'involucro dell'OBB per la broad-phase
+	r.obb.Rebuild(r.center.x, r.center.y, r.center.z, cw, ch, cd, r.orientation)
+	r.obb.EnclosingAABB(r.aabb)
 }
 
 // GetAABB returns the axis-aligned bounding box (AABB) of the BoundingBox.
+// When the box is oriented, the AABB encloses the rotated box.
 func (r *BoundingBox) GetAABB() *AABB { return r.aabb }
 
+// GetOBB returns the oriented bounding box of the BoundingBox, or nil if the box is axis-aligned.
+func (r *BoundingBox) GetOBB() *OBB { return r.obb }
+
+// GetOrientation returns the orientation of the box around its center.
+func (r *BoundingBox) GetOrientation() Quaternion { return r.orientation }
+
+// IsOriented reports whether the box is rotated around its center.
+func (r *BoundingBox) IsOriented() bool { return r.obb != nil }
+
+// SweptOBB rebuilds dst as the oriented box enclosing the box moved by (dx, dy, dz). An axis-aligned box yields an
+// OBB with the world axes.
+func (r *BoundingBox) SweptOBB(dst *OBB, dx, dy, dz float64) {
+	if r.obb != nil {
+		*dst = *r.obb
+	} else {
+		dst.RebuildFromAABB(r.aabb)
+	}
+	dst.Sweep(dx, dy, dz)
+}
+
+// SetOrientation rotates the box around its center. The bottom-left corner and the size keep describing the
+// unrotated box, while the AABB is rebuilt to enclose the rotated one.
+func (r *BoundingBox) SetOrientation(q Quaternion) {
+	r.orientation = q
+	if r.obb == nil {
+		r.obb = NewOBB()
+	}
+	r.rebuild()
+}
+
+// ClearOrientation makes the box axis-aligned again.
+func (r *BoundingBox) ClearOrientation() {
+	r.orientation = NewQuaternionIdentity()
+	r.obb = nil
+	r.rebuild()
+}
+
 // GetWidth returns the width of the bounding box as a float64 value.
 func (r *BoundingBox) GetWidth() float64 { return r.size.GetWidth() }
 
@@ -119,6 +169,21 @@ func (r *BoundingBox) MoveToZ(z float64) {
 	r.rebuild()
 }
 
+// Snapshot stores the current bottom-left corner as the previous simulation state used for interpolation.
+func (r *BoundingBox) Snapshot() {
+	r.previous = r.bottomLeft
+}
+
+// GetBottomCenterLerp returns the bottom-center point blended between the previous snapshot and the current state.
+// alpha is the interpolation factor in [0, 1], where 0 is the previous state and 1 the current one.
+func (r *BoundingBox) GetBottomCenterLerp(alpha float64) (float64, float64, float64) {
+	cw, ch, _ := r.size.GetCenter()
+	x := r.previous.x + (r.bottomLeft.x-r.previous.x)*alpha
+	y := r.previous.y + (r.bottomLeft.y-r.previous.y)*alpha
+	z := r.previous.z + (r.bottomLeft.z-r.previous.z)*alpha
+	return x + cw, y + ch, z
+}
+
 // MoveTest computes new coordinates by adding the given offsets to the BoundingBox's bottom-left corner position.
 func (r *BoundingBox) MoveTest(vx, vy, vz float64) (float64, float64, float64) {
 	x := r.bottomLeft.x + vx
@@ -18,6 +18,10 @@ Architetturalmente l'ECS-lite (Cinematic + BoundingBox) ti garantisce stabilità
 Se il motore è destinato a muovere entità orientate assialmente (come giocatori FPS, mostri, proiettili o veicoli hover),
 il core matematico è completo e corretto. Se invece in futuro vorrai simulare scatole che ruzzolano o ragdoll,
 l'architettura dovrà espandere il modulo Cinematic per includere i quaternioni e la matrice del tensore d'inerzia.
+
+Nota: la dinamica rotazionale è ora disponibile come stato opzionale (rotation.go, EnableRotation): orientamento
+in quaternione, velocità angolare e tensore d'inerzia di un parallelepipedo, con gli impulsi applicati nel punto di
+contatto stimato sull'OBB. Senza stato rotazionale il comportamento resta quello del punto materiale.
 */
 
 const (
@@ -57,6 +61,7 @@ type Cinematic struct {
 	dt                float64
 	terminalZVelocity float64
 	onGround          bool
+	rotation          *rotation
 }
 
 // NewCinematic initializes a new Cinematic instance with specified mass, restitution, ground friction, and gravitational force.
@@ -104,11 +109,12 @@ func (e *Cinematic) SetOptions(dt, gFriction, aFriction float64) {
 	e.SetOnGround(e.onGround)
 }
 
-// Stop halts the entity's motion by setting its velocity components (vx, vy, vz) to zero.
+// Stop halts the entity's motion by setting its velocity components (vx, vy, vz) and its angular velocity to zero.
 func (e *Cinematic) Stop() {
 	e.vx = 0.0
 	e.vy = 0.0
 	e.vz = 0.0
+	e.SetAngularVelocity(0.0, 0.0, 0.0)
 }
 
 // SetDt updates the time step (dt) and recalculates damping and velocity limits based on current friction values.
@@ -221,6 +227,23 @@ func (e *Cinematic) IsMoving() bool {
 	return e.vx != 0 || e.vy != 0 || e.vz != 0
 }
 
+// HasForce reports whether a force or a torque has been accumulated since the last Update.
+func (e *Cinematic) HasForce() bool {
+	if e.ax != 0 || e.ay != 0 || e.az != 0 {
+		return true
+	}
+	return e.rotation != nil && (e.rotation.tx != 0 || e.rotation.ty != 0 || e.rotation.tz != 0)
+}
+
+// GetSpeedSq returns the squared linear speed of the Cinematic plus, for a rotational Cinematic, its squared angular speed.
+func (e *Cinematic) GetSpeedSq() float64 {
+	speedSq := e.vx*e.vx + e.vy*e.vy + e.vz*e.vz
+	if e.rotation != nil {
+		speedSq += e.rotation.wx*e.rotation.wx + e.rotation.wy*e.rotation.wy + e.rotation.wz*e.rotation.wz
+	}
+	return speedSq
+}
+
 // AddForce applies a force to the object by modifying its acceleration components (ax, ay, az) using the force and inverse mass.
 func (e *Cinematic) AddForce(fx, fy, fz float64) {
 	e.ax += fx * e.invMass
@@ -231,6 +254,9 @@ func (e *Cinematic) AddForce(fx, fy, fz float64) {
 // Update updates the state of the Cinematic object by integrating acceleration, velocity, and damping forces over time.
 func (e *Cinematic) Update() {
 	const sleepEpsilon = 0.005
+	if e.rotation != nil {
+		e.updateRotation()
+	}
 	e.vx += e.ax * e.dt
 	e.vy += e.ay * e.dt
 	gForce := e.gForce
@@ -269,6 +295,8 @@ func (e *Cinematic) Update() {
 }
 
 // ResolveImpact resolves a collision between two Cinematic objects by applying normal and tangential impulses for both.
+// The impulses are applied at the contact points estimated from the oriented boxes of the rotational objects, so that
+// an impact off the center of mass makes them spin; objects without a rotational state are hit at their center.
 func (e *Cinematic) ResolveImpact(e2 *Cinematic, nx, ny, nz float64, _ float64) {
 	// safe normalization (anti-explosion)
 	nLen := math.Sqrt(nx*nx + ny*ny + nz*nz)
@@ -280,10 +308,21 @@ func (e *Cinematic) ResolveImpact(e2 *Cinematic, nx, ny, nz float64, _ float64)
 		return // null vector, cannot resolve
 	}
 
-	// relative velocity along the normal
-	vrx := e.vx - e2.vx
-	vry := e.vy - e2.vy
-	vrz := e.vz - e2.vz
+	// contact arms: the normal points from e2 towards e. Resting contacts are resolved at the centers, the settling
+	// of the rotational state keeps the bodies on their faces.
+	var r1x, r1y, r1z, r2x, r2y, r2z float64
+	const restingSpeed = 1.0
+	if (e.vx-e2.vx)*nx+(e.vy-e2.vy)*ny+(e.vz-e2.vz)*nz < -restingSpeed {
+		r1x, r1y, r1z = e.contactArm(-nx, -ny, -nz)
+		r2x, r2y, r2z = e2.contactArm(nx, ny, nz)
+	}
+
+	// relative velocity of the contact points along the normal
+	v1x, v1y, v1z := e.pointVelocity(r1x, r1y, r1z)
+	v2x, v2y, v2z := e2.pointVelocity(r2x, r2y, r2z)
+	vrx := v1x - v2x
+	vry := v1y - v2y
+	vrz := v1z - v2z
 	vRelDotN := vrx*nx + vry*ny + vrz*nz
 
 	// if bodies are separating, no collision to resolve
@@ -312,21 +351,20 @@ func (e *Cinematic) ResolveImpact(e2 *Cinematic, nx, ny, nz float64, _ float64)
 
 	// normal impulse (pure, without baumgarte bias)
 	impulse := -(1.0 + actualRestitution) * vRelDotN
-	j := impulse / invMassSum
+	kn := invMassSum + e.angularMass(r1x, r1y, r1z, nx, ny, nz) + e2.angularMass(r2x, r2y, r2z, nx, ny, nz)
+	j := impulse / kn
 
 	// apply normal impulse
-	e.vx += (j * nx) * e.invMass
-	e.vy += (j * ny) * e.invMass
-	e.vz += (j * nz) * e.invMass
-	e2.vx -= (j * nx) * e2.invMass
-	e2.vy -= (j * ny) * e2.invMass
-	e2.vz -= (j * nz) * e2.invMass
+	e.ApplyImpulseAt(j*nx, j*ny, j*nz, r1x, r1y, r1z)
+	e2.ApplyImpulseAt(-j*nx, -j*ny, -j*nz, r2x, r2y, r2z)
 
 	// tangential impulse (friction)
 	// recalculates relative velocity after normal impulse
-	vrx = e.vx - e2.vx
-	vry = e.vy - e2.vy
-	vrz = e.vz - e2.vz
+	v1x, v1y, v1z = e.pointVelocity(r1x, r1y, r1z)
+	v2x, v2y, v2z = e2.pointVelocity(r2x, r2y, r2z)
+	vrx = v1x - v2x
+	vry = v1y - v2y
+	vrz = v1z - v2z
 	vRelDotNPost := vrx*nx + vry*ny + vrz*nz
 
 	// find tangent vector
@@ -340,7 +378,8 @@ func (e *Cinematic) ResolveImpact(e2 *Cinematic, nx, ny, nz float64, _ float64)
 		tz /= tLen
 
 		vRelDotT := vrx*tx + vry*ty + vrz*tz
-		jt := -vRelDotT / invMassSum
+		kt := invMassSum + e.angularMass(r1x, r1y, r1z, tx, ty, tz) + e2.angularMass(r2x, r2y, r2z, tx, ty, tz)
+		jt := -vRelDotT / kt
 
 		// friction mixing: geometric mean of the two friction coefficients
 		mu := math.Sqrt(e.frictionActive * e2.frictionActive)
@@ -352,11 +391,7 @@ func (e *Cinematic) ResolveImpact(e2 *Cinematic, nx, ny, nz float64, _ float64)
 		}
 
 		// apply friction impulse
-		e.vx += (jt * tx) * e.invMass
-		e.vy += (jt * ty) * e.invMass
-		e.vz += (jt * tz) * e.invMass
-		e2.vx -= (jt * tx) * e2.invMass
-		e2.vy -= (jt * ty) * e2.invMass
-		e2.vz -= (jt * tz) * e2.invMass
+		e.ApplyImpulseAt(jt*tx, jt*ty, jt*tz, r1x, r1y, r1z)
+		e2.ApplyImpulseAt(-jt*tx, -jt*ty, -jt*tz, r2x, r2y, r2z)
 	}
 }
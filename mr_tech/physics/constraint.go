@@ -0,0 +1,322 @@
+package physics
+
+import "math"
+
+// ConstraintKind identifies the rule enforced by a Constraint.
+type ConstraintKind int
+
+// ConstraintDistance keeps the two anchors at a fixed distance, like a rigid rod.
+// ConstraintRope keeps the two anchors no farther than the length, like a rope or a chain link: it only pulls.
+// ConstraintSpring pulls the two anchors towards the rest length with a damped spring force.
+// ConstraintHinge joins the two anchors and lets the entities turn only around the hinge axis.
+// ConstraintFixed joins the two anchors and locks the relative rotation of the entities.
+const (
+	ConstraintDistance ConstraintKind = iota
+	ConstraintRope
+	ConstraintSpring
+	ConstraintHinge
+	ConstraintFixed
+)
+
+// constraintBias is the fraction of the position error recovered at each time step (Baumgarte stabilization).
+// constraintSlop is the position error tolerated without correction, to avoid the jitter of a resting joint; the
+// smallest correction (constraintBias * constraintSlop per time step) stays above the rest speed of a Cinematic.
+const (
+	constraintBias = 0.2
+
+	constraintSlop = 0.05
+)
+
+// Constraint binds an entity to a second entity, or to a point of the world when the second entity is nil. The
+// anchors are kept in the local frames of the entities, so that they follow the orientation of the rotational ones.
+// A Constraint is solved with sequential impulses, together with the contacts, at each solver iteration: the
+// velocities are corrected and the residual position error is recovered by a bias velocity.
+type Constraint struct {
+	kind      ConstraintKind
+	a         *Entity
+	b         *Entity
+	anchorA   [3]float64
+	anchorB   [3]float64
+	axis      [3]float64
+	length    float64
+	stiffness float64
+	damping   float64
+}
+
+// NewConstraint creates a Constraint of the given kind between a and b. The anchors are the offsets from the centers
+// of the entities, in world coordinates at the current orientation; when b is nil the second anchor is a point of the
+// world. The length is the current distance between the anchors and the hinge axis is the world Z axis.
+func NewConstraint(kind ConstraintKind, a, b *Entity, ax, ay, az, bx, by, bz float64) *Constraint {
+	c := &Constraint{kind: kind, a: a, b: b}
+	c.anchorA = localOffset(a, ax, ay, az)
+	if b != nil {
+		c.anchorB = localOffset(b, bx, by, bz)
+	} else {
+		c.anchorB = [3]float64{bx, by, bz}
+	}
+	c.SetAxis(0, 0, 1)
+	_, _, _, dx, dy, dz := c.separation()
+	c.length = math.Sqrt(dx*dx + dy*dy + dz*dz)
+	return c
+}
+
+// localOffset converts an offset in world coordinates into the local frame of the entity.
+func localOffset(e *Entity, x, y, z float64) [3]float64 {
+	x, y, z = e.GetOrientation().InverseRotate(x, y, z)
+	return [3]float64{x, y, z}
+}
+
+// GetKind returns the kind of the Constraint.
+func (c *Constraint) GetKind() ConstraintKind {
+	return c.kind
+}
+
+// GetA returns the first entity of the Constraint.
+func (c *Constraint) GetA() *Entity {
+	return c.a
+}
+
+// GetB returns the second entity of the Constraint, nil when it is anchored to the world.
+func (c *Constraint) GetB() *Entity {
+	return c.b
+}
+
+// GetLength returns the length kept by a distance or rope Constraint, or the rest length of a spring.
+func (c *Constraint) GetLength() float64 {
+	return c.length
+}
+
+// SetLength sets the length kept by a distance or rope Constraint, or the rest length of a spring.
+func (c *Constraint) SetLength(length float64) {
+	c.length = math.Max(length, 0)
+}
+
+// SetSpring sets the stiffness, force per unit of stretch, and the damping, force per unit of relative speed, of a
+// spring Constraint.
+func (c *Constraint) SetSpring(stiffness, damping float64) {
+	c.stiffness = math.Max(stiffness, 0)
+	c.damping = math.Max(damping, 0)
+}
+
+// SetAxis sets the hinge axis, in world coordinates at the current orientation of the first entity.
+func (c *Constraint) SetAxis(x, y, z float64) {
+	l := math.Sqrt(x*x + y*y + z*z)
+	if l == 0 {
+		return
+	}
+	c.axis = localOffset(c.a, x/l, y/l, z/l)
+}
+
+// GetAnchors returns the world positions of the two anchors.
+func (c *Constraint) GetAnchors() (float64, float64, float64, float64, float64, float64) {
+	rAx, rAy, rAz, dx, dy, dz := c.separation()
+	aX, aY, aZ := c.a.GetCenter()
+	aX, aY, aZ = aX+rAx, aY+rAy, aZ+rAz
+	return aX, aY, aZ, aX - dx, aY - dy, aZ - dz
+}
+
+// IsViolated reports whether the anchors are farther than the tolerated error from the position required by the
+// Constraint: a violated Constraint must be solved even when its entities are at rest.
+func (c *Constraint) IsViolated() bool {
+	_, _, _, dx, dy, dz := c.separation()
+	dist := math.Sqrt(dx*dx + dy*dy + dz*dz)
+	switch c.kind {
+	case ConstraintRope:
+		return dist-c.length > constraintSlop
+	case ConstraintSpring:
+		return c.stiffness > 0 && math.Abs(dist-c.length) > constraintSlop
+	case ConstraintHinge, ConstraintFixed:
+		return dist > constraintSlop
+	}
+	return math.Abs(dist-c.length) > constraintSlop
+}
+
+// Solve applies the impulses of one solver iteration. The spring force is applied once per step, at the first
+// iteration; the other kinds are corrected at every iteration.
+func (c *Constraint) Solve(iteration int) {
+	switch c.kind {
+	case ConstraintSpring:
+		if iteration == 0 {
+			c.solveSpring()
+		}
+	case ConstraintDistance, ConstraintRope:
+		c.solveDistance()
+	case ConstraintHinge:
+		c.solvePoint()
+		c.solveAngular(true)
+	case ConstraintFixed:
+		c.solvePoint()
+		c.solveAngular(false)
+	}
+}
+
+// arms returns the world offsets of the anchors from the centers of the entities, zero for a world anchor.
+func (c *Constraint) arms() (float64, float64, float64, float64, float64, float64) {
+	rAx, rAy, rAz := c.a.GetOrientation().Rotate(c.anchorA[0], c.anchorA[1], c.anchorA[2])
+	if c.b == nil {
+		return rAx, rAy, rAz, 0, 0, 0
+	}
+	rBx, rBy, rBz := c.b.GetOrientation().Rotate(c.anchorB[0], c.anchorB[1], c.anchorB[2])
+	return rAx, rAy, rAz, rBx, rBy, rBz
+}
+
+// separation returns the world offset of the first anchor and the vector from the second anchor to the first one.
+func (c *Constraint) separation() (float64, float64, float64, float64, float64, float64) {
+	rAx, rAy, rAz, rBx, rBy, rBz := c.arms()
+	aX, aY, aZ := c.a.GetCenter()
+	bX, bY, bZ := c.anchorB[0], c.anchorB[1], c.anchorB[2]
+	if c.b != nil {
+		bX, bY, bZ = c.b.GetCenter()
+		bX, bY, bZ = bX+rBx, bY+rBy, bZ+rBz
+	}
+	return rAx, rAy, rAz, aX + rAx - bX, aY + rAy - bY, aZ + rAz - bZ
+}
+
+// relativeVelocity returns the velocity of the first anchor relative to the second one.
+func (c *Constraint) relativeVelocity(rAx, rAy, rAz, rBx, rBy, rBz float64) (float64, float64, float64) {
+	vx, vy, vz := c.a.pointVelocity(rAx, rAy, rAz)
+	if c.b != nil {
+		bx, by, bz := c.b.pointVelocity(rBx, rBy, rBz)
+		vx, vy, vz = vx-bx, vy-by, vz-bz
+	}
+	return vx, vy, vz
+}
+
+// effectiveMass returns the inverse of the effective mass of an impulse along (nx, ny, nz) at the anchors.
+func (c *Constraint) effectiveMass(rAx, rAy, rAz, rBx, rBy, rBz, nx, ny, nz float64) float64 {
+	k := c.a.invMass + c.a.angularMass(rAx, rAy, rAz, nx, ny, nz)
+	if c.b != nil {
+		k += c.b.invMass + c.b.angularMass(rBx, rBy, rBz, nx, ny, nz)
+	}
+	return k
+}
+
+// applyImpulse applies the impulse j along (nx, ny, nz) to the first entity and the opposite one to the second.
+func (c *Constraint) applyImpulse(j, rAx, rAy, rAz, rBx, rBy, rBz, nx, ny, nz float64) {
+	c.a.ApplyImpulseAt(j*nx, j*ny, j*nz, rAx, rAy, rAz)
+	if c.b != nil {
+		c.b.ApplyImpulseAt(-j*nx, -j*ny, -j*nz, rBx, rBy, rBz)
+	}
+}
+
+// solveDistance keeps the anchors at the length; a rope only pulls, and only when taut.
+func (c *Constraint) solveDistance() {
+	rAx, rAy, rAz, rBx, rBy, rBz := c.arms()
+	_, _, _, dx, dy, dz := c.separation()
+	dist := math.Sqrt(dx*dx + dy*dy + dz*dz)
+	if dist < minThickness {
+		return
+	}
+	nx, ny, nz := dx/dist, dy/dist, dz/dist
+	stretch := dist - c.length
+	if c.kind == ConstraintRope && stretch <= 0 {
+		return
+	}
+	k := c.effectiveMass(rAx, rAy, rAz, rBx, rBy, rBz, nx, ny, nz)
+	if k == 0 {
+		return
+	}
+	vx, vy, vz := c.relativeVelocity(rAx, rAy, rAz, rBx, rBy, rBz)
+	j := -(vx*nx + vy*ny + vz*nz + c.bias(stretch)) / k
+	if c.kind == ConstraintRope && j > 0 {
+		// Una fune tira ma non spinge
+		return
+	}
+	c.applyImpulse(j, rAx, rAy, rAz, rBx, rBy, rBz, nx, ny, nz)
+}
+
+// solveSpring applies the impulse of the damped spring force over one time step.
+func (c *Constraint) solveSpring() {
+	rAx, rAy, rAz, rBx, rBy, rBz := c.arms()
+	_, _, _, dx, dy, dz := c.separation()
+	dist := math.Sqrt(dx*dx + dy*dy + dz*dz)
+	if dist < minThickness {
+		return
+	}
+	nx, ny, nz := dx/dist, dy/dist, dz/dist
+	vx, vy, vz := c.relativeVelocity(rAx, rAy, rAz, rBx, rBy, rBz)
+	force := -c.stiffness*(dist-c.length) - c.damping*(vx*nx+vy*ny+vz*nz)
+	c.applyImpulse(force*c.a.dt, rAx, rAy, rAz, rBx, rBy, rBz, nx, ny, nz)
+}
+
+// solvePoint joins the two anchors, solving the three world axes in turn.
+func (c *Constraint) solvePoint() {
+	rAx, rAy, rAz, rBx, rBy, rBz := c.arms()
+	_, _, _, dx, dy, dz := c.separation()
+	d := [3]float64{dx, dy, dz}
+	for i := 0; i < 3; i++ {
+		var n [3]float64
+		n[i] = 1
+		k := c.effectiveMass(rAx, rAy, rAz, rBx, rBy, rBz, n[0], n[1], n[2])
+		if k == 0 {
+			continue
+		}
+		vx, vy, vz := c.relativeVelocity(rAx, rAy, rAz, rBx, rBy, rBz)
+		v := [3]float64{vx, vy, vz}
+		j := -(v[i] + c.bias(d[i])) / k
+		c.applyImpulse(j, rAx, rAy, rAz, rBx, rBy, rBz, n[0], n[1], n[2])
+	}
+}
+
+// solveAngular removes the relative angular velocity of the rotational entities: around the two directions
+// orthogonal to the hinge axis for a hinge, around every direction for a fixed joint.
+func (c *Constraint) solveAngular(hinge bool) {
+	if c.a.rotation == nil && (c.b == nil || c.b.rotation == nil) {
+		return
+	}
+	dirs := [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	if hinge {
+		ax, ay, az := c.a.GetOrientation().Rotate(c.axis[0], c.axis[1], c.axis[2])
+		dirs[0], dirs[1] = orthogonalBasis(ax, ay, az)
+	}
+	count := 3
+	if hinge {
+		count = 2
+	}
+	for i := 0; i < count; i++ {
+		ux, uy, uz := dirs[i][0], dirs[i][1], dirs[i][2]
+		k := c.a.angularInertia(ux, uy, uz)
+		if c.b != nil {
+			k += c.b.angularInertia(ux, uy, uz)
+		}
+		if k == 0 {
+			continue
+		}
+		wx, wy, wz := c.a.GetAngularVelocity()
+		if c.b != nil {
+			bx, by, bz := c.b.GetAngularVelocity()
+			wx, wy, wz = wx-bx, wy-by, wz-bz
+		}
+		l := -(wx*ux + wy*uy + wz*uz) / k
+		c.a.applyAngularImpulse(l*ux, l*uy, l*uz)
+		if c.b != nil {
+			c.b.applyAngularImpulse(-l*ux, -l*uy, -l*uz)
+		}
+	}
+}
+
+// bias returns the velocity that recovers a fraction of the position error in one time step.
+func (c *Constraint) bias(err float64) float64 {
+	if math.Abs(err) < constraintSlop {
+		return 0
+	}
+	return constraintBias * err / c.a.dt
+}
+
+// orthogonalBasis returns two unit vectors orthogonal to the unit vector (x, y, z) and to each other.
+func orthogonalBasis(x, y, z float64) ([3]float64, [3]float64) {
+	// Si parte dall'asse del mondo meno allineato con il vettore dato
+	var t [3]float64
+	switch {
+	case math.Abs(x) <= math.Abs(y) && math.Abs(x) <= math.Abs(z):
+		t = [3]float64{1, 0, 0}
+	case math.Abs(y) <= math.Abs(z):
+		t = [3]float64{0, 1, 0}
+	default:
+		t = [3]float64{0, 0, 1}
+	}
+	ux, uy, uz := y*t[2]-z*t[1], z*t[0]-x*t[2], x*t[1]-y*t[0]
+	l := math.Sqrt(ux*ux + uy*uy + uz*uz)
+	ux, uy, uz = ux/l, uy/l, uz/l
+	return [3]float64{ux, uy, uz}, [3]float64{y*uz - z*uy, z*ux - x*uz, x*uy - y*ux}
+}
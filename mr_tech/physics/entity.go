@@ -4,12 +4,16 @@ import "sync/atomic"
 
 // dt60 defines the fixed time step duration (in seconds) for 60 frames per second simulations.
 // dt120 defines the fixed time step duration (in seconds) for 120 frames per second simulations.
+// DefaultDt is the time step assigned to new entities until the simulation clock overrides it.
 const (
 	// dt60 represents the fixed time step duration in seconds, commonly used for 60 frames per second simulations.
 	dt60 float64 = 1.0 / 60.0
 
 	// dt120 represents the fixed time step duration equivalent to 1/120th of a second.
 	dt120 float64 = 1.0 / 120.0
+
+	// DefaultDt represents the default fixed time step of a new Entity.
+	DefaultDt = dt60
 )
 
 // _globalId is an internal counter used to generate unique identifiers in a thread-safe manner.
@@ -32,7 +36,7 @@ func NewEntity(mass, restitution, groundFriction, gForce float64) *Entity {
 	e := &Entity{
 		id:          uint64(GetGlobalId()),
 		BoundingBox: NewBoundingBox(0, 0, 0, 0, 0, 0),
-		Cinematic:   NewCinematic(dt60, mass, restitution, groundFriction, gForce),
+		Cinematic:   NewCinematic(DefaultDt, mass, restitution, groundFriction, gForce),
 	}
 	return e
 }
@@ -42,6 +46,40 @@ func (e *Entity) GetId() uint64 {
 	return e.id
 }
 
+// EnableRotation gives the entity a rotational state sized on its bounding box, so that it can tumble.
+func (e *Entity) EnableRotation() {
+	e.Cinematic.EnableRotation(e.BoundingBox.GetSize())
+	e.BoundingBox.SetOrientation(e.Cinematic.GetOrientation())
+}
+
+// DisableRotation removes the rotational state of the entity and makes its bounding box axis-aligned again.
+func (e *Entity) DisableRotation() {
+	e.Cinematic.DisableRotation()
+	e.BoundingBox.ClearOrientation()
+}
+
+// GetOrientation returns the orientation of the entity.
+func (e *Entity) GetOrientation() Quaternion {
+	return e.Cinematic.GetOrientation()
+}
+
+// SetOrientation sets the orientation of a rotational entity and rotates its bounding box accordingly.
+func (e *Entity) SetOrientation(q Quaternion) {
+	if !e.IsRotational() {
+		return
+	}
+	e.Cinematic.SetOrientation(q)
+	e.BoundingBox.SetOrientation(e.Cinematic.GetOrientation())
+}
+
+// Update integrates the cinematic state of the entity and rotates its bounding box to the new orientation.
+func (e *Entity) Update() {
+	e.Cinematic.Update()
+	if e.IsRotational() {
+		e.BoundingBox.SetOrientation(e.Cinematic.GetOrientation())
+	}
+}
+
 // ResolveImpact resolves a collision between two entities by applying impact forces based on their cinematic properties.
 func (e *Entity) ResolveImpact(e2 *Entity, nx, ny, nz float64, penetration float64) {
 	e.Cinematic.ResolveImpact(e2.Cinematic, nx, ny, nz, penetration)
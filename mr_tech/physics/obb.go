@@ -0,0 +1,160 @@
+package physics
+
+import "math"
+
+// obbEpsilon is added to the absolute rotation terms of the separating axis test to absorb the cross products of
+// nearly parallel edges.
+const obbEpsilon = 1e-9
+
+// OBB represents an oriented bounding box in 3D space, defined by its center, its half extents along the local axes
+// and the local axes expressed in world coordinates.
+type OBB struct {
+	cx   float64
+	cy   float64
+	cz   float64
+	half [3]float64
+	axes [3][3]float64
+}
+
+// NewOBB creates and returns a new oriented bounding box with null extents and the world axes.
+func NewOBB() *OBB {
+	return &OBB{
+		axes: [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
+	}
+}
+
+// Rebuild sets the center, the half extents and the orientation of the OBB.
+func (o *OBB) Rebuild(cx, cy, cz, hx, hy, hz float64, orientation Quaternion) {
+	o.cx, o.cy, o.cz = cx, cy, cz
+	o.half = [3]float64{hx, hy, hz}
+	o.axes = orientation.GetAxes()
+}
+
+// RebuildFromAABB sets the OBB to the axis-aligned box a.
+func (o *OBB) RebuildFromAABB(a *AABB) {
+	o.cx, o.cy, o.cz = a.GetCentroid()
+	o.half = [3]float64{a.GetWidth() * 0.5, a.GetHeight() * 0.5, a.GetDepth() * 0.5}
+	o.axes = [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+}
+
+// GetCenter returns the x, y, and z coordinates of the center of the OBB.
+func (o *OBB) GetCenter() (float64, float64, float64) {
+	return o.cx, o.cy, o.cz
+}
+
+// GetHalfExtents returns the half extents of the OBB along its local axes.
+func (o *OBB) GetHalfExtents() (float64, float64, float64) {
+	return o.half[0], o.half[1], o.half[2]
+}
+
+// GetAxis returns the idx-th local axis (0 = X, 1 = Y, 2 = Z) of the OBB in world coordinates.
+func (o *OBB) GetAxis(idx int) (float64, float64, float64) {
+	return o.axes[idx][0], o.axes[idx][1], o.axes[idx][2]
+}
+
+// GetExtents returns the half extents, along the world axes, of the smallest AABB enclosing the OBB.
+func (o *OBB) GetExtents() (float64, float64, float64) {
+	var e [3]float64
+	for k := 0; k < 3; k++ {
+		for i := 0; i < 3; i++ {
+			e[k] += math.Abs(o.axes[i][k]) * o.half[i]
+		}
+	}
+	return e[0], e[1], e[2]
+}
+
+// EnclosingAABB rebuilds dst as the smallest axis-aligned box enclosing the OBB.
+func (o *OBB) EnclosingAABB(dst *AABB) {
+	ex, ey, ez := o.GetExtents()
+	dst.Rebuild(o.cx-ex, o.cy-ey, o.cz-ez, o.cx+ex, o.cy+ey, o.cz+ez)
+}
+
+// Support returns the offset, from the center, of the point of the OBB farthest along the direction (dx, dy, dz).
+// The axes almost perpendicular to the direction do not contribute, so that a face lying on a plane yields its
+// center instead of an arbitrary corner.
+func (o *OBB) Support(dx, dy, dz float64) (float64, float64, float64) {
+	const flatThreshold = 0.05
+	var rx, ry, rz float64
+	for i := 0; i < 3; i++ {
+		a := o.axes[i]
+		s := dx*a[0] + dy*a[1] + dz*a[2]
+		if math.Abs(s) < flatThreshold {
+			continue
+		}
+		h := math.Copysign(o.half[i], s)
+		rx += a[0] * h
+		ry += a[1] * h
+		rz += a[2] * h
+	}
+	return rx, ry, rz
+}
+
+// ContainsPoint3d checks whether the point (px, py, pz) lies inside the OBB.
+func (o *OBB) ContainsPoint3d(px, py, pz float64) bool {
+	dx, dy, dz := px-o.cx, py-o.cy, pz-o.cz
+	for i := 0; i < 3; i++ {
+		a := o.axes[i]
+		if math.Abs(dx*a[0]+dy*a[1]+dz*a[2]) > o.half[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Overlaps checks whether the OBB intersects the other OBB, testing the 15 separating axes of the two boxes.
+func (o *OBB) Overlaps(other *OBB) bool {
+	// Separating Axis Theorem (Gottschalk): rotazione e traslazione di other espresse nel frame di o
+	var r, absR [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			a, b := o.axes[i], other.axes[j]
+			r[i][j] = a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+			absR[i][j] = math.Abs(r[i][j]) + obbEpsilon
+		}
+	}
+	dx, dy, dz := other.cx-o.cx, other.cy-o.cy, other.cz-o.cz
+	var t [3]float64
+	for i := 0; i < 3; i++ {
+		a := o.axes[i]
+		t[i] = dx*a[0] + dy*a[1] + dz*a[2]
+	}
+	ha, hb := o.half, other.half
+
+	// assi di o
+	for i := 0; i < 3; i++ {
+		rb := hb[0]*absR[i][0] + hb[1]*absR[i][1] + hb[2]*absR[i][2]
+		if math.Abs(t[i]) > ha[i]+rb {
+			return false
+		}
+	}
+	// assi di other
+	for j := 0; j < 3; j++ {
+		ra := ha[0]*absR[0][j] + ha[1]*absR[1][j] + ha[2]*absR[2][j]
+		if math.Abs(t[0]*r[0][j]+t[1]*r[1][j]+t[2]*r[2][j]) > ra+hb[j] {
+			return false
+		}
+	}
+	// prodotti vettoriali degli spigoli
+	for i := 0; i < 3; i++ {
+		i1, i2 := (i+1)%3, (i+2)%3
+		for j := 0; j < 3; j++ {
+			j1, j2 := (j+1)%3, (j+2)%3
+			ra := ha[i1]*absR[i2][j] + ha[i2]*absR[i1][j]
+			rb := hb[j1]*absR[i][j2] + hb[j2]*absR[i][j1]
+			if math.Abs(t[i2]*r[i1][j]-t[i1]*r[i2][j]) > ra+rb {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Sweep moves the OBB by half the displacement (dx, dy, dz) and grows it along its axes, so that it encloses the box
+// along the whole displacement.
+func (o *OBB) Sweep(dx, dy, dz float64) {
+	o.cx, o.cy, o.cz = o.cx+dx*0.5, o.cy+dy*0.5, o.cz+dz*0.5
+	for i := 0; i < 3; i++ {
+		a := o.axes[i]
+		o.half[i] += math.Abs(dx*a[0]+dy*a[1]+dz*a[2]) * 0.5
+	}
+}
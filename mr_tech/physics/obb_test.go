@@ -0,0 +1,41 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOBBOverlapsRotated(t *testing.T) {
+	// Un cubo ruotato di 45° attorno a Z: il suo AABB tocca l'altro cubo, il rombo no
+	a := NewOBB()
+	a.Rebuild(0, 0, 0, 1, 1, 1, NewQuaternionAxisAngle(0, 0, 1, math.Pi/4))
+	b := NewOBB()
+	b.Rebuild(2.2, 2.2, 0, 1, 1, 1, NewQuaternionIdentity())
+	var aBox, bBox AABB
+	a.EnclosingAABB(&aBox)
+	b.EnclosingAABB(&bBox)
+	if !aBox.Overlaps(&bBox) {
+		t.Fatal("the enclosing AABBs do not overlap")
+	}
+	if a.Overlaps(b) || b.Overlaps(a) {
+		t.Fatal("the rotated boxes overlap")
+	}
+	b.Rebuild(1.6, 0, 0, 1, 1, 1, NewQuaternionIdentity())
+	if !a.Overlaps(b) || !b.Overlaps(a) {
+		t.Fatal("the rotated boxes do not overlap")
+	}
+}
+
+func TestOBBSweep(t *testing.T) {
+	o := NewOBB()
+	o.Rebuild(0, 0, 0, 1, 1, 1, NewQuaternionAxisAngle(0, 0, 1, math.Pi/4))
+	o.Sweep(4, 0, 0)
+	for _, x := range []float64{-1.4, 0, 2, 4, 5.4} {
+		if !o.ContainsPoint3d(x, 0, 0) {
+			t.Fatalf("the swept box does not contain (%f, 0, 0)", x)
+		}
+	}
+	if o.ContainsPoint3d(5.5, 0, 0) || o.ContainsPoint3d(-1.5, 0, 0) {
+		t.Fatal("the swept box is longer than the sweep")
+	}
+}
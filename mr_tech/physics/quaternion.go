@@ -0,0 +1,109 @@
+package physics
+
+import "math"
+
+// Quaternion represents a unit quaternion (w, x, y, z) describing an orientation in 3D space.
+type Quaternion struct {
+	w float64
+	x float64
+	y float64
+	z float64
+}
+
+// NewQuaternion creates a Quaternion with the specified components, normalized to unit length.
+func NewQuaternion(w, x, y, z float64) Quaternion {
+	q := Quaternion{w: w, x: x, y: y, z: z}
+	return q.Normalize()
+}
+
+// NewQuaternionIdentity creates the Quaternion of the null rotation.
+func NewQuaternionIdentity() Quaternion {
+	return Quaternion{w: 1}
+}
+
+// NewQuaternionAxisAngle creates the Quaternion of a rotation of angle radians around the axis (ax, ay, az).
+func NewQuaternionAxisAngle(ax, ay, az, angle float64) Quaternion {
+	l := math.Sqrt(ax*ax + ay*ay + az*az)
+	if l < 1e-12 {
+		return NewQuaternionIdentity()
+	}
+	s := math.Sin(angle*0.5) / l
+	return Quaternion{w: math.Cos(angle * 0.5), x: ax * s, y: ay * s, z: az * s}
+}
+
+// Get returns the w, x, y and z components of the Quaternion.
+func (q Quaternion) Get() (float64, float64, float64, float64) {
+	return q.w, q.x, q.y, q.z
+}
+
+// IsIdentity reports whether the Quaternion describes the null rotation.
+func (q Quaternion) IsIdentity() bool {
+	return q.x == 0 && q.y == 0 && q.z == 0
+}
+
+// Normalize returns the Quaternion scaled to unit length, or the identity if its length is zero.
+func (q Quaternion) Normalize() Quaternion {
+	l := math.Sqrt(q.w*q.w + q.x*q.x + q.y*q.y + q.z*q.z)
+	if l < 1e-12 {
+		return NewQuaternionIdentity()
+	}
+	return Quaternion{w: q.w / l, x: q.x / l, y: q.y / l, z: q.z / l}
+}
+
+// Mul returns the Hamilton product q * o, the rotation o followed by the rotation q.
+func (q Quaternion) Mul(o Quaternion) Quaternion {
+	return Quaternion{
+		w: q.w*o.w - q.x*o.x - q.y*o.y - q.z*o.z,
+		x: q.w*o.x + q.x*o.w + q.y*o.z - q.z*o.y,
+		y: q.w*o.y - q.x*o.z + q.y*o.w + q.z*o.x,
+		z: q.w*o.z + q.x*o.y - q.y*o.x + q.z*o.w,
+	}
+}
+
+// Conjugate returns the inverse rotation of the unit Quaternion.
+func (q Quaternion) Conjugate() Quaternion {
+	return Quaternion{w: q.w, x: -q.x, y: -q.y, z: -q.z}
+}
+
+// Rotate applies the rotation of the Quaternion to the vector (vx, vy, vz).
+func (q Quaternion) Rotate(vx, vy, vz float64) (float64, float64, float64) {
+	// v' = v + 2w(u x v) + 2u x (u x v), con u la parte vettoriale
+	tx := 2 * (q.y*vz - q.z*vy)
+	ty := 2 * (q.z*vx - q.x*vz)
+	tz := 2 * (q.x*vy - q.y*vx)
+	return vx + q.w*tx + (q.y*tz - q.z*ty),
+		vy + q.w*ty + (q.z*tx - q.x*tz),
+		vz + q.w*tz + (q.x*ty - q.y*tx)
+}
+
+// InverseRotate applies the inverse rotation of the Quaternion to the vector (vx, vy, vz).
+func (q Quaternion) InverseRotate(vx, vy, vz float64) (float64, float64, float64) {
+	return q.Conjugate().Rotate(vx, vy, vz)
+}
+
+// GetAxes returns the local X, Y and Z axes of the orientation expressed in world coordinates.
+func (q Quaternion) GetAxes() [3][3]float64 {
+	xx, yy, zz := q.x*q.x, q.y*q.y, q.z*q.z
+	xy, xz, yz := q.x*q.y, q.x*q.z, q.y*q.z
+	wx, wy, wz := q.w*q.x, q.w*q.y, q.w*q.z
+	return [3][3]float64{
+		{1 - 2*(yy+zz), 2 * (xy + wz), 2 * (xz - wy)},
+		{2 * (xy - wz), 1 - 2*(xx+zz), 2 * (yz + wx)},
+		{2 * (xz + wy), 2 * (yz - wx), 1 - 2*(xx+yy)},
+	}
+}
+
+// GetYaw returns the rotation of the local X axis around the world Z axis, in radians.
+func (q Quaternion) GetYaw() float64 {
+	return math.Atan2(2*(q.w*q.z+q.x*q.y), 1-2*(q.y*q.y+q.z*q.z))
+}
+
+// Integrate returns the orientation reached after rotating for dt seconds at the angular velocity (wx, wy, wz),
+// expressed in world coordinates.
+func (q Quaternion) Integrate(wx, wy, wz, dt float64) Quaternion {
+	// dq/dt = 0.5 * (0, w) * q
+	h := 0.5 * dt
+	spin := Quaternion{w: 0, x: wx * h, y: wy * h, z: wz * h}.Mul(q)
+	r := Quaternion{w: q.w + spin.w, x: q.x + spin.x, y: q.y + spin.y, z: q.z + spin.z}
+	return r.Normalize()
+}
@@ -0,0 +1,234 @@
+package physics
+
+import "math"
+
+// angularAirFriction is the fraction of the angular velocity kept after one second of flight.
+// angularGroundFriction is the fraction of the angular velocity kept after one second of contact with the ground.
+// angularSleep is the angular speed, in radians per second, below which a body resting on the ground stops spinning.
+// settleTilt is the sine of the largest tilt at which a body resting on the ground is laid flat on its face.
+// settleStiffness is the angular acceleration, per radian of tilt, that tips a body resting on the ground onto its
+// nearest face, standing in for the torque of gravity around the contact edge.
+const (
+	angularAirFriction = 0.6
+
+	angularGroundFriction = 0.02
+
+	angularSleep = 0.05
+
+	settleTilt = 0.1
+
+	settleStiffness = 60.0
+)
+
+// rotation is the optional rotational state of a Cinematic: the orientation, the angular velocity in world
+// coordinates, the inverse of the principal moments of inertia and the half extents of the box used to find the
+// contact points.
+type rotation struct {
+	orientation Quaternion
+	wx          float64
+	wy          float64
+	wz          float64
+	tx          float64
+	ty          float64
+	tz          float64
+	invInertia  [3]float64
+	half        [3]float64
+}
+
+// EnableRotation gives the Cinematic a rotational state, treating it as a solid box of the given width, height and
+// depth. Impulses off the center of mass then make it spin; static bodies (zero mass) never rotate.
+func (e *Cinematic) EnableRotation(w, h, d float64) {
+	r := &rotation{orientation: NewQuaternionIdentity()}
+	if e.rotation != nil {
+		r.orientation = e.rotation.orientation
+	}
+	w, h, d = math.Max(w, minThickness), math.Max(h, minThickness), math.Max(d, minThickness)
+	r.half = [3]float64{w * 0.5, h * 0.5, d * 0.5}
+	if e.mass > 0 {
+		// Tensore d'inerzia di un parallelepipedo pieno, diagonale negli assi locali
+		k := e.mass / 12.0
+		r.invInertia = [3]float64{1.0 / (k * (h*h + d*d)), 1.0 / (k * (w*w + d*d)), 1.0 / (k * (w*w + h*h))}
+	}
+	e.rotation = r
+}
+
+// DisableRotation removes the rotational state: the Cinematic moves again as a point mass.
+func (e *Cinematic) DisableRotation() {
+	e.rotation = nil
+}
+
+// IsRotational reports whether the Cinematic has a rotational state.
+func (e *Cinematic) IsRotational() bool {
+	return e.rotation != nil
+}
+
+// GetOrientation returns the orientation of the Cinematic, the identity when it has no rotational state.
+func (e *Cinematic) GetOrientation() Quaternion {
+	if e.rotation == nil {
+		return NewQuaternionIdentity()
+	}
+	return e.rotation.orientation
+}
+
+// SetOrientation sets the orientation of a rotational Cinematic.
+func (e *Cinematic) SetOrientation(q Quaternion) {
+	if e.rotation == nil {
+		return
+	}
+	e.rotation.orientation = q.Normalize()
+}
+
+// GetAngularVelocity returns the angular velocity in world coordinates, in radians per second.
+func (e *Cinematic) GetAngularVelocity() (float64, float64, float64) {
+	if e.rotation == nil {
+		return 0, 0, 0
+	}
+	return e.rotation.wx, e.rotation.wy, e.rotation.wz
+}
+
+// SetAngularVelocity sets the angular velocity, in world coordinates, of a rotational Cinematic.
+func (e *Cinematic) SetAngularVelocity(wx, wy, wz float64) {
+	if e.rotation == nil {
+		return
+	}
+	e.rotation.wx, e.rotation.wy, e.rotation.wz = wx, wy, wz
+}
+
+// AddTorque accumulates a torque, in world coordinates, applied at the next Update.
+func (e *Cinematic) AddTorque(tx, ty, tz float64) {
+	if e.rotation == nil {
+		return
+	}
+	e.rotation.tx += tx
+	e.rotation.ty += ty
+	e.rotation.tz += tz
+}
+
+// ApplyImpulseAt applies the impulse (jx, jy, jz) at the offset (rx, ry, rz) from the center of mass, changing the
+// linear velocity and, for a rotational Cinematic, the angular velocity. A static Cinematic (zero mass) is never
+// written, so that the static level geometry can be shared by concurrent solvers.
+func (e *Cinematic) ApplyImpulseAt(jx, jy, jz, rx, ry, rz float64) {
+	if e.invMass == 0 {
+		return
+	}
+	e.vx += jx * e.invMass
+	e.vy += jy * e.invMass
+	e.vz += jz * e.invMass
+	if e.rotation == nil {
+		return
+	}
+	ax, ay, az := e.applyInvInertia(ry*jz-rz*jy, rz*jx-rx*jz, rx*jy-ry*jx)
+	e.rotation.wx += ax
+	e.rotation.wy += ay
+	e.rotation.wz += az
+}
+
+// applyAngularImpulse applies the angular impulse (lx, ly, lz), in world coordinates, to a rotational Cinematic.
+func (e *Cinematic) applyAngularImpulse(lx, ly, lz float64) {
+	if e.rotation == nil || e.invMass == 0 {
+		return
+	}
+	ax, ay, az := e.applyInvInertia(lx, ly, lz)
+	e.rotation.wx += ax
+	e.rotation.wy += ay
+	e.rotation.wz += az
+}
+
+// angularInertia returns the inverse of the moment of inertia around the unit direction (ux, uy, uz): u . I^-1 u.
+// It is zero without a rotational state.
+func (e *Cinematic) angularInertia(ux, uy, uz float64) float64 {
+	if e.rotation == nil || e.invMass == 0 {
+		return 0
+	}
+	ix, iy, iz := e.applyInvInertia(ux, uy, uz)
+	return ux*ix + uy*iy + uz*iz
+}
+
+// applyInvInertia multiplies the vector by the inverse inertia tensor in world coordinates (R I^-1 R^T).
+func (e *Cinematic) applyInvInertia(vx, vy, vz float64) (float64, float64, float64) {
+	r := e.rotation
+	lx, ly, lz := r.orientation.InverseRotate(vx, vy, vz)
+	return r.orientation.Rotate(lx*r.invInertia[0], ly*r.invInertia[1], lz*r.invInertia[2])
+}
+
+// contactArm returns the offset, from the center of mass, of the point of the box farthest along the direction
+// (dx, dy, dz): the estimated contact point of an impact. It is zero without a rotational state.
+func (e *Cinematic) contactArm(dx, dy, dz float64) (float64, float64, float64) {
+	if e.rotation == nil {
+		return 0, 0, 0
+	}
+	var o OBB
+	o.Rebuild(0, 0, 0, e.rotation.half[0], e.rotation.half[1], e.rotation.half[2], e.rotation.orientation)
+	return o.Support(dx, dy, dz)
+}
+
+// pointVelocity returns the velocity of the point at the offset (rx, ry, rz) from the center of mass.
+func (e *Cinematic) pointVelocity(rx, ry, rz float64) (float64, float64, float64) {
+	if e.rotation == nil {
+		return e.vx, e.vy, e.vz
+	}
+	r := e.rotation
+	return e.vx + r.wy*rz - r.wz*ry, e.vy + r.wz*rx - r.wx*rz, e.vz + r.wx*ry - r.wy*rx
+}
+
+// angularMass returns the contribution of the rotation to the effective mass of an impulse along the direction
+// (dx, dy, dz) applied at the offset (rx, ry, rz): (r x d) . I^-1 (r x d).
+func (e *Cinematic) angularMass(rx, ry, rz, dx, dy, dz float64) float64 {
+	if e.rotation == nil {
+		return 0
+	}
+	cx, cy, cz := ry*dz-rz*dy, rz*dx-rx*dz, rx*dy-ry*dx
+	ix, iy, iz := e.applyInvInertia(cx, cy, cz)
+	return cx*ix + cy*iy + cz*iz
+}
+
+// updateRotation integrates the torque, the damping and the orientation over the time step. On the ground the body
+// is tipped onto its nearest face and put to sleep once it stops spinning.
+func (e *Cinematic) updateRotation() {
+	r := e.rotation
+	ax, ay, az := e.applyInvInertia(r.tx, r.ty, r.tz)
+	r.wx += ax * e.dt
+	r.wy += ay * e.dt
+	r.wz += az * e.dt
+	r.tx, r.ty, r.tz = 0.0, 0.0, 0.0
+
+	friction := angularAirFriction
+	var upX, upY, upZ float64
+	if e.onGround {
+		friction = angularGroundFriction
+		upX, upY, upZ = r.upAxis()
+		// up x Z = (upY, -upX, 0), di modulo pari al seno dell'inclinazione
+		r.wx += upY * settleStiffness * e.dt
+		r.wy += -upX * settleStiffness * e.dt
+	}
+	damping := math.Pow(friction, e.dt)
+	r.wx *= damping
+	r.wy *= damping
+	r.wz *= damping
+
+	if r.wx*r.wx+r.wy*r.wy+r.wz*r.wz < angularSleep*angularSleep {
+		if e.onGround && upX*upX+upY*upY < settleTilt*settleTilt {
+			r.wx, r.wy, r.wz = 0.0, 0.0, 0.0
+			// A riposo la faccia appoggiata viene allineata al pavimento, conservando l'imbardata
+			r.orientation = NewQuaternionAxisAngle(upY, -upX, 0, math.Acos(math.Min(upZ, 1.0))).Mul(r.orientation).Normalize()
+		}
+		return
+	}
+	r.orientation = r.orientation.Integrate(r.wx, r.wy, r.wz, e.dt)
+}
+
+// upAxis returns the local axis, or its opposite, closest to the world Z axis and pointing upwards.
+func (r *rotation) upAxis() (float64, float64, float64) {
+	axes := r.orientation.GetAxes()
+	best := 0
+	for i := 1; i < 3; i++ {
+		if math.Abs(axes[i][2]) > math.Abs(axes[best][2]) {
+			best = i
+		}
+	}
+	a := axes[best]
+	if a[2] < 0 {
+		return -a[0], -a[1], -a[2]
+	}
+	return a[0], a[1], a[2]
+}
@@ -0,0 +1,146 @@
+package physics
+
+import "math"
+
+// IntersectRayTriangle returns the distance, in units of the direction (dX, dY, dZ), from the origin (oX, oY, oZ) to
+// the point where the ray crosses the triangle (p0, p1, p2), from either side (Möller-Trumbore).
+func IntersectRayTriangle(oX, oY, oZ, dX, dY, dZ float64, p0, p1, p2 Point) (float64, bool) {
+	const eps = 1e-12
+	e1x, e1y, e1z := p1.x-p0.x, p1.y-p0.y, p1.z-p0.z
+	e2x, e2y, e2z := p2.x-p0.x, p2.y-p0.y, p2.z-p0.z
+	hx, hy, hz := dY*e2z-dZ*e2y, dZ*e2x-dX*e2z, dX*e2y-dY*e2x
+	a := e1x*hx + e1y*hy + e1z*hz
+	// Raggio parallelo al piano del triangolo
+	if a > -eps && a < eps {
+		return 0, false
+	}
+	invDet := 1.0 / a
+	sx, sy, sz := oX-p0.x, oY-p0.y, oZ-p0.z
+	u := (sx*hx + sy*hy + sz*hz) * invDet
+	if u < 0.0 || u > 1.0 {
+		return 0, false
+	}
+	qx, qy, qz := sy*e1z-sz*e1y, sz*e1x-sx*e1z, sx*e1y-sy*e1x
+	v := (dX*qx + dY*qy + dZ*qz) * invDet
+	if v < 0.0 || u+v > 1.0 {
+		return 0, false
+	}
+	t := (e2x*qx + e2y*qy + e2z*qz) * invDet
+	if t < 0.0 {
+		return 0, false
+	}
+	return t, true
+}
+
+// ClosestPointTriangle returns the point of the triangle (p0, p1, p2) closest to (px, py, pz).
+func ClosestPointTriangle(px, py, pz float64, p0, p1, p2 Point) (float64, float64, float64) {
+	abx, aby, abz := p1.x-p0.x, p1.y-p0.y, p1.z-p0.z
+	acx, acy, acz := p2.x-p0.x, p2.y-p0.y, p2.z-p0.z
+	apx, apy, apz := px-p0.x, py-p0.y, pz-p0.z
+	d1 := abx*apx + aby*apy + abz*apz
+	d2 := acx*apx + acy*apy + acz*apz
+	if d1 <= 0 && d2 <= 0 {
+		return p0.x, p0.y, p0.z
+	}
+	bpx, bpy, bpz := px-p1.x, py-p1.y, pz-p1.z
+	d3 := abx*bpx + aby*bpy + abz*bpz
+	d4 := acx*bpx + acy*bpy + acz*bpz
+	if d3 >= 0 && d4 <= d3 {
+		return p1.x, p1.y, p1.z
+	}
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		v := d1 / (d1 - d3)
+		return p0.x + abx*v, p0.y + aby*v, p0.z + abz*v
+	}
+	cpx, cpy, cpz := px-p2.x, py-p2.y, pz-p2.z
+	d5 := abx*cpx + aby*cpy + abz*cpz
+	d6 := acx*cpx + acy*cpy + acz*cpz
+	if d6 >= 0 && d5 <= d6 {
+		return p2.x, p2.y, p2.z
+	}
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		w := d2 / (d2 - d6)
+		return p0.x + acx*w, p0.y + acy*w, p0.z + acz*w
+	}
+	va := d3*d6 - d5*d4
+	if va <= 0 && (d4-d3) >= 0 && (d5-d6) >= 0 {
+		w := (d4 - d3) / ((d4 - d3) + (d5 - d6))
+		return p1.x + (p2.x-p1.x)*w, p1.y + (p2.y-p1.y)*w, p1.z + (p2.z-p1.z)*w
+	}
+	// Proiezione interna al triangolo
+	denom := 1.0 / (va + vb + vc)
+	v := vb * denom
+	w := vc * denom
+	return p0.x + abx*v + acx*w, p0.y + aby*v + acy*w, p0.z + abz*v + acz*w
+}
+
+// OverlapsTriangle reports whether the AABB intersects the triangle (p0, p1, p2), testing the 13 separating axes of
+// a box and a triangle: the box axes, the triangle normal and the cross products of the box axes with the edges.
+func (a *AABB) OverlapsTriangle(p0, p1, p2 Point) bool {
+	cx, cy, cz := a.GetCentroid()
+	ex, ey, ez := (a.maxX-a.minX)*0.5, (a.maxY-a.minY)*0.5, (a.maxZ-a.minZ)*0.5
+	// Triangolo nel sistema del centro del box
+	v := [3][3]float64{
+		{p0.x - cx, p0.y - cy, p0.z - cz},
+		{p1.x - cx, p1.y - cy, p1.z - cz},
+		{p2.x - cx, p2.y - cy, p2.z - cz},
+	}
+	ext := [3]float64{ex, ey, ez}
+	// Assi del box
+	for i := 0; i < 3; i++ {
+		if math.Min(v[0][i], math.Min(v[1][i], v[2][i])) > ext[i] || math.Max(v[0][i], math.Max(v[1][i], v[2][i])) < -ext[i] {
+			return false
+		}
+	}
+	edges := [3][3]float64{
+		{v[1][0] - v[0][0], v[1][1] - v[0][1], v[1][2] - v[0][2]},
+		{v[2][0] - v[1][0], v[2][1] - v[1][1], v[2][2] - v[1][2]},
+		{v[0][0] - v[2][0], v[0][1] - v[2][1], v[0][2] - v[2][2]},
+	}
+	separated := func(axX, axY, axZ float64) bool {
+		if math.Abs(axX) < 1e-12 && math.Abs(axY) < 1e-12 && math.Abs(axZ) < 1e-12 {
+			return false
+		}
+		q0 := v[0][0]*axX + v[0][1]*axY + v[0][2]*axZ
+		q1 := v[1][0]*axX + v[1][1]*axY + v[1][2]*axZ
+		q2 := v[2][0]*axX + v[2][1]*axY + v[2][2]*axZ
+		r := ex*math.Abs(axX) + ey*math.Abs(axY) + ez*math.Abs(axZ)
+		return math.Min(q0, math.Min(q1, q2)) > r || math.Max(q0, math.Max(q1, q2)) < -r
+	}
+	// Normale del triangolo
+	e0, e1 := edges[0], edges[1]
+	if separated(e0[1]*e1[2]-e0[2]*e1[1], e0[2]*e1[0]-e0[0]*e1[2], e0[0]*e1[1]-e0[1]*e1[0]) {
+		return false
+	}
+	// Prodotti vettoriali fra gli assi del box e gli spigoli
+	for _, e := range edges {
+		if separated(0, -e[2], e[1]) || separated(e[2], 0, -e[0]) || separated(-e[1], e[0], 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalAt returns the outward normal of the face of the AABB closest to the point (px, py, pz).
+func (a *AABB) NormalAt(px, py, pz float64) (float64, float64, float64) {
+	best := math.Abs(px - a.minX)
+	nx, ny, nz := -1.0, 0.0, 0.0
+	candidates := [5]struct {
+		d          float64
+		nx, ny, nz float64
+	}{
+		{math.Abs(px - a.maxX), 1, 0, 0},
+		{math.Abs(py - a.minY), 0, -1, 0},
+		{math.Abs(py - a.maxY), 0, 1, 0},
+		{math.Abs(pz - a.minZ), 0, 0, -1},
+		{math.Abs(pz - a.maxZ), 0, 0, 1},
+	}
+	for _, c := range candidates {
+		if c.d < best {
+			best, nx, ny, nz = c.d, c.nx, c.ny, c.nz
+		}
+	}
+	return nx, ny, nz
+}
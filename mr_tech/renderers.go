@@ -0,0 +1,16 @@
+//go:build !headless
+
+package main
+
+import (
+	"github.com/markel1974/godoom/mr_tech/renderers/open_gl"
+	"github.com/markel1974/godoom/mr_tech/renderers/software"
+)
+
+// newRender creates the interactive renderer, either the software rasterizer or the OpenGL pipeline.
+func newRender(softwareRender bool, width, height int32) (IRender, error) {
+	if softwareRender {
+		return software.NewRender(width, height), nil
+	}
+	return open_gl.NewRender(width, height), nil
+}
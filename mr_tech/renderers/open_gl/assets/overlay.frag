@@ -0,0 +1,24 @@
+#version 330 core
+in vec2 v_uv;
+out vec4 FragColor;
+
+uniform sampler2DArray u_texture[4];
+uniform float u_layer;
+
+vec4 getDiffuse(vec3 tc) {
+    int b = int(tc.z) / 1000;
+    float l = mod(tc.z, 1000.0);
+    if (b == 0) return texture(u_texture[0], vec3(tc.xy, l));
+    if (b == 1) return texture(u_texture[1], vec3(tc.xy, l));
+    if (b == 2) return texture(u_texture[2], vec3(tc.xy, l));
+    return texture(u_texture[3], vec3(tc.xy, l));
+}
+
+void main() {
+    vec4 color = getDiffuse(vec3(v_uv, u_layer));
+    // I texel trasparenti del modello non coprono la scena
+    if (color.a < 0.5) {
+        discard;
+    }
+    FragColor = vec4(color.rgb, 1.0);
+}
@@ -0,0 +1,14 @@
+#version 330 core
+
+layout (location = 0) in vec2 aPos;
+
+out vec2 v_uv;
+
+uniform vec4 u_rect;
+
+void main() {
+    // Il quad unitario viene portato nel rettangolo (x0, y0, x1, y1) in coordinate NDC
+    vec2 pos = mix(u_rect.xy, u_rect.zw, aPos * 0.5 + 0.5);
+    v_uv = vec2(aPos.x * 0.5 + 0.5, 0.5 - aPos.y * 0.5);
+    gl_Position = vec4(pos, 0.0, 1.0);
+}
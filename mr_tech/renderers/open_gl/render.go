@@ -11,9 +11,11 @@ import (
 // scaleFactor defines a constant value for scaling factors used in the application.
 // maxBatchVertices specifies the maximum number of vertices that can be processed in a single batch.
 // maxFrameCommands sets the limit on the number of commands that can be issued per frame.
+// viewModelHeight is the fraction of the screen height covered by the view model of the selected weapon.
 const (
 	startBatchVertices = 16384
 	startFrameCommands = 1024
+	viewModelHeight    = 0.4
 )
 
 type IBuilder interface {
@@ -149,9 +151,39 @@ func (w *RenderOpenGL) doRender() {
 			skyLayer, skyEnabled = w.tex.Get(cSky)
 		}
 		w.shaders.Render(w.vi, int32(fbW), int32(fbH), vert, vertLen, indices, indicesLen, commands, skyEnabled, skyLayer, light, lightsCount, shadowLights, shadowLightsCount)
+		w.drawViewModel(fbW, fbH)
 	})
 }
 
+// drawViewModel draws the view model frame of the selected weapon at the bottom center of the framebuffer, scaled to
+// viewModelHeight of its height, over the post-processed frame.
+func (w *RenderOpenGL) drawViewModel(fbW, fbH int) {
+	weapon := w.player.GetArsenal().GetCurrent()
+	if weapon == nil || fbW <= 0 || fbH <= 0 {
+		return
+	}
+	material, frame := weapon.GetViewFrame()
+	if material == nil {
+		return
+	}
+	tex := w.engine.GetThings().GetMaterials().GetMaterial(material).GetFrame(frame)
+	if tex == nil {
+		return
+	}
+	layer, ok := w.tex.Get(tex)
+	if !ok {
+		return
+	}
+	texW, texH := tex.Size()
+	if texW <= 0 || texH <= 0 {
+		return
+	}
+	// Dimensioni in coordinate NDC, mantenendo le proporzioni della texture in pixel
+	h := float32(viewModelHeight * 2)
+	halfW := h * float32(texW) / float32(texH) * float32(fbH) / float32(fbW) * 0.5
+	w.shaders.RenderOverlay(layer, -halfW, -1, halfW, -1+h)
+}
+
 // doRun executes the main rendering and input handling loop for the RenderOpenGL instance.
 func (w *RenderOpenGL) doRun() {
 	if err := w.doInitialize(); err != nil {
@@ -212,9 +244,12 @@ func (w *RenderOpenGL) doRun() {
 		if w.win.JustPressed(pixels.KeyO) {
 			w.doPlayerThrow()
 		}
-		if w.win.JustPressed(pixels.KeyP) {
+		if w.win.Pressed(pixels.KeyP) {
 			w.doPlayerFire()
 		}
+		if w.win.JustPressed(pixels.KeyQ) {
+			w.doPlayerNextWeapon()
+		}
 		if w.win.JustPressed(pixels.KeyC) {
 			w.enableClear = true
 		}
@@ -224,6 +259,9 @@ func (w *RenderOpenGL) doRun() {
 		if w.win.JustPressed(pixels.KeySpace) {
 			w.doPlayerJump(false)
 		}
+		if w.win.JustPressed(pixels.KeyE) {
+			w.doPlayerUse()
+		}
 		if w.win.Pressed(pixels.MouseButton1) {
 			w.doPlayerJump(true)
 		}
@@ -242,20 +280,25 @@ func (w *RenderOpenGL) doRun() {
 	}
 }
 
-// doPlayerFire triggers the player's fire action by retrieving position, angle, and sector, and invoking the engine's fire logic.
+// doPlayerThrow requests a shot of the first projectile weapon of the player.
 func (w *RenderOpenGL) doPlayerThrow() {
-	const throwableIndex = 2
-	w.player.Throw(throwableIndex, 300)
+	w.player.Throw()
 }
 
-// doPlayerFire triggers the player's fire action by retrieving position, angle, and sector, and invoking the engine's fire logic.
+// doPlayerFire requests a shot of the selected weapon of the player.
 func (w *RenderOpenGL) doPlayerFire() {
-	w.player.Fire("gun")
+	w.player.Fire()
 }
 
+// doPlayerNextWeapon switches the player to the next owned weapon.
+func (w *RenderOpenGL) doPlayerNextWeapon() { w.player.NextWeapon() }
+
 // doPlayerDuckingToggle toggles the player's ducking state by invoking the SetDucking method on the player instance.
 func (w *RenderOpenGL) doPlayerDuckingToggle() { w.player.SetDucking() }
 
+// doPlayerUse requests the activation of the line specials in front of the player.
+func (w *RenderOpenGL) doPlayerUse() { w.player.Use() }
+
 // doPlayerJump triggers the player's jump action by invoking the SetJump method on the player instance.
 func (w *RenderOpenGL) doPlayerJump(multi bool) { w.player.SetJump(multi) }
 
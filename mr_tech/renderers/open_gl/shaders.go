@@ -44,6 +44,7 @@ type Shaders struct {
 	shadowLight   *shaders.ShadowLight
 	post          *shaders.Post
 	bloom         *shaders.Bloom
+	overlay       *shaders.Overlay
 	container     []IShader
 	enableShadows bool
 	metrics       *shaders.MapMetrics
@@ -68,6 +69,7 @@ func NewShaders() *Shaders {
 		shadowLight:   nil,
 		post:          nil,
 		bloom:         nil,
+		overlay:       nil,
 		enableShadows: false,
 	}
 	return c
@@ -95,8 +97,9 @@ func (w *Shaders) Setup(vStride, lStride int32, p *model.ThingPlayer, cal *model
 	w.shadowLight = shaders.NewShaderShadowLight(w.cal)
 	w.post = shaders.NewPost()
 	w.bloom = shaders.NewBloom()
+	w.overlay = shaders.NewOverlay()
 	w.enableShadows = false
-	w.container = append(w.container, w.main, w.sky, w.geometry, w.ssao, w.blur, w.depth, w.lights, w.shadowLight, w.post, w.bloom)
+	w.container = append(w.container, w.main, w.sky, w.geometry, w.ssao, w.blur, w.depth, w.lights, w.shadowLight, w.post, w.bloom, w.overlay)
 	w.SetShadowEnabled(true)
 
 	for _, s := range w.container {
@@ -250,6 +253,13 @@ func (w *Shaders) Render(vi *model.ViewMatrix, fbW int32, fbH int32, vert []floa
 	w.post.Render(w.bloom.GetBloomTexture(), fbW, fbH)
 }
 
+// RenderOverlay draws the texture with the packed bucket and layer on the NDC rectangle (x0, y0) - (x1, y1), over the
+// frame produced by Render.
+func (w *Shaders) RenderOverlay(layer float32, x0, y0, x1, y1 float32) {
+	w.bindTextureBuckets()
+	w.overlay.Render(layer, x0, y0, x1, y1)
+}
+
 // ToggleShadows toggles the state of shadow rendering in the shader system.
 func (w *Shaders) ToggleShadows() { w.SetShadowEnabled(!w.enableShadows) }
 
@@ -0,0 +1,119 @@
+package shaders
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v3.3-core/gl"
+)
+
+// ShaderOverlayLoc represents the location index of shader uniforms specific to the Overlay renderer.
+type ShaderOverlayLoc int
+
+// ShaderOverlayLocTexture represents the diffuse texture arrays location for the overlay shader.
+// ShaderOverlayLocLayer represents the packed bucket and layer of the drawn texture.
+// ShaderOverlayLocRect represents the screen rectangle, in NDC, covered by the texture.
+// ShaderOverlayLocLast represents the total count of overlay shader locations.
+const (
+	ShaderOverlayLocTexture = ShaderOverlayLoc(iota)
+	ShaderOverlayLocLayer
+	ShaderOverlayLocRect
+	ShaderOverlayLocLast
+)
+
+// Overlay draws a texture of the diffuse arrays on a screen rectangle, over the post-processed frame, as the view
+// model of the selected weapon.
+type Overlay struct {
+	prg   uint32
+	table [ShaderOverlayLocLast]int32
+	vao   uint32
+	vbo   uint32
+}
+
+// NewOverlay creates and returns a new instance of Overlay with default uninitialized properties.
+func NewOverlay() *Overlay {
+	return &Overlay{
+		prg: 0,
+	}
+}
+
+// SetupSamplers initializes the quad vertex array and buffer objects and binds the diffuse texture units.
+func (s *Overlay) SetupSamplers() error {
+	gl.UseProgram(s.prg)
+
+	units := []int32{0, 1, 2, 3}
+	gl.Uniform1iv(s.GetUniform(ShaderOverlayLocTexture), 4, &units[0])
+
+	gl.GenVertexArrays(1, &s.vao)
+	gl.BindVertexArray(s.vao)
+	gl.GenBuffers(1, &s.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, s.vbo)
+	quadVertices := []float32{-1.0, -1.0, 1.0, -1.0, -1.0, 1.0, 1.0, 1.0}
+	gl.BufferData(gl.ARRAY_BUFFER, len(quadVertices)*4, gl.Ptr(quadVertices), gl.STATIC_DRAW)
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, 2*4, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+	return nil
+}
+
+// Init initializes the Overlay instance by setting up necessary resources and ensuring its readiness for rendering.
+func (s *Overlay) Init() error {
+	return nil
+}
+
+// GetProgram returns the OpenGL program identifier associated with the Overlay instance.
+func (s *Overlay) GetProgram() uint32 {
+	return s.prg
+}
+
+// GetUniform retrieves the location of a shader uniform variable by its ID.
+func (s *Overlay) GetUniform(id ShaderOverlayLoc) int32 {
+	return s.table[id]
+}
+
+// Compile compiles the shader program for rendering the overlay using provided vertex and fragment shaders from assets.
+func (s *Overlay) Compile(a IAssets) error {
+	const vertId = "overlay.vert"
+	const fragId = "overlay.frag"
+
+	vertexSrc, fragmentSrc, err := a.ReadMulti(vertId, fragId)
+	if err != nil {
+		return err
+	}
+	vertexShader, err := ShaderCompile(vertId, string(vertexSrc), gl.VERTEX_SHADER)
+	if err != nil {
+		return err
+	}
+	fragmentShader, err := ShaderCompile(fragId, string(fragmentSrc), gl.FRAGMENT_SHADER)
+	if err != nil {
+		gl.DeleteShader(vertexShader)
+		return err
+	}
+	s.prg, err = ShaderCreateProgram("overlay", vertexShader, fragmentShader)
+	if err != nil {
+		return err
+	}
+	s.table[ShaderOverlayLocTexture] = gl.GetUniformLocation(s.prg, gl.Str("u_texture\x00"))
+	s.table[ShaderOverlayLocLayer] = gl.GetUniformLocation(s.prg, gl.Str("u_layer\x00"))
+	s.table[ShaderOverlayLocRect] = gl.GetUniformLocation(s.prg, gl.Str("u_rect\x00"))
+	for idx, v := range s.table {
+		if v < 0 {
+			return fmt.Errorf("invalid uniform location in overlay: %d", idx)
+		}
+	}
+	return nil
+}
+
+// Render draws the texture with the packed bucket and layer on the NDC rectangle (x0, y0) - (x1, y1) of the default
+// framebuffer, without depth test.
+func (s *Overlay) Render(layer float32, x0, y0, x1, y1 float32) {
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.Disable(gl.DEPTH_TEST)
+
+	gl.UseProgram(s.GetProgram())
+	gl.Uniform1f(s.GetUniform(ShaderOverlayLocLayer), layer)
+	gl.Uniform4f(s.GetUniform(ShaderOverlayLocRect), x0, y0, x1, y1)
+
+	gl.BindVertexArray(s.vao)
+	gl.DrawArrays(gl.TRIANGLE_STRIP, 0, 4)
+
+	gl.Enable(gl.DEPTH_TEST)
+}
@@ -13,6 +13,9 @@ import (
 	"github.com/markel1974/godoom/pixels"
 )
 
+// viewModelHeight is the fraction of the screen height covered by the view model of the selected weapon.
+const viewModelHeight = 0.4
+
 // Render is a struct responsible for handling software-based 2D rendering functionality.
 type Render struct {
 	win                *pixels.GLWindow
@@ -178,6 +181,18 @@ func (w *Render) doRun() {
 		if w.win.JustPressed(pixels.KeySpace) {
 			w.doPlayerJump(false)
 		}
+		if w.win.JustPressed(pixels.KeyE) {
+			w.doPlayerUse()
+		}
+		if w.win.JustPressed(pixels.KeyO) {
+			w.doPlayerThrow()
+		}
+		if w.win.Pressed(pixels.KeyP) {
+			w.doPlayerFire()
+		}
+		if w.win.JustPressed(pixels.KeyQ) {
+			w.doPlayerNextWeapon()
+		}
 		if w.win.Pressed(pixels.MouseButton1) {
 			w.doPlayerJump(true)
 		}
@@ -212,6 +227,7 @@ func (w *Render) doRender() {
 	w.targetLastCompiled = count
 	w.doSerialRender(w.mainSurface, w.vi, cs, count)
 	//w.parallelRender(surface, vi, css, compiled)
+	w.drawViewModel(w.mainSurface)
 	w.mainSurface.ApplyFastAA(20)
 	if w.debug {
 		w.drawStub()
@@ -289,11 +305,68 @@ func (w *Render) RenderSector(sector *model.Sector) {
 	dp.DrawLines(false)
 }
 
+// doPlayerThrow requests a shot of the first projectile weapon of the player.
+func (w *Render) doPlayerThrow() {
+	w.player.Throw()
+}
+
+// doPlayerFire requests a shot of the selected weapon of the player.
+func (w *Render) doPlayerFire() {
+	w.player.Fire()
+}
+
+// doPlayerNextWeapon switches the player to the next owned weapon.
+func (w *Render) doPlayerNextWeapon() {
+	w.player.NextWeapon()
+}
+
+// drawViewModel draws the view model frame of the selected weapon at the bottom center of the surface, scaled to
+// viewModelHeight of the screen height. The transparent texels are skipped.
+func (w *Render) drawViewModel(surface *pixels.PictureRGBA) {
+	weapon := w.player.GetArsenal().GetCurrent()
+	if weapon == nil {
+		return
+	}
+	material, frame := weapon.GetViewFrame()
+	if material == nil {
+		return
+	}
+	tex := w.engine.GetThings().GetMaterials().GetMaterial(material).GetFrame(frame)
+	if tex == nil {
+		return
+	}
+	texW, texH := tex.Size()
+	if texW <= 0 || texH <= 0 {
+		return
+	}
+	scale := viewModelHeight * float64(w.h) / float64(texH)
+	dstW := int(float64(texW) * scale)
+	dstH := int(float64(texH) * scale)
+	left := (int(w.w) - dstW) / 2
+	top := int(w.h) - dstH
+	for y := 0; y < dstH; y++ {
+		texY := int(float64(y) / scale)
+		for x := max(0, -left); x < dstW && left+x < int(w.w); x++ {
+			// I texel sono impacchettati come RGBA, con l'alfa nel byte basso
+			c := tex.Get(int(float64(x)/scale), texY)
+			if c&255 == 0 {
+				continue
+			}
+			surface.SetRGBA(left+x, top+y, uint8(c>>24), uint8(c>>16), uint8(c>>8), 255)
+		}
+	}
+}
+
 // doPlayerDuckingToggle toggles the ducking state of the player by invoking the player's SetDucking method.
 func (w *Render) doPlayerDuckingToggle() {
 	w.player.SetDucking()
 }
 
+// doPlayerUse requests the activation of the line specials in front of the player.
+func (w *Render) doPlayerUse() {
+	w.player.Use()
+}
+
 // doPlayerJump triggers the player's jump behavior by calling the appropriate method on the player instance.
 func (w *Render) doPlayerJump(multi bool) {
 	w.player.SetJump(multi)
@@ -0,0 +1,10 @@
+//go:build headless
+
+package main
+
+import "fmt"
+
+// newRender is unavailable in binaries built with the headless tag, which carry no window or GL dependency.
+func newRender(_ bool, _, _ int32) (IRender, error) {
+	return nil, fmt.Errorf("interactive renderers are not available in headless builds, use -headless")
+}
@@ -47,6 +47,8 @@ type Material struct {
 	scaleH      float64
 	u           float64
 	v           float64
+	pinned      bool
+	pin         uint64
 }
 
 // NewMaterial creates a new Material instance from a provided slice of Texture pointers.
@@ -88,8 +90,38 @@ func (a *Material) Kind() int {
 	return a.kind
 }
 
+// Clone returns a copy of the Material sharing the same frames, that can be pinned independently.
+func (a *Material) Clone() *Material {
+	c := *a
+	return &c
+}
+
+// Pin stops the animation on the given frame: the frames become states (as switches) instead of an animation.
+func (a *Material) Pin(frame int) {
+	a.pinned = true
+	a.pin = uint64(frame)
+}
+
+// NextFrame advances a pinned Material to the next frame, wrapping around the last one.
+func (a *Material) NextFrame() {
+	if a.pinned && a.totalFrames > 0 {
+		a.pin = (a.pin + 1) % a.totalFrames
+	}
+}
+
+// GetFrame returns the index-th frame of the Material, or nil if the index is out of range.
+func (a *Material) GetFrame(index int) *Texture {
+	if index < 0 || uint64(index) >= a.totalFrames {
+		return nil
+	}
+	return a.frames[index]
+}
+
 // CurrentFrame returns the currently active frame of the animation based on global tick and tick interval.
 func (a *Material) CurrentFrame() *Texture {
+	if a.pinned && a.totalFrames > 1 {
+		return a.frames[a.pin%a.totalFrames]
+	}
 	if a.totalFrames > 1 {
 		frameIdx := _currentTick % a.totalFrames
 		return a.frames[frameIdx]